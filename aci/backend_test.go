@@ -142,3 +142,10 @@ func (s *mockLoginService) Logout(ctx context.Context) error {
 	args := s.Called(ctx)
 	return args.Error(0)
 }
+
+func TestImageDigest(t *testing.T) {
+	assert.Equal(t, imageDigest("nginx"), "")
+	assert.Equal(t, imageDigest("nginx:1.19"), "")
+	assert.Equal(t, imageDigest("myregistry.azurecr.io/app@sha256:2f2e5b8e4b3c2e8c0f7b53d3e8a0f0a9e2d1c6b7a8f9e0d1c2b3a4f5e6d7c8b9"),
+		"sha256:2f2e5b8e4b3c2e8c0f7b53d3e8a0f0a9e2d1c6b7a8f9e0d1c2b3a4f5e6d7c8b9")
+}
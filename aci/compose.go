@@ -22,7 +22,9 @@ import (
 	"io"
 	"net/http"
 
+	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/distribution/reference"
 	"github.com/sirupsen/logrus"
 
 	"github.com/docker/compose-cli/aci/convert"
@@ -33,20 +35,34 @@ import (
 )
 
 type aciComposeService struct {
-	ctx          store.AciContext
-	storageLogin login.StorageLoginImpl
+	ctx           store.AciContext
+	storageLogin  login.StorageLoginImpl
+	keyVaultLogin login.KeyVaultLoginImpl
 }
 
 func newComposeService(ctx store.AciContext) aciComposeService {
 	return aciComposeService{
-		ctx:          ctx,
-		storageLogin: login.StorageLoginImpl{AciContext: ctx},
+		ctx:           ctx,
+		storageLogin:  login.StorageLoginImpl{AciContext: ctx},
+		keyVaultLogin: login.KeyVaultLoginImpl{},
 	}
 }
 
 func (cs *aciComposeService) Up(ctx context.Context, project *types.Project, detach bool) error {
 	logrus.Debugf("Up on project with name %q", project.Name)
-	groupDefinition, err := convert.ToContainerGroup(ctx, cs.ctx, *project, cs.storageLogin)
+	err := createFileShares(ctx, cs.ctx, *project)
+	if err != nil {
+		return err
+	}
+	err = createNetworkProfile(ctx, cs.ctx, *project)
+	if err != nil {
+		return err
+	}
+	err = storeKeyVaultSecrets(ctx, cs.ctx, *project)
+	if err != nil {
+		return err
+	}
+	groupDefinition, err := convert.ToContainerGroup(ctx, cs.ctx, *project, cs.storageLogin, cs.keyVaultLogin)
 	addTag(&groupDefinition, composeContainerTag)
 
 	if err != nil {
@@ -125,10 +141,98 @@ func (cs *aciComposeService) List(ctx context.Context, project string) ([]compos
 	return stacks, nil
 }
 
-func (cs *aciComposeService) Logs(ctx context.Context, project string, w io.Writer) error {
+func (cs *aciComposeService) Logs(ctx context.Context, project string, w io.Writer, opts compose.LogOptions) error {
+	return errdefs.ErrNotImplemented
+}
+
+func (cs *aciComposeService) RunOneOffContainer(ctx context.Context, project *types.Project, opts compose.RunOptions) (int, error) {
+	return 0, errdefs.ErrNotImplemented
+}
+
+func (cs *aciComposeService) Exec(ctx context.Context, projectName string, opts compose.ExecOptions) error {
+	return errdefs.ErrNotImplemented
+}
+
+func (cs *aciComposeService) PortForward(ctx context.Context, projectName string, opts compose.PortForwardOptions) error {
+	return errdefs.ErrNotImplemented
+}
+
+func (cs *aciComposeService) Cost(ctx context.Context, project *types.Project) ([]compose.CostEstimate, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (cs *aciComposeService) Stop(ctx context.Context, projectName string) error {
+	return errdefs.ErrNotImplemented
+}
+
+func (cs *aciComposeService) Start(ctx context.Context, projectName string) error {
+	return errdefs.ErrNotImplemented
+}
+
+func (cs *aciComposeService) Restart(ctx context.Context, projectName string, services []string) error {
+	return errdefs.ErrNotImplemented
+}
+
+func (cs *aciComposeService) Kill(ctx context.Context, projectName string, opts compose.KillOptions) error {
+	return errdefs.ErrNotImplemented
+}
+
+func (cs *aciComposeService) Scale(ctx context.Context, projectName string, replicas map[string]int) error {
+	return errdefs.ErrNotImplemented
+}
+
+func (cs *aciComposeService) Top(ctx context.Context, projectName string, services []string) ([]compose.ContainerProcSummary, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (cs *aciComposeService) Images(ctx context.Context, project string) ([]compose.ImageSummary, error) {
+	groupsClient, err := login.NewContainerGroupsClient(cs.ctx.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := groupsClient.Get(ctx, cs.ctx.ResourceGroup, project)
+	if err != nil {
+		return nil, err
+	}
+
+	if group.Containers == nil || len(*group.Containers) == 0 {
+		return nil, fmt.Errorf("no containers found in ACI container group %s", project)
+	}
+
+	res := []compose.ImageSummary{}
+	for _, container := range *group.Containers {
+		if *container.Name == convert.ComposeDNSSidecarName {
+			continue
+		}
+		image := to.String(container.Image)
+		res = append(res, compose.ImageSummary{
+			ID:       getContainerID(group, container),
+			Service:  *container.Name,
+			Image:    image,
+			Digest:   imageDigest(image),
+			Platform: string(group.OsType),
+		})
+	}
+	return res, nil
+}
+
+// imageDigest returns the digest of an image reference, only known by ACI when the image is pinned by digest
+func imageDigest(image string) string {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return ""
+	}
+	if canonical, ok := named.(reference.Canonical); ok {
+		return canonical.Digest().String()
+	}
+	return ""
+}
+
+func (cs *aciComposeService) Events(ctx context.Context, projectName string, consumer func(compose.Event)) error {
 	return errdefs.ErrNotImplemented
 }
 
-func (cs *aciComposeService) Convert(ctx context.Context, project *types.Project) ([]byte, error) {
+func (cs *aciComposeService) Convert(ctx context.Context, project *types.Project, format string) ([]byte, error) {
 	return nil, errdefs.ErrNotImplemented
 }
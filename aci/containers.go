@@ -37,14 +37,16 @@ import (
 )
 
 type aciContainerService struct {
-	ctx          store.AciContext
-	storageLogin login.StorageLoginImpl
+	ctx           store.AciContext
+	storageLogin  login.StorageLoginImpl
+	keyVaultLogin login.KeyVaultLoginImpl
 }
 
 func newContainerService(ctx store.AciContext) aciContainerService {
 	return aciContainerService{
-		ctx:          ctx,
-		storageLogin: login.StorageLoginImpl{AciContext: ctx},
+		ctx:           ctx,
+		storageLogin:  login.StorageLoginImpl{AciContext: ctx},
+		keyVaultLogin: login.KeyVaultLoginImpl{},
 	}
 }
 
@@ -81,7 +83,7 @@ func (cs *aciContainerService) Run(ctx context.Context, r containers.ContainerCo
 	}
 
 	logrus.Debugf("Running container %q with name %q", r.Image, r.ID)
-	groupDefinition, err := convert.ToContainerGroup(ctx, cs.ctx, project, cs.storageLogin)
+	groupDefinition, err := convert.ToContainerGroup(ctx, cs.ctx, project, cs.storageLogin, cs.keyVaultLogin)
 	if err != nil {
 		return err
 	}
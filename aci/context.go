@@ -27,6 +27,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 
+	"github.com/docker/compose-cli/aci/convert"
 	"github.com/docker/compose-cli/context/store"
 	"github.com/docker/compose-cli/errdefs"
 	"github.com/docker/compose-cli/prompt"
@@ -38,6 +39,11 @@ type ContextParams struct {
 	Location       string
 	SubscriptionID string
 	ResourceGroup  string
+	// Tags are applied to all Azure resources created using this context
+	Tags map[string]string
+	// VNet and Subnet are the virtual network container groups are privately deployed into, if set
+	VNet   string
+	Subnet string
 }
 
 // ErrSubscriptionNotFound is returned when a required subscription is not found
@@ -110,16 +116,20 @@ func (helper contextCreateACIHelper) createContextData(ctx context.Context, opts
 		SubscriptionID: subscriptionID,
 		Location:       location,
 		ResourceGroup:  *group.Name,
+		Tags:           opts.Tags,
+		VNet:           opts.VNet,
+		Subnet:         opts.Subnet,
 	}, description, nil
 }
 
-func (helper contextCreateACIHelper) createGroup(ctx context.Context, subscriptionID, location string) (resources.Group, error) {
+func (helper contextCreateACIHelper) createGroup(ctx context.Context, subscriptionID, location string, tags map[string]string) (resources.Group, error) {
 	if location == "" {
 		location = "eastus"
 	}
 	gid := uuid.New().String()
 	g, err := helper.resourceGroupHelper.CreateOrUpdate(ctx, subscriptionID, gid, resources.Group{
 		Location: &location,
+		Tags:     convert.ToAzureTags(tags),
 	})
 	if err != nil {
 		return resources.Group{}, err
@@ -146,7 +156,7 @@ func (helper contextCreateACIHelper) chooseGroup(ctx context.Context, subscripti
 	}
 
 	if group == 0 {
-		return helper.createGroup(ctx, subscriptionID, opts.Location)
+		return helper.createGroup(ctx, subscriptionID, opts.Location, opts.Tags)
 	}
 
 	return groups[group-1], nil
@@ -46,14 +46,18 @@ const (
 )
 
 // ToContainerGroup converts a compose project into a ACI container group
-func ToContainerGroup(ctx context.Context, aciContext store.AciContext, p types.Project, storageHelper login.StorageLogin) (containerinstance.ContainerGroup, error) {
+func ToContainerGroup(ctx context.Context, aciContext store.AciContext, p types.Project, storageHelper login.StorageLogin, keyVaultHelper login.KeyVaultLogin) (containerinstance.ContainerGroup, error) {
 	project := projectAciHelper(p)
 	containerGroupName := strings.ToLower(project.Name)
 	volumesCache, volumesSlice, err := project.getAciFileVolumes(ctx, storageHelper)
 	if err != nil {
 		return containerinstance.ContainerGroup{}, err
 	}
-	secretVolumes, err := project.getAciSecretVolumes()
+	secretContents, err := project.getSecretContents(ctx, keyVaultHelper)
+	if err != nil {
+		return containerinstance.ContainerGroup{}, err
+	}
+	secretVolumes, err := project.getAciSecretVolumes(secretContents)
 	if err != nil {
 		return containerinstance.ContainerGroup{}, err
 	}
@@ -73,9 +77,19 @@ func ToContainerGroup(ctx context.Context, aciContext store.AciContext, p types.
 	if err != nil {
 		return containerinstance.ContainerGroup{}, err
 	}
+	network, private, err := GetVirtualNetwork(p, aciContext)
+	if err != nil {
+		return containerinstance.ContainerGroup{}, err
+	}
+	identity, err := getIdentity(p, aciContext)
+	if err != nil {
+		return containerinstance.ContainerGroup{}, err
+	}
 	groupDefinition := containerinstance.ContainerGroup{
 		Name:     &containerGroupName,
 		Location: &aciContext.Location,
+		Tags:     ToAzureTags(aciContext.Tags),
+		Identity: identity,
 		ContainerGroupProperties: &containerinstance.ContainerGroupProperties{
 			OsType:                   containerinstance.Linux,
 			Containers:               &containers,
@@ -84,15 +98,26 @@ func ToContainerGroup(ctx context.Context, aciContext store.AciContext, p types.
 			RestartPolicy:            restartPolicy,
 		},
 	}
+	if private {
+		groupDefinition.ContainerGroupProperties.NetworkProfile = &containerinstance.ContainerGroupNetworkProfile{
+			ID: to.StringPtr(network.ProfileID(aciContext)),
+		}
+	}
 
 	var groupPorts []containerinstance.Port
 	var dnsLabelName *string
 	for _, s := range project.Services {
+		secretEnv, secretFiles := project.getAciSecretEnvironment(s, secretContents)
 		service := serviceConfigAciHelper(s)
+		service.Secrets = secretFiles
 		containerDefinition, err := service.getAciContainer(volumesCache)
 		if err != nil {
 			return containerinstance.ContainerGroup{}, err
 		}
+		if len(secretEnv) > 0 {
+			env := append(*containerDefinition.EnvironmentVariables, secretEnv...)
+			containerDefinition.EnvironmentVariables = &env
+		}
 		if service.Labels != nil && len(service.Labels) > 0 {
 			return containerinstance.ContainerGroup{}, errors.New("ACI integration does not support labels in compose applications")
 		}
@@ -112,9 +137,23 @@ func ToContainerGroup(ctx context.Context, aciContext store.AciContext, p types.
 
 		containers = append(containers, containerDefinition)
 	}
-	if len(groupPorts) > 0 {
+	if err := checkGPUs(containers, aciContext.Location); err != nil {
+		return containerinstance.ContainerGroup{}, err
+	}
+	if private && dnsLabelName != nil {
+		return containerinstance.ContainerGroup{}, fmt.Errorf("ACI integration does not support domain names on services deployed into a virtual network")
+	}
+	// container groups in a virtual network always get a private IP, services are reached on, even without ports
+	if len(groupPorts) > 0 || private {
+		ipType := containerinstance.Public
+		if private {
+			ipType = containerinstance.Private
+		}
+		if groupPorts == nil {
+			groupPorts = []containerinstance.Port{}
+		}
 		groupDefinition.ContainerGroupProperties.IPAddress = &containerinstance.IPAddress{
-			Type:         containerinstance.Public,
+			Type:         ipType,
 			Ports:        &groupPorts,
 			DNSNameLabel: dnsLabelName,
 		}
@@ -128,6 +167,18 @@ func ToContainerGroup(ctx context.Context, aciContext store.AciContext, p types.
 	return groupDefinition, nil
 }
 
+// ToAzureTags converts tags set by context into Azure resource tags
+func ToAzureTags(tags map[string]string) map[string]*string {
+	if len(tags) == 0 {
+		return nil
+	}
+	azureTags := make(map[string]*string, len(tags))
+	for k, v := range tags {
+		azureTags[k] = to.StringPtr(v)
+	}
+	return azureTags
+}
+
 func getDNSSidecar(containers []containerinstance.Container) containerinstance.Container {
 	var commands []string
 	for _, container := range containers {
@@ -228,14 +279,20 @@ func (s serviceConfigAciHelper) getResourceRequestsLimits() (*containerinstance.
 			}
 		}
 	}
+	gpu, err := s.getGPU()
+	if err != nil {
+		return nil, err
+	}
 	resources := containerinstance.ResourceRequirements{
 		Requests: &containerinstance.ResourceRequests{
 			MemoryInGB: to.Float64Ptr(memRequest),
 			CPU:        to.Float64Ptr(cpuRequest),
+			Gpu:        gpu,
 		},
 		Limits: &containerinstance.ResourceLimits{
 			MemoryInGB: to.Float64Ptr(memLimit),
 			CPU:        to.Float64Ptr(cpuLimit),
+			Gpu:        gpu,
 		},
 	}
 	return &resources, nil
@@ -274,15 +331,24 @@ func ContainerGroupToServiceStatus(containerID string, group containerinstance.C
 	if GetStatus(container, group) != StatusRunning {
 		replicas = 0
 	}
+	ports := formatter.PortsToStrings(ToPorts(group.IPAddress, *container.Ports), fqdn(group, region))
+	// services of a privately deployed container group are reachable on its IP, even without published ports
+	if len(ports) == 0 && isPrivate(group) {
+		ports = []string{*group.IPAddress.IP}
+	}
 	return compose.ServiceStatus{
 		ID:       containerID,
 		Name:     *container.Name,
-		Ports:    formatter.PortsToStrings(ToPorts(group.IPAddress, *container.Ports), fqdn(group, region)),
+		Ports:    ports,
 		Replicas: replicas,
 		Desired:  1,
 	}
 }
 
+func isPrivate(group containerinstance.ContainerGroup) bool {
+	return group.IPAddress != nil && group.IPAddress.Type == containerinstance.Private && group.IPAddress.IP != nil
+}
+
 func fqdn(group containerinstance.ContainerGroup, region string) string {
 	fqdn := ""
 	if group.IPAddress != nil && group.IPAddress.DNSNameLabel != nil && *group.IPAddress.DNSNameLabel != "" {
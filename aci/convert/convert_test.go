@@ -38,14 +38,15 @@ var (
 		ResourceGroup:  "rg",
 		Location:       "eu",
 	}
-	mockStorageHelper = &mockStorageLogin{}
+	mockStorageHelper  = &mockStorageLogin{}
+	mockKeyVaultHelper = &mockKeyVaultLogin{}
 )
 
 func TestProjectName(t *testing.T) {
 	project := types.Project{
 		Name: "TEST",
 	}
-	containerGroup, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper)
+	containerGroup, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper, mockKeyVaultHelper)
 	assert.NilError(t, err)
 	assert.Equal(t, *containerGroup.Name, "test")
 }
@@ -171,7 +172,7 @@ func TestComposeContainerGroupToContainerWithDnsSideCarSide(t *testing.T) {
 		},
 	}
 
-	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper)
+	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper, mockKeyVaultHelper)
 	assert.NilError(t, err)
 	assert.Assert(t, is.Len(*group.Containers, 3))
 
@@ -186,6 +187,23 @@ func TestComposeContainerGroupToContainerWithDnsSideCarSide(t *testing.T) {
 	assert.Equal(t, *(*group.Containers)[2].Image, dnsSidecarImage)
 }
 
+func TestContainerGroupTags(t *testing.T) {
+	project := types.Project{
+		Services: []types.ServiceConfig{
+			{
+				Name:  "service1",
+				Image: "image1",
+			},
+		},
+	}
+	aciContext := convertCtx
+	aciContext.Tags = map[string]string{"cost-center": "42"}
+
+	group, err := ToContainerGroup(context.TODO(), aciContext, project, mockStorageHelper, mockKeyVaultHelper)
+	assert.NilError(t, err)
+	assert.Equal(t, *group.Tags["cost-center"], "42")
+}
+
 func TestComposeSingleContainerGroupToContainerNoDnsSideCarSide(t *testing.T) {
 	project := types.Project{
 		Services: []types.ServiceConfig{
@@ -196,7 +214,7 @@ func TestComposeSingleContainerGroupToContainerNoDnsSideCarSide(t *testing.T) {
 		},
 	}
 
-	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper)
+	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper, mockKeyVaultHelper)
 	assert.NilError(t, err)
 
 	assert.Assert(t, is.Len(*group.Containers, 1))
@@ -217,7 +235,7 @@ func TestLabelsErrorMessage(t *testing.T) {
 		},
 	}
 
-	_, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper)
+	_, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper, mockKeyVaultHelper)
 	assert.Error(t, err, "ACI integration does not support labels in compose applications")
 }
 
@@ -248,7 +266,7 @@ func TestComposeContainerGroupToContainerWithDomainName(t *testing.T) {
 		},
 	}
 
-	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper)
+	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper, mockKeyVaultHelper)
 	assert.NilError(t, err)
 	assert.Assert(t, is.Len(*group.Containers, 3))
 
@@ -275,7 +293,7 @@ func TestComposeContainerGroupToContainerErrorWhenSeveralDomainNames(t *testing.
 		},
 	}
 
-	_, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper)
+	_, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper, mockKeyVaultHelper)
 	assert.Error(t, err, "ACI integration does not support specifying different domain names on services in the same compose application")
 }
 
@@ -296,7 +314,7 @@ func TestComposeContainerGroupToContainerIgnoreDomainNameWithoutPorts(t *testing
 		},
 	}
 
-	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper)
+	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper, mockKeyVaultHelper)
 	assert.NilError(t, err)
 	assert.Assert(t, is.Len(*group.Containers, 3))
 	assert.Assert(t, group.IPAddress == nil)
@@ -322,7 +340,7 @@ func TestComposeContainerGroupToContainerResourceRequests(t *testing.T) {
 		},
 	}
 
-	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper)
+	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper, mockKeyVaultHelper)
 	assert.NilError(t, err)
 
 	request := *((*group.Containers)[0]).Resources.Requests
@@ -355,7 +373,7 @@ func TestComposeContainerGroupToContainerResourceRequestsAndLimits(t *testing.T)
 		},
 	}
 
-	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper)
+	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper, mockKeyVaultHelper)
 	assert.NilError(t, err)
 
 	request := *((*group.Containers)[0]).Resources.Requests
@@ -384,7 +402,7 @@ func TestComposeContainerGroupToContainerResourceLimitsOnly(t *testing.T) {
 		},
 	}
 
-	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper)
+	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper, mockKeyVaultHelper)
 	assert.NilError(t, err)
 
 	request := *((*group.Containers)[0]).Resources.Requests
@@ -413,7 +431,7 @@ func TestComposeContainerGroupToContainerResourceRequestsDefaults(t *testing.T)
 		},
 	}
 
-	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper)
+	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper, mockKeyVaultHelper)
 	assert.NilError(t, err)
 
 	request := *((*group.Containers)[0]).Resources.Requests
@@ -437,7 +455,7 @@ func TestComposeContainerGroupToContainerenvVar(t *testing.T) {
 		},
 	}
 
-	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper)
+	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper, mockKeyVaultHelper)
 	assert.NilError(t, err)
 
 	envVars := *((*group.Containers)[0]).EnvironmentVariables
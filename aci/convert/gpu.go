@@ -0,0 +1,104 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package convert
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+const (
+	// extensionGPUSku selects the GPU model of a service reserving GPUs
+	extensionGPUSku = "x-aci-gpu_sku"
+	gpuResourceKind = "gpus"
+)
+
+// gpuRegions lists the regions where ACI offers each GPU SKU
+var gpuRegions = map[containerinstance.GpuSku][]string{
+	containerinstance.K80:  {"eastus", "northeurope", "westeurope", "westus2"},
+	containerinstance.P100: {"eastus", "southeastasia", "westeurope", "westus2"},
+	containerinstance.V100: {"centralindia", "eastus", "southeastasia", "westeurope", "westus2"},
+}
+
+// getGPU returns the GPUs reserved by a service through generic resources, of the SKU set by x-aci-gpu_sku, K80 by
+// default
+func (s serviceConfigAciHelper) getGPU() (*containerinstance.GpuResource, error) {
+	var count int64
+	if s.Deploy != nil && s.Deploy.Resources.Reservations != nil {
+		for _, r := range s.Deploy.Resources.Reservations.GenericResources {
+			if r.DiscreteResourceSpec != nil && r.DiscreteResourceSpec.Kind == gpuResourceKind {
+				count = r.DiscreteResourceSpec.Value
+			}
+		}
+	}
+	sku, hasSku := s.Extensions[extensionGPUSku]
+	if count == 0 {
+		if hasSku {
+			return nil, errors.Wrapf(errdefs.ErrParsingFailed, "service %s sets %s without reserving GPUs", s.Name, extensionGPUSku)
+		}
+		return nil, nil
+	}
+	switch count {
+	case 1, 2, 4:
+	default:
+		return nil, errors.Wrapf(errdefs.ErrParsingFailed, "service %s reserves %d GPUs, ACI supports 1, 2 or 4 GPUs per container", s.Name, count)
+	}
+	gpu := containerinstance.GpuResource{
+		Count: to.Int32Ptr(int32(count)),
+		Sku:   containerinstance.K80,
+	}
+	if hasSku {
+		gpu.Sku = containerinstance.GpuSku(strings.ToUpper(fmt.Sprint(sku)))
+		if _, ok := gpuRegions[gpu.Sku]; !ok {
+			return nil, errors.Wrapf(errdefs.ErrParsingFailed, "unsupported GPU SKU %q for service %s, expected one of K80, P100 or V100", sku, s.Name)
+		}
+	}
+	return &gpu, nil
+}
+
+// checkGPUs checks that containers reserving GPUs use a single SKU, available in the container group location
+func checkGPUs(containers []containerinstance.Container, location string) error {
+	var sku containerinstance.GpuSku
+	for _, c := range containers {
+		if c.Resources == nil || c.Resources.Requests == nil || c.Resources.Requests.Gpu == nil {
+			continue
+		}
+		gpu := c.Resources.Requests.Gpu
+		if sku != "" && gpu.Sku != sku {
+			return fmt.Errorf("ACI container groups can't mix GPU SKUs, found %s and %s", sku, gpu.Sku)
+		}
+		sku = gpu.Sku
+	}
+	if sku == "" {
+		return nil
+	}
+	regions := gpuRegions[sku]
+	for _, r := range regions {
+		if r == strings.ToLower(location) {
+			return nil
+		}
+	}
+	sort.Strings(regions)
+	return fmt.Errorf("%s GPUs are not available in region %s, use a context in one of %s", sku, location, strings.Join(regions, ", "))
+}
@@ -0,0 +1,99 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package convert
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/context/store"
+)
+
+func gpuService(name string, count int64, sku string) types.ServiceConfig {
+	service := types.ServiceConfig{
+		Name:  name,
+		Image: "tensorflow/tensorflow:latest-gpu",
+		Deploy: &types.DeployConfig{
+			Resources: types.Resources{
+				Reservations: &types.Resource{
+					GenericResources: []types.GenericResource{
+						{DiscreteResourceSpec: &types.DiscreteGenericResource{Kind: "gpus", Value: count}},
+					},
+				},
+			},
+		},
+	}
+	if sku != "" {
+		service.Extensions = map[string]interface{}{extensionGPUSku: sku}
+	}
+	return service
+}
+
+func TestContainerGroupGPU(t *testing.T) {
+	project := types.Project{
+		Services: []types.ServiceConfig{
+			gpuService("inference", 2, "v100"),
+		},
+	}
+	aciContext := store.AciContext{Location: "westeurope"}
+	group, err := ToContainerGroup(context.TODO(), aciContext, project, mockStorageHelper, mockKeyVaultHelper)
+	assert.NilError(t, err)
+	resources := (*group.Containers)[0].Resources
+	expected := &containerinstance.GpuResource{Count: to.Int32Ptr(2), Sku: containerinstance.V100}
+	assert.DeepEqual(t, resources.Requests.Gpu, expected)
+	assert.DeepEqual(t, resources.Limits.Gpu, expected)
+}
+
+func TestGPUDefaultSku(t *testing.T) {
+	gpu, err := serviceConfigAciHelper(gpuService("inference", 1, "")).getGPU()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, gpu, &containerinstance.GpuResource{Count: to.Int32Ptr(1), Sku: containerinstance.K80})
+
+	gpu, err = serviceConfigAciHelper(types.ServiceConfig{Name: "web"}).getGPU()
+	assert.NilError(t, err)
+	assert.Assert(t, gpu == nil)
+}
+
+func TestInvalidGPU(t *testing.T) {
+	_, err := serviceConfigAciHelper(gpuService("inference", 3, "")).getGPU()
+	assert.ErrorContains(t, err, "service inference reserves 3 GPUs, ACI supports 1, 2 or 4 GPUs per container")
+
+	_, err = serviceConfigAciHelper(gpuService("inference", 1, "A100")).getGPU()
+	assert.ErrorContains(t, err, `unsupported GPU SKU "A100" for service inference`)
+
+	_, err = serviceConfigAciHelper(gpuService("inference", 0, "K80")).getGPU()
+	assert.ErrorContains(t, err, "service inference sets x-aci-gpu_sku without reserving GPUs")
+}
+
+func TestCheckGPUs(t *testing.T) {
+	project := types.Project{
+		Services: []types.ServiceConfig{
+			gpuService("inference", 1, "P100"),
+		},
+	}
+	_, err := ToContainerGroup(context.TODO(), store.AciContext{Location: "northeurope"}, project, mockStorageHelper, mockKeyVaultHelper)
+	assert.Error(t, err, "P100 GPUs are not available in region northeurope, use a context in one of eastus, southeastasia, westeurope, westus2")
+
+	project.Services = append(project.Services, gpuService("training", 1, "V100"))
+	_, err = ToContainerGroup(context.TODO(), store.AciContext{Location: "westeurope"}, project, mockStorageHelper, mockKeyVaultHelper)
+	assert.Error(t, err, "ACI container groups can't mix GPU SKUs, found P100 and V100")
+}
@@ -0,0 +1,91 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package convert
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+const (
+	// extensionManagedIdentity assigns managed identities to the container group
+	extensionManagedIdentity = "x-aci-managed-identity"
+	systemAssignedIdentity   = "system"
+)
+
+// getIdentity returns the managed identities set by x-aci-managed-identity, either `system` for the container group
+// system-assigned identity, or user-assigned identity names or resource IDs
+func getIdentity(p types.Project, aciContext store.AciContext) (*containerinstance.ContainerGroupIdentity, error) {
+	var names []string
+	switch v := p.Extensions[extensionManagedIdentity].(type) {
+	case nil:
+	case string:
+		names = []string{v}
+	case []interface{}:
+		for _, name := range v {
+			names = append(names, fmt.Sprint(name))
+		}
+	default:
+		return nil, errors.Wrapf(errdefs.ErrParsingFailed, "%s must be an identity or a list of identities", extensionManagedIdentity)
+	}
+
+	system := false
+	userAssigned := map[string]*containerinstance.ContainerGroupIdentityUserAssignedIdentitiesValue{}
+	for _, name := range names {
+		switch {
+		case name == "":
+			return nil, errors.Wrapf(errdefs.ErrParsingFailed, "%s has an empty identity", extensionManagedIdentity)
+		case strings.EqualFold(name, systemAssignedIdentity):
+			system = true
+		default:
+			userAssigned[userAssignedIdentityID(aciContext, name)] = &containerinstance.ContainerGroupIdentityUserAssignedIdentitiesValue{}
+		}
+	}
+
+	identity := containerinstance.ContainerGroupIdentity{}
+	switch {
+	case system && len(userAssigned) > 0:
+		identity.Type = containerinstance.SystemAssignedUserAssigned
+	case system:
+		identity.Type = containerinstance.SystemAssigned
+	case len(userAssigned) > 0:
+		identity.Type = containerinstance.UserAssigned
+	default:
+		return nil, nil
+	}
+	if len(userAssigned) > 0 {
+		identity.UserAssignedIdentities = userAssigned
+	}
+	return &identity, nil
+}
+
+// userAssignedIdentityID returns the resource ID of a user-assigned identity, names being looked up in the context
+// resource group
+func userAssignedIdentityID(aciContext store.AciContext, name string) string {
+	if strings.HasPrefix(name, "/") {
+		return name
+	}
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ManagedIdentity/userAssignedIdentities/%s",
+		aciContext.SubscriptionID, aciContext.ResourceGroup, name)
+}
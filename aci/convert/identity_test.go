@@ -0,0 +1,78 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package convert
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func identityProject(identity interface{}) types.Project {
+	return types.Project{
+		Services: []types.ServiceConfig{
+			{
+				Name:  "service1",
+				Image: "image1",
+			},
+		},
+		Extensions: map[string]interface{}{extensionManagedIdentity: identity},
+	}
+}
+
+func TestSystemAssignedIdentity(t *testing.T) {
+	group, err := ToContainerGroup(context.TODO(), convertCtx, identityProject("system"), mockStorageHelper, mockKeyVaultHelper)
+	assert.NilError(t, err)
+	assert.Equal(t, group.Identity.Type, containerinstance.SystemAssigned)
+	assert.Assert(t, group.Identity.UserAssignedIdentities == nil)
+}
+
+func TestUserAssignedIdentities(t *testing.T) {
+	id := "/subscriptions/otherSub/resourceGroups/otherRG/providers/Microsoft.ManagedIdentity/userAssignedIdentities/other"
+	identity, err := getIdentity(identityProject([]interface{}{"myidentity", id}), convertCtx)
+	assert.NilError(t, err)
+	assert.Equal(t, identity.Type, containerinstance.UserAssigned)
+	assert.Equal(t, len(identity.UserAssignedIdentities), 2)
+	_, ok := identity.UserAssignedIdentities["/subscriptions/subID/resourceGroups/rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/myidentity"]
+	assert.Assert(t, ok)
+	_, ok = identity.UserAssignedIdentities[id]
+	assert.Assert(t, ok)
+}
+
+func TestSystemAndUserAssignedIdentities(t *testing.T) {
+	identity, err := getIdentity(identityProject([]interface{}{"system", "myidentity"}), convertCtx)
+	assert.NilError(t, err)
+	assert.Equal(t, identity.Type, containerinstance.SystemAssignedUserAssigned)
+	assert.Equal(t, len(identity.UserAssignedIdentities), 1)
+}
+
+func TestNoIdentity(t *testing.T) {
+	identity, err := getIdentity(types.Project{}, convertCtx)
+	assert.NilError(t, err)
+	assert.Assert(t, identity == nil)
+}
+
+func TestInvalidIdentity(t *testing.T) {
+	_, err := getIdentity(identityProject(map[string]interface{}{"name": "myidentity"}), convertCtx)
+	assert.ErrorContains(t, err, "x-aci-managed-identity must be an identity or a list of identities")
+
+	_, err = getIdentity(identityProject([]interface{}{""}), convertCtx)
+	assert.ErrorContains(t, err, "x-aci-managed-identity has an empty identity")
+}
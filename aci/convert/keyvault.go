@@ -0,0 +1,57 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package convert
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+const (
+	// extensionKeyVault sets the Key Vault storing the project secrets
+	extensionKeyVault = "x-aci-keyvault"
+	// extensionSecretEnvironment exposes a secret as a secure environment variable rather than a file
+	extensionSecretEnvironment = "x-aci-environment"
+)
+
+var keyVaultSecretName = regexp.MustCompile(`^[0-9a-zA-Z-]{1,127}$`)
+
+// GetKeyVault returns the Key Vault set by x-aci-keyvault, empty if project secrets aren't stored in Key Vault
+func GetKeyVault(p types.Project) string {
+	if x, ok := p.Extensions[extensionKeyVault]; ok {
+		return fmt.Sprint(x)
+	}
+	return ""
+}
+
+// KeyVaultSecretName returns the name of a compose secret in Key Vault: the secret name for external secrets, the
+// secret key otherwise
+func KeyVaultSecretName(key string, config types.SecretConfig) (string, error) {
+	name := key
+	if config.External.External && config.Name != "" {
+		name = config.Name
+	}
+	if !keyVaultSecretName.MatchString(name) {
+		return "", errors.Wrapf(errdefs.ErrParsingFailed, "invalid Key Vault secret name %q for secret %s, only alphanumeric characters and dashes are allowed", name, key)
+	}
+	return name, nil
+}
@@ -0,0 +1,82 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package convert
+
+import (
+	"fmt"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+const (
+	// extensionVNet sets the virtual network container groups are deployed into
+	extensionVNet = "x-aci-vnet"
+	// extensionSubnet sets the subnet of the virtual network container groups are deployed into
+	extensionSubnet = "x-aci-subnet"
+
+	defaultSubnet = "aci"
+)
+
+// VirtualNetwork is the subnet of a virtual network a container group is privately deployed into
+type VirtualNetwork struct {
+	VNet   string
+	Subnet string
+}
+
+// ProfileName returns the name of the network profile attaching container groups to the subnet
+func (n VirtualNetwork) ProfileName() string {
+	return fmt.Sprintf("%s-%s-profile", n.VNet, n.Subnet)
+}
+
+// ProfileID returns the resource ID of the network profile attaching container groups to the subnet
+func (n VirtualNetwork) ProfileID(aciContext store.AciContext) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/networkProfiles/%s",
+		aciContext.SubscriptionID, aciContext.ResourceGroup, n.ProfileName())
+}
+
+// GetVirtualNetwork returns the virtual network set by x-aci-vnet and x-aci-subnet, defaulting to the context ones.
+// It returns false when the container group isn't deployed into a virtual network.
+func GetVirtualNetwork(p types.Project, aciContext store.AciContext) (VirtualNetwork, bool, error) {
+	network := VirtualNetwork{
+		VNet:   aciContext.VNet,
+		Subnet: aciContext.Subnet,
+	}
+	if x, ok := p.Extensions[extensionVNet]; ok {
+		network.VNet = fmt.Sprint(x)
+		// the context subnet belongs to the context virtual network
+		if network.VNet != aciContext.VNet {
+			network.Subnet = ""
+		}
+	}
+	if x, ok := p.Extensions[extensionSubnet]; ok {
+		network.Subnet = fmt.Sprint(x)
+	}
+	if network.VNet == "" {
+		if network.Subnet != "" {
+			return VirtualNetwork{}, false, errors.Wrapf(errdefs.ErrParsingFailed, "%s requires a virtual network, set with %s", extensionSubnet, extensionVNet)
+		}
+		return VirtualNetwork{}, false, nil
+	}
+	if network.Subnet == "" {
+		network.Subnet = defaultSubnet
+	}
+	return network, true, nil
+}
@@ -0,0 +1,182 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package convert
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestGetVirtualNetwork(t *testing.T) {
+	vnetCtx := convertCtx
+	vnetCtx.VNet = "ctx-vnet"
+	vnetCtx.Subnet = "ctx-subnet"
+
+	tests := []struct {
+		name       string
+		extensions map[string]interface{}
+		aciContext bool
+		expected   VirtualNetwork
+		private    bool
+	}{
+		{name: "public"},
+		{
+			name:       "context defaults",
+			aciContext: true,
+			expected:   VirtualNetwork{VNet: "ctx-vnet", Subnet: "ctx-subnet"},
+			private:    true,
+		},
+		{
+			name:       "default subnet",
+			extensions: map[string]interface{}{extensionVNet: "myvnet"},
+			expected:   VirtualNetwork{VNet: "myvnet", Subnet: "aci"},
+			private:    true,
+		},
+		{
+			name:       "vnet overrides context",
+			extensions: map[string]interface{}{extensionVNet: "myvnet"},
+			aciContext: true,
+			expected:   VirtualNetwork{VNet: "myvnet", Subnet: "aci"},
+			private:    true,
+		},
+		{
+			name:       "subnet of context vnet",
+			extensions: map[string]interface{}{extensionSubnet: "mysubnet"},
+			aciContext: true,
+			expected:   VirtualNetwork{VNet: "ctx-vnet", Subnet: "mysubnet"},
+			private:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aciContext := convertCtx
+			if tt.aciContext {
+				aciContext = vnetCtx
+			}
+			network, private, err := GetVirtualNetwork(types.Project{Extensions: tt.extensions}, aciContext)
+			assert.NilError(t, err)
+			assert.Equal(t, private, tt.private)
+			assert.Equal(t, network, tt.expected)
+		})
+	}
+}
+
+func TestGetVirtualNetworkSubnetWithoutVNet(t *testing.T) {
+	project := types.Project{Extensions: map[string]interface{}{extensionSubnet: "mysubnet"}}
+	_, _, err := GetVirtualNetwork(project, convertCtx)
+	assert.ErrorContains(t, err, "x-aci-subnet requires a virtual network")
+}
+
+func TestComposeContainerGroupToContainerInVirtualNetwork(t *testing.T) {
+	project := types.Project{
+		Services: []types.ServiceConfig{
+			{
+				Name:  "service1",
+				Image: "image1",
+				Ports: []types.ServicePortConfig{
+					{
+						Published: 80,
+						Target:    80,
+					},
+				},
+			},
+		},
+		Extensions: map[string]interface{}{extensionVNet: "myvnet", extensionSubnet: "mysubnet"},
+	}
+
+	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper, mockKeyVaultHelper)
+	assert.NilError(t, err)
+	assert.Equal(t, *group.NetworkProfile.ID, "/subscriptions/subID/resourceGroups/rg/providers/Microsoft.Network/networkProfiles/myvnet-mysubnet-profile")
+	assert.Equal(t, group.IPAddress.Type, containerinstance.Private)
+	assert.Equal(t, *(*group.IPAddress.Ports)[0].Port, int32(80))
+}
+
+func TestComposeContainerGroupToContainerInVirtualNetworkWithoutPorts(t *testing.T) {
+	project := types.Project{
+		Services: []types.ServiceConfig{
+			{
+				Name:  "worker",
+				Image: "image1",
+			},
+		},
+		Extensions: map[string]interface{}{extensionVNet: "myvnet"},
+	}
+
+	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper, mockKeyVaultHelper)
+	assert.NilError(t, err)
+	assert.Assert(t, group.NetworkProfile != nil)
+	assert.Equal(t, group.IPAddress.Type, containerinstance.Private)
+	assert.Equal(t, len(*group.IPAddress.Ports), 0)
+}
+
+func TestComposeContainerGroupToContainerInVirtualNetworkWithDomainName(t *testing.T) {
+	project := types.Project{
+		Services: []types.ServiceConfig{
+			{
+				Name:  "service1",
+				Image: "image1",
+				Ports: []types.ServicePortConfig{
+					{
+						Published: 80,
+						Target:    80,
+					},
+				},
+				DomainName: "myApp",
+			},
+		},
+		Extensions: map[string]interface{}{extensionVNet: "myvnet"},
+	}
+
+	_, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper, mockKeyVaultHelper)
+	assert.ErrorContains(t, err, "does not support domain names on services deployed into a virtual network")
+}
+
+func TestPrivateContainerGroupToServiceStatus(t *testing.T) {
+	group := containerinstance.ContainerGroup{
+		ContainerGroupProperties: &containerinstance.ContainerGroupProperties{
+			IPAddress: &containerinstance.IPAddress{
+				Type: containerinstance.Private,
+				Ports: &[]containerinstance.Port{{
+					Port: to.Int32Ptr(80),
+				}},
+				IP: to.StringPtr("10.0.0.4"),
+			},
+		},
+	}
+	web := containerinstance.Container{
+		Name: to.StringPtr("web"),
+		ContainerProperties: &containerinstance.ContainerProperties{
+			Ports: &[]containerinstance.ContainerPort{{
+				Port: to.Int32Ptr(80),
+			}},
+		},
+	}
+	worker := containerinstance.Container{
+		Name: to.StringPtr("worker"),
+		ContainerProperties: &containerinstance.ContainerProperties{
+			Ports: &[]containerinstance.ContainerPort{},
+		},
+	}
+
+	assert.DeepEqual(t, ContainerGroupToServiceStatus("web", group, web, "eastus").Ports, []string{"10.0.0.4:80->80/tcp"})
+	assert.DeepEqual(t, ContainerGroupToServiceStatus("worker", group, worker, "eastus").Ports, []string{"10.0.0.4"})
+}
@@ -55,7 +55,7 @@ func TestComposeContainerGroupToContainerMultiplePorts(t *testing.T) {
 		},
 	}
 
-	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper)
+	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper, mockKeyVaultHelper)
 	assert.NilError(t, err)
 	assert.Assert(t, is.Len(*group.Containers, 3))
 
@@ -17,7 +17,10 @@
 package convert
 
 import (
+	"strings"
+
 	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
+	"github.com/compose-spec/compose-go/types"
 	"github.com/pkg/errors"
 
 	"github.com/docker/compose-cli/api/containers"
@@ -29,22 +32,40 @@ func (p projectAciHelper) getRestartPolicy() (containerinstance.ContainerGroupRe
 		alreadySpecified := false
 		restartPolicyCondition = containerinstance.Always
 		for _, service := range p.Services {
-			if service.Deploy != nil &&
-				service.Deploy.RestartPolicy != nil {
-				if !alreadySpecified {
-					alreadySpecified = true
-					restartPolicyCondition = toAciRestartPolicy(service.Deploy.RestartPolicy.Condition)
-				}
-				if alreadySpecified && restartPolicyCondition != toAciRestartPolicy(service.Deploy.RestartPolicy.Condition) {
-					return "", errors.New("ACI integration does not support specifying different restart policies on services in the same compose application")
-				}
-
+			condition, ok := getRestartCondition(service)
+			if !ok {
+				continue
+			}
+			if !alreadySpecified {
+				alreadySpecified = true
+				restartPolicyCondition = toAciRestartPolicy(condition)
+			}
+			if restartPolicyCondition != toAciRestartPolicy(condition) {
+				return "", errors.New("ACI integration does not support specifying different restart policies on services in the same compose application")
 			}
 		}
 	}
 	return restartPolicyCondition, nil
 }
 
+// getRestartCondition returns the restart condition of a service, set by deploy.restart_policy or by the restart field
+func getRestartCondition(service types.ServiceConfig) (string, bool) {
+	if service.Deploy != nil && service.Deploy.RestartPolicy != nil {
+		return service.Deploy.RestartPolicy.Condition, true
+	}
+	switch {
+	case service.Restart == "":
+		return "", false
+	case service.Restart == "no":
+		return containers.RestartPolicyNone, true
+	case strings.HasPrefix(service.Restart, "on-failure"):
+		return containers.RestartPolicyOnFailure, true
+	default:
+		// always and unless-stopped
+		return containers.RestartPolicyAny, true
+	}
+}
+
 func toAciRestartPolicy(restartPolicy string) containerinstance.ContainerGroupRestartPolicy {
 	switch restartPolicy {
 	case containers.RestartPolicyNone:
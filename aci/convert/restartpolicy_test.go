@@ -41,7 +41,7 @@ func TestComposeSingleContainerRestartPolicy(t *testing.T) {
 		},
 	}
 
-	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper)
+	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper, mockKeyVaultHelper)
 	assert.NilError(t, err)
 
 	assert.Assert(t, is.Len(*group.Containers, 1))
@@ -73,7 +73,7 @@ func TestComposeMultiContainerRestartPolicy(t *testing.T) {
 		},
 	}
 
-	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper)
+	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper, mockKeyVaultHelper)
 	assert.NilError(t, err)
 
 	assert.Assert(t, is.Len(*group.Containers, 3))
@@ -107,7 +107,7 @@ func TestComposeInconsistentMultiContainerRestartPolicy(t *testing.T) {
 		},
 	}
 
-	_, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper)
+	_, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper, mockKeyVaultHelper)
 	assert.Error(t, err, "ACI integration does not support specifying different restart policies on services in the same compose application")
 }
 
@@ -121,7 +121,7 @@ func TestComposeSingleContainerGroupToContainerDefaultRestartPolicy(t *testing.T
 		},
 	}
 
-	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper)
+	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper, mockKeyVaultHelper)
 	assert.NilError(t, err)
 
 	assert.Assert(t, is.Len(*group.Containers, 1))
@@ -129,6 +129,60 @@ func TestComposeSingleContainerGroupToContainerDefaultRestartPolicy(t *testing.T
 	assert.Equal(t, group.RestartPolicy, containerinstance.Always)
 }
 
+func TestComposeRestartField(t *testing.T) {
+	tests := []struct {
+		restart  string
+		expected containerinstance.ContainerGroupRestartPolicy
+	}{
+		{restart: "no", expected: containerinstance.Never},
+		{restart: "on-failure", expected: containerinstance.OnFailure},
+		{restart: "on-failure:3", expected: containerinstance.OnFailure},
+		{restart: "always", expected: containerinstance.Always},
+		{restart: "unless-stopped", expected: containerinstance.Always},
+	}
+	for _, tt := range tests {
+		t.Run(tt.restart, func(t *testing.T) {
+			project := types.Project{
+				Services: []types.ServiceConfig{
+					{
+						Name:    "job",
+						Image:   "image1",
+						Restart: tt.restart,
+					},
+				},
+			}
+			group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper, mockKeyVaultHelper)
+			assert.NilError(t, err)
+			assert.Equal(t, group.RestartPolicy, tt.expected)
+		})
+	}
+}
+
+func TestComposeRestartPolicyOverridesRestartField(t *testing.T) {
+	project := types.Project{
+		Services: []types.ServiceConfig{
+			{
+				Name:    "job",
+				Image:   "image1",
+				Restart: "always",
+				Deploy: &types.DeployConfig{
+					RestartPolicy: &types.RestartPolicy{
+						Condition: "none",
+					},
+				},
+			},
+			{
+				Name:    "worker",
+				Image:   "image2",
+				Restart: "no",
+			},
+		},
+	}
+	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper, mockKeyVaultHelper)
+	assert.NilError(t, err)
+	assert.Equal(t, group.RestartPolicy, containerinstance.Never)
+}
+
 func TestConvertToAciRestartPolicyCondition(t *testing.T) {
 	assert.Equal(t, toAciRestartPolicy("none"), containerinstance.Never)
 	assert.Equal(t, toAciRestartPolicy("always"), containerinstance.Always)
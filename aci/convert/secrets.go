@@ -17,6 +17,7 @@
 package convert
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
 	"io/ioutil"
@@ -25,7 +26,11 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
 	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/compose-spec/compose-go/types"
 	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/aci/login"
+	"github.com/docker/compose-cli/errdefs"
 )
 
 const (
@@ -38,15 +43,81 @@ func getServiceSecretKey(serviceName, targetDir string) string {
 		serviceSecretAbsPathPrefix, serviceName, strings.ReplaceAll(targetDir, "/", "-"))
 }
 
-func (p projectAciHelper) getAciSecretVolumes() ([]containerinstance.Volume, error) {
+// getSecretContents reads the secrets used by services from their file or, for external secrets, from the project Key
+// Vault
+func (p projectAciHelper) getSecretContents(ctx context.Context, keyVaultHelper login.KeyVaultLogin) (map[string][]byte, error) {
+	vault := GetKeyVault(types.Project(p))
+	contents := make(map[string][]byte)
+	for _, svc := range p.Services {
+		for _, scr := range svc.Secrets {
+			if _, ok := contents[scr.Source]; ok {
+				continue
+			}
+			config := p.Secrets[scr.Source]
+			if !config.External.External {
+				data, err := ioutil.ReadFile(config.File)
+				if err != nil {
+					return nil, err
+				}
+				contents[scr.Source] = data
+				continue
+			}
+			if vault == "" {
+				return nil, errors.Wrapf(errdefs.ErrParsingFailed, "external secret %s requires a Key Vault, set with %s", scr.Source, extensionKeyVault)
+			}
+			name, err := KeyVaultSecretName(scr.Source, config)
+			if err != nil {
+				return nil, err
+			}
+			value, err := keyVaultHelper.GetKeyVaultSecret(ctx, vault, name)
+			if err != nil {
+				return nil, err
+			}
+			contents[scr.Source] = []byte(value)
+		}
+	}
+	return contents, nil
+}
+
+// secretEnvironmentVariable returns the environment variable a secret is exposed as, set by x-aci-environment
+func (p projectAciHelper) secretEnvironmentVariable(source string) (string, bool) {
+	x, ok := p.Secrets[source].Extensions[extensionSecretEnvironment]
+	if !ok {
+		return "", false
+	}
+	return fmt.Sprint(x), true
+}
+
+// getAciSecretEnvironment returns the secure environment variables of service secrets exposed with x-aci-environment,
+// and the service secrets mounted as files
+func (p projectAciHelper) getAciSecretEnvironment(svc types.ServiceConfig, contents map[string][]byte) ([]containerinstance.EnvironmentVariable, []types.ServiceSecretConfig) {
+	var (
+		env   []containerinstance.EnvironmentVariable
+		files []types.ServiceSecretConfig
+	)
+	for _, scr := range svc.Secrets {
+		name, ok := p.secretEnvironmentVariable(scr.Source)
+		if !ok {
+			files = append(files, scr)
+			continue
+		}
+		env = append(env, containerinstance.EnvironmentVariable{
+			Name:        to.StringPtr(name),
+			SecureValue: to.StringPtr(string(contents[scr.Source])),
+		})
+	}
+	return env, files
+}
+
+func (p projectAciHelper) getAciSecretVolumes(contents map[string][]byte) ([]containerinstance.Volume, error) {
 	var secretVolumes []containerinstance.Volume
 	for _, svc := range p.Services {
 		squashedTargetVolumes := make(map[string]containerinstance.Volume)
 		for _, scr := range svc.Secrets {
-			data, err := ioutil.ReadFile(p.Secrets[scr.Source].File)
-			if err != nil {
-				return secretVolumes, err
+			if _, ok := p.secretEnvironmentVariable(scr.Source); ok {
+				continue
 			}
+			data := contents[scr.Source]
 			if len(data) == 0 {
 				continue
 			}
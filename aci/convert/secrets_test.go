@@ -17,13 +17,18 @@
 package convert
 
 import (
+	"context"
+	"encoding/base64"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
+	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/compose-spec/compose-go/types"
+	"github.com/stretchr/testify/mock"
 	"gotest.tools/v3/assert"
 )
 
@@ -73,7 +78,7 @@ func TestConvertSecrets(t *testing.T) {
 				},
 			},
 		}
-		volumes, err := pSquashedDefaultAndAbs.getAciSecretVolumes()
+		volumes, err := pSquashedDefaultAndAbs.getAciSecretVolumes(secretContents(t, pSquashedDefaultAndAbs))
 		assert.NilError(t, err)
 		assert.Equal(t, len(volumes), 2)
 
@@ -123,7 +128,7 @@ func TestConvertSecrets(t *testing.T) {
 				},
 			},
 		}
-		_, err := pInvalidRelativePathTarget.getAciSecretVolumes()
+		_, err := pInvalidRelativePathTarget.getAciSecretVolumes(secretContents(t, pInvalidRelativePathTarget))
 		assert.Equal(t, err.Error(),
 			fmt.Sprintf(`in service %q, secret with source %q cannot have a relative path as target. Only absolute paths are allowed. Found %q`,
 				serviceName, secretName, targetName))
@@ -177,3 +182,100 @@ func TestConvertSecrets(t *testing.T) {
 				path.Dir(targetName1), path.Dir(targetName2)))
 	})
 }
+
+func TestConvertKeyVaultSecrets(t *testing.T) {
+	tmpFile, err := ioutil.TempFile(os.TempDir(), "TestConvertKeyVaultSecrets-")
+	assert.NilError(t, err)
+	_, err = tmpFile.Write([]byte("file content"))
+	assert.NilError(t, err)
+	t.Cleanup(func() {
+		_ = os.Remove(tmpFile.Name())
+	})
+
+	project := types.Project{
+		Services: []types.ServiceConfig{
+			{
+				Name:  "web",
+				Image: "nginx",
+				Secrets: []types.ServiceSecretConfig{
+					{Source: "dbpassword"},
+					{Source: "apikey"},
+				},
+			},
+		},
+		Secrets: map[string]types.SecretConfig{
+			"dbpassword": {
+				File: tmpFile.Name(),
+			},
+			"apikey": {
+				Name:       "prod-api-key",
+				External:   types.External{External: true},
+				Extensions: map[string]interface{}{extensionSecretEnvironment: "API_KEY"},
+			},
+		},
+		Extensions: map[string]interface{}{extensionKeyVault: "myvault"},
+	}
+	keyVaultHelper := &mockKeyVaultLogin{}
+	keyVaultHelper.On("GetKeyVaultSecret", mock.Anything, "myvault", "prod-api-key").Return("vault content", nil)
+
+	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper, keyVaultHelper)
+	assert.NilError(t, err)
+	keyVaultHelper.AssertExpectations(t)
+
+	assert.Equal(t, len(*group.Volumes), 1)
+	assert.DeepEqual(t, (*group.Volumes)[0].Secret, map[string]*string{
+		"dbpassword": to.StringPtr(base64.StdEncoding.EncodeToString([]byte("file content"))),
+	})
+	container := (*group.Containers)[0]
+	assert.Equal(t, len(*container.VolumeMounts), 1)
+	assert.Equal(t, *(*container.VolumeMounts)[0].MountPath, defaultSecretsPath)
+	assert.DeepEqual(t, *container.EnvironmentVariables, []containerinstance.EnvironmentVariable{
+		{Name: to.StringPtr("API_KEY"), SecureValue: to.StringPtr("vault content")},
+	})
+	assert.Assert(t, group.Identity == nil)
+}
+
+func TestExternalSecretRequiresKeyVault(t *testing.T) {
+	project := types.Project{
+		Services: []types.ServiceConfig{
+			{
+				Name:    "web",
+				Image:   "nginx",
+				Secrets: []types.ServiceSecretConfig{{Source: "apikey"}},
+			},
+		},
+		Secrets: map[string]types.SecretConfig{
+			"apikey": {External: types.External{External: true}},
+		},
+	}
+	_, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper, mockKeyVaultHelper)
+	assert.ErrorContains(t, err, "external secret apikey requires a Key Vault, set with x-aci-keyvault")
+}
+
+func TestKeyVaultSecretName(t *testing.T) {
+	name, err := KeyVaultSecretName("db-password", types.SecretConfig{Name: "myproject_db-password", File: "./password"})
+	assert.NilError(t, err)
+	assert.Equal(t, name, "db-password")
+
+	name, err = KeyVaultSecretName("apikey", types.SecretConfig{Name: "prod-api-key", External: types.External{External: true}})
+	assert.NilError(t, err)
+	assert.Equal(t, name, "prod-api-key")
+
+	_, err = KeyVaultSecretName("db_password", types.SecretConfig{})
+	assert.ErrorContains(t, err, `invalid Key Vault secret name "db_password"`)
+}
+
+func secretContents(t *testing.T, p projectAciHelper) map[string][]byte {
+	contents, err := p.getSecretContents(context.TODO(), mockKeyVaultHelper)
+	assert.NilError(t, err)
+	return contents
+}
+
+type mockKeyVaultLogin struct {
+	mock.Mock
+}
+
+func (s *mockKeyVaultLogin) GetKeyVaultSecret(ctx context.Context, vault string, name string) (string, error) {
+	args := s.Called(ctx, vault, name)
+	return args.String(0), args.Error(1)
+}
@@ -19,10 +19,12 @@ package convert
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/compose-spec/compose-go/types"
 	"github.com/docker/compose-cli/aci/login"
@@ -36,20 +38,88 @@ const (
 	volumeDriveroptsShareNameKey   = "share_name"
 	volumeDriveroptsAccountNameKey = "storage_account_name"
 	volumeReadOnly                 = "read_only"
+	volumeDriveroptsAccountSKUKey  = "storage_account_sku"
+	volumeDriveroptsShareQuotaKey  = "share_quota"
+	// maxShareQuota is the maximum size of a file share in GiB, reached by premium file shares
+	maxShareQuota = 102400
 )
 
+// FileShareOptions describe the file share of an azure_file volume, and how to create it if it doesn't exist
+type FileShareOptions struct {
+	Account string
+	Share   string
+	// SKU of the storage account, if it is created
+	SKU string
+	// Quota of the file share in GiB, if it is created
+	Quota int32
+}
+
+// GetFileShareOptions returns the file shares of project azure_file volumes, sorted by volume name
+func GetFileShareOptions(p types.Project) ([]FileShareOptions, error) {
+	var names []string
+	for name, v := range p.Volumes {
+		if v.Driver == azureFileDriverName {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	var shares []FileShareOptions
+	for _, name := range names {
+		share, err := fileShareOptions(name, p.Volumes[name])
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, share)
+	}
+	return shares, nil
+}
+
+func fileShareOptions(name string, v types.VolumeConfig) (FileShareOptions, error) {
+	shareName, ok := v.DriverOpts[volumeDriveroptsShareNameKey]
+	if !ok {
+		return FileShareOptions{}, fmt.Errorf("cannot retrieve fileshare name for Azurefile")
+	}
+	accountName, ok := v.DriverOpts[volumeDriveroptsAccountNameKey]
+	if !ok {
+		return FileShareOptions{}, fmt.Errorf("cannot retrieve account name for Azurefile")
+	}
+	share := FileShareOptions{
+		Account: accountName,
+		Share:   shareName,
+	}
+	if sku, ok := v.DriverOpts[volumeDriveroptsAccountSKUKey]; ok {
+		if !isStorageSKU(sku) {
+			return FileShareOptions{}, errors.Wrapf(errdefs.ErrParsingFailed, "invalid storage account SKU %q for volume %s", sku, name)
+		}
+		share.SKU = sku
+	}
+	if quota, ok := v.DriverOpts[volumeDriveroptsShareQuotaKey]; ok {
+		q, err := strconv.ParseInt(quota, 10, 32)
+		if err != nil || q < 1 || q > maxShareQuota {
+			return FileShareOptions{}, errors.Wrapf(errdefs.ErrParsingFailed, "invalid share quota %q for volume %s, expected a size in GiB between 1 and %d", quota, name, maxShareQuota)
+		}
+		share.Quota = int32(q)
+	}
+	return share, nil
+}
+
+func isStorageSKU(sku string) bool {
+	for _, s := range storage.PossibleSkuNameValues() {
+		if string(s) == sku {
+			return true
+		}
+	}
+	return false
+}
+
 func (p projectAciHelper) getAciFileVolumes(ctx context.Context, helper login.StorageLogin) (map[string]bool, []containerinstance.Volume, error) {
 	azureFileVolumesMap := make(map[string]bool, len(p.Volumes))
 	var azureFileVolumesSlice []containerinstance.Volume
 	for name, v := range p.Volumes {
 		if v.Driver == azureFileDriverName {
-			shareName, ok := v.DriverOpts[volumeDriveroptsShareNameKey]
-			if !ok {
-				return nil, nil, fmt.Errorf("cannot retrieve fileshare name for Azurefile")
-			}
-			accountName, ok := v.DriverOpts[volumeDriveroptsAccountNameKey]
-			if !ok {
-				return nil, nil, fmt.Errorf("cannot retrieve account name for Azurefile")
+			share, err := fileShareOptions(name, v)
+			if err != nil {
+				return nil, nil, err
 			}
 			readOnly, ok := v.DriverOpts[volumeReadOnly]
 			if !ok {
@@ -59,15 +129,15 @@ func (p projectAciHelper) getAciFileVolumes(ctx context.Context, helper login.St
 			if err != nil {
 				return nil, nil, fmt.Errorf("invalid mode %q for volume", readOnly)
 			}
-			accountKey, err := helper.GetAzureStorageAccountKey(ctx, accountName)
+			accountKey, err := helper.GetAzureStorageAccountKey(ctx, share.Account)
 			if err != nil {
 				return nil, nil, err
 			}
 			aciVolume := containerinstance.Volume{
 				Name: to.StringPtr(name),
 				AzureFile: &containerinstance.AzureFileVolume{
-					ShareName:          to.StringPtr(shareName),
-					StorageAccountName: to.StringPtr(accountName),
+					ShareName:          to.StringPtr(share.Share),
+					StorageAccountName: to.StringPtr(share.Account),
 					StorageAccountKey:  to.StringPtr(accountKey),
 					ReadOnly:           &ro,
 				},
@@ -85,10 +155,14 @@ func (s serviceConfigAciHelper) getAciFileVolumeMounts(volumesCache map[string]b
 		if !volumesCache[sv.Source] {
 			return []containerinstance.VolumeMount{}, fmt.Errorf("could not find volume source %q", sv.Source)
 		}
-		aciServiceVolumes = append(aciServiceVolumes, containerinstance.VolumeMount{
+		mount := containerinstance.VolumeMount{
 			Name:      to.StringPtr(sv.Source),
 			MountPath: to.StringPtr(sv.Target),
-		})
+		}
+		if sv.ReadOnly {
+			mount.ReadOnly = to.BoolPtr(true)
+		}
+		aciServiceVolumes = append(aciServiceVolumes, mount)
 	}
 	return aciServiceVolumes, nil
 }
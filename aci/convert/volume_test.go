@@ -101,7 +101,7 @@ func TestComposeVolumes(t *testing.T) {
 		},
 	}
 
-	group, err := ToContainerGroup(ctx, convertCtx, project, mockStorageHelper)
+	group, err := ToContainerGroup(ctx, convertCtx, project, mockStorageHelper, mockKeyVaultHelper)
 	assert.NilError(t, err)
 
 	assert.Assert(t, is.Len(*group.Containers, 1))
@@ -142,7 +142,7 @@ func TestComposeVolumesRO(t *testing.T) {
 		},
 	}
 
-	group, err := ToContainerGroup(ctx, convertCtx, project, mockStorageHelper)
+	group, err := ToContainerGroup(ctx, convertCtx, project, mockStorageHelper, mockKeyVaultHelper)
 	assert.NilError(t, err)
 
 	assert.Assert(t, is.Len(*group.Containers, 1))
@@ -189,3 +189,71 @@ func getAzurefileVolumeConfig(name string, accountNameKey string, shareNameKey s
 		},
 	}
 }
+
+func TestGetFileShareOptions(t *testing.T) {
+	project := types.Project{
+		Volumes: types.Volumes{
+			"vol2": types.VolumeConfig{
+				Driver: "azure_file",
+				DriverOpts: map[string]string{
+					"share_name":           "share2",
+					"storage_account_name": "account",
+					"storage_account_sku":  "Premium_LRS",
+					"share_quota":          "100",
+				},
+			},
+			"vol1": types.VolumeConfig{
+				Driver: "azure_file",
+				DriverOpts: map[string]string{
+					"share_name":           "share1",
+					"storage_account_name": "account",
+				},
+			},
+			"local": types.VolumeConfig{},
+		},
+	}
+	shares, err := GetFileShareOptions(project)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, shares, []FileShareOptions{
+		{Account: "account", Share: "share1"},
+		{Account: "account", Share: "share2", SKU: "Premium_LRS", Quota: 100},
+	})
+}
+
+func TestGetFileShareOptionsInvalid(t *testing.T) {
+	for opts, expected := range map[[2]string]string{
+		{"storage_account_sku", "Gold_LRS"}: `invalid storage account SKU "Gold_LRS" for volume vol1`,
+		{"share_quota", "0"}:                `invalid share quota "0" for volume vol1, expected a size in GiB between 1 and 102400`,
+		{"share_quota", "1T"}:               `invalid share quota "1T" for volume vol1`,
+	} {
+		project := types.Project{
+			Volumes: types.Volumes{
+				"vol1": types.VolumeConfig{
+					Driver: "azure_file",
+					DriverOpts: map[string]string{
+						"share_name":           "share1",
+						"storage_account_name": "account",
+						opts[0]:                opts[1],
+					},
+				},
+			},
+		}
+		_, err := GetFileShareOptions(project)
+		assert.ErrorContains(t, err, expected)
+	}
+}
+
+func TestReadOnlyVolumeMounts(t *testing.T) {
+	service := serviceConfigAciHelper{
+		Volumes: []types.ServiceVolumeConfig{
+			{Source: "vol1", Target: "/data"},
+			{Source: "vol2", Target: "/config", ReadOnly: true},
+		},
+	}
+	mounts, err := service.getAciFileVolumeMounts(map[string]bool{"vol1": true, "vol2": true})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, mounts, []containerinstance.VolumeMount{
+		{Name: to.StringPtr("vol1"), MountPath: to.StringPtr("/data")},
+		{Name: to.StringPtr("vol2"), MountPath: to.StringPtr("/config"), ReadOnly: to.BoolPtr(true)},
+	})
+}
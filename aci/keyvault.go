@@ -0,0 +1,81 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"sort"
+
+	"github.com/Azure/azure-sdk-for-go/services/keyvault/v7.0/keyvault"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/aci/convert"
+	"github.com/docker/compose-cli/aci/login"
+	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/progress"
+)
+
+// storeKeyVaultSecrets stores the content of project file secrets in the project Key Vault
+func storeKeyVaultSecrets(ctx context.Context, aciContext store.AciContext, project types.Project) error {
+	vault := convert.GetKeyVault(project)
+	if vault == "" {
+		return nil
+	}
+	var keys []string
+	for key, config := range project.Secrets {
+		if !config.External.External {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	sort.Strings(keys)
+
+	w := progress.ContextWriter(ctx)
+	keyVaultClient, err := login.NewKeyVaultClient()
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		config := project.Secrets[key]
+		name, err := convert.KeyVaultSecretName(key, config)
+		if err != nil {
+			return err
+		}
+		data, err := ioutil.ReadFile(config.File)
+		if err != nil {
+			return err
+		}
+		id := fmt.Sprintf("%s/%s", vault, name)
+		w.Event(event(id, progress.Working, "Storing"))
+		_, err = keyVaultClient.SetSecret(ctx, login.KeyVaultURL(vault), name, keyvault.SecretSetParameters{
+			Value: to.StringPtr(string(data)),
+			Tags:  convert.ToAzureTags(aciContext.Tags),
+		})
+		if err != nil {
+			w.Event(errorEvent(id))
+			return errors.Wrapf(err, "could not store secret %s in Key Vault %s", name, vault)
+		}
+		w.Event(event(id, progress.Done, "Stored"))
+	}
+	return nil
+}
@@ -17,11 +17,14 @@
 package login
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/profiles/2019-03-01/resources/mgmt/resources"
 	"github.com/Azure/azure-sdk-for-go/profiles/preview/preview/subscription/mgmt/subscription"
 	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
+	"github.com/Azure/azure-sdk-for-go/services/keyvault/v7.0/keyvault"
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-11-01/network"
 	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/pkg/errors"
@@ -79,6 +82,59 @@ func NewFileShareClient(subscriptionID string) (storage.FileSharesClient, error)
 	return containerGroupsClient, nil
 }
 
+// NewVirtualNetworksClient get client to manipulate virtual networks
+func NewVirtualNetworksClient(subscriptionID string) (network.VirtualNetworksClient, error) {
+	vnetClient := network.NewVirtualNetworksClient(subscriptionID)
+	err := setupClient(&vnetClient.Client)
+	if err != nil {
+		return network.VirtualNetworksClient{}, err
+	}
+	vnetClient.PollingDelay = 5 * time.Second
+	vnetClient.RetryAttempts = 30
+	vnetClient.RetryDuration = 1 * time.Second
+	return vnetClient, nil
+}
+
+// NewSubnetsClient get client to manipulate virtual network subnets
+func NewSubnetsClient(subscriptionID string) (network.SubnetsClient, error) {
+	subnetsClient := network.NewSubnetsClient(subscriptionID)
+	err := setupClient(&subnetsClient.Client)
+	if err != nil {
+		return network.SubnetsClient{}, err
+	}
+	subnetsClient.PollingDelay = 5 * time.Second
+	subnetsClient.RetryAttempts = 30
+	subnetsClient.RetryDuration = 1 * time.Second
+	return subnetsClient, nil
+}
+
+// NewNetworkProfilesClient get client to manipulate network profiles
+func NewNetworkProfilesClient(subscriptionID string) (network.ProfilesClient, error) {
+	profilesClient := network.NewProfilesClient(subscriptionID)
+	err := setupClient(&profilesClient.Client)
+	if err != nil {
+		return network.ProfilesClient{}, err
+	}
+	return profilesClient, nil
+}
+
+// NewKeyVaultClient get client to manipulate Key Vault secrets
+func NewKeyVaultClient() (keyvault.BaseClient, error) {
+	keyVaultClient := keyvault.New()
+	keyVaultClient.UserAgent = internal.UserAgentName + "/" + internal.Version
+	auth, err := NewKeyVaultAuthorizerFromLogin()
+	if err != nil {
+		return keyvault.BaseClient{}, err
+	}
+	keyVaultClient.Authorizer = auth
+	return keyVaultClient, nil
+}
+
+// KeyVaultURL returns the base URL of a Key Vault
+func KeyVaultURL(vault string) string {
+	return fmt.Sprintf("https://%s.vault.azure.net", vault)
+}
+
 // NewSubscriptionsClient get subscription client
 func NewSubscriptionsClient() (subscription.SubscriptionsClient, error) {
 	subc := subscription.NewSubscriptionsClient()
@@ -51,6 +51,9 @@ const (
 	// v1 scope like "https://management.azure.com/.default" for ARM access
 	scopes   = "offline_access " + azureResouceManagementURL + ".default"
 	clientID = "04b07795-8ddb-461a-bbee-02f9e1bf7b46" // Azure CLI client id
+
+	// keyVaultScope is requested with the login refresh token to access Key Vault secrets
+	keyVaultScope = "https://vault.azure.net/.default"
 )
 
 type (
@@ -303,6 +306,39 @@ func newAuthorizerFromLoginStorePath(storeTokenPath string) (autorest.Authorizer
 	return autorest.NewBearerAuthorizer(&token), nil
 }
 
+// NewKeyVaultAuthorizerFromLogin creates an authorizer for Key Vault secrets, based on login refresh token
+func NewKeyVaultAuthorizerFromLogin() (autorest.Authorizer, error) {
+	login, err := NewAzureLoginService()
+	if err != nil {
+		return nil, err
+	}
+	return login.keyVaultAuthorizer()
+}
+
+func (login *AzureLoginService) keyVaultAuthorizer() (autorest.Authorizer, error) {
+	loginInfo, err := login.tokenStore.readToken()
+	if err != nil {
+		return nil, errors.Wrap(err, "not logged in to azure, you need to run \"docker login azure\" first")
+	}
+	if loginInfo.Token.RefreshToken == "" {
+		return nil, errors.Wrap(errdefs.ErrLoginRequired, "Key Vault access requires an interactive login with \"docker login azure\"")
+	}
+	data := url.Values{
+		"grant_type":    []string{"refresh_token"},
+		"client_id":     []string{clientID},
+		"scope":         []string{keyVaultScope},
+		"refresh_token": []string{loginInfo.Token.RefreshToken},
+	}
+	token, err := login.apiHelper.queryToken(data, loginInfo.TenantID)
+	if err != nil {
+		return nil, errors.Wrap(err, "Key Vault access token request failed")
+	}
+	return autorest.NewBearerAuthorizer(&adal.Token{
+		AccessToken: token.AccessToken,
+		Type:        token.Type,
+	}), nil
+}
+
 // GetTenantID returns tenantID for current login
 func (login AzureLoginService) GetTenantID() (string, error) {
 	loginInfo, err := login.tokenStore.readToken()
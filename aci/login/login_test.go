@@ -368,6 +368,52 @@ func TestValidThroughDeviceCodeFlow(t *testing.T) {
 	assert.Equal(t, loginToken.Token.Type(), "Bearer")
 }
 
+func TestKeyVaultAuthorizer(t *testing.T) {
+	data := refreshTokenData("refreshToken")
+	data.Set("scope", keyVaultScope)
+	m := &MockAzureHelper{}
+	m.On("queryToken", data, "123456").Return(azureToken{
+		AccessToken: "keyVaultAccessToken",
+		Type:        "Bearer",
+		ExpiresIn:   3600,
+	}, nil)
+
+	azureLogin, err := testLoginService(t, m)
+	assert.NilError(t, err)
+	err = azureLogin.tokenStore.writeLoginInfo(TokenInfo{
+		TenantID: "123456",
+		Token: oauth2.Token{
+			AccessToken:  "accessToken",
+			RefreshToken: "refreshToken",
+			Expiry:       time.Now().Add(1 * time.Hour),
+			TokenType:    "Bearer",
+		},
+	})
+	assert.NilError(t, err)
+
+	authorizer, err := azureLogin.keyVaultAuthorizer()
+	assert.NilError(t, err)
+	assert.Assert(t, authorizer != nil)
+	m.AssertExpectations(t)
+}
+
+func TestKeyVaultAuthorizerRequiresRefreshToken(t *testing.T) {
+	azureLogin, err := testLoginService(t, &MockAzureHelper{})
+	assert.NilError(t, err)
+	err = azureLogin.tokenStore.writeLoginInfo(TokenInfo{
+		TenantID: "123456",
+		Token: oauth2.Token{
+			AccessToken: "accessToken",
+			Expiry:      time.Now().Add(1 * time.Hour),
+			TokenType:   "Bearer",
+		},
+	})
+	assert.NilError(t, err)
+
+	_, err = azureLogin.keyVaultAuthorizer()
+	assert.ErrorContains(t, err, "Key Vault access requires an interactive login")
+}
+
 func refreshTokenData(refreshToken string) url.Values {
 	return url.Values{
 		"grant_type":    []string{"refresh_token"},
@@ -53,3 +53,28 @@ func (helper StorageLoginImpl) GetAzureStorageAccountKey(ctx context.Context, ac
 	key := (*result.Keys)[0]
 	return *key.Value, nil
 }
+
+// KeyVaultLogin helper for Azure Key Vault secrets
+type KeyVaultLogin interface {
+	// GetKeyVaultSecret retrieves the value of a Key Vault secret using the current azure login
+	GetKeyVaultSecret(ctx context.Context, vault string, name string) (string, error)
+}
+
+// KeyVaultLoginImpl implementation of KeyVaultLogin
+type KeyVaultLoginImpl struct{}
+
+// GetKeyVaultSecret retrieves the value of a Key Vault secret using the current azure login
+func (helper KeyVaultLoginImpl) GetKeyVaultSecret(ctx context.Context, vault string, name string) (string, error) {
+	client, err := NewKeyVaultClient()
+	if err != nil {
+		return "", err
+	}
+	secret, err := client.GetSecret(ctx, KeyVaultURL(vault), name, "")
+	if err != nil {
+		return "", errors.Wrapf(err, "could not read secret %s from Key Vault %s, using the azure login", name, vault)
+	}
+	if secret.Value == nil {
+		return "", fmt.Errorf("secret %s of Key Vault %s has no value", name, vault)
+	}
+	return *secret.Value, nil
+}
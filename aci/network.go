@@ -0,0 +1,306 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-11-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/aci/convert"
+	"github.com/docker/compose-cli/aci/login"
+	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/progress"
+)
+
+const (
+	aciDelegation      = "Microsoft.ContainerInstance/containerGroups"
+	defaultVNetPrefix  = "10.0.0.0/16"
+	subnetPrefixLength = 24
+)
+
+// createNetworkProfile creates the virtual network, the subnet delegated to ACI and the network profile a project is
+// privately deployed into, unless they already exist
+func createNetworkProfile(ctx context.Context, aciContext store.AciContext, project types.Project) error {
+	virtualNetwork, private, err := convert.GetVirtualNetwork(project, aciContext)
+	if err != nil || !private {
+		return err
+	}
+	vnet, err := getOrCreateVirtualNetwork(ctx, aciContext, virtualNetwork.VNet)
+	if err != nil {
+		return err
+	}
+	subnet, err := getOrCreateSubnet(ctx, aciContext, vnet, virtualNetwork.Subnet)
+	if err != nil {
+		return err
+	}
+
+	w := progress.ContextWriter(ctx)
+	name := virtualNetwork.ProfileName()
+	profilesClient, err := login.NewNetworkProfilesClient(aciContext.SubscriptionID)
+	if err != nil {
+		return err
+	}
+	profile, err := profilesClient.Get(ctx, aciContext.ResourceGroup, name, "")
+	if err == nil {
+		w.Event(event(name, progress.Done, "Use existing"))
+		return nil
+	}
+	if !profile.HasHTTPStatus(http.StatusNotFound) {
+		return err
+	}
+	w.Event(event(name, progress.Working, "Creating"))
+	_, err = profilesClient.CreateOrUpdate(ctx, aciContext.ResourceGroup, name, networkProfileParams(aciContext, subnet))
+	if err != nil {
+		w.Event(errorEvent(name))
+		return err
+	}
+	w.Event(event(name, progress.Done, "Created"))
+	return nil
+}
+
+// getOrCreateVirtualNetwork returns a virtual network, creating it in the context location if it doesn't exist
+func getOrCreateVirtualNetwork(ctx context.Context, aciContext store.AciContext, name string) (network.VirtualNetwork, error) {
+	w := progress.ContextWriter(ctx)
+	vnetClient, err := login.NewVirtualNetworksClient(aciContext.SubscriptionID)
+	if err != nil {
+		return network.VirtualNetwork{}, err
+	}
+	vnet, err := vnetClient.Get(ctx, aciContext.ResourceGroup, name, "")
+	if err == nil {
+		if vnet.Location != nil && *vnet.Location != aciContext.Location {
+			return network.VirtualNetwork{}, errors.Wrapf(errdefs.ErrParsingFailed, "virtual network %s is in %s, container groups of this context are deployed in %s", name, *vnet.Location, aciContext.Location)
+		}
+		w.Event(event(name, progress.Done, "Use existing"))
+		return vnet, nil
+	}
+	if !vnet.HasHTTPStatus(http.StatusNotFound) {
+		return network.VirtualNetwork{}, err
+	}
+	w.Event(event(name, progress.Working, "Creating"))
+	future, err := vnetClient.CreateOrUpdate(ctx, aciContext.ResourceGroup, name, network.VirtualNetwork{
+		Location: to.StringPtr(aciContext.Location),
+		Tags:     convert.ToAzureTags(aciContext.Tags),
+		VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
+			AddressSpace: &network.AddressSpace{
+				AddressPrefixes: &[]string{defaultVNetPrefix},
+			},
+		},
+	})
+	if err != nil {
+		w.Event(errorEvent(name))
+		return network.VirtualNetwork{}, err
+	}
+	if err := future.WaitForCompletionRef(ctx, vnetClient.Client); err != nil {
+		w.Event(errorEvent(name))
+		return network.VirtualNetwork{}, err
+	}
+	vnet, err = future.Result(vnetClient)
+	if err != nil {
+		w.Event(errorEvent(name))
+		return network.VirtualNetwork{}, err
+	}
+	w.Event(event(name, progress.Done, "Created"))
+	return vnet, nil
+}
+
+// getOrCreateSubnet returns a subnet of vnet delegated to ACI, adding the delegation to an existing subnet or creating
+// the subnet in a free address range of the virtual network
+func getOrCreateSubnet(ctx context.Context, aciContext store.AciContext, vnet network.VirtualNetwork, name string) (network.Subnet, error) {
+	w := progress.ContextWriter(ctx)
+	id := fmt.Sprintf("%s/%s", *vnet.Name, name)
+	subnetsClient, err := login.NewSubnetsClient(aciContext.SubscriptionID)
+	if err != nil {
+		return network.Subnet{}, err
+	}
+	subnet, err := subnetsClient.Get(ctx, aciContext.ResourceGroup, *vnet.Name, name, "")
+	done := "Created"
+	switch {
+	case err == nil && hasACIDelegation(subnet):
+		w.Event(event(id, progress.Done, "Use existing"))
+		return subnet, nil
+	case err == nil:
+		w.Event(event(id, progress.Working, "Delegating"))
+		done = "Delegated"
+		if subnet.SubnetPropertiesFormat == nil {
+			subnet.SubnetPropertiesFormat = &network.SubnetPropertiesFormat{}
+		}
+		var delegations []network.Delegation
+		if subnet.Delegations != nil {
+			delegations = *subnet.Delegations
+		}
+		delegations = append(delegations, aciSubnetDelegation())
+		subnet.Delegations = &delegations
+	case subnet.HasHTTPStatus(http.StatusNotFound):
+		w.Event(event(id, progress.Working, "Creating"))
+		spaces, used := vnetAddressPrefixes(vnet)
+		prefix, err := freeSubnetPrefix(spaces, used)
+		if err != nil {
+			w.Event(errorEvent(id))
+			return network.Subnet{}, errors.Wrapf(err, "cannot create subnet %s in virtual network %s", name, *vnet.Name)
+		}
+		subnet = network.Subnet{
+			SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+				AddressPrefix: to.StringPtr(prefix),
+				Delegations:   &[]network.Delegation{aciSubnetDelegation()},
+			},
+		}
+	default:
+		return network.Subnet{}, err
+	}
+
+	future, err := subnetsClient.CreateOrUpdate(ctx, aciContext.ResourceGroup, *vnet.Name, name, subnet)
+	if err != nil {
+		w.Event(errorEvent(id))
+		return network.Subnet{}, err
+	}
+	if err := future.WaitForCompletionRef(ctx, subnetsClient.Client); err != nil {
+		w.Event(errorEvent(id))
+		return network.Subnet{}, err
+	}
+	subnet, err = future.Result(subnetsClient)
+	if err != nil {
+		w.Event(errorEvent(id))
+		return network.Subnet{}, err
+	}
+	w.Event(event(id, progress.Done, done))
+	return subnet, nil
+}
+
+func aciSubnetDelegation() network.Delegation {
+	return network.Delegation{
+		Name: to.StringPtr("aci"),
+		ServiceDelegationPropertiesFormat: &network.ServiceDelegationPropertiesFormat{
+			ServiceName: to.StringPtr(aciDelegation),
+		},
+	}
+}
+
+func hasACIDelegation(subnet network.Subnet) bool {
+	if subnet.SubnetPropertiesFormat == nil || subnet.Delegations == nil {
+		return false
+	}
+	for _, d := range *subnet.Delegations {
+		if d.ServiceDelegationPropertiesFormat != nil && d.ServiceName != nil && *d.ServiceName == aciDelegation {
+			return true
+		}
+	}
+	return false
+}
+
+// vnetAddressPrefixes returns the address spaces of a virtual network and the address prefixes used by its subnets
+func vnetAddressPrefixes(vnet network.VirtualNetwork) ([]string, []string) {
+	var spaces, used []string
+	if vnet.VirtualNetworkPropertiesFormat == nil {
+		return spaces, used
+	}
+	if vnet.AddressSpace != nil && vnet.AddressSpace.AddressPrefixes != nil {
+		spaces = *vnet.AddressSpace.AddressPrefixes
+	}
+	if vnet.Subnets != nil {
+		for _, s := range *vnet.Subnets {
+			if s.SubnetPropertiesFormat == nil {
+				continue
+			}
+			if s.AddressPrefix != nil {
+				used = append(used, *s.AddressPrefix)
+			}
+			if s.AddressPrefixes != nil {
+				used = append(used, *s.AddressPrefixes...)
+			}
+		}
+	}
+	return spaces, used
+}
+
+// freeSubnetPrefix returns the first /24 IPv4 range of the address spaces which doesn't overlap used prefixes
+func freeSubnetPrefix(spaces []string, used []string) (string, error) {
+	var usedNets []*net.IPNet
+	for _, u := range used {
+		_, n, err := net.ParseCIDR(u)
+		if err != nil {
+			return "", err
+		}
+		usedNets = append(usedNets, n)
+	}
+	for _, space := range spaces {
+		_, spaceNet, err := net.ParseCIDR(space)
+		if err != nil {
+			return "", err
+		}
+		ip := spaceNet.IP.To4()
+		if ip == nil {
+			continue
+		}
+		ones, _ := spaceNet.Mask.Size()
+		length := subnetPrefixLength
+		if ones > length {
+			length = ones
+		}
+		base := binary.BigEndian.Uint32(ip)
+		for i := uint32(0); i < 1<<uint(length-ones); i++ {
+			candidateIP := make(net.IP, net.IPv4len)
+			binary.BigEndian.PutUint32(candidateIP, base+i<<uint(32-length))
+			candidate := &net.IPNet{IP: candidateIP, Mask: net.CIDRMask(length, 32)}
+			if !overlaps(candidate, usedNets) {
+				return candidate.String(), nil
+			}
+		}
+	}
+	return "", errors.Wrapf(errdefs.ErrNotFound, "no free address range in %v", spaces)
+}
+
+func overlaps(n *net.IPNet, nets []*net.IPNet) bool {
+	for _, other := range nets {
+		if n.Contains(other.IP) || other.Contains(n.IP) {
+			return true
+		}
+	}
+	return false
+}
+
+func networkProfileParams(aciContext store.AciContext, subnet network.Subnet) network.Profile {
+	return network.Profile{
+		Location: to.StringPtr(aciContext.Location),
+		Tags:     convert.ToAzureTags(aciContext.Tags),
+		ProfilePropertiesFormat: &network.ProfilePropertiesFormat{
+			ContainerNetworkInterfaceConfigurations: &[]network.ContainerNetworkInterfaceConfiguration{
+				{
+					Name: to.StringPtr("eth0"),
+					ContainerNetworkInterfaceConfigurationPropertiesFormat: &network.ContainerNetworkInterfaceConfigurationPropertiesFormat{
+						IPConfigurations: &[]network.IPConfigurationProfile{
+							{
+								Name: to.StringPtr("ipconfig"),
+								IPConfigurationProfilePropertiesFormat: &network.IPConfigurationProfilePropertiesFormat{
+									Subnet: &network.Subnet{ID: subnet.ID},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,78 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-11-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/context/store"
+)
+
+func TestFreeSubnetPrefix(t *testing.T) {
+	prefix, err := freeSubnetPrefix([]string{"10.0.0.0/16"}, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, prefix, "10.0.0.0/24")
+
+	prefix, err = freeSubnetPrefix([]string{"10.0.0.0/16"}, []string{"10.0.0.0/24", "10.0.1.128/25"})
+	assert.NilError(t, err)
+	assert.Equal(t, prefix, "10.0.2.0/24")
+
+	prefix, err = freeSubnetPrefix([]string{"10.1.0.0/24", "10.2.0.0/16"}, []string{"10.1.0.0/26"})
+	assert.NilError(t, err)
+	assert.Equal(t, prefix, "10.2.0.0/24")
+
+	prefix, err = freeSubnetPrefix([]string{"192.168.1.0/27"}, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, prefix, "192.168.1.0/27")
+
+	_, err = freeSubnetPrefix([]string{"10.0.0.0/24"}, []string{"10.0.0.0/16"})
+	assert.ErrorContains(t, err, "no free address range")
+}
+
+func TestHasACIDelegation(t *testing.T) {
+	assert.Assert(t, !hasACIDelegation(network.Subnet{}))
+	assert.Assert(t, !hasACIDelegation(network.Subnet{
+		SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+			Delegations: &[]network.Delegation{{
+				ServiceDelegationPropertiesFormat: &network.ServiceDelegationPropertiesFormat{
+					ServiceName: to.StringPtr("Microsoft.Web/serverFarms"),
+				},
+			}},
+		},
+	}))
+	assert.Assert(t, hasACIDelegation(network.Subnet{
+		SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+			Delegations: &[]network.Delegation{aciSubnetDelegation()},
+		},
+	}))
+}
+
+func TestNetworkProfileParams(t *testing.T) {
+	aciContext := store.AciContext{Location: "westeurope"}
+	subnet := network.Subnet{ID: to.StringPtr("subnetID")}
+	params := networkProfileParams(aciContext, subnet)
+	assert.Equal(t, *params.Location, "westeurope")
+	configs := *params.ContainerNetworkInterfaceConfigurations
+	assert.Equal(t, len(configs), 1)
+	ipConfigs := *configs[0].IPConfigurations
+	assert.Equal(t, len(ipConfigs), 1)
+	assert.Equal(t, *ipConfigs[0].Subnet.ID, "subnetID")
+}
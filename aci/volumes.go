@@ -27,7 +27,9 @@ import (
 	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
 	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
 	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/compose-spec/compose-go/types"
 
+	"github.com/docker/compose-cli/aci/convert"
 	"github.com/docker/compose-cli/aci/login"
 	"github.com/docker/compose-cli/api/volumes"
 	"github.com/docker/compose-cli/context/store"
@@ -76,6 +78,10 @@ func (cs *aciVolumeService) List(ctx context.Context) ([]volumes.Volume, error)
 // VolumeCreateOptions options to create a new ACI volume
 type VolumeCreateOptions struct {
 	Account string
+	// SKU of the storage account, if it is created. Defaults to Standard_LRS
+	SKU string
+	// Quota of the file share in GiB. Defaults to the maximum size allowed by the storage account
+	Quota int32
 }
 
 func (cs *aciVolumeService) Create(ctx context.Context, name string, options interface{}) (volumes.Volume, error) {
@@ -84,47 +90,10 @@ func (cs *aciVolumeService) Create(ctx context.Context, name string, options int
 		return volumes.Volume{}, errors.New("could not read Azure VolumeCreateOptions struct from generic parameter")
 	}
 	w := progress.ContextWriter(ctx)
-	w.Event(event(opts.Account, progress.Working, "Validating"))
-	accountClient, err := login.NewStorageAccountsClient(cs.aciContext.SubscriptionID)
+	account, err := getOrCreateStorageAccount(ctx, cs.aciContext, opts.Account, opts.SKU)
 	if err != nil {
 		return volumes.Volume{}, err
 	}
-	account, err := accountClient.GetProperties(ctx, cs.aciContext.ResourceGroup, opts.Account, "")
-	if err == nil {
-		w.Event(event(opts.Account, progress.Done, "Use existing"))
-	} else if !account.HasHTTPStatus(http.StatusNotFound) {
-		return volumes.Volume{}, err
-	} else {
-		result, err := accountClient.CheckNameAvailability(ctx, storage.AccountCheckNameAvailabilityParameters{
-			Name: to.StringPtr(opts.Account),
-			Type: to.StringPtr("Microsoft.Storage/storageAccounts"),
-		})
-		if err != nil {
-			return volumes.Volume{}, err
-		}
-		if !*result.NameAvailable {
-			return volumes.Volume{}, errors.New("error: " + *result.Message)
-		}
-		parameters := defaultStorageAccountParams(cs.aciContext)
-
-		w.Event(event(opts.Account, progress.Working, "Creating"))
-
-		future, err := accountClient.Create(ctx, cs.aciContext.ResourceGroup, opts.Account, parameters)
-		if err != nil {
-			w.Event(errorEvent(opts.Account))
-			return volumes.Volume{}, err
-		}
-		if err := future.WaitForCompletionRef(ctx, accountClient.Client); err != nil {
-			w.Event(errorEvent(opts.Account))
-			return volumes.Volume{}, err
-		}
-		account, err = future.Result(accountClient)
-		if err != nil {
-			w.Event(errorEvent(opts.Account))
-			return volumes.Volume{}, err
-		}
-		w.Event(event(opts.Account, progress.Done, "Created"))
-	}
 	w.Event(event(name, progress.Working, "Creating"))
 	fileShareClient, err := login.NewFileShareClient(cs.aciContext.SubscriptionID)
 	if err != nil {
@@ -140,7 +109,7 @@ func (cs *aciVolumeService) Create(ctx context.Context, name string, options int
 		w.Event(errorEvent(name))
 		return volumes.Volume{}, err
 	}
-	fileShare, err = fileShareClient.Create(ctx, cs.aciContext.ResourceGroup, *account.Name, name, storage.FileShare{})
+	fileShare, err = fileShareClient.Create(ctx, cs.aciContext.ResourceGroup, *account.Name, name, fileShareParams(opts.Quota))
 	if err != nil {
 		w.Event(errorEvent(name))
 		return volumes.Volume{}, err
@@ -149,6 +118,92 @@ func (cs *aciVolumeService) Create(ctx context.Context, name string, options int
 	return toVolume(*account.Name, *fileShare.Name), nil
 }
 
+// createFileShares creates the file shares of project azure_file volumes which don't exist yet, along with their
+// storage account
+func createFileShares(ctx context.Context, aciContext store.AciContext, project types.Project) error {
+	shares, err := convert.GetFileShareOptions(project)
+	if err != nil || len(shares) == 0 {
+		return err
+	}
+	w := progress.ContextWriter(ctx)
+	fileShareClient, err := login.NewFileShareClient(aciContext.SubscriptionID)
+	if err != nil {
+		return err
+	}
+	for _, share := range shares {
+		account, err := getOrCreateStorageAccount(ctx, aciContext, share.Account, share.SKU)
+		if err != nil {
+			return err
+		}
+		id := volumeID(share.Account, share.Share)
+		fileShare, err := fileShareClient.Get(ctx, aciContext.ResourceGroup, *account.Name, share.Share, "")
+		if err == nil {
+			w.Event(event(id, progress.Done, "Use existing"))
+			continue
+		}
+		if !fileShare.HasHTTPStatus(http.StatusNotFound) {
+			w.Event(errorEvent(id))
+			return err
+		}
+		w.Event(event(id, progress.Working, "Creating"))
+		_, err = fileShareClient.Create(ctx, aciContext.ResourceGroup, *account.Name, share.Share, fileShareParams(share.Quota))
+		if err != nil {
+			w.Event(errorEvent(id))
+			return err
+		}
+		w.Event(event(id, progress.Done, "Created"))
+	}
+	return nil
+}
+
+// getOrCreateStorageAccount returns a storage account, creating it with sku if it doesn't exist
+func getOrCreateStorageAccount(ctx context.Context, aciContext store.AciContext, name string, sku string) (storage.Account, error) {
+	w := progress.ContextWriter(ctx)
+	w.Event(event(name, progress.Working, "Validating"))
+	accountClient, err := login.NewStorageAccountsClient(aciContext.SubscriptionID)
+	if err != nil {
+		return storage.Account{}, err
+	}
+	account, err := accountClient.GetProperties(ctx, aciContext.ResourceGroup, name, "")
+	if err == nil {
+		w.Event(event(name, progress.Done, "Use existing"))
+		return account, nil
+	}
+	if !account.HasHTTPStatus(http.StatusNotFound) {
+		return storage.Account{}, err
+	}
+	result, err := accountClient.CheckNameAvailability(ctx, storage.AccountCheckNameAvailabilityParameters{
+		Name: to.StringPtr(name),
+		Type: to.StringPtr("Microsoft.Storage/storageAccounts"),
+	})
+	if err != nil {
+		return storage.Account{}, err
+	}
+	if !*result.NameAvailable {
+		return storage.Account{}, errors.New("error: " + *result.Message)
+	}
+	parameters := storageAccountParams(aciContext, sku)
+
+	w.Event(event(name, progress.Working, "Creating"))
+
+	future, err := accountClient.Create(ctx, aciContext.ResourceGroup, name, parameters)
+	if err != nil {
+		w.Event(errorEvent(name))
+		return storage.Account{}, err
+	}
+	if err := future.WaitForCompletionRef(ctx, accountClient.Client); err != nil {
+		w.Event(errorEvent(name))
+		return storage.Account{}, err
+	}
+	account, err = future.Result(accountClient)
+	if err != nil {
+		w.Event(errorEvent(name))
+		return storage.Account{}, err
+	}
+	w.Event(event(name, progress.Done, "Created"))
+	return account, nil
+}
+
 func event(resource string, status progress.EventStatus, text string) progress.Event {
 	return progress.Event{
 		ID:         resource,
@@ -265,15 +320,38 @@ func volumeID(storageAccount string, fileShareName string) string {
 	return fmt.Sprintf("%s/%s", storageAccount, fileShareName)
 }
 
-func defaultStorageAccountParams(aciContext store.AciContext) storage.AccountCreateParameters {
-	tags := map[string]*string{dockerVolumeTag: to.StringPtr(dockerVolumeTag)}
-	return storage.AccountCreateParameters{
+func storageAccountParams(aciContext store.AciContext, sku string) storage.AccountCreateParameters {
+	tags := convert.ToAzureTags(aciContext.Tags)
+	if tags == nil {
+		tags = map[string]*string{}
+	}
+	tags[dockerVolumeTag] = to.StringPtr(dockerVolumeTag)
+	parameters := storage.AccountCreateParameters{
 		Location: to.StringPtr(aciContext.Location),
 		Sku: &storage.Sku{
 			Name: storage.StandardLRS,
 		},
 		Tags: tags,
 	}
+	if sku != "" {
+		parameters.Sku.Name = storage.SkuName(sku)
+	}
+	// premium file shares require a FileStorage account
+	if strings.HasPrefix(sku, "Premium_") {
+		parameters.Kind = storage.FileStorage
+	}
+	return parameters
+}
+
+func fileShareParams(quota int32) storage.FileShare {
+	if quota <= 0 {
+		return storage.FileShare{}
+	}
+	return storage.FileShare{
+		FileShareProperties: &storage.FileShareProperties{
+			ShareQuota: to.Int32Ptr(quota),
+		},
+	}
 }
 
 func getStorageAccountAndFileshare(volumeID string) (string, string, error) {
@@ -0,0 +1,49 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
+	"github.com/Azure/go-autorest/autorest/to"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/context/store"
+)
+
+func TestStorageAccountParams(t *testing.T) {
+	aciContext := store.AciContext{Location: "westeurope"}
+	params := storageAccountParams(aciContext, "")
+	assert.Equal(t, params.Sku.Name, storage.StandardLRS)
+	assert.Equal(t, params.Kind, storage.Kind(""))
+	assert.Equal(t, *params.Location, "westeurope")
+	assert.Equal(t, *params.Tags[dockerVolumeTag], dockerVolumeTag)
+
+	params = storageAccountParams(aciContext, "Premium_LRS")
+	assert.Equal(t, params.Sku.Name, storage.PremiumLRS)
+	assert.Equal(t, params.Kind, storage.FileStorage)
+}
+
+func TestFileShareParams(t *testing.T) {
+	assert.DeepEqual(t, fileShareParams(0), storage.FileShare{})
+	assert.DeepEqual(t, fileShareParams(100), storage.FileShare{
+		FileShareProperties: &storage.FileShareProperties{
+			ShareQuota: to.Int32Ptr(100),
+		},
+	})
+}
@@ -40,7 +40,7 @@ func (c *composeService) Down(context.Context, string) error {
 }
 
 // Logs executes the equivalent to a `compose logs`
-func (c *composeService) Logs(context.Context, string, io.Writer) error {
+func (c *composeService) Logs(context.Context, string, io.Writer, compose.LogOptions) error {
 	return errdefs.ErrNotImplemented
 }
 
@@ -54,7 +54,67 @@ func (c *composeService) List(context.Context, string) ([]compose.Stack, error)
 	return nil, errdefs.ErrNotImplemented
 }
 
+// RunOneOffContainer executes the equivalent to a `compose run`
+func (c *composeService) RunOneOffContainer(context.Context, *types.Project, compose.RunOptions) (int, error) {
+	return 0, errdefs.ErrNotImplemented
+}
+
+// Exec executes the equivalent to a `compose exec`
+func (c *composeService) Exec(context.Context, string, compose.ExecOptions) error {
+	return errdefs.ErrNotImplemented
+}
+
+// PortForward forwards a local port to a running container
+func (c *composeService) PortForward(context.Context, string, compose.PortForwardOptions) error {
+	return errdefs.ErrNotImplemented
+}
+
+// Cost estimates the monthly cost of running a project
+func (c *composeService) Cost(context.Context, *types.Project) ([]compose.CostEstimate, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+// Stop pauses a project
+func (c *composeService) Stop(context.Context, string) error {
+	return errdefs.ErrNotImplemented
+}
+
+// Start resumes a paused project
+func (c *composeService) Start(context.Context, string) error {
+	return errdefs.ErrNotImplemented
+}
+
+// Restart replaces containers of services
+func (c *composeService) Restart(context.Context, string, []string) error {
+	return errdefs.ErrNotImplemented
+}
+
+// Kill stops running containers of services
+func (c *composeService) Kill(context.Context, string, compose.KillOptions) error {
+	return errdefs.ErrNotImplemented
+}
+
+// Scale sets the number of containers of services
+func (c *composeService) Scale(context.Context, string, map[string]int) error {
+	return errdefs.ErrNotImplemented
+}
+
+// Top lists processes running in containers of a project services
+func (c *composeService) Top(context.Context, string, []string) ([]compose.ContainerProcSummary, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+// Images lists images run by containers of a project services
+func (c *composeService) Images(context.Context, string) ([]compose.ImageSummary, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+// Events streams state changes of a project services
+func (c *composeService) Events(context.Context, string, func(compose.Event)) error {
+	return errdefs.ErrNotImplemented
+}
+
 // Convert translate compose model into backend's native format
-func (c *composeService) Convert(context.Context, *types.Project) ([]byte, error) {
+func (c *composeService) Convert(context.Context, *types.Project, string) ([]byte, error) {
 	return nil, errdefs.ErrNotImplemented
 }
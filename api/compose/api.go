@@ -19,6 +19,7 @@ package compose
 import (
 	"context"
 	"io"
+	"time"
 
 	"github.com/compose-spec/compose-go/types"
 )
@@ -30,13 +31,121 @@ type Service interface {
 	// Down executes the equivalent to a `compose down`
 	Down(ctx context.Context, projectName string) error
 	// Logs executes the equivalent to a `compose logs`
-	Logs(ctx context.Context, projectName string, w io.Writer) error
+	Logs(ctx context.Context, projectName string, w io.Writer, opts LogOptions) error
 	// Ps executes the equivalent to a `compose ps`
 	Ps(ctx context.Context, projectName string) ([]ServiceStatus, error)
 	// List executes the equivalent to a `docker stack ls`
 	List(ctx context.Context, projectName string) ([]Stack, error)
 	// Convert translate compose model into backend's native format
-	Convert(ctx context.Context, project *types.Project) ([]byte, error)
+	Convert(ctx context.Context, project *types.Project, format string) ([]byte, error)
+	// RunOneOffContainer executes the equivalent to a `compose run`, returning the exit code of the container
+	RunOneOffContainer(ctx context.Context, project *types.Project, opts RunOptions) (int, error)
+	// Exec executes the equivalent to a `compose exec`
+	Exec(ctx context.Context, projectName string, opts ExecOptions) error
+	// PortForward forwards a local port to a port of a running container of service, until ctx is done
+	PortForward(ctx context.Context, projectName string, opts PortForwardOptions) error
+	// Cost estimates the monthly cost of running a project
+	Cost(ctx context.Context, project *types.Project) ([]CostEstimate, error)
+	// Stop pauses a project, stopping all its containers but keeping its resources
+	Stop(ctx context.Context, projectName string) error
+	// Start resumes a project paused by Stop
+	Start(ctx context.Context, projectName string) error
+	// Restart replaces containers of services, all services when empty, with new ones
+	Restart(ctx context.Context, projectName string, services []string) error
+	// Kill executes the equivalent to a `compose kill`, stopping running containers of services
+	Kill(ctx context.Context, projectName string, opts KillOptions) error
+	// Scale sets the number of containers of services, by name
+	Scale(ctx context.Context, projectName string, replicas map[string]int) error
+	// Top executes the equivalent to a `compose top`, listing processes running in containers of services, all
+	// services when empty
+	Top(ctx context.Context, projectName string, services []string) ([]ContainerProcSummary, error)
+	// Images executes the equivalent to a `compose images`, listing images run by containers of services
+	Images(ctx context.Context, projectName string) ([]ImageSummary, error)
+	// Events executes the equivalent to a `compose events`, sending state changes to consumer until ctx is done
+	Events(ctx context.Context, projectName string, consumer func(Event)) error
+}
+
+// RunOptions holds options for a one-off container run
+type RunOptions struct {
+	Service string
+	Command []string
+	Detach  bool
+}
+
+// LogOptions holds options for logs
+type LogOptions struct {
+	Follow bool
+	// Tail is the number of lines to show from the end of each container logs, all lines when negative
+	Tail int
+	// Since only shows logs after this time, when set
+	Since      time.Time
+	Timestamps bool
+}
+
+// ExecOptions holds options for an interactive command executed in a running container
+type ExecOptions struct {
+	Service string
+	Command []string
+	// Index selects the container among service replicas, starting at 1
+	Index int
+}
+
+// KillOptions holds options for compose kill
+type KillOptions struct {
+	// Services to kill containers of, all services when empty
+	Services []string
+	// Signal sent to containers
+	Signal string
+}
+
+// PortForwardOptions holds options for forwarding a local port to a running container
+type PortForwardOptions struct {
+	Service string
+	Port    int
+	// LocalPort is the local port to listen on, Port when zero
+	LocalPort int
+	// Index selects the container among service replicas, starting at 1
+	Index int
+}
+
+// CostEstimate holds the estimated monthly cost of a resource
+type CostEstimate struct {
+	// Service is the service the resource is created for, empty for resources shared by the project
+	Service  string
+	Resource string
+	// Monthly is the estimated cost in USD for a month
+	Monthly float64
+	// PerGB is set when Monthly is the cost of each GB stored or ingested
+	PerGB bool
+}
+
+// ContainerProcSummary holds the processes running in a container
+type ContainerProcSummary struct {
+	ID        string
+	Service   string
+	Titles    []string
+	Processes [][]string
+}
+
+// ImageSummary holds the image run by a container of a service
+type ImageSummary struct {
+	ID      string
+	Service string
+	Image   string
+	// Digest is the manifest digest the image resolved to, when known
+	Digest   string
+	Platform string
+}
+
+// Event is a state change of a service or of one of its containers
+type Event struct {
+	Timestamp time.Time
+	// Type is either service or container
+	Type       string
+	Action     string
+	ID         string
+	Service    string
+	Attributes map[string]string
 }
 
 // PortPublisher hold status about published port
@@ -55,6 +164,20 @@ type ServiceStatus struct {
 	Desired    int
 	Ports      []string
 	Publishers []PortPublisher
+	// Rollout is the state of the service latest deployment
+	Rollout string
+	Tasks   []TaskStatus
+}
+
+// TaskStatus hold status about a task running a service
+type TaskStatus struct {
+	ID     string
+	Status string
+	Health string
+	// TargetHealth is the health of the task as a load balancer target
+	TargetHealth string
+	PrivateIP    string
+	PublicIP     string
 }
 
 const (
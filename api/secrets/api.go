@@ -31,10 +31,11 @@ type Service interface {
 
 // Secret hold sensitive data
 type Secret struct {
-	ID      string            `json:"ID"`
-	Name    string            `json:"Name"`
-	Labels  map[string]string `json:"Tags"`
-	content []byte
+	ID          string            `json:"ID"`
+	Name        string            `json:"Name"`
+	Description string            `json:"Description,omitempty"`
+	Labels      map[string]string `json:"Tags"`
+	content     []byte
 }
 
 // NewSecret builds a secret
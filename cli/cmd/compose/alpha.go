@@ -0,0 +1,93 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/compose-spec/compose-go/cli"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/formatter"
+)
+
+func alphaCommand() *cobra.Command {
+	alphaCmd := &cobra.Command{
+		Use:   "alpha",
+		Short: "Experimental commands",
+	}
+	alphaCmd.AddCommand(costCommand())
+	return alphaCmd
+}
+
+func costCommand() *cobra.Command {
+	opts := composeOptions{}
+	costCmd := &cobra.Command{
+		Use:   "cost",
+		Short: "Estimate the monthly cost of deploying the application",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCost(cmd.Context(), opts)
+		},
+	}
+	costCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	costCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	costCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	costCmd.Flags().StringArrayVarP(&opts.Environment, "environment", "e", []string{}, "Environment variables")
+	costCmd.Flags().StringVar(&opts.Format, "format", "", "Format the output. Values: [pretty | json]. (Default: pretty)")
+	return costCmd
+}
+
+func runCost(ctx context.Context, opts composeOptions) error {
+	c, err := client.New(ctx)
+	if err != nil {
+		return err
+	}
+
+	options, err := opts.toProjectOptions()
+	if err != nil {
+		return err
+	}
+	project, err := cli.ProjectFromOptions(options)
+	if err != nil {
+		return err
+	}
+
+	estimates, err := c.ComposeService().Cost(ctx, project)
+	if err != nil {
+		return err
+	}
+	return formatter.Print(estimates, opts.Format, os.Stdout, func(w io.Writer) {
+		var total float64
+		for _, estimate := range estimates {
+			service := estimate.Service
+			if service == "" {
+				service = "-"
+			}
+			if estimate.PerGB {
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%.2f/GB\n", service, estimate.Resource, estimate.Monthly)
+				continue
+			}
+			total += estimate.Monthly
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%.2f\n", service, estimate.Resource, estimate.Monthly)
+		}
+		_, _ = fmt.Fprintf(w, "TOTAL\t\t%.2f\n", total)
+	}, "SERVICE", "RESOURCE", "MONTHLY (USD)")
+}
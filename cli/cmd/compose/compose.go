@@ -18,6 +18,7 @@ package compose
 
 import (
 	"context"
+	"time"
 
 	"github.com/compose-spec/compose-go/cli"
 	"github.com/spf13/pflag"
@@ -25,7 +26,10 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/ecs"
 	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/progress"
 )
 
 type composeOptions struct {
@@ -37,6 +41,48 @@ type composeOptions struct {
 	Format      string
 	Detach      bool
 	Quiet       bool
+	Region      string
+	// RoleARN and ExternalID select an IAM role ECS commands assume, e.g. to target another account
+	RoleARN    string
+	ExternalID string
+	// DeployStrategy overrides x-aws-blue_green on ECS services
+	DeployStrategy string
+	// DryRun only previews ECS stack changes
+	DryRun bool
+	// AutoApprove applies ECS stack changes replacing or deleting resources without confirmation
+	AutoApprove bool
+	// Volumes lets down delete ECS retained resources, as EFS file systems
+	Volumes bool
+	// Index selects a container among service replicas
+	Index int
+	// Build lets ECS up build services images and push them to Amazon ECR
+	Build bool
+	// AutofixResources lets ECS up raise resources limits to the Fargate task size they are rounded up to
+	AutofixResources bool
+	// MaxMonthlyCost aborts ECS up if estimated monthly cost exceeds it, in USD
+	MaxMonthlyCost float64
+	// Follow, Tail, Since and Timestamps select logs to display
+	Follow     bool
+	Tail       string
+	Since      string
+	Timestamps bool
+	// Scale overrides services replicas, as SERVICE=NUM
+	Scale []string
+	// Attach streams services logs once ECS up has deployed them
+	Attach bool
+	// DownOnExit deletes the project when user interrupts ECS up attached to logs
+	DownOnExit bool
+	// NoWait lets ECS up return once deployment is submitted
+	NoWait bool
+	// WaitTimeout and WaitInterval control waiting for ECS stack and services to be stable
+	WaitTimeout  time.Duration
+	WaitInterval time.Duration
+	// Keep is the number of superseded ECS task definition revisions to keep per service, negative to keep them all
+	Keep int
+	// Signal is sent by kill to containers
+	Signal string
+	// Plain displays progress as plain text, one line per event
+	Plain bool
 }
 
 func addComposeCommonFlags(f *pflag.FlagSet, opts *composeOptions) {
@@ -45,6 +91,119 @@ func addComposeCommonFlags(f *pflag.FlagSet, opts *composeOptions) {
 	f.BoolVarP(&opts.Quiet, "quiet", "q", false, "Only display IDs")
 }
 
+// addProgressFlags lets commands hide progress, or display it as plain text
+func addProgressFlags(cmd *cobra.Command, opts *composeOptions) {
+	cmd.Flags().BoolVarP(&opts.Quiet, "quiet", "q", false, "Don't display progress")
+	cmd.Flags().BoolVar(&opts.Plain, "plain", false, "Display progress as plain text, one line per event, e.g. for CI logs")
+}
+
+func (o composeOptions) withProgressMode(ctx context.Context) context.Context {
+	switch {
+	case o.Quiet:
+		return progress.WithMode(ctx, progress.ModeQuiet)
+	case o.Plain:
+		return progress.WithMode(ctx, progress.ModePlain)
+	}
+	return ctx
+}
+
+// addTargetFlags lets ECS commands target another region than the one set by current context, and another account by
+// assuming a role
+func addTargetFlags(cmd *cobra.Command, contextType string, opts *composeOptions) {
+	if contextType == store.EcsContextType {
+		cmd.Flags().StringVar(&opts.Region, "region", "", "AWS region, overriding the region of current context")
+		cmd.Flags().StringVar(&opts.RoleARN, "role-arn", "", "IAM role to assume with current context credentials, e.g. to deploy in another account")
+		cmd.Flags().StringVar(&opts.ExternalID, "external-id", "", "External ID required to assume the role set by --role-arn")
+	}
+}
+
+// addWaitFlags lets ECS commands control waiting for stack and services to be stable
+func addWaitFlags(cmd *cobra.Command, contextType string, opts *composeOptions) {
+	if contextType == store.EcsContextType {
+		cmd.Flags().DurationVar(&opts.WaitTimeout, "wait-timeout", 0, "Stop waiting for the deployment to complete after this duration, e.g. 15m (Default: no timeout)")
+		cmd.Flags().DurationVar(&opts.WaitInterval, "wait-interval", 0, "Interval between polls of the deployment status, e.g. 10s")
+	}
+}
+
+// addKeepFlag lets ECS commands keep superseded task definition revisions they would otherwise deregister
+func addKeepFlag(cmd *cobra.Command, contextType string, opts *composeOptions) {
+	if contextType == store.EcsContextType {
+		cmd.Flags().IntVar(&opts.Keep, "keep", -1, "Deregister superseded task definition revisions, but this number of the most recent ones per service")
+	}
+}
+
+func (o *composeOptions) withTarget(ctx context.Context) context.Context {
+	if o.Region != "" {
+		ctx = ecs.WithRegion(ctx, o.Region)
+	}
+	if o.RoleARN != "" {
+		ctx = ecs.WithAssumeRole(ctx, ecs.AssumeRole{
+			ARN:        o.RoleARN,
+			ExternalID: o.ExternalID,
+		})
+	}
+	return ctx
+}
+
+func (o *composeOptions) withDeployStrategy(ctx context.Context) context.Context {
+	if o.DeployStrategy == "" {
+		return ctx
+	}
+	return ecs.WithDeployStrategy(ctx, o.DeployStrategy)
+}
+
+func (o *composeOptions) withChangeSetReview(ctx context.Context) context.Context {
+	if !o.DryRun && !o.AutoApprove {
+		return ctx
+	}
+	return ecs.WithChangeSetReview(ctx, o.DryRun, o.AutoApprove)
+}
+
+func (o *composeOptions) withRemoveVolumes(ctx context.Context) context.Context {
+	if !o.Volumes {
+		return ctx
+	}
+	return ecs.WithRemoveVolumes(ctx, o.AutoApprove)
+}
+
+func (o *composeOptions) withBuild(ctx context.Context) context.Context {
+	if !o.Build {
+		return ctx
+	}
+	return ecs.WithBuild(ctx)
+}
+
+func (o *composeOptions) withAutofixResources(ctx context.Context) context.Context {
+	if !o.AutofixResources {
+		return ctx
+	}
+	return ecs.WithAutofixResources(ctx)
+}
+
+func (o *composeOptions) withMaxMonthlyCost(ctx context.Context) context.Context {
+	if o.MaxMonthlyCost <= 0 {
+		return ctx
+	}
+	return ecs.WithMaxMonthlyCost(ctx, o.MaxMonthlyCost)
+}
+
+func (o *composeOptions) withWaitOptions(ctx context.Context) context.Context {
+	if o.WaitTimeout <= 0 && o.WaitInterval <= 0 {
+		return ctx
+	}
+	return ecs.WithWaitOptions(ctx, ecs.WaitOptions{
+		Timeout:  o.WaitTimeout,
+		Interval: o.WaitInterval,
+	})
+}
+
+func (o *composeOptions) withKeepRevisions(ctx context.Context) context.Context {
+	if o.Keep < 0 {
+		return ctx
+	}
+	return ecs.WithKeepRevisions(ctx, o.Keep)
+}
+
 func (o *composeOptions) toProjectName() (string, error) {
 	if o.Name != "" {
 		return o.Name, nil
@@ -82,11 +241,23 @@ func Command(contextType string) *cobra.Command {
 
 	command.AddCommand(
 		upCommand(contextType),
-		downCommand(),
-		psCommand(),
+		downCommand(contextType),
+		psCommand(contextType),
 		listCommand(),
-		logsCommand(),
+		logsCommand(contextType),
+		eventsCommand(contextType),
+		topCommand(contextType),
+		imagesCommand(contextType),
+		scaleCommand(contextType),
+		stopCommand(contextType),
+		startCommand(contextType),
+		restartCommand(contextType),
+		killCommand(contextType),
 		convertCommand(),
+		runCommand(contextType),
+		execCommand(contextType),
+		portForwardCommand(contextType),
+		alphaCommand(),
 	)
 
 	return command
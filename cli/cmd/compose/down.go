@@ -22,10 +22,11 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/context/store"
 	"github.com/docker/compose-cli/progress"
 )
 
-func downCommand() *cobra.Command {
+func downCommand(contextType string) *cobra.Command {
 	opts := composeOptions{}
 	downCmd := &cobra.Command{
 		Use: "down",
@@ -37,10 +38,21 @@ func downCommand() *cobra.Command {
 	downCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
 	downCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
 
+	addTargetFlags(downCmd, contextType, &opts)
+	addWaitFlags(downCmd, contextType, &opts)
+	addKeepFlag(downCmd, contextType, &opts)
+	if contextType == store.EcsContextType {
+		downCmd.Flags().BoolVar(&opts.Volumes, "volumes", false, "Also delete EFS file systems and other retained resources")
+		downCmd.Flags().BoolVarP(&opts.AutoApprove, "yes", "y", false, "Delete retained resources without confirmation")
+	}
 	return downCmd
 }
 
 func runDown(ctx context.Context, opts composeOptions) error {
+	ctx = opts.withTarget(ctx)
+	ctx = opts.withWaitOptions(ctx)
+	ctx = opts.withRemoveVolumes(ctx)
+	ctx = opts.withKeepRevisions(ctx)
 	c, err := client.New(ctx)
 	if err != nil {
 		return err
@@ -0,0 +1,95 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/formatter"
+)
+
+// eventTimeFormat is the fixed width timestamp format of docker events
+const eventTimeFormat = "2006-01-02T15:04:05.000000000Z07:00"
+
+func eventsCommand(contextType string) *cobra.Command {
+	opts := composeOptions{}
+	eventsCmd := &cobra.Command{
+		Use:   "events",
+		Short: "Receive real time events from services and their containers",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runEvents(cmd.Context(), cmd.OutOrStdout(), opts)
+		},
+	}
+	eventsCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	eventsCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	eventsCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	eventsCmd.Flags().StringVar(&opts.Format, "format", "", "Format the output. Values: [pretty | json]. (Default: pretty)")
+
+	addTargetFlags(eventsCmd, contextType, &opts)
+	return eventsCmd
+}
+
+func runEvents(ctx context.Context, out io.Writer, opts composeOptions) error {
+	ctx = opts.withTarget(ctx)
+	c, err := client.New(ctx)
+	if err != nil {
+		return err
+	}
+	projectName, err := opts.toProjectName()
+	if err != nil {
+		return err
+	}
+	format := strings.ToLower(opts.Format)
+	if format != "" && format != formatter.PRETTY && format != formatter.JSON {
+		return errors.Wrapf(errdefs.ErrParsingFailed, "format value %q could not be parsed", opts.Format)
+	}
+	return c.ComposeService().Events(ctx, projectName, func(event compose.Event) {
+		if format == formatter.JSON {
+			// one event per line, as a stream
+			b, err := formatter.ToJSON(event, "", "")
+			if err == nil {
+				_, _ = fmt.Fprint(out, b)
+			}
+			return
+		}
+		printEvent(out, event)
+	})
+}
+
+// printEvent prints an event the way docker events does
+func printEvent(out io.Writer, event compose.Event) {
+	keys := make([]string, 0, len(event.Attributes))
+	for k := range event.Attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	attributes := make([]string, len(keys))
+	for i, k := range keys {
+		attributes[i] = fmt.Sprintf("%s=%s", k, event.Attributes[k])
+	}
+	_, _ = fmt.Fprintf(out, "%s %s %s %s (%s)\n", event.Timestamp.Format(eventTimeFormat), event.Type, event.Action, event.ID, strings.Join(attributes, ", "))
+}
@@ -0,0 +1,65 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func execCommand(contextType string) *cobra.Command {
+	opts := composeOptions{}
+	execCmd := &cobra.Command{
+		Use:   "exec [options] SERVICE [COMMAND] [ARGS...]",
+		Short: "Execute an interactive command in a running container",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runExec(cmd.Context(), opts, args)
+		},
+	}
+	execCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	execCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	execCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	execCmd.Flags().IntVar(&opts.Index, "index", 1, "Index of the container if service has multiple replicas")
+	// stop parsing flags at service name, so that command flags are passed as is
+	execCmd.Flags().SetInterspersed(false)
+
+	addTargetFlags(execCmd, contextType, &opts)
+	return execCmd
+}
+
+func runExec(ctx context.Context, opts composeOptions, args []string) error {
+	ctx = opts.withTarget(ctx)
+	c, err := client.New(ctx)
+	if err != nil {
+		return err
+	}
+
+	projectName, err := opts.toProjectName()
+	if err != nil {
+		return err
+	}
+	return c.ComposeService().Exec(ctx, projectName, compose.ExecOptions{
+		Service: args[0],
+		Command: args[1:],
+		Index:   opts.Index,
+	})
+}
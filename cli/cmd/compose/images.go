@@ -0,0 +1,78 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/formatter"
+	"github.com/docker/compose-cli/utils"
+)
+
+func imagesCommand(contextType string) *cobra.Command {
+	opts := composeOptions{}
+	imagesCmd := &cobra.Command{
+		Use:   "images",
+		Short: "List images used by the running containers of services",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runImages(cmd.Context(), cmd.OutOrStdout(), opts)
+		},
+	}
+	imagesCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	imagesCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	addComposeCommonFlags(imagesCmd.Flags(), &opts)
+	addTargetFlags(imagesCmd, contextType, &opts)
+	return imagesCmd
+}
+
+func runImages(ctx context.Context, out io.Writer, opts composeOptions) error {
+	ctx = opts.withTarget(ctx)
+	c, err := client.New(ctx)
+	if err != nil {
+		return err
+	}
+	projectName, err := opts.toProjectName()
+	if err != nil {
+		return err
+	}
+	images, err := c.ComposeService().Images(ctx, projectName)
+	if err != nil {
+		return err
+	}
+	if opts.Quiet {
+		printed := []string{}
+		for _, image := range images {
+			if !utils.StringContains(printed, image.Image) {
+				_, _ = fmt.Fprintln(out, image.Image)
+				printed = append(printed, image.Image)
+			}
+		}
+		return nil
+	}
+	return formatter.Print(images, opts.Format, out,
+		func(w io.Writer) {
+			for _, image := range images {
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", image.Service, image.ID, image.Image, image.Digest, image.Platform)
+			}
+		},
+		"SERVICE", "CONTAINER", "IMAGE", "DIGEST", "PLATFORM")
+}
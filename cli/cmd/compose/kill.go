@@ -0,0 +1,71 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/progress"
+)
+
+func killCommand(contextType string) *cobra.Command {
+	opts := composeOptions{}
+	killCmd := &cobra.Command{
+		Use:   "kill [SERVICE...]",
+		Short: "Force stop containers of services",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runKill(cmd.Context(), opts, args)
+		},
+	}
+	killCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	killCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	killCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	defaultSignal := "SIGKILL"
+	if contextType == store.EcsContextType {
+		// ECS stops tasks gracefully, sending SIGTERM first
+		defaultSignal = "SIGTERM"
+	}
+	killCmd.Flags().StringVarP(&opts.Signal, "signal", "s", defaultSignal, "SIGNAL to send to the container")
+
+	addTargetFlags(killCmd, contextType, &opts)
+	return killCmd
+}
+
+func runKill(ctx context.Context, opts composeOptions, services []string) error {
+	ctx = opts.withTarget(ctx)
+	c, err := client.New(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = progress.Run(ctx, func(ctx context.Context) (string, error) {
+		projectName, err := opts.toProjectName()
+		if err != nil {
+			return "", err
+		}
+		return projectName, c.ComposeService().Kill(ctx, projectName, compose.KillOptions{
+			Services: services,
+			Signal:   opts.Signal,
+		})
+	})
+	return err
+}
@@ -19,13 +19,19 @@ package compose
 import (
 	"context"
 	"os"
+	"strconv"
+	"time"
 
+	timetypes "github.com/docker/docker/api/types/time"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
 )
 
-func logsCommand() *cobra.Command {
+func logsCommand(contextType string) *cobra.Command {
 	opts := composeOptions{}
 	logsCmd := &cobra.Command{
 		Use: "logs",
@@ -36,11 +42,17 @@ func logsCommand() *cobra.Command {
 	logsCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
 	logsCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
 	logsCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	logsCmd.Flags().BoolVar(&opts.Follow, "follow", false, "Follow log output")
+	logsCmd.Flags().StringVar(&opts.Tail, "tail", "all", "Number of lines to show from the end of the logs of each container")
+	logsCmd.Flags().StringVar(&opts.Since, "since", "", "Show logs since timestamp (e.g. 2013-01-02T13:23:37) or relative (e.g. 42m for 42 minutes)")
+	logsCmd.Flags().BoolVarP(&opts.Timestamps, "timestamps", "t", false, "Show timestamps")
 
+	addTargetFlags(logsCmd, contextType, &opts)
 	return logsCmd
 }
 
 func runLogs(ctx context.Context, opts composeOptions) error {
+	ctx = opts.withTarget(ctx)
 	c, err := client.New(ctx)
 	if err != nil {
 		return err
@@ -50,5 +62,36 @@ func runLogs(ctx context.Context, opts composeOptions) error {
 	if err != nil {
 		return err
 	}
-	return c.ComposeService().Logs(ctx, projectName, os.Stdout)
+	logOpts, err := opts.toLogOptions()
+	if err != nil {
+		return err
+	}
+	return c.ComposeService().Logs(ctx, projectName, os.Stdout, logOpts)
+}
+
+func (o *composeOptions) toLogOptions() (compose.LogOptions, error) {
+	logOpts := compose.LogOptions{
+		Follow:     o.Follow,
+		Tail:       -1,
+		Timestamps: o.Timestamps,
+	}
+	if o.Tail != "all" {
+		tail, err := strconv.Atoi(o.Tail)
+		if err != nil || tail < 0 {
+			return logOpts, errors.Wrapf(errdefs.ErrParsingFailed, "invalid tail value %q, expected a number of lines or all", o.Tail)
+		}
+		logOpts.Tail = tail
+	}
+	if o.Since != "" {
+		ts, err := timetypes.GetTimestamp(o.Since, time.Now())
+		if err != nil {
+			return logOpts, errors.Wrapf(errdefs.ErrParsingFailed, "invalid since value %q: %s", o.Since, err)
+		}
+		seconds, nanoseconds, err := timetypes.ParseTimestamps(ts, 0)
+		if err != nil {
+			return logOpts, err
+		}
+		logOpts.Since = time.Unix(seconds, nanoseconds)
+	}
+	return logOpts, nil
 }
@@ -0,0 +1,99 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func portForwardCommand(contextType string) *cobra.Command {
+	opts := composeOptions{}
+	portForwardCmd := &cobra.Command{
+		Use:   "port-forward [options] SERVICE [LOCAL_PORT:]PORT",
+		Short: "Forward a local port to a port of a running container",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPortForward(cmd.Context(), opts, args[0], args[1])
+		},
+	}
+	portForwardCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	portForwardCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	portForwardCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	portForwardCmd.Flags().IntVar(&opts.Index, "index", 1, "Index of the container if service has multiple replicas")
+
+	addTargetFlags(portForwardCmd, contextType, &opts)
+	return portForwardCmd
+}
+
+func runPortForward(ctx context.Context, opts composeOptions, service string, ports string) error {
+	local, remote, err := parsePortForward(ports)
+	if err != nil {
+		return err
+	}
+	ctx = opts.withTarget(ctx)
+	c, err := client.New(ctx)
+	if err != nil {
+		return err
+	}
+
+	projectName, err := opts.toProjectName()
+	if err != nil {
+		return err
+	}
+	return c.ComposeService().PortForward(ctx, projectName, compose.PortForwardOptions{
+		Service:   service,
+		Port:      remote,
+		LocalPort: local,
+		Index:     opts.Index,
+	})
+}
+
+// parsePortForward parses `[LOCAL_PORT:]PORT`, local port being 0 when not set
+func parsePortForward(ports string) (int, int, error) {
+	local, remote := "", ports
+	if i := strings.Index(ports, ":"); i >= 0 {
+		local, remote = ports[:i], ports[i+1:]
+	}
+	port, err := parsePort(remote)
+	if err != nil {
+		return 0, 0, err
+	}
+	if local == "" {
+		return 0, port, nil
+	}
+	localPort, err := parsePort(local)
+	if err != nil {
+		return 0, 0, err
+	}
+	return localPort, port, nil
+}
+
+func parsePort(s string) (int, error) {
+	port, err := strconv.Atoi(s)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("invalid port %q", s)
+	}
+	return port, nil
+}
@@ -30,7 +30,7 @@ import (
 	"github.com/docker/compose-cli/formatter"
 )
 
-func psCommand() *cobra.Command {
+func psCommand(contextType string) *cobra.Command {
 	opts := composeOptions{}
 	psCmd := &cobra.Command{
 		Use: "ps",
@@ -41,10 +41,12 @@ func psCommand() *cobra.Command {
 	psCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
 	psCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
 	addComposeCommonFlags(psCmd.Flags(), &opts)
+	addTargetFlags(psCmd, contextType, &opts)
 	return psCmd
 }
 
 func runPs(ctx context.Context, opts composeOptions) error {
+	ctx = opts.withTarget(ctx)
 	c, err := client.New(ctx)
 	if err != nil {
 		return err
@@ -65,13 +67,36 @@ func runPs(ctx context.Context, opts composeOptions) error {
 		return nil
 	}
 	view := viewFromServiceStatusList(serviceList)
-	return formatter.Print(view, opts.Format, os.Stdout,
+	err = formatter.Print(view, opts.Format, os.Stdout,
 		func(w io.Writer) {
 			for _, service := range view {
-				_, _ = fmt.Fprintf(w, "%s\t%s\t%d/%d\t%s\n", service.ID, service.Name, service.Replicas, service.Desired, strings.Join(service.Ports, ", "))
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%d/%d\t%s\t%s\n", service.ID, service.Name, service.Replicas, service.Desired, service.Rollout, strings.Join(service.Ports, ", "))
 			}
 		},
-		"ID", "NAME", "REPLICAS", "PORTS")
+		"ID", "NAME", "REPLICAS", "ROLLOUT", "PORTS")
+	if err != nil || strings.ToLower(opts.Format) == formatter.JSON {
+		return err
+	}
+	return printTasks(os.Stdout, view)
+}
+
+// printTasks prints tasks of services, for backends reporting them
+func printTasks(out io.Writer, view []serviceStatusView) error {
+	var tasks int
+	for _, service := range view {
+		tasks += len(service.Tasks)
+	}
+	if tasks == 0 {
+		return nil
+	}
+	_, _ = fmt.Fprintln(out)
+	return formatter.PrintPrettySection(out, func(w io.Writer) {
+		for _, service := range view {
+			for _, task := range service.Tasks {
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", task.ID, service.Name, task.Status, task.Health, task.TargetHealth, task.PrivateIP, task.PublicIP)
+			}
+		}
+	}, "TASK", "SERVICE", "STATUS", "HEALTH", "TARGET HEALTH", "PRIVATE IP", "PUBLIC IP")
 }
 
 type serviceStatusView struct {
@@ -79,7 +104,9 @@ type serviceStatusView struct {
 	Name     string
 	Replicas int
 	Desired  int
+	Rollout  string
 	Ports    []string
+	Tasks    []compose.TaskStatus
 }
 
 func viewFromServiceStatusList(serviceStatusList []compose.ServiceStatus) []serviceStatusView {
@@ -90,7 +117,9 @@ func viewFromServiceStatusList(serviceStatusList []compose.ServiceStatus) []serv
 			Name:     s.Name,
 			Replicas: s.Replicas,
 			Desired:  s.Desired,
+			Rollout:  s.Rollout,
 			Ports:    s.Ports,
+			Tasks:    s.Tasks,
 		}
 	}
 	return retList
@@ -0,0 +1,80 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+
+	"github.com/compose-spec/compose-go/cli"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func runCommand(contextType string) *cobra.Command {
+	opts := composeOptions{}
+	runCmd := &cobra.Command{
+		Use:   "run [options] SERVICE [COMMAND] [ARGS...]",
+		Short: "Run a one-off command on a service",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRun(cmd.Context(), opts, args)
+		},
+	}
+	runCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	runCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	runCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	runCmd.Flags().StringArrayVarP(&opts.Environment, "environment", "e", []string{}, "Environment variables")
+	runCmd.Flags().BoolVarP(&opts.Detach, "detach", "d", false, "Run container in background and print its ID")
+	// stop parsing flags at service name, so that command flags are passed as is
+	runCmd.Flags().SetInterspersed(false)
+
+	addTargetFlags(runCmd, contextType, &opts)
+	return runCmd
+}
+
+func runRun(ctx context.Context, opts composeOptions, args []string) error {
+	ctx = opts.withTarget(ctx)
+	c, err := client.New(ctx)
+	if err != nil {
+		return err
+	}
+
+	options, err := opts.toProjectOptions()
+	if err != nil {
+		return err
+	}
+	project, err := cli.ProjectFromOptions(options)
+	if err != nil {
+		return err
+	}
+
+	exitCode, err := c.ComposeService().RunOneOffContainer(ctx, project, compose.RunOptions{
+		Service: args[0],
+		Command: args[1:],
+		Detach:  opts.Detach,
+	})
+	if err != nil {
+		return err
+	}
+	if exitCode != 0 {
+		return errdefs.StatusError{StatusCode: exitCode}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/formatter"
+	"github.com/docker/compose-cli/progress"
+)
+
+func scaleCommand(contextType string) *cobra.Command {
+	opts := composeOptions{}
+	scaleCmd := &cobra.Command{
+		Use:   "scale SERVICE=NUM...",
+		Short: "Set the number of containers of services",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runScale(cmd.Context(), opts, args)
+		},
+	}
+	scaleCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	scaleCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	scaleCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+
+	addTargetFlags(scaleCmd, contextType, &opts)
+	addWaitFlags(scaleCmd, contextType, &opts)
+	return scaleCmd
+}
+
+func runScale(ctx context.Context, opts composeOptions, args []string) error {
+	ctx = opts.withTarget(ctx)
+	ctx = opts.withWaitOptions(ctx)
+	replicas, err := parseScale(args)
+	if err != nil {
+		return err
+	}
+	c, err := client.New(ctx)
+	if err != nil {
+		return err
+	}
+	projectName, err := opts.toProjectName()
+	if err != nil {
+		return err
+	}
+	_, err = progress.Run(ctx, func(ctx context.Context) (string, error) {
+		return "", c.ComposeService().Scale(ctx, projectName, replicas)
+	})
+	if err != nil {
+		return err
+	}
+
+	serviceList, err := c.ComposeService().Ps(ctx, projectName)
+	if err != nil {
+		return err
+	}
+	view := []serviceStatusView{}
+	for _, service := range viewFromServiceStatusList(serviceList) {
+		if _, ok := replicas[service.Name]; ok {
+			view = append(view, service)
+		}
+	}
+	err = formatter.PrintPrettySection(os.Stdout, func(w io.Writer) {
+		for _, service := range view {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%d/%d\n", service.ID, service.Name, service.Replicas, service.Desired)
+		}
+	}, "ID", "NAME", "REPLICAS")
+	if err != nil {
+		return err
+	}
+	return printTasks(os.Stdout, view)
+}
+
+// parseScale parses SERVICE=NUM arguments
+func parseScale(args []string) (map[string]int, error) {
+	replicas := map[string]int{}
+	for _, arg := range args {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Wrapf(errdefs.ErrParsingFailed, "invalid scale %q, expected SERVICE=NUM", arg)
+		}
+		n, err := strconv.Atoi(parts[1])
+		if err != nil || n < 0 {
+			return nil, errors.Wrapf(errdefs.ErrParsingFailed, "invalid number of containers %q for service %s", parts[1], parts[0])
+		}
+		replicas[parts[0]] = n
+	}
+	return replicas, nil
+}
+
+// applyScale overrides deploy.replicas of services with SERVICE=NUM values
+func applyScale(project *types.Project, scale []string) error {
+	replicas, err := parseScale(scale)
+	if err != nil {
+		return err
+	}
+	for name, n := range replicas {
+		service, err := project.GetService(name)
+		if err != nil {
+			return err
+		}
+		if service.Deploy == nil {
+			service.Deploy = &types.DeployConfig{}
+		}
+		count := uint64(n)
+		service.Deploy.Replicas = &count
+		for i, s := range project.Services {
+			if s.Name == name {
+				project.Services[i] = service
+			}
+		}
+	}
+	return nil
+}
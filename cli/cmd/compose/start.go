@@ -0,0 +1,62 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/progress"
+)
+
+func startCommand(contextType string) *cobra.Command {
+	opts := composeOptions{}
+	startCmd := &cobra.Command{
+		Use:   "start",
+		Short: "Start services stopped by compose stop",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runStart(cmd.Context(), opts)
+		},
+	}
+	startCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	startCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	startCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+
+	addTargetFlags(startCmd, contextType, &opts)
+	addWaitFlags(startCmd, contextType, &opts)
+	return startCmd
+}
+
+func runStart(ctx context.Context, opts composeOptions) error {
+	ctx = opts.withTarget(ctx)
+	ctx = opts.withWaitOptions(ctx)
+	c, err := client.New(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = progress.Run(ctx, func(ctx context.Context) (string, error) {
+		projectName, err := opts.toProjectName()
+		if err != nil {
+			return "", err
+		}
+		return projectName, c.ComposeService().Start(ctx, projectName)
+	})
+	return err
+}
@@ -0,0 +1,82 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/formatter"
+)
+
+func topCommand(contextType string) *cobra.Command {
+	opts := composeOptions{}
+	topCmd := &cobra.Command{
+		Use:   "top [SERVICE...]",
+		Short: "Display the running processes of services containers",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTop(cmd.Context(), cmd.OutOrStdout(), opts, args)
+		},
+	}
+	topCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	topCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	topCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	topCmd.Flags().StringVar(&opts.Format, "format", "", "Format the output. Values: [pretty | json]. (Default: pretty)")
+
+	addTargetFlags(topCmd, contextType, &opts)
+	return topCmd
+}
+
+func runTop(ctx context.Context, out io.Writer, opts composeOptions, services []string) error {
+	ctx = opts.withTarget(ctx)
+	c, err := client.New(ctx)
+	if err != nil {
+		return err
+	}
+	projectName, err := opts.toProjectName()
+	if err != nil {
+		return err
+	}
+	containers, err := c.ComposeService().Top(ctx, projectName, services)
+	if err != nil {
+		return err
+	}
+	if format := strings.ToLower(opts.Format); format != "" && format != formatter.PRETTY {
+		// processes are listed by container in sections, only other formats go through the formatter
+		return formatter.Print(containers, opts.Format, out, nil)
+	}
+	for i, container := range containers {
+		if i > 0 {
+			_, _ = fmt.Fprintln(out)
+		}
+		_, _ = fmt.Fprintf(out, "%s (%s)\n", container.Service, container.ID)
+		err := formatter.PrintPrettySection(out, func(w io.Writer) {
+			for _, process := range container.Processes {
+				_, _ = fmt.Fprintln(w, strings.Join(process, "\t"))
+			}
+		}, container.Titles...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
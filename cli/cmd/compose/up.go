@@ -18,13 +18,19 @@ package compose
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"time"
 
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
 	"github.com/compose-spec/compose-go/cli"
 
 	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
 	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/ecs"
 	"github.com/docker/compose-cli/progress"
 )
 
@@ -33,29 +39,68 @@ func upCommand(contextType string) *cobra.Command {
 	upCmd := &cobra.Command{
 		Use: "up",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runUp(cmd.Context(), opts)
+			return runUp(cmd.Context(), contextType, opts, args)
 		},
 	}
+	if contextType == store.EcsContextType {
+		upCmd.Use = "up [SERVICE...]"
+	} else {
+		upCmd.Args = cobra.NoArgs
+	}
 	upCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
 	upCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
 	upCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
 	upCmd.Flags().StringArrayVarP(&opts.Environment, "environment", "e", []string{}, "Environment variables")
 	upCmd.Flags().BoolVarP(&opts.Detach, "detach", "d", false, " Detached mode: Run containers in the background")
+	upCmd.Flags().StringArrayVar(&opts.Scale, "scale", []string{}, "Scale SERVICE to NUM instances, as SERVICE=NUM. Overrides deploy.replicas set in compose file")
 
 	if contextType == store.AciContextType {
 		upCmd.Flags().StringVar(&opts.DomainName, "domainname", "", "Container NIS domain name")
 	}
+	addTargetFlags(upCmd, contextType, &opts)
+	addWaitFlags(upCmd, contextType, &opts)
+	addKeepFlag(upCmd, contextType, &opts)
+	addProgressFlags(upCmd, &opts)
+	if contextType == store.EcsContextType {
+		upCmd.Flags().StringVar(&opts.DeployStrategy, "deploy-strategy", "", "Deployment strategy of services exposing ports. Values: [rolling | blue_green]")
+		upCmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Preview stack changes without applying them")
+		upCmd.Flags().BoolVarP(&opts.AutoApprove, "yes", "y", false, "Apply stack changes replacing or deleting resources without confirmation")
+		upCmd.Flags().BoolVar(&opts.Build, "build", false, "Build images and push them to Amazon ECR before deploying")
+		upCmd.Flags().BoolVar(&opts.AutofixResources, "autofix-resources", false, "Raise resources limits to the Fargate task size they are rounded up to")
+		upCmd.Flags().Float64Var(&opts.MaxMonthlyCost, "max-monthly-cost", 0, "Abort deployment if its estimated monthly cost exceeds this amount, in USD")
+		upCmd.Flags().BoolVar(&opts.Attach, "attach", false, "Attach to services logs once deployed, until interrupted")
+		upCmd.Flags().BoolVar(&opts.DownOnExit, "down-on-exit", false, "Delete the application when interrupted while attached to logs")
+		upCmd.Flags().BoolVar(&opts.NoWait, "no-wait", false, "Return once deployment is submitted, without waiting for it to complete")
+	}
 
 	return upCmd
 }
 
-func runUp(ctx context.Context, opts composeOptions) error {
+func runUp(ctx context.Context, contextType string, opts composeOptions, services []string) error {
+	ctx = opts.withTarget(ctx)
+	ctx = opts.withWaitOptions(ctx)
+	ctx = opts.withDeployStrategy(ctx)
+	ctx = opts.withChangeSetReview(ctx)
+	ctx = opts.withBuild(ctx)
+	ctx = opts.withAutofixResources(ctx)
+	ctx = opts.withMaxMonthlyCost(ctx)
+	ctx = opts.withKeepRevisions(ctx)
+	ctx = opts.withProgressMode(ctx)
+	if len(services) > 0 {
+		ctx = ecs.WithServices(ctx, services)
+	}
+	if opts.DownOnExit && !opts.Attach {
+		return fmt.Errorf("--down-on-exit requires --attach")
+	}
 	c, err := client.New(ctx)
 	if err != nil {
 		return err
 	}
 
-	_, err = progress.Run(ctx, func(ctx context.Context) (string, error) {
+	// ECS up waits for deployment to complete unless --no-wait is set, detach only skips attaching to logs
+	detach := (opts.Detach && contextType != store.EcsContextType) || opts.NoWait
+	started := time.Now()
+	projectName, err := progress.Run(ctx, func(ctx context.Context) (string, error) {
 		options, err := opts.toProjectOptions()
 		if err != nil {
 			return "", err
@@ -68,7 +113,34 @@ func runUp(ctx context.Context, opts composeOptions) error {
 		if err != nil {
 			return "", err
 		}
-		return "", c.ComposeService().Up(ctx, project, opts.Detach)
+		err = applyScale(project, opts.Scale)
+		if err != nil {
+			return "", err
+		}
+		return project.Name, c.ComposeService().Up(ctx, project, detach)
+	})
+	if err != nil || !opts.Attach || opts.Detach || opts.NoWait || opts.DryRun || contextType != store.EcsContextType {
+		return err
+	}
+	return attach(ctx, c, projectName, started, opts.DownOnExit)
+}
+
+// attach streams application logs since deployment started, until user interrupts it
+func attach(ctx context.Context, c *client.Client, projectName string, since time.Time, downOnExit bool) error {
+	logrus.Info("Attaching to logs, press Ctrl+C to exit")
+	err := c.ComposeService().Logs(ctx, projectName, os.Stdout, compose.LogOptions{
+		Follow: true,
+		Tail:   -1,
+		Since:  since,
+	})
+	if ctx.Err() == nil {
+		return err
+	}
+	if !downOnExit {
+		return nil
+	}
+	_, err = progress.Run(context.Background(), func(downCtx context.Context) (string, error) {
+		return projectName, c.ComposeService().Down(downCtx, projectName)
 	})
 	return err
 }
@@ -34,6 +34,8 @@ func Command() *cobra.Command {
 		showCommand(),
 		useCommand(),
 		inspectCommand(),
+		updateCommand(),
+		exportAWSProfileCommand(),
 	)
 
 	return cmd
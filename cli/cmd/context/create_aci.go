@@ -52,6 +52,9 @@ func createAciCommand() *cobra.Command {
 	cmd.Flags().StringVar(&opts.Location, "location", "eastus", "Location")
 	cmd.Flags().StringVar(&opts.SubscriptionID, "subscription-id", "", "Location")
 	cmd.Flags().StringVar(&opts.ResourceGroup, "resource-group", "", "Resource group")
+	cmd.Flags().StringToStringVar(&opts.Tags, "tag", nil, "Tag applied to all Azure resources created with this context, as KEY=VALUE")
+	cmd.Flags().StringVar(&opts.VNet, "vnet", "", "Virtual network to deploy to privately, unless compose file sets x-aci-vnet")
+	cmd.Flags().StringVar(&opts.Subnet, "subnet", "", "Subnet of the virtual network to deploy to (default \"aci\")")
 
 	return cmd
 }
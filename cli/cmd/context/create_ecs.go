@@ -56,6 +56,21 @@ func createEcsCommand() *cobra.Command {
 	cmd.Flags().BoolVar(&localSimulation, "local-simulation", false, "Create context for ECS local simulation endpoints")
 	cmd.Flags().StringVar(&opts.Profile, "profile", "", "Profile")
 	cmd.Flags().StringVar(&opts.Region, "region", "", "Region")
+	cmd.Flags().StringVar(&opts.FromAWSProfile, "from-aws-profile", "", "Import region and credentials source from an existing AWS CLI profile")
+	cmd.Flags().StringVar(&opts.CreateProfile, "profile-create", "", "Create a new profile using the provided AWS credentials")
+	cmd.Flags().StringVar(&opts.AccessKey, "access-key", "", "AWS Access Key ID for the new profile")
+	cmd.Flags().StringVar(&opts.SecretKey, "secret-key", "", "AWS Secret Access Key for the new profile")
+	cmd.Flags().StringVar(&opts.SessionToken, "session-token", "", "AWS Session Token for the new profile")
+	cmd.Flags().BoolVar(&opts.Keychain, "keychain", false, "Store credentials for the new profile in the OS keychain using the docker credentials store")
+	cmd.Flags().BoolVar(&opts.CredentialsFromEnv, "from-env", false, "Use AWS credentials (static keys or web identity token) from environment variables each time a command runs, instead of a profile")
+	cmd.Flags().StringVar(&opts.Cluster, "cluster", "", "ECS cluster name or ARN to deploy to, unless compose file sets x-aws-cluster")
+	cmd.Flags().StringVar(&opts.VPC, "vpc", "", "VPC to deploy to, unless compose file sets x-aws-vpc")
+	cmd.Flags().StringSliceVar(&opts.Subnets, "subnets", nil, "Subnets of the VPC to deploy to (default: all subnets of the VPC)")
+	cmd.Flags().StringToStringVar(&opts.Tags, "tag", nil, "Tag applied to all CloudFormation stacks created with this context, as KEY=VALUE")
+	cmd.Flags().StringVar(&opts.EndpointURL, "endpoint-url", "", "Send requests for all AWS services to this URL, typically a local simulator like LocalStack")
+	cmd.Flags().StringToStringVar(&opts.Endpoints, "endpoint", nil, "Override endpoint URL for an AWS service, as SERVICE=URL (e.g. ecs=http://localhost:4566)")
+	cmd.Flags().BoolVar(&opts.TestPermissions, "test", false, "Check AWS credentials are granted all permissions required to deploy on ECS")
+	cmd.Flags().BoolVar(&opts.NonInteractive, "non-interactive", false, "Fail instead of prompting for missing values")
 	return cmd
 }
 
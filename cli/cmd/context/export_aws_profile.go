@@ -0,0 +1,63 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package context
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/ecs"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func exportAWSProfileCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "export-aws-profile CONTEXT [PROFILE]",
+		Short: "Write an AWS CLI profile matching an ECS context (profile defaults to context name)",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			profile := args[0]
+			if len(args) > 1 {
+				profile = args[1]
+			}
+			return runExportAWSProfile(cmd, args[0], profile)
+		},
+	}
+}
+
+func runExportAWSProfile(cmd *cobra.Command, name string, profile string) error {
+	s := store.ContextStore(cmd.Context())
+	meta, err := s.Get(name)
+	if err != nil {
+		return err
+	}
+	if meta.Type() != store.EcsContextType {
+		return errors.Wrapf(errdefs.ErrNotImplemented, "context %q is not an ECS context", name)
+	}
+	var ecsCtx store.EcsContext
+	if err := s.GetEndpoint(name, &ecsCtx); err != nil {
+		return err
+	}
+	if err := ecs.ExportAWSProfile(ecsCtx, profile); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "AWS profile %q written\n", profile)
+	return nil
+}
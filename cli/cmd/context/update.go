@@ -0,0 +1,142 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package context
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/cli/mobycli"
+	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+type updateOpts struct {
+	description   string
+	profile       string
+	region        string
+	location      string
+	resourceGroup string
+	vnet          string
+	subnet        string
+}
+
+func updateCommand() *cobra.Command {
+	var opts updateOpts
+	cmd := &cobra.Command{
+		Use:   "update [OPTIONS] CONTEXT",
+		Short: "Update a context",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUpdate(cmd, args[0], opts)
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&opts.description, "description", "", "Description of the context")
+	flags.StringVar(&opts.profile, "profile", "", "AWS profile (ECS contexts)")
+	flags.StringVar(&opts.region, "region", "", "AWS region (ECS contexts)")
+	flags.StringVar(&opts.location, "location", "", "Azure location (ACI contexts)")
+	flags.StringVar(&opts.resourceGroup, "resource-group", "", "Azure resource group (ACI contexts)")
+	flags.StringVar(&opts.vnet, "vnet", "", "Azure virtual network to deploy to privately, empty to deploy publicly (ACI contexts)")
+	flags.StringVar(&opts.subnet, "subnet", "", "Subnet of the Azure virtual network to deploy to (ACI contexts)")
+	// flags for docker engine contexts, delegated to the docker CLI
+	flags.String("default-stack-orchestrator", "", "Default orchestrator for stack operations to use with this context (swarm|kubernetes|all)")
+	flags.StringToString("docker", nil, "Set the docker endpoint")
+	flags.StringToString("kubernetes", nil, "Set the kubernetes endpoint")
+	return cmd
+}
+
+func runUpdate(cmd *cobra.Command, name string, opts updateOpts) error {
+	s := store.ContextStore(cmd.Context())
+	meta, err := s.Get(name)
+	if err != nil {
+		return err
+	}
+	changed := func(flags ...string) bool {
+		for _, f := range flags {
+			if cmd.Flags().Changed(f) {
+				return true
+			}
+		}
+		return false
+	}
+
+	description := meta.Metadata.Description
+	if changed("description") {
+		description = opts.description
+	}
+
+	var data interface{}
+	switch meta.Type() {
+	case store.EcsContextType:
+		if changed("location", "resource-group", "vnet", "subnet") {
+			return errors.Wrapf(errdefs.ErrParsingFailed, "--location, --resource-group, --vnet and --subnet only apply to ACI contexts")
+		}
+		var ecsCtx store.EcsContext
+		if err := s.GetEndpoint(name, &ecsCtx); err != nil {
+			return err
+		}
+		if changed("profile") {
+			if ecsCtx.CredentialsFromEnv {
+				return errors.Wrapf(errdefs.ErrParsingFailed, "context %q uses credentials from environment and has no profile", name)
+			}
+			ecsCtx.Profile = opts.profile
+		}
+		if changed("region") {
+			ecsCtx.Region = opts.region
+		}
+		data = ecsCtx
+	case store.AciContextType:
+		if changed("profile", "region") {
+			return errors.Wrapf(errdefs.ErrParsingFailed, "--profile and --region only apply to ECS contexts")
+		}
+		var aciCtx store.AciContext
+		if err := s.GetEndpoint(name, &aciCtx); err != nil {
+			return err
+		}
+		if changed("location") {
+			aciCtx.Location = opts.location
+		}
+		if changed("resource-group") {
+			aciCtx.ResourceGroup = opts.resourceGroup
+		}
+		if changed("vnet") {
+			aciCtx.VNet = opts.vnet
+			aciCtx.Subnet = ""
+		}
+		if changed("subnet") {
+			aciCtx.Subnet = opts.subnet
+		}
+		data = aciCtx
+	case store.DefaultContextType:
+		mobycli.Exec(cmd.Root())
+		return nil
+	default:
+		if changed("profile", "region", "location", "resource-group", "vnet", "subnet") {
+			return errors.Wrapf(errdefs.ErrParsingFailed, "context %q of type %s only supports updating its description", name, meta.Type())
+		}
+		data = meta.Endpoints[meta.Type()]
+	}
+
+	if err := s.Update(name, description, data); err != nil {
+		return err
+	}
+	fmt.Println(name)
+	return nil
+}
@@ -22,11 +22,14 @@ import (
 	"io/ioutil"
 	"os"
 
+	cliopts "github.com/docker/cli/opts"
+	"github.com/hashicorp/go-multierror"
 	"github.com/spf13/cobra"
 
 	"github.com/docker/compose-cli/api/client"
 	"github.com/docker/compose-cli/api/secrets"
-	"github.com/docker/compose-cli/formatter"
+	"github.com/docker/compose-cli/cli/formatter"
+	formatter2 "github.com/docker/compose-cli/formatter"
 )
 
 // SecretCommand manage secrets
@@ -45,7 +48,13 @@ func SecretCommand() *cobra.Command {
 	return cmd
 }
 
+type createSecretOptions struct {
+	description string
+	labels      []string
+}
+
 func createSecret() *cobra.Command {
+	opts := createSecretOptions{}
 	cmd := &cobra.Command{
 		Use:   "create [OPTIONS] SECRET [file|-]",
 		Short: "Creates a secret.",
@@ -79,6 +88,10 @@ func createSecret() *cobra.Command {
 			}
 			name := args[0]
 			secret := secrets.NewSecret(name, content)
+			secret.Description = opts.description
+			if len(opts.labels) > 0 {
+				secret.Labels = cliopts.ConvertKVStringsToMap(opts.labels)
+			}
 			id, err := c.SecretsService().CreateSecret(cmd.Context(), secret)
 			if err != nil {
 				return err
@@ -87,12 +100,14 @@ func createSecret() *cobra.Command {
 			return nil
 		},
 	}
+	cmd.Flags().StringVar(&opts.description, "description", "", "Secret description")
+	cmd.Flags().StringArrayVarP(&opts.labels, "label", "l", []string{}, "Secret labels")
 	return cmd
 }
 
 func inspectSecret() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "inspect ID",
+		Use:   "inspect ID|NAME",
 		Short: "Displays secret details",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -141,12 +156,7 @@ func listSecrets() *cobra.Command {
 				}
 				return nil
 			}
-			view := viewFromSecretList(secretsList)
-			return formatter.Print(view, opts.format, os.Stdout, func(w io.Writer) {
-				for _, secret := range view {
-					_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", secret.ID, secret.Name, secret.Description)
-				}
-			}, "ID", "NAME")
+			return printList(os.Stdout, secretsList, opts.format)
 		},
 	}
 	cmd.Flags().StringVar(&opts.format, "format", "", "Format the output. Values: [pretty | json]. (Default: pretty)")
@@ -154,6 +164,15 @@ func listSecrets() *cobra.Command {
 	return cmd
 }
 
+func printList(out io.Writer, secretList []secrets.Secret, format string) error {
+	view := viewFromSecretList(secretList)
+	return formatter2.Print(view, format, out, func(w io.Writer) {
+		for _, secret := range view {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", secret.ID, secret.Name, secret.Description)
+		}
+	}, "ID", "NAME", "DESCRIPTION")
+}
+
 type secretView struct {
 	ID          string
 	Name        string
@@ -164,8 +183,9 @@ func viewFromSecretList(secretList []secrets.Secret) []secretView {
 	retList := make([]secretView, len(secretList))
 	for i, s := range secretList {
 		retList[i] = secretView{
-			ID:   s.ID,
-			Name: s.Name,
+			ID:          s.ID,
+			Name:        s.Name,
+			Description: s.Description,
 		}
 	}
 	return retList
@@ -178,16 +198,26 @@ type deleteSecretOptions struct {
 func deleteSecret() *cobra.Command {
 	opts := deleteSecretOptions{}
 	cmd := &cobra.Command{
-		Use:     "delete NAME",
+		Use:     "delete NAME...",
 		Aliases: []string{"rm", "remove"},
-		Short:   "Removes a secret.",
-		Args:    cobra.ExactArgs(1),
+		Short:   "Removes one or more secrets.",
+		Args:    cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			c, err := client.New(cmd.Context())
 			if err != nil {
 				return err
 			}
-			return c.SecretsService().DeleteSecret(cmd.Context(), args[0], opts.recover)
+			var errs *multierror.Error
+			for _, name := range args {
+				err := c.SecretsService().DeleteSecret(cmd.Context(), name, opts.recover)
+				if err != nil {
+					errs = multierror.Append(errs, err)
+					continue
+				}
+				fmt.Println(name)
+			}
+			formatter.SetMultiErrorFormat(errs)
+			return errs.ErrorOrNil()
 		},
 	}
 	cmd.Flags().BoolVar(&opts.recover, "recover", false, "Enable recovery.")
@@ -94,6 +94,8 @@ func createVolume(ctype string) *cobra.Command {
 		aciOpts := aci.VolumeCreateOptions{}
 		cmd.Flags().StringVar(&aciOpts.Account, "storage-account", "", "Storage account name")
 		_ = cmd.MarkFlagRequired("storage-account")
+		cmd.Flags().StringVar(&aciOpts.SKU, "sku", "", "SKU of the storage account, if it is created (Standard_LRS|Standard_GRS|Standard_ZRS|Premium_LRS|...)")
+		cmd.Flags().Int32Var(&aciOpts.Quota, "quota", 0, "Maximum size of the file share in GiB")
 		opts = &aciOpts
 	case store.EcsContextType:
 		ecsOpts := ecs.VolumeCreateOptions{}
@@ -101,6 +103,10 @@ func createVolume(ctype string) *cobra.Command {
 		cmd.Flags().StringVar(&ecsOpts.PerformanceMode, "performance-mode", "", "performance mode of the file system. (generalPurpose|maxIO)")
 		cmd.Flags().Float64Var(&ecsOpts.ProvisionedThroughputInMibps, "provisioned-throughput", 0, "throughput in MiB/s (1-1024)")
 		cmd.Flags().StringVar(&ecsOpts.ThroughputMode, "throughput-mode", "", "throughput mode (bursting|provisioned)")
+		cmd.Flags().StringVar(&ecsOpts.UID, "uid", "", "POSIX user ID applied to file system requests made through the access point")
+		cmd.Flags().StringVar(&ecsOpts.GID, "gid", "", "POSIX group ID applied to file system requests made through the access point")
+		cmd.Flags().StringVar(&ecsOpts.RootDirectory, "root-directory", "", "directory exposed as the root of the file system through the access point")
+		cmd.Flags().StringVar(&ecsOpts.Permissions, "permissions", "", "POSIX permissions applied to the root directory, when created")
 		opts = &ecsOpts
 	}
 	return cmd
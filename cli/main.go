@@ -194,6 +194,10 @@ func main() {
 			metrics.Track(ctype, os.Args[1:], metrics.CanceledStatus)
 			os.Exit(130)
 		}
+		if code, ok := errdefs.ExitCode(err); ok {
+			metrics.Track(ctype, os.Args[1:], metrics.FailureStatus)
+			os.Exit(code)
+		}
 		if ctype == store.AwsContextType {
 			exit(currentContext, errors.Errorf(`%q context type has been renamed. Recreate the context by running:
 $ docker context create %s <name>`, cc.Type(), store.EcsContextType), ctype)
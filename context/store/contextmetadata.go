@@ -44,15 +44,25 @@ type ContextMetadata struct {
 
 // AciContext is the context for the ACI backend
 type AciContext struct {
-	SubscriptionID string `json:",omitempty"`
-	Location       string `json:",omitempty"`
-	ResourceGroup  string `json:",omitempty"`
+	SubscriptionID string            `json:",omitempty"`
+	Location       string            `json:",omitempty"`
+	ResourceGroup  string            `json:",omitempty"`
+	Tags           map[string]string `json:",omitempty"`
+	VNet           string            `json:",omitempty"`
+	Subnet         string            `json:",omitempty"`
 }
 
 // EcsContext is the context for the AWS backend
 type EcsContext struct {
-	Profile string `json:",omitempty"`
-	Region  string `json:",omitempty"`
+	Profile               string            `json:",omitempty"`
+	Region                string            `json:",omitempty"`
+	CredentialsFromEnv    bool              `json:",omitempty"`
+	CredentialsInKeychain bool              `json:",omitempty"`
+	Endpoints             map[string]string `json:",omitempty"`
+	Cluster               string            `json:",omitempty"`
+	VPC                   string            `json:",omitempty"`
+	Subnets               []string          `json:",omitempty"`
+	Tags                  map[string]string `json:",omitempty"`
 }
 
 // AwsContext is the context for the ecs plugin
@@ -91,6 +91,9 @@ type Store interface {
 	// Create creates a new context, it returns an error if a context with the
 	// same name exists already.
 	Create(name string, contextType string, description string, data interface{}) error
+	// Update replaces the description and endpoint data of an existing
+	// context, keeping its type and other metadata
+	Update(name string, description string, data interface{}) error
 	// List returns the list of created contexts
 	List() ([]*DockerContext, error)
 	// Remove removes a context by name from the context store
@@ -255,6 +258,27 @@ func (s *store) Create(name string, contextType string, description string, data
 	return ioutil.WriteFile(filepath.Join(metaDir, metaFile), bytes, 0644)
 }
 
+func (s *store) Update(name string, description string, data interface{}) error {
+	if name == DefaultContextName {
+		return errors.Wrap(errdefs.ErrForbidden, objectName(name))
+	}
+	meta, err := s.Get(name)
+	if err != nil {
+		return err
+	}
+	meta.Metadata.Description = description
+	meta.Endpoints[dockerEndpointKey] = data
+	meta.Endpoints[meta.Type()] = data
+
+	bytes, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+
+	metaDir := filepath.Join(s.root, contextsDir, metadataDir, contextDirOf(name))
+	return ioutil.WriteFile(filepath.Join(metaDir, metaFile), bytes, 0644)
+}
+
 func (s *store) List() ([]*DockerContext, error) {
 	root := filepath.Join(s.root, contextsDir, metadataDir)
 	c, err := ioutil.ReadDir(root)
@@ -119,3 +119,38 @@ func TestRemove(t *testing.T) {
 	assert.Assert(t, cmp.Nil(meta))
 
 }
+
+func TestUpdate(t *testing.T) {
+	s := testStore(t)
+	err := s.Create("ecs", EcsContextType, "description", EcsContext{
+		Profile: "default",
+		Region:  "eu-west-1",
+	})
+	assert.NilError(t, err)
+
+	err = s.Update("ecs", "new description", EcsContext{
+		Profile: "other",
+		Region:  "eu-west-3",
+	})
+	assert.NilError(t, err)
+
+	meta, err := s.Get("ecs")
+	assert.NilError(t, err)
+	assert.Equal(t, meta.Type(), EcsContextType)
+	assert.Equal(t, meta.Metadata.Description, "new description")
+
+	var ctx EcsContext
+	err = s.GetEndpoint("ecs", &ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, ctx.Profile, "other")
+	assert.Equal(t, ctx.Region, "eu-west-3")
+}
+
+func TestUpdateNotFound(t *testing.T) {
+	s := testStore(t)
+	err := s.Update("notfound", "description", EcsContext{})
+	assert.Assert(t, errdefs.IsNotFoundError(err))
+
+	err = s.Update(DefaultContextName, "description", EcsContext{})
+	assert.Assert(t, errdefs.IsForbiddenError(err))
+}
@@ -0,0 +1,217 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/cloudformation/cloudwatch"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+const (
+	alarmCPU     = "cpu"
+	alarmMemory  = "memory"
+	alarmHTTP5XX = "http_5xx"
+	// alarmHTTP5XXGreen watches 5xx responses of the green target group, which serves new tasks during blue/green
+	// deployments
+	alarmHTTP5XXGreen = "http_5xx_green"
+
+	defaultAlarmPeriod = 300
+)
+
+// alarmConfig is a CloudWatch alarm set by x-aws-alarms, triggered when metric exceeds threshold over
+// evaluation_periods periods of period seconds
+type alarmConfig struct {
+	Threshold         float64 `json:"threshold,omitempty"`
+	Period            int     `json:"period,omitempty"`
+	EvaluationPeriods int     `json:"evaluation_periods,omitempty"`
+	Topic             string  `json:"topic,omitempty"`
+}
+
+// alarmsConfig is x-aws-alarms, topic being the default SNS topic notified by alarms
+type alarmsConfig struct {
+	Topic   string       `json:"topic,omitempty"`
+	CPU     *alarmConfig `json:"cpu,omitempty"`
+	Memory  *alarmConfig `json:"memory,omitempty"`
+	HTTP5XX *alarmConfig `json:"http_5xx,omitempty"`
+}
+
+// alarmMetric describes the CloudWatch metric an alarm watches
+type alarmMetric struct {
+	namespace  string
+	name       string
+	statistic  string
+	dimensions []cloudwatch.Alarm_Dimension
+}
+
+func getAlarmsConfig(service types.ServiceConfig) (*alarmsConfig, error) {
+	v, ok := service.Extensions[extensionAlarms]
+	if !ok {
+		return nil, nil
+	}
+	marshalled, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var config alarmsConfig
+	err = json.Unmarshal(marshalled, &config)
+	if err != nil {
+		return nil, errors.Wrapf(errdefs.ErrParsingFailed, "service %q: invalid %s: %s", service.Name, extensionAlarms, err)
+	}
+	return &config, nil
+}
+
+// createAlarms adds CloudWatch alarms set by x-aws-alarms on service CPU and memory utilization, and on HTTP 5xx
+// responses of its targets
+func (b *ecsAPIService) createAlarms(project *types.Project, service types.ServiceConfig, template *cloudformation.Template, resources awsResources) error {
+	config, err := getAlarmsConfig(service)
+	if err != nil || config == nil {
+		return err
+	}
+	if isScheduled(project, service.Name) {
+		return fmt.Errorf("service %q: scheduled tasks can't use %s", service.Name, extensionAlarms)
+	}
+
+	serviceDimensions := []cloudwatch.Alarm_Dimension{
+		{Name: "ClusterName", Value: resources.cluster.ID()},
+		{Name: "ServiceName", Value: cloudformation.GetAtt(serviceResourceName(service.Name), "Name")},
+	}
+	alarms := map[string]*alarmConfig{
+		alarmCPU:     config.CPU,
+		alarmMemory:  config.Memory,
+		alarmHTTP5XX: config.HTTP5XX,
+	}
+	metrics := map[string]alarmMetric{
+		alarmCPU:    {namespace: "AWS/ECS", name: "CPUUtilization", statistic: "Average", dimensions: serviceDimensions},
+		alarmMemory: {namespace: "AWS/ECS", name: "MemoryUtilization", statistic: "Average", dimensions: serviceDimensions},
+	}
+	if config.HTTP5XX != nil {
+		if len(service.Ports) == 0 || !exposedByApplicationLoadBalancer(resources, service) {
+			return fmt.Errorf("service %q: %s %s alarm requires service to be exposed by an application load balancer", service.Name, extensionAlarms, alarmHTTP5XX)
+		}
+		loadBalancer, _ := resources.portLoadBalancer(service, service.Ports[0])
+		metrics[alarmHTTP5XX] = alarmMetric{
+			namespace: "AWS/ApplicationELB",
+			name:      "HTTPCode_Target_5XX_Count",
+			statistic: "Sum",
+			dimensions: []cloudwatch.Alarm_Dimension{
+				{Name: "LoadBalancer", Value: loadBalancerFullName(loadBalancer)},
+				{Name: "TargetGroup", Value: cloudformation.GetAtt(targetGroupResourceName(service, service.Ports[0]), "TargetGroupFullName")},
+			},
+		}
+		if _, ok := template.Resources[targetGroupResourceName(service, service.Ports[0])+"Green"]; ok {
+			alarms[alarmHTTP5XXGreen] = config.HTTP5XX
+			metrics[alarmHTTP5XXGreen] = alarmMetric{
+				namespace: "AWS/ApplicationELB",
+				name:      "HTTPCode_Target_5XX_Count",
+				statistic: "Sum",
+				dimensions: []cloudwatch.Alarm_Dimension{
+					{Name: "LoadBalancer", Value: loadBalancerFullName(loadBalancer)},
+					{Name: "TargetGroup", Value: cloudformation.GetAtt(targetGroupResourceName(service, service.Ports[0])+"Green", "TargetGroupFullName")},
+				},
+			}
+		}
+	}
+
+	var names []string
+	for name, alarm := range alarms {
+		if alarm != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		alarm := alarms[name]
+		if alarm.Threshold <= 0 {
+			return fmt.Errorf("service %q: %s %s alarm requires a threshold", service.Name, extensionAlarms, name)
+		}
+		period := alarm.Period
+		if period == 0 {
+			period = defaultAlarmPeriod
+		}
+		if period < 60 || period%60 != 0 {
+			return fmt.Errorf("service %q: %s %s alarm period must be a multiple of 60 seconds", service.Name, extensionAlarms, name)
+		}
+		evaluationPeriods := alarm.EvaluationPeriods
+		if evaluationPeriods == 0 {
+			evaluationPeriods = 1
+		}
+		topic := alarm.Topic
+		if topic == "" {
+			topic = config.Topic
+		}
+		var actions []string
+		if topic != "" {
+			actions = []string{topic}
+		}
+		metric := metrics[name]
+		template.Resources[alarmResourceName(service.Name, name)] = &cloudwatch.Alarm{
+			AlarmName:          alarmName(project, service, name),
+			AlarmDescription:   fmt.Sprintf("%s %s of service %s exceeds %g", metric.statistic, metric.name, service.Name, alarm.Threshold),
+			Namespace:          metric.namespace,
+			MetricName:         metric.name,
+			Statistic:          metric.statistic,
+			Dimensions:         metric.dimensions,
+			ComparisonOperator: "GreaterThanThreshold",
+			Threshold:          alarm.Threshold,
+			Period:             period,
+			EvaluationPeriods:  evaluationPeriods,
+			TreatMissingData:   "notBreaching",
+			AlarmActions:       actions,
+			OKActions:          actions,
+		}
+	}
+	return nil
+}
+
+// alarmName is the name of a service alarm in CloudWatch, which x-aws-blue_green alarms can refer to
+func alarmName(project *types.Project, service types.ServiceConfig, alarm string) string {
+	return fmt.Sprintf("%s-%s-%s", project.Name, service.Name, alarm)
+}
+
+// serviceAlarmNames returns the names of the alarms x-aws-alarms creates for service
+func serviceAlarmNames(project *types.Project, service types.ServiceConfig) ([]string, error) {
+	config, err := getAlarmsConfig(service)
+	if err != nil || config == nil {
+		return nil, err
+	}
+	var names []string
+	if config.CPU != nil {
+		names = append(names, alarmName(project, service, alarmCPU))
+	}
+	if config.Memory != nil {
+		names = append(names, alarmName(project, service, alarmMemory))
+	}
+	if config.HTTP5XX != nil {
+		names = append(names, alarmName(project, service, alarmHTTP5XX))
+		if _, ok := service.Extensions[extensionBlueGreen]; ok {
+			names = append(names, alarmName(project, service, alarmHTTP5XXGreen))
+		}
+	}
+	return names, nil
+}
+
+func alarmResourceName(service string, alarm string) string {
+	return fmt.Sprintf("%s%sAlarm", normalizeResourceName(service), normalizeResourceName(alarm))
+}
@@ -0,0 +1,99 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/cloudformation/cloudwatch"
+	"gotest.tools/v3/assert"
+)
+
+func TestAlarms(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  web:
+    image: nginx
+    ports:
+      - 80:80
+    x-aws-alarms:
+      topic: arn:aws:sns:eu-west-3:123456789012:ops
+      cpu:
+        threshold: 80
+      http_5xx:
+        threshold: 10
+        period: 60
+        evaluation_periods: 3
+        topic: arn:aws:sns:eu-west-3:123456789012:oncall
+`, useDefaultVPC)
+
+	cpu := template.Resources["WebCpuAlarm"].(*cloudwatch.Alarm)
+	assert.Equal(t, cpu.AlarmName, "TestAlarms-web-cpu")
+	assert.Equal(t, cpu.Namespace, "AWS/ECS")
+	assert.Equal(t, cpu.MetricName, "CPUUtilization")
+	assert.Equal(t, cpu.Threshold, float64(80))
+	assert.Equal(t, cpu.Period, 300)
+	assert.Equal(t, cpu.EvaluationPeriods, 1)
+	assert.DeepEqual(t, cpu.AlarmActions, []string{"arn:aws:sns:eu-west-3:123456789012:ops"})
+	assert.DeepEqual(t, cpu.Dimensions, []cloudwatch.Alarm_Dimension{
+		{Name: "ClusterName", Value: cloudformation.Ref("Cluster")},
+		{Name: "ServiceName", Value: cloudformation.GetAtt("WebService", "Name")},
+	})
+
+	http5xx := template.Resources["WebHttp5xxAlarm"].(*cloudwatch.Alarm)
+	assert.Equal(t, http5xx.MetricName, "HTTPCode_Target_5XX_Count")
+	assert.Equal(t, http5xx.Statistic, "Sum")
+	assert.Equal(t, http5xx.Period, 60)
+	assert.Equal(t, http5xx.EvaluationPeriods, 3)
+	assert.DeepEqual(t, http5xx.AlarmActions, []string{"arn:aws:sns:eu-west-3:123456789012:oncall"})
+
+	_, ok := template.Resources["WebMemoryAlarm"]
+	assert.Check(t, !ok)
+}
+
+func TestAlarmsConfig(t *testing.T) {
+	tests := []struct {
+		yaml string
+		err  string
+	}{
+		{
+			yaml: `x-aws-alarms: {memory: {}}`,
+			err:  "memory alarm requires a threshold",
+		},
+		{
+			yaml: `x-aws-alarms: {cpu: {threshold: 80, period: 90}}`,
+			err:  "cpu alarm period must be a multiple of 60 seconds",
+		},
+		{
+			yaml: `x-aws-alarms: {http_5xx: {threshold: 10}}`,
+			err:  "http_5xx alarm requires service to be exposed by an application load balancer",
+		},
+	}
+	for _, test := range tests {
+		project := loadConfig(t, `
+services:
+  worker:
+    image: worker
+    `+test.yaml)
+		backend := &ecsAPIService{}
+		err := backend.createAlarms(project, project.Services[0], cloudformation.NewTemplate(), awsResources{
+			cluster: cloudformationResource{logicalName: "Cluster"},
+		})
+		assert.ErrorContains(t, err, test.err)
+	}
+}
@@ -0,0 +1,289 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	ecsapi "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/cloudformation/appmesh"
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	envoyContainerName = "envoy"
+	envoyImage         = "public.ecr.aws/appmesh/aws-appmesh-envoy:v1.15.1.0-prod"
+	envoyUID           = "1337"
+	envoyIngressPort   = "15000"
+	envoyEgressPort    = "15001"
+)
+
+// appMeshConfig is the x-aws-appmesh project configuration, set to true to use defaults
+type appMeshConfig struct {
+	// Name of the mesh, project name by default
+	Name string `json:"name,omitempty"`
+	// Certificate is the ARN of the ACM certificate virtual nodes serve TLS with, for *.<namespace>. TLS is one-way, as
+	// App Mesh doesn't accept ACM certificates as client certificates for mutual TLS.
+	Certificate string `json:"certificate,omitempty"`
+	// CertificateAuthority is the ARN of the ACM Private CA which issued certificate, clients validate it with
+	CertificateAuthority string `json:"certificate_authority,omitempty"`
+}
+
+func getAppMeshConfig(project *types.Project) (*appMeshConfig, error) {
+	v, ok := project.Extensions[extensionAppMesh]
+	if !ok {
+		return nil, nil
+	}
+	config := appMeshConfig{}
+	if enabled, ok := v.(bool); ok {
+		if !enabled {
+			return nil, nil
+		}
+	} else {
+		marshalled, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal(marshalled, &config)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be true or a mesh configuration: %w", extensionAppMesh, err)
+		}
+	}
+	if config.Name == "" {
+		config.Name = project.Name
+	}
+	if (config.Certificate == "") != (config.CertificateAuthority == "") {
+		return nil, fmt.Errorf("%s: TLS requires both certificate and certificate_authority", extensionAppMesh)
+	}
+	return &config, nil
+}
+
+// inMesh tells if service joins the mesh. Only long-running services exposing ports get a virtual node.
+func inMesh(project *types.Project, service types.ServiceConfig) bool {
+	_, ok := project.Extensions[extensionAppMesh]
+	if !ok || len(service.Ports) == 0 || isScheduled(project, service.Name) {
+		return false
+	}
+	config, err := getAppMeshConfig(project)
+	return err == nil && config != nil
+}
+
+func virtualNodeName(service string) string {
+	return fmt.Sprintf("%sVirtualNode", normalizeResourceName(service))
+}
+
+// createAppMesh creates the mesh, and for each service a virtual node behind a virtual router, exposed to other
+// services as virtual service <service>.<namespace>
+func (b *ecsAPIService) createAppMesh(project *types.Project, template *cloudformation.Template) error {
+	config, err := getAppMeshConfig(project)
+	if err != nil || config == nil {
+		return err
+	}
+	template.Resources["Mesh"] = &appmesh.Mesh{
+		MeshName: config.Name,
+		Spec: &appmesh.Mesh_MeshSpec{
+			EgressFilter: &appmesh.Mesh_EgressFilter{
+				Type: "ALLOW_ALL",
+			},
+		},
+		Tags: projectTags(project),
+	}
+	meshName := cloudformation.GetAtt("Mesh", "MeshName")
+	namespace := cloudMapNamespace(project)
+
+	var meshed []types.ServiceConfig
+	for _, service := range project.Services {
+		if inMesh(project, service) {
+			meshed = append(meshed, service)
+		}
+	}
+
+	for _, service := range meshed {
+		if len(service.Ports) > 1 {
+			logrus.Warnf("service %q: virtual node only listens on port %d", service.Name, service.Ports[0].Target)
+		}
+		port := int(service.Ports[0].Target)
+
+		var backends []appmesh.VirtualNode_Backend
+		for _, s := range meshed {
+			if s.Name == service.Name {
+				continue
+			}
+			backends = append(backends, appmesh.VirtualNode_Backend{
+				VirtualService: &appmesh.VirtualNode_VirtualServiceBackend{
+					VirtualServiceName: fmt.Sprintf("%s.%s", s.Name, namespace),
+				},
+			})
+		}
+
+		listener := appmesh.VirtualNode_Listener{
+			PortMapping: &appmesh.VirtualNode_PortMapping{
+				Port:     port,
+				Protocol: "tcp",
+			},
+		}
+		var backendDefaults *appmesh.VirtualNode_BackendDefaults
+		if config.Certificate != "" {
+			listener.TLS = &appmesh.VirtualNode_ListenerTls{
+				Mode: "STRICT",
+				Certificate: &appmesh.VirtualNode_ListenerTlsCertificate{
+					ACM: &appmesh.VirtualNode_ListenerTlsAcmCertificate{
+						CertificateArn: config.Certificate,
+					},
+				},
+			}
+			backendDefaults = &appmesh.VirtualNode_BackendDefaults{
+				ClientPolicy: &appmesh.VirtualNode_ClientPolicy{
+					TLS: &appmesh.VirtualNode_ClientPolicyTls{
+						Enforce: true,
+						Validation: &appmesh.VirtualNode_TlsValidationContext{
+							Trust: &appmesh.VirtualNode_TlsValidationContextTrust{
+								ACM: &appmesh.VirtualNode_TlsValidationContextAcmTrust{
+									CertificateAuthorityArns: []string{config.CertificateAuthority},
+								},
+							},
+						},
+					},
+				},
+			}
+		}
+
+		node := virtualNodeName(service.Name)
+		template.Resources[node] = &appmesh.VirtualNode{
+			MeshName:        meshName,
+			VirtualNodeName: service.Name,
+			Spec: &appmesh.VirtualNode_VirtualNodeSpec{
+				BackendDefaults: backendDefaults,
+				Backends:        backends,
+				Listeners:       []appmesh.VirtualNode_Listener{listener},
+				ServiceDiscovery: &appmesh.VirtualNode_ServiceDiscovery{
+					AWSCloudMap: &appmesh.VirtualNode_AwsCloudMapServiceDiscovery{
+						NamespaceName: namespace,
+						ServiceName:   service.Name,
+					},
+				},
+			},
+			Tags: serviceTags(project, service),
+		}
+
+		router := fmt.Sprintf("%sVirtualRouter", normalizeResourceName(service.Name))
+		template.Resources[router] = &appmesh.VirtualRouter{
+			MeshName:          meshName,
+			VirtualRouterName: service.Name,
+			Spec: &appmesh.VirtualRouter_VirtualRouterSpec{
+				Listeners: []appmesh.VirtualRouter_VirtualRouterListener{
+					{
+						PortMapping: &appmesh.VirtualRouter_PortMapping{
+							Port:     port,
+							Protocol: "tcp",
+						},
+					},
+				},
+			},
+			Tags: serviceTags(project, service),
+		}
+
+		template.Resources[fmt.Sprintf("%sRoute", normalizeResourceName(service.Name))] = &appmesh.Route{
+			MeshName:          meshName,
+			RouteName:         service.Name,
+			VirtualRouterName: cloudformation.GetAtt(router, "VirtualRouterName"),
+			Spec: &appmesh.Route_RouteSpec{
+				TcpRoute: &appmesh.Route_TcpRoute{
+					Action: &appmesh.Route_TcpRouteAction{
+						WeightedTargets: []appmesh.Route_WeightedTarget{
+							{
+								VirtualNode: cloudformation.GetAtt(node, "VirtualNodeName"),
+								Weight:      100,
+							},
+						},
+					},
+				},
+			},
+			Tags: serviceTags(project, service),
+		}
+
+		template.Resources[fmt.Sprintf("%sVirtualService", normalizeResourceName(service.Name))] = &appmesh.VirtualService{
+			MeshName:           meshName,
+			VirtualServiceName: fmt.Sprintf("%s.%s", service.Name, namespace),
+			Spec: &appmesh.VirtualService_VirtualServiceSpec{
+				Provider: &appmesh.VirtualService_VirtualServiceProvider{
+					VirtualRouter: &appmesh.VirtualService_VirtualRouterServiceProvider{
+						VirtualRouterName: cloudformation.GetAtt(router, "VirtualRouterName"),
+					},
+				},
+			},
+			Tags: serviceTags(project, service),
+		}
+	}
+	return nil
+}
+
+// createEnvoyContainer returns the Envoy sidecar proxying service traffic within the mesh, and the task proxy
+// configuration redirecting traffic to it
+func createEnvoyContainer(service types.ServiceConfig, logConfiguration *ecs.TaskDefinition_LogConfiguration) (ecs.TaskDefinition_ContainerDefinition, *ecs.TaskDefinition_ProxyConfiguration) {
+	envoy := ecs.TaskDefinition_ContainerDefinition{
+		Name:      envoyContainerName,
+		Image:     envoyImage,
+		Essential: true,
+		User:      envoyUID,
+		Environment: []ecs.TaskDefinition_KeyValuePair{
+			{
+				Name:  "APPMESH_RESOURCE_ARN",
+				Value: cloudformation.Ref(virtualNodeName(service.Name)),
+			},
+		},
+		HealthCheck: &ecs.TaskDefinition_HealthCheck{
+			Command:     []string{"CMD-SHELL", "curl -s http://localhost:9901/server_info | grep state | grep -q LIVE"},
+			Interval:    5,
+			Retries:     3,
+			StartPeriod: 10,
+			Timeout:     2,
+		},
+		LogConfiguration: logConfiguration,
+	}
+
+	var ports []string
+	for _, p := range service.Ports {
+		ports = append(ports, fmt.Sprint(p.Target))
+	}
+	proxy := &ecs.TaskDefinition_ProxyConfiguration{
+		ContainerName: envoyContainerName,
+		Type:          "APPMESH",
+		ProxyConfigurationProperties: []ecs.TaskDefinition_KeyValuePair{
+			{Name: "IgnoredUID", Value: envoyUID},
+			{Name: "ProxyIngressPort", Value: envoyIngressPort},
+			{Name: "ProxyEgressPort", Value: envoyEgressPort},
+			{Name: "AppPorts", Value: strings.Join(ports, ",")},
+			// ECS task metadata and credentials endpoints
+			{Name: "EgressIgnoredIPs", Value: "169.254.170.2,169.254.169.254"},
+		},
+	}
+	return envoy, proxy
+}
+
+// envoyDependency makes a container wait for Envoy to be ready, as task traffic is redirected to it
+func envoyDependency() ecs.TaskDefinition_ContainerDependency {
+	return ecs.TaskDefinition_ContainerDependency{
+		Condition:     ecsapi.ContainerConditionHealthy,
+		ContainerName: envoyContainerName,
+	}
+}
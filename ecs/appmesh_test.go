@@ -0,0 +1,90 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/cloudformation/appmesh"
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"github.com/awslabs/goformation/v4/cloudformation/iam"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestAppMesh(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  web:
+    image: nginx
+    ports:
+      - 80:80
+  api:
+    image: myorg/api
+    ports:
+      - 8080:8080
+  worker:
+    image: myorg/worker
+x-aws-cloudmap_namespace: corp.internal
+x-aws-appmesh:
+  name: mymesh
+  certificate: arn:aws:acm:eu-west-3:012345678910:certificate/123abc
+  certificate_authority: arn:aws:acm-pca:eu-west-3:012345678910:certificate-authority/456def
+`, useDefaultVPC)
+	mesh := template.Resources["Mesh"].(*appmesh.Mesh)
+	assert.Equal(t, mesh.MeshName, "mymesh")
+
+	node := template.Resources["WebVirtualNode"].(*appmesh.VirtualNode)
+	assert.Equal(t, node.Spec.Listeners[0].PortMapping.Port, 80)
+	assert.Equal(t, node.Spec.Listeners[0].TLS.Mode, "STRICT")
+	assert.Equal(t, node.Spec.ServiceDiscovery.AWSCloudMap.ServiceName, "web")
+	assert.Equal(t, len(node.Spec.Backends), 1)
+	assert.Equal(t, node.Spec.Backends[0].VirtualService.VirtualServiceName, "api.corp.internal")
+
+	service := template.Resources["ApiVirtualService"].(*appmesh.VirtualService)
+	assert.Equal(t, service.VirtualServiceName, "api.corp.internal")
+	assert.Equal(t, service.Spec.Provider.VirtualRouter.VirtualRouterName, cloudformation.GetAtt("ApiVirtualRouter", "VirtualRouterName"))
+
+	_, ok := template.Resources["WorkerVirtualNode"]
+	assert.Check(t, !ok)
+
+	def := template.Resources["WebTaskDefinition"].(*ecs.TaskDefinition)
+	assert.Equal(t, def.ProxyConfiguration.ContainerName, "envoy")
+	assert.Check(t, is.Contains(def.ProxyConfiguration.ProxyConfigurationProperties, ecs.TaskDefinition_KeyValuePair{Name: "AppPorts", Value: "80"}))
+	var envoy *ecs.TaskDefinition_ContainerDefinition
+	for i, c := range def.ContainerDefinitions {
+		if c.Name == "envoy" {
+			envoy = &def.ContainerDefinitions[i]
+		}
+	}
+	assert.Assert(t, envoy != nil)
+	assert.Equal(t, envoy.Environment[0].Value, cloudformation.Ref("WebVirtualNode"))
+	main := getMainContainer(def, t)
+	assert.Check(t, is.Contains(main.DependsOnProp, ecs.TaskDefinition_ContainerDependency{
+		Condition:     "HEALTHY",
+		ContainerName: "envoy",
+	}))
+
+	role := template.Resources["WebTaskRole"].(*iam.Role)
+	assert.Equal(t, len(role.Policies), 1)
+	policy := role.Policies[0].PolicyDocument.(PolicyDocument)
+	assert.Equal(t, len(policy.Statement), 3)
+
+	worker := template.Resources["WorkerTaskDefinition"].(*ecs.TaskDefinition)
+	assert.Check(t, worker.ProxyConfiguration == nil)
+}
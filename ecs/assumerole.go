@@ -0,0 +1,63 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+type assumeRoleKey struct{}
+
+// AssumeRole is an IAM role assumed with context credentials, e.g. to deploy in another account
+type AssumeRole struct {
+	ARN string
+	// ExternalID is required by roles which trust another account on this condition
+	ExternalID string
+}
+
+// WithAssumeRole makes backend created with ctx assume role, using credentials of ECS context as source credentials
+func WithAssumeRole(ctx context.Context, role AssumeRole) context.Context {
+	return context.WithValue(ctx, assumeRoleKey{}, role)
+}
+
+func getAssumeRole(ctx context.Context) AssumeRole {
+	role, _ := ctx.Value(assumeRoleKey{}).(AssumeRole)
+	return role
+}
+
+// assumeRole replaces session credentials by temporary credentials of role, refreshed as they expire
+func assumeRole(sess *session.Session, role AssumeRole) error {
+	a, err := arn.Parse(role.ARN)
+	if err != nil || a.Service != "iam" {
+		return fmt.Errorf("invalid role ARN %q", role.ARN)
+	}
+	// the STS client is configured with current session credentials, used as source credentials
+	sess.Config.Credentials = stscreds.NewCredentials(sess, role.ARN, func(p *stscreds.AssumeRoleProvider) {
+		p.RoleSessionName = fmt.Sprintf("docker-compose-cli-%d", time.Now().UnixNano())
+		if role.ExternalID != "" {
+			p.ExternalID = aws.String(role.ExternalID)
+		}
+	})
+	return nil
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	applicationautoscaling2 "github.com/aws/aws-sdk-go/service/applicationautoscaling"
+	"github.com/aws/aws-sdk-go/service/elbv2"
 	"github.com/awslabs/goformation/v4/cloudformation"
 	"github.com/awslabs/goformation/v4/cloudformation/applicationautoscaling"
 	"github.com/awslabs/goformation/v4/cloudformation/iam"
@@ -28,10 +29,11 @@ import (
 )
 
 type autoscalingConfig struct {
-	Memory int `json:"memory,omitempty"`
-	CPU    int `json:"cpu,omitempty"`
-	Min    int `json:"min,omitempty"`
-	Max    int `json:"max,omitempty"`
+	Memory   int `json:"memory,omitempty"`
+	CPU      int `json:"cpu,omitempty"`
+	Requests int `json:"requests,omitempty"`
+	Min      int `json:"min,omitempty"`
+	Max      int `json:"max,omitempty"`
 }
 
 func (b *ecsAPIService) createAutoscalingPolicy(project *types.Project, resources awsResources, template *cloudformation.Template, service types.ServiceConfig) error {
@@ -53,8 +55,17 @@ func (b *ecsAPIService) createAutoscalingPolicy(project *types.Project, resource
 		return err
 	}
 
-	if config.Memory != 0 && config.CPU != 0 {
-		return fmt.Errorf("%s can't be set with both cpu and memory targets", extensionAutoScaling)
+	targets := 0
+	for _, v := range []int{config.Memory, config.CPU, config.Requests} {
+		if v != 0 {
+			targets++
+		}
+	}
+	if targets > 1 {
+		return fmt.Errorf("%s can only set one of cpu, memory or requests targets", extensionAutoScaling)
+	}
+	if config.Requests != 0 && (len(service.Ports) == 0 || !exposedByApplicationLoadBalancer(resources, service)) {
+		return fmt.Errorf("%s requests target requires service %q to be exposed by an application load balancer", extensionAutoScaling, service.Name)
 	}
 	if config.Max == 0 {
 		return fmt.Errorf("%s MUST define max replicas", extensionAutoScaling)
@@ -103,11 +114,22 @@ func (b *ecsAPIService) createAutoscalingPolicy(project *types.Project, resource
 	var (
 		metric        = applicationautoscaling2.MetricTypeEcsserviceAverageCpuutilization
 		targetPercent = config.CPU
+		resourceLabel string
 	)
 	if config.Memory != 0 {
 		metric = applicationautoscaling2.MetricTypeEcsserviceAverageMemoryUtilization
 		targetPercent = config.Memory
 	}
+	if config.Requests != 0 {
+		// requests count per target is tracked on target group of the first exposed port
+		loadBalancer, _ := resources.portLoadBalancer(service, service.Ports[0])
+		metric = applicationautoscaling2.MetricTypeAlbrequestCountPerTarget
+		targetPercent = config.Requests
+		resourceLabel = cloudformation.Join("/", []string{
+			loadBalancerFullName(loadBalancer),
+			cloudformation.GetAtt(targetGroupResourceName(service, service.Ports[0]), "TargetGroupFullName"),
+		})
+	}
 
 	policy := fmt.Sprintf("%sScalingPolicy", normalizeResourceName(service.Name))
 	template.Resources[policy] = &applicationautoscaling.ScalingPolicy{
@@ -118,6 +140,7 @@ func (b *ecsAPIService) createAutoscalingPolicy(project *types.Project, resource
 		TargetTrackingScalingPolicyConfiguration: &applicationautoscaling.ScalingPolicy_TargetTrackingScalingPolicyConfiguration{
 			PredefinedMetricSpecification: &applicationautoscaling.ScalingPolicy_PredefinedMetricSpecification{
 				PredefinedMetricType: metric,
+				ResourceLabel:        resourceLabel,
 			},
 			ScaleOutCooldown: 60,
 			ScaleInCooldown:  60,
@@ -126,3 +149,8 @@ func (b *ecsAPIService) createAutoscalingPolicy(project *types.Project, resource
 	}
 	return nil
 }
+
+func exposedByApplicationLoadBalancer(resources awsResources, service types.ServiceConfig) bool {
+	_, loadBalancerType := resources.portLoadBalancer(service, service.Ports[0])
+	return loadBalancerType == elbv2.LoadBalancerTypeEnumApplication
+}
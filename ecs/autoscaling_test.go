@@ -19,6 +19,7 @@ package ecs
 import (
 	"testing"
 
+	"github.com/awslabs/goformation/v4/cloudformation"
 	autoscaling "github.com/awslabs/goformation/v4/cloudformation/applicationautoscaling"
 	"gotest.tools/v3/assert"
 )
@@ -41,3 +42,30 @@ services:
 	assert.Check(t, policy != nil)
 	assert.Check(t, policy.TargetTrackingScalingPolicyConfiguration.TargetValue == float64(75)) //nolint:staticcheck
 }
+
+func TestAutoScalingRequests(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  foo:
+    image: hello_world
+    ports:
+      - 80:80
+    deploy:
+      x-aws-autoscaling:
+        requests: 1000
+        min: 2
+        max: 10
+`, useDefaultVPC)
+	policy := template.Resources["FooScalingPolicy"].(*autoscaling.ScalingPolicy)
+	metric := policy.TargetTrackingScalingPolicyConfiguration.PredefinedMetricSpecification
+	assert.Equal(t, metric.PredefinedMetricType, "ALBRequestCountPerTarget")
+	assert.Equal(t, metric.ResourceLabel, cloudformation.Join("/", []string{
+		cloudformation.GetAtt("LoadBalancer", "LoadBalancerFullName"),
+		cloudformation.GetAtt("FooTCP80TargetGroup", "TargetGroupFullName"),
+	}))
+}
+
+func TestLoadBalancerFullName(t *testing.T) {
+	lb := existingAWSResource{arn: "arn:aws:elasticloadbalancing:eu-west-3:123456789012:loadbalancer/app/shared/50dc6c495c0c9188"}
+	assert.Equal(t, loadBalancerFullName(lb), "app/shared/50dc6c495c0c9188")
+}
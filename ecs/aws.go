@@ -18,16 +18,23 @@ package ecs
 
 import (
 	"context"
+	"time"
 
 	"github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/aws/aws-sdk-go/service/ecs"
 	"github.com/docker/compose-cli/api/compose"
 	"github.com/docker/compose-cli/api/secrets"
+	dockertypes "github.com/docker/docker/api/types"
 )
 
 const (
 	awsTypeCapacityProvider = "AWS::ECS::CapacityProvider"
 	awsTypeAutoscalingGroup = "AWS::AutoScaling::AutoScalingGroup"
+	awsTypeListener         = "AWS::ElasticLoadBalancingV2::Listener"
+	awsTypeService          = "AWS::ECS::Service"
+	awsTypeTaskDefinition   = "AWS::ECS::TaskDefinition"
+	awsTypeFileSystem       = "AWS::EFS::FileSystem"
+	awsTypeLoadBalancer     = "AWS::ElasticLoadBalancingV2::LoadBalancer"
 )
 
 //go:generate mockgen -destination=./aws_mock.go -self_package "github.com/docker/compose-cli/ecs" -package=ecs . API
@@ -37,13 +44,19 @@ type API interface {
 	CheckRequirements(ctx context.Context, region string) error
 	ResolveCluster(ctx context.Context, nameOrArn string) (awsResource, error)
 	CreateCluster(ctx context.Context, name string) (string, error)
+	ContainerInsightsEnabled(ctx context.Context, cluster string) (bool, error)
 	CheckVPC(ctx context.Context, vpcID string) error
+	CheckSubnets(ctx context.Context, vpcID string, subnets []string, internet bool) error
+	CheckSubnetsIPv6(ctx context.Context, subnets []string) error
 	GetDefaultVPC(ctx context.Context) (string, error)
 	GetSubNets(ctx context.Context, vpcID string) ([]awsResource, error)
+	GetRouteTables(ctx context.Context, vpcID string, subnets []string) ([]string, error)
 	GetRoleArn(ctx context.Context, name string) (string, error)
 	StackExists(ctx context.Context, name string) (bool, error)
 	CreateStack(ctx context.Context, name string, template []byte) error
 	CreateChangeSet(ctx context.Context, name string, template []byte) (string, error)
+	DescribeChangeSet(ctx context.Context, changeset string) ([]resourceChange, error)
+	DeleteChangeSet(ctx context.Context, changeset string) error
 	UpdateStack(ctx context.Context, changeset string) error
 	WaitStackComplete(ctx context.Context, name string, operation int) error
 	GetStackID(ctx context.Context, name string) (string, error)
@@ -53,21 +66,32 @@ type API interface {
 	ListStackServices(ctx context.Context, stack string) ([]string, error)
 	GetServiceTasks(ctx context.Context, cluster string, service string, stopped bool) ([]*ecs.Task, error)
 	GetTaskStoppedReason(ctx context.Context, cluster string, taskArn string) (string, error)
+	RunTask(ctx context.Context, cluster string, service string, container string, command []string) (string, error)
+	WaitTaskStopped(ctx context.Context, cluster string, task string, container string) (int, error)
+	ExecuteCommand(ctx context.Context, cluster string, task string, container string, command string) (execSession, error)
+	StartPortForwardingSession(ctx context.Context, target string, port int, localPort int) (execSession, error)
 	DescribeStackEvents(ctx context.Context, stackID string) ([]*cloudformation.StackEvent, error)
 	ListStackParameters(ctx context.Context, name string) (map[string]string, error)
+	ListStackOutputs(ctx context.Context, name string) (map[string]string, error)
 	ListStackResources(ctx context.Context, name string) (stackResources, error)
+	GetStackTags(ctx context.Context, name string) (map[string]string, error)
+	GetStackTemplate(ctx context.Context, name string) ([]byte, error)
+	UpdateStackTags(ctx context.Context, name string, tags map[string]string) error
 	DeleteStack(ctx context.Context, name string) error
 	CreateSecret(ctx context.Context, secret secrets.Secret) (string, error)
 	InspectSecret(ctx context.Context, id string) (secrets.Secret, error)
 	ListSecrets(ctx context.Context) ([]secrets.Secret, error)
 	DeleteSecret(ctx context.Context, id string, recover bool) error
-	GetLogs(ctx context.Context, name string, consumer func(service, container, message string)) error
-	DescribeService(ctx context.Context, cluster string, arn string) (compose.ServiceStatus, error)
+	GetLogs(ctx context.Context, name string, consumer func(service, container, message string, timestamp time.Time), opts compose.LogOptions) error
+	DescribeServices(ctx context.Context, cluster string, arns []string) ([]compose.ServiceStatus, error)
+	GetServiceEvents(ctx context.Context, cluster string, arn string) (string, []*ecs.ServiceEvent, error)
+	GetServiceNames(ctx context.Context, cluster string, arns []string) (map[string]string, error)
 	getURLWithPortMapping(ctx context.Context, targetGroupArns []string) ([]compose.PortPublisher, error)
 	ListTasks(ctx context.Context, cluster string, family string) ([]string, error)
 	GetPublicIPs(ctx context.Context, interfaces ...string) (map[string]string, error)
 	ResolveLoadBalancer(ctx context.Context, nameOrArn string) (awsResource, string, error)
 	GetLoadBalancerURL(ctx context.Context, arn string) (string, error)
+	GetLoadBalancerListeners(ctx context.Context, arn string) (map[int64]string, error)
 	GetParameter(ctx context.Context, name string) (string, error)
 	SecurityGroupExists(ctx context.Context, sg string) (bool, error)
 	DeleteCapacityProvider(ctx context.Context, arn string) error
@@ -76,4 +100,22 @@ type API interface {
 	ListFileSystems(ctx context.Context, tags map[string]string) ([]awsResource, error)
 	CreateFileSystem(ctx context.Context, tags map[string]string, options VolumeCreateOptions) (awsResource, error)
 	DeleteFileSystem(ctx context.Context, id string) error
+	DescribeFileSystems(ctx context.Context) ([]fileSystem, error)
+	CreateAccessPoint(ctx context.Context, id string, tags map[string]string, options VolumeCreateOptions) (string, error)
+	DeployBlueGreen(ctx context.Context, deployment blueGreenDeployment) (string, error)
+	WaitDeploymentComplete(ctx context.Context, id string) error
+	ScaleService(ctx context.Context, cluster string, arn string, count int) error
+	RestartService(ctx context.Context, cluster string, arn string) error
+	StopTask(ctx context.Context, cluster string, task string, reason string) error
+	RegisterTaskDefinitionRevision(ctx context.Context, taskDefinition string, updates map[string]containerUpdate) (string, error)
+	UpdateServiceTaskDefinition(ctx context.Context, cluster string, arn string, taskDefinition string) error
+	ListTaskDefinitionRevisions(ctx context.Context, project string) (map[string][]string, error)
+	DeregisterTaskDefinition(ctx context.Context, arn string) error
+	WaitServicesStable(ctx context.Context, cluster string, arns []string) error
+	GetImagePlatforms(ctx context.Context, image string) ([]string, error)
+	EnsureRepository(ctx context.Context, name string, tags map[string]string) (string, error)
+	GetRegistryAuth(ctx context.Context) (dockertypes.AuthConfig, error)
+	GetImageDigest(ctx context.Context, image string, platform string) (string, error)
+	GetPrices(ctx context.Context, serviceCode string, filters map[string]string) (map[string]float64, error)
+	PublishNotification(ctx context.Context, topic string, subject string, message string) error
 }
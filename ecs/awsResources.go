@@ -25,6 +25,7 @@ import (
 	"github.com/docker/compose-cli/api/compose"
 	"github.com/docker/compose-cli/errdefs"
 
+	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/aws/aws-sdk-go/service/elbv2"
 	"github.com/awslabs/goformation/v4/cloudformation"
 	"github.com/awslabs/goformation/v4/cloudformation/ec2"
@@ -43,8 +44,19 @@ type awsResources struct {
 	cluster          awsResource
 	loadBalancer     awsResource
 	loadBalancerType string
-	securityGroups   map[string]string
-	filesystems      map[string]awsResource
+	// extraLoadBalancer exposes ports x-aws-loadbalancer_type sets to another type than loadBalancer
+	extraLoadBalancer     awsResource
+	extraLoadBalancerType string
+	securityGroups        map[string]string
+	filesystems           map[string]awsResource
+}
+
+// portLoadBalancer returns the load balancer exposing service port, and its type
+func (r *awsResources) portLoadBalancer(service types.ServiceConfig, port types.ServicePortConfig) (awsResource, string) {
+	if t, _ := portLoadBalancerType(service, port); t != "" && t == r.extraLoadBalancerType {
+		return r.extraLoadBalancer, r.extraLoadBalancerType
+	}
+	return r.loadBalancer, r.loadBalancerType
 }
 
 func (r *awsResources) serviceSecurityGroups(service types.ServiceConfig) []string {
@@ -144,19 +156,23 @@ func (b *ecsAPIService) parse(ctx context.Context, project *types.Project, templ
 	if err != nil {
 		return r, err
 	}
+	err = b.parseExternalSecrets(ctx, project)
+	if err != nil {
+		return r, err
+	}
 	return r, nil
 }
 
 func (b *ecsAPIService) parseClusterExtension(ctx context.Context, project *types.Project, template *cloudformation.Template) (awsResource, error) {
+	nameOrArn := b.ctx.Cluster // can be name _or_ ARN.
 	if x, ok := project.Extensions[extensionCluster]; ok {
-		nameOrArn := x.(string) // can be name _or_ ARN.
+		nameOrArn = x.(string)
+	}
+	if nameOrArn != "" {
 		cluster, err := b.aws.ResolveCluster(ctx, nameOrArn)
 		if err != nil {
 			return nil, err
 		}
-		if !ok {
-			return nil, errors.Wrapf(errdefs.ErrNotFound, "cluster %q does not exist", cluster)
-		}
 
 		template.Metadata["Cluster"] = cluster.ARN()
 		return cluster, nil
@@ -165,14 +181,15 @@ func (b *ecsAPIService) parseClusterExtension(ctx context.Context, project *type
 }
 
 func (b *ecsAPIService) parseVPCExtension(ctx context.Context, project *types.Project) (string, []awsResource, error) {
-	var vpc string
+	vpc := b.ctx.VPC
 	if x, ok := project.Extensions[extensionVPC]; ok {
-		vpcID := x.(string)
-		err := b.aws.CheckVPC(ctx, vpcID)
+		vpc = x.(string)
+	}
+	if vpc != "" {
+		err := b.aws.CheckVPC(ctx, vpc)
 		if err != nil {
 			return "", nil, err
 		}
-
 	} else {
 		defaultVPC, err := b.aws.GetDefaultVPC(ctx)
 		if err != nil {
@@ -181,9 +198,34 @@ func (b *ecsAPIService) parseVPCExtension(ctx context.Context, project *types.Pr
 		vpc = defaultVPC
 	}
 
-	subNets, err := b.aws.GetSubNets(ctx, vpc)
-	if err != nil {
-		return "", nil, err
+	var subNets []awsResource
+	if x, ok := project.Extensions[extensionSubnets]; ok {
+		list, ok := x.([]interface{})
+		if !ok {
+			return "", nil, fmt.Errorf("%s must be a list of subnet IDs", extensionSubnets)
+		}
+		var ids []string
+		for _, id := range list {
+			ids = append(ids, fmt.Sprint(id))
+		}
+		// tasks get a public IP to reach the internet, unless VPC endpoints replace it
+		err := b.aws.CheckSubnets(ctx, vpc, ids, !useVPCEndpoints(project))
+		if err != nil {
+			return "", nil, err
+		}
+		for _, id := range ids {
+			subNets = append(subNets, existingAWSResource{id: id})
+		}
+	} else if vpc == b.ctx.VPC && len(b.ctx.Subnets) > 0 {
+		for _, id := range b.ctx.Subnets {
+			subNets = append(subNets, existingAWSResource{id: id})
+		}
+	} else {
+		var err error
+		subNets, err = b.aws.GetSubNets(ctx, vpc)
+		if err != nil {
+			return "", nil, err
+		}
 	}
 	if len(subNets) < 2 {
 		return "", nil, fmt.Errorf("VPC %s should have at least 2 associated subnets in different availability zones", vpc)
@@ -199,22 +241,72 @@ func (b *ecsAPIService) parseLoadBalancerExtension(ctx context.Context, project
 			return nil, "", err
 		}
 
-		required := getRequiredLoadBalancerType(project)
+		required, _, err := getRequiredLoadBalancerTypes(project)
+		if err != nil {
+			return nil, "", err
+		}
 		if loadBalancerType != required {
 			return nil, "", fmt.Errorf("load balancer %q is of type %s, project require a %s", nameOrArn, loadBalancerType, required)
 		}
 
+		err = b.checkListenersConflicts(ctx, project, loadBalancer)
 		return loadBalancer, loadBalancerType, err
 	}
 	return nil, "", nil
 }
 
+// checkListenersConflicts checks an existing load balancer doesn't already listen on ports published by project,
+// but by listeners created by a previous deployment of this project
+func (b *ecsAPIService) checkListenersConflicts(ctx context.Context, project *types.Project, loadBalancer awsResource) error {
+	listeners, err := b.aws.GetLoadBalancerListeners(ctx, loadBalancer.ARN())
+	if err != nil || len(listeners) == 0 {
+		return err
+	}
+	owned := map[string]bool{}
+	exists, err := b.aws.StackExists(ctx, project.Name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		resources, err := b.aws.ListStackResources(ctx, project.Name)
+		if err != nil {
+			return err
+		}
+		for _, r := range resources {
+			if r.Type == awsTypeListener {
+				owned[r.ARN] = true
+			}
+		}
+	}
+	required, _, err := getRequiredLoadBalancerTypes(project)
+	if err != nil {
+		return err
+	}
+	for _, service := range project.Services {
+		for _, port := range service.Ports {
+			if t, _ := portLoadBalancerType(service, port); t != "" && t != required {
+				// exposed by another load balancer
+				continue
+			}
+			if arn, ok := listeners[int64(port.Target)]; ok && !owned[arn] {
+				return fmt.Errorf("service %q listens on target port %d, but load balancer %q already has a listener on this port", service.Name, port.Target, loadBalancer.ID())
+			}
+		}
+	}
+	return nil
+}
+
 func (b *ecsAPIService) parseExternalNetworks(ctx context.Context, project *types.Project) (map[string]string, error) {
 	securityGroups := make(map[string]string, len(project.Networks))
 	for name, net := range project.Networks {
-		// FIXME remove this for G.A
-		if x, ok := net.Extensions[extensionSecurityGroup]; ok {
-			logrus.Warn("to use an existing security-group, use `network.external` and `network.name` in your compose file")
+		x, ok := net.Extensions[extensionNetworkSecGroup]
+		if !ok {
+			// FIXME remove this for G.A
+			if x, ok = net.Extensions[extensionSecurityGroup]; ok {
+				logrus.Warnf("%s is deprecated, use %s to set an existing security group", extensionSecurityGroup, extensionNetworkSecGroup)
+			}
+		}
+		if ok {
 			logrus.Debugf("Security Group for network %q set by user to %q", net.Name, x)
 			net.External.External = true
 			net.Name = x.(string)
@@ -239,6 +331,15 @@ func (b *ecsAPIService) parseExternalNetworks(ctx context.Context, project *type
 func (b *ecsAPIService) parseExternalVolumes(ctx context.Context, project *types.Project) (map[string]awsResource, error) {
 	filesystems := make(map[string]awsResource, len(project.Volumes))
 	for name, vol := range project.Volumes {
+		if vol.External.External && !strings.HasPrefix(vol.Name, "fs-") && !arn.IsARN(vol.Name) {
+			// volume created by docker volume create
+			fs, err := ecsVolumeService{backend: b}.resolve(ctx, vol.Name)
+			if err != nil {
+				return nil, err
+			}
+			filesystems[name] = existingAWSResource{arn: fs.ARN, id: fs.ID}
+			continue
+		}
 		if vol.External.External {
 			arn, err := b.aws.ResolveFileSystem(ctx, vol.Name)
 			if err != nil {
@@ -247,6 +348,15 @@ func (b *ecsAPIService) parseExternalVolumes(ctx context.Context, project *types
 			filesystems[name] = arn
 			continue
 		}
+		if id, ok := vol.DriverOpts["filesystem_id"]; ok {
+			// reuse an existing filesystem, still managing mount targets and access point as part of the stack
+			fs, err := b.aws.ResolveFileSystem(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			filesystems[name] = fs
+			continue
+		}
 
 		logrus.Debugf("searching for existing filesystem as volume %q", name)
 		tags := map[string]string{
@@ -268,6 +378,23 @@ func (b *ecsAPIService) parseExternalVolumes(ctx context.Context, project *types
 	return filesystems, nil
 }
 
+// parseExternalSecrets resolves external secrets set by name to their ARN, as required by task definition and IAM policies
+func (b *ecsAPIService) parseExternalSecrets(ctx context.Context, project *types.Project) error {
+	for name, secret := range project.Secrets {
+		if !secret.External.External || arn.IsARN(secret.Name) {
+			continue
+		}
+		logrus.Debugf("searching for existing secret %q", secret.Name)
+		s, err := b.aws.InspectSecret(ctx, secret.Name)
+		if err != nil {
+			return errors.Wrapf(err, "external secret %q", secret.Name)
+		}
+		secret.Name = s.ID
+		project.Secrets[name] = secret
+	}
+	return nil
+}
+
 // ensureResources create required resources in template if not yet defined
 func (b *ecsAPIService) ensureResources(resources *awsResources, project *types.Project, template *cloudformation.Template) error {
 	b.ensureCluster(resources, project, template)
@@ -276,8 +403,7 @@ func (b *ecsAPIService) ensureResources(resources *awsResources, project *types.
 	if err != nil {
 		return err
 	}
-	b.ensureLoadBalancer(resources, project, template)
-	return nil
+	return b.ensureLoadBalancer(resources, project, template)
 }
 
 func (b *ecsAPIService) ensureCluster(r *awsResources, project *types.Project, template *cloudformation.Template) {
@@ -349,7 +475,13 @@ func (b *ecsAPIService) ensureVolumes(r *awsResources, project *types.Project, t
 
 		var performanceMode = volume.DriverOpts["performance_mode"]
 		var throughputMode = volume.DriverOpts["throughput_mode"]
+		if throughputMode == "" && provisionedThroughputInMibps > 0 {
+			throughputMode = "provisioned"
+		}
 		var kmsKeyID = volume.DriverOpts["kms_key_id"]
+		if key, ok := project.Extensions[extensionKMSKey]; ok && kmsKeyID == "" {
+			kmsKeyID = kmsKeyARN(fmt.Sprint(key))
+		}
 
 		n := volumeResourceName(name)
 		template.Resources[n] = &efs.FileSystem{
@@ -382,18 +514,37 @@ func (b *ecsAPIService) ensureVolumes(r *awsResources, project *types.Project, t
 	return nil
 }
 
-func (b *ecsAPIService) ensureLoadBalancer(r *awsResources, project *types.Project, template *cloudformation.Template) {
-	if r.loadBalancer != nil {
-		return
+func (b *ecsAPIService) ensureLoadBalancer(r *awsResources, project *types.Project, template *cloudformation.Template) error {
+	if useExternal(project) {
+		logrus.Debug("Ports are published by external instances, so no need for a LoadBalancer")
+		return nil
 	}
 	if allServices(project.Services, func(it types.ServiceConfig) bool {
 		return len(it.Ports) == 0
 	}) {
 		logrus.Debug("Application does not expose any public port, so no need for a LoadBalancer")
-		return
+		return nil
+	}
+	balancerType, extraType, err := getRequiredLoadBalancerTypes(project)
+	if err != nil {
+		return err
+	}
+	if r.loadBalancer == nil {
+		r.loadBalancer = r.createLoadBalancer(project, template, "LoadBalancer", balancerType)
+		r.loadBalancerType = balancerType
+	}
+	if extraType != "" {
+		name := "NetworkLoadBalancer"
+		if extraType == elbv2.LoadBalancerTypeEnumApplication {
+			name = "ApplicationLoadBalancer"
+		}
+		r.extraLoadBalancer = r.createLoadBalancer(project, template, name, extraType)
+		r.extraLoadBalancerType = extraType
 	}
+	return nil
+}
 
-	balancerType := getRequiredLoadBalancerType(project)
+func (r *awsResources) createLoadBalancer(project *types.Project, template *cloudformation.Template, name string, balancerType string) awsResource {
 	var securityGroups []string
 	if balancerType == elbv2.LoadBalancerTypeEnumApplication {
 		// see https://docs.aws.amazon.com/elasticloadbalancing/latest/network/target-group-register-targets.html#target-security-groups
@@ -411,19 +562,41 @@ func (b *ecsAPIService) ensureLoadBalancer(r *awsResources, project *types.Proje
 			})
 	}
 
-	template.Resources["LoadBalancer"] = &elasticloadbalancingv2.LoadBalancer{
-		Scheme:                 elbv2.LoadBalancerSchemeEnumInternetFacing,
+	var ipAddressType string
+	if dualStack(project) {
+		ipAddressType = ipAddressTypeDualStack
+	}
+	scheme := elbv2.LoadBalancerSchemeEnumInternetFacing
+	if useVPCEndpoints(project) {
+		// private subnets have no route to an internet gateway
+		scheme = elbv2.LoadBalancerSchemeEnumInternal
+	}
+
+	template.Resources[name] = &elasticloadbalancingv2.LoadBalancer{
+		IpAddressType:          ipAddressType,
+		Scheme:                 scheme,
 		SecurityGroups:         securityGroups,
 		Subnets:                r.subnetsIDs(),
 		Tags:                   projectTags(project),
 		Type:                   balancerType,
 		LoadBalancerAttributes: loadBalancerAttributes,
 	}
-	r.loadBalancer = cloudformationARNResource{
-		logicalName:  "LoadBalancer",
+	return cloudformationARNResource{
+		logicalName:  name,
 		nameProperty: "LoadBalancerName",
 	}
-	r.loadBalancerType = balancerType
+}
+
+// loadBalancerFullName returns the app/<name>/<id> suffix of load balancer ARN, as used by CloudWatch metrics dimensions
+func loadBalancerFullName(r awsResource) string {
+	if lb, ok := r.(cloudformationARNResource); ok {
+		return cloudformation.GetAtt(lb.logicalName, "LoadBalancerFullName")
+	}
+	arn := r.ARN()
+	if i := strings.Index(arn, ":loadbalancer/"); i >= 0 {
+		return arn[i+len(":loadbalancer/"):]
+	}
+	return arn
 }
 
 func (r *awsResources) getLoadBalancerSecurityGroups(project *types.Project) []string {
@@ -436,17 +609,72 @@ func (r *awsResources) getLoadBalancerSecurityGroups(project *types.Project) []s
 	return securityGroups
 }
 
-func getRequiredLoadBalancerType(project *types.Project) string {
-	loadBalancerType := elbv2.LoadBalancerTypeEnumNetwork
-	if allServices(project.Services, func(it types.ServiceConfig) bool {
-		return allPorts(it.Ports, portIsHTTP)
-	}) {
-		loadBalancerType = elbv2.LoadBalancerTypeEnumApplication
+// getRequiredLoadBalancerTypes returns the type of load balancer exposing ports x-aws-loadbalancer_type doesn't set,
+// application if they all are HTTP, and the type of an extra load balancer for ports set to the other type, if any
+func getRequiredLoadBalancerTypes(project *types.Project) (string, string, error) {
+	var (
+		defaultPorts bool
+		allHTTP      = true
+		forced       = map[string]bool{}
+	)
+	for _, service := range project.Services {
+		for _, port := range service.Ports {
+			t, err := portLoadBalancerType(service, port)
+			if err != nil {
+				return "", "", err
+			}
+			if t != "" {
+				forced[t] = true
+				continue
+			}
+			defaultPorts = true
+			allHTTP = allHTTP && portIsHTTP(port)
+		}
+	}
+
+	loadBalancerType := elbv2.LoadBalancerTypeEnumApplication
+	switch {
+	case defaultPorts && !allHTTP:
+		loadBalancerType = elbv2.LoadBalancerTypeEnumNetwork
+	case !defaultPorts && len(forced) == 1 && forced[elbv2.LoadBalancerTypeEnumNetwork]:
+		loadBalancerType = elbv2.LoadBalancerTypeEnumNetwork
+	}
+	extra := ""
+	for _, t := range []string{elbv2.LoadBalancerTypeEnumApplication, elbv2.LoadBalancerTypeEnumNetwork} {
+		if forced[t] && t != loadBalancerType {
+			extra = t
+		}
+	}
+	return loadBalancerType, extra, nil
+}
+
+// portLoadBalancerType returns the load balancer type set by x-aws-loadbalancer_type on port, or service, if any
+func portLoadBalancerType(service types.ServiceConfig, port types.ServicePortConfig) (string, error) {
+	v, ok := port.Extensions[extensionBalancerType]
+	if !ok {
+		v, ok = service.Extensions[extensionBalancerType]
+	}
+	if !ok {
+		return "", nil
+	}
+	switch t := fmt.Sprint(v); t {
+	case elbv2.LoadBalancerTypeEnumApplication:
+		if strings.EqualFold(port.Protocol, "udp") {
+			return "", fmt.Errorf("service %q: UDP port %d can't be exposed by an application load balancer", service.Name, port.Target)
+		}
+		return t, nil
+	case elbv2.LoadBalancerTypeEnumNetwork:
+		return t, nil
+	default:
+		return "", fmt.Errorf("service %q: %s must be %s or %s, got %q", service.Name, extensionBalancerType,
+			elbv2.LoadBalancerTypeEnumApplication, elbv2.LoadBalancerTypeEnumNetwork, t)
 	}
-	return loadBalancerType
 }
 
 func portIsHTTP(it types.ServicePortConfig) bool {
+	if strings.EqualFold(it.Protocol, "udp") {
+		return false
+	}
 	if v, ok := it.Extensions[extensionProtocol]; ok {
 		protocol := v.(string)
 		return protocol == "http" || protocol == "https"
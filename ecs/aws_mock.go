@@ -10,8 +10,10 @@ import (
 	ecs "github.com/aws/aws-sdk-go/service/ecs"
 	compose "github.com/docker/compose-cli/api/compose"
 	secrets "github.com/docker/compose-cli/api/secrets"
+	types "github.com/docker/docker/api/types"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
+	time "time"
 )
 
 // MockAPI is a mock of API interface
@@ -51,6 +53,34 @@ func (mr *MockAPIMockRecorder) CheckRequirements(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckRequirements", reflect.TypeOf((*MockAPI)(nil).CheckRequirements), arg0, arg1)
 }
 
+// CheckSubnets mocks base method
+func (m *MockAPI) CheckSubnets(arg0 context.Context, arg1 string, arg2 []string, arg3 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckSubnets", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckSubnets indicates an expected call of CheckSubnets
+func (mr *MockAPIMockRecorder) CheckSubnets(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckSubnets", reflect.TypeOf((*MockAPI)(nil).CheckSubnets), arg0, arg1, arg2, arg3)
+}
+
+// CheckSubnetsIPv6 mocks base method
+func (m *MockAPI) CheckSubnetsIPv6(arg0 context.Context, arg1 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckSubnetsIPv6", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckSubnetsIPv6 indicates an expected call of CheckSubnetsIPv6
+func (mr *MockAPIMockRecorder) CheckSubnetsIPv6(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckSubnetsIPv6", reflect.TypeOf((*MockAPI)(nil).CheckSubnetsIPv6), arg0, arg1)
+}
+
 // CheckVPC mocks base method
 func (m *MockAPI) CheckVPC(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -65,6 +95,36 @@ func (mr *MockAPIMockRecorder) CheckVPC(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckVPC", reflect.TypeOf((*MockAPI)(nil).CheckVPC), arg0, arg1)
 }
 
+// ContainerInsightsEnabled mocks base method
+func (m *MockAPI) ContainerInsightsEnabled(arg0 context.Context, arg1 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ContainerInsightsEnabled", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ContainerInsightsEnabled indicates an expected call of ContainerInsightsEnabled
+func (mr *MockAPIMockRecorder) ContainerInsightsEnabled(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContainerInsightsEnabled", reflect.TypeOf((*MockAPI)(nil).ContainerInsightsEnabled), arg0, arg1)
+}
+
+// CreateAccessPoint mocks base method
+func (m *MockAPI) CreateAccessPoint(arg0 context.Context, arg1 string, arg2 map[string]string, arg3 VolumeCreateOptions) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccessPoint", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccessPoint indicates an expected call of CreateAccessPoint
+func (mr *MockAPIMockRecorder) CreateAccessPoint(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccessPoint", reflect.TypeOf((*MockAPI)(nil).CreateAccessPoint), arg0, arg1, arg2, arg3)
+}
+
 // CreateChangeSet mocks base method
 func (m *MockAPI) CreateChangeSet(arg0 context.Context, arg1 string, arg2 []byte) (string, error) {
 	m.ctrl.T.Helper()
@@ -167,6 +227,20 @@ func (mr *MockAPIMockRecorder) DeleteCapacityProvider(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCapacityProvider", reflect.TypeOf((*MockAPI)(nil).DeleteCapacityProvider), arg0, arg1)
 }
 
+// DeleteChangeSet mocks base method
+func (m *MockAPI) DeleteChangeSet(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteChangeSet", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteChangeSet indicates an expected call of DeleteChangeSet
+func (mr *MockAPIMockRecorder) DeleteChangeSet(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteChangeSet", reflect.TypeOf((*MockAPI)(nil).DeleteChangeSet), arg0, arg1)
+}
+
 // DeleteFileSystem mocks base method
 func (m *MockAPI) DeleteFileSystem(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -209,19 +283,78 @@ func (mr *MockAPIMockRecorder) DeleteStack(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStack", reflect.TypeOf((*MockAPI)(nil).DeleteStack), arg0, arg1)
 }
 
-// DescribeService mocks base method
-func (m *MockAPI) DescribeService(arg0 context.Context, arg1, arg2 string) (compose.ServiceStatus, error) {
+// DeployBlueGreen mocks base method
+func (m *MockAPI) DeployBlueGreen(arg0 context.Context, arg1 blueGreenDeployment) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DescribeService", arg0, arg1, arg2)
-	ret0, _ := ret[0].(compose.ServiceStatus)
+	ret := m.ctrl.Call(m, "DeployBlueGreen", arg0, arg1)
+	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// DescribeService indicates an expected call of DescribeService
-func (mr *MockAPIMockRecorder) DescribeService(arg0, arg1, arg2 interface{}) *gomock.Call {
+// DeployBlueGreen indicates an expected call of DeployBlueGreen
+func (mr *MockAPIMockRecorder) DeployBlueGreen(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeService", reflect.TypeOf((*MockAPI)(nil).DescribeService), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeployBlueGreen", reflect.TypeOf((*MockAPI)(nil).DeployBlueGreen), arg0, arg1)
+}
+
+// DeregisterTaskDefinition mocks base method
+func (m *MockAPI) DeregisterTaskDefinition(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeregisterTaskDefinition", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeregisterTaskDefinition indicates an expected call of DeregisterTaskDefinition
+func (mr *MockAPIMockRecorder) DeregisterTaskDefinition(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeregisterTaskDefinition", reflect.TypeOf((*MockAPI)(nil).DeregisterTaskDefinition), arg0, arg1)
+}
+
+// DescribeChangeSet mocks base method
+func (m *MockAPI) DescribeChangeSet(arg0 context.Context, arg1 string) ([]resourceChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeChangeSet", arg0, arg1)
+	ret0, _ := ret[0].([]resourceChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeChangeSet indicates an expected call of DescribeChangeSet
+func (mr *MockAPIMockRecorder) DescribeChangeSet(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeChangeSet", reflect.TypeOf((*MockAPI)(nil).DescribeChangeSet), arg0, arg1)
+}
+
+// DescribeFileSystems mocks base method
+func (m *MockAPI) DescribeFileSystems(arg0 context.Context) ([]fileSystem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeFileSystems", arg0)
+	ret0, _ := ret[0].([]fileSystem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeFileSystems indicates an expected call of DescribeFileSystems
+func (mr *MockAPIMockRecorder) DescribeFileSystems(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeFileSystems", reflect.TypeOf((*MockAPI)(nil).DescribeFileSystems), arg0)
+}
+
+// DescribeServices mocks base method
+func (m *MockAPI) DescribeServices(arg0 context.Context, arg1 string, arg2 []string) ([]compose.ServiceStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeServices", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]compose.ServiceStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeServices indicates an expected call of DescribeServices
+func (mr *MockAPIMockRecorder) DescribeServices(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeServices", reflect.TypeOf((*MockAPI)(nil).DescribeServices), arg0, arg1, arg2)
 }
 
 // DescribeStackEvents mocks base method
@@ -239,6 +372,36 @@ func (mr *MockAPIMockRecorder) DescribeStackEvents(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeStackEvents", reflect.TypeOf((*MockAPI)(nil).DescribeStackEvents), arg0, arg1)
 }
 
+// EnsureRepository mocks base method
+func (m *MockAPI) EnsureRepository(arg0 context.Context, arg1 string, arg2 map[string]string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureRepository", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnsureRepository indicates an expected call of EnsureRepository
+func (mr *MockAPIMockRecorder) EnsureRepository(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureRepository", reflect.TypeOf((*MockAPI)(nil).EnsureRepository), arg0, arg1, arg2)
+}
+
+// ExecuteCommand mocks base method
+func (m *MockAPI) ExecuteCommand(arg0 context.Context, arg1, arg2, arg3, arg4 string) (execSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteCommand", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(execSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecuteCommand indicates an expected call of ExecuteCommand
+func (mr *MockAPIMockRecorder) ExecuteCommand(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteCommand", reflect.TypeOf((*MockAPI)(nil).ExecuteCommand), arg0, arg1, arg2, arg3, arg4)
+}
+
 // GetDefaultVPC mocks base method
 func (m *MockAPI) GetDefaultVPC(arg0 context.Context) (string, error) {
 	m.ctrl.T.Helper()
@@ -254,6 +417,51 @@ func (mr *MockAPIMockRecorder) GetDefaultVPC(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDefaultVPC", reflect.TypeOf((*MockAPI)(nil).GetDefaultVPC), arg0)
 }
 
+// GetImageDigest mocks base method
+func (m *MockAPI) GetImageDigest(arg0 context.Context, arg1 string, arg2 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImageDigest", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImageDigest indicates an expected call of GetImageDigest
+func (mr *MockAPIMockRecorder) GetImageDigest(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImageDigest", reflect.TypeOf((*MockAPI)(nil).GetImageDigest), arg0, arg1, arg2)
+}
+
+// GetImagePlatforms mocks base method
+func (m *MockAPI) GetImagePlatforms(arg0 context.Context, arg1 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImagePlatforms", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImagePlatforms indicates an expected call of GetImagePlatforms
+func (mr *MockAPIMockRecorder) GetImagePlatforms(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImagePlatforms", reflect.TypeOf((*MockAPI)(nil).GetImagePlatforms), arg0, arg1)
+}
+
+// GetLoadBalancerListeners mocks base method
+func (m *MockAPI) GetLoadBalancerListeners(arg0 context.Context, arg1 string) (map[int64]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoadBalancerListeners", arg0, arg1)
+	ret0, _ := ret[0].(map[int64]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoadBalancerListeners indicates an expected call of GetLoadBalancerListeners
+func (mr *MockAPIMockRecorder) GetLoadBalancerListeners(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoadBalancerListeners", reflect.TypeOf((*MockAPI)(nil).GetLoadBalancerListeners), arg0, arg1)
+}
+
 // GetLoadBalancerURL mocks base method
 func (m *MockAPI) GetLoadBalancerURL(arg0 context.Context, arg1 string) (string, error) {
 	m.ctrl.T.Helper()
@@ -270,17 +478,17 @@ func (mr *MockAPIMockRecorder) GetLoadBalancerURL(arg0, arg1 interface{}) *gomoc
 }
 
 // GetLogs mocks base method
-func (m *MockAPI) GetLogs(arg0 context.Context, arg1 string, arg2 func(string, string, string)) error {
+func (m *MockAPI) GetLogs(arg0 context.Context, arg1 string, arg2 func(string, string, string, time.Time), arg3 compose.LogOptions) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLogs", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "GetLogs", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // GetLogs indicates an expected call of GetLogs
-func (mr *MockAPIMockRecorder) GetLogs(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockAPIMockRecorder) GetLogs(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogs", reflect.TypeOf((*MockAPI)(nil).GetLogs), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogs", reflect.TypeOf((*MockAPI)(nil).GetLogs), arg0, arg1, arg2, arg3)
 }
 
 // GetParameter mocks base method
@@ -298,6 +506,21 @@ func (mr *MockAPIMockRecorder) GetParameter(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetParameter", reflect.TypeOf((*MockAPI)(nil).GetParameter), arg0, arg1)
 }
 
+// GetPrices mocks base method
+func (m *MockAPI) GetPrices(arg0 context.Context, arg1 string, arg2 map[string]string) (map[string]float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPrices", arg0, arg1, arg2)
+	ret0, _ := ret[0].(map[string]float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPrices indicates an expected call of GetPrices
+func (mr *MockAPIMockRecorder) GetPrices(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrices", reflect.TypeOf((*MockAPI)(nil).GetPrices), arg0, arg1, arg2)
+}
+
 // GetPublicIPs mocks base method
 func (m *MockAPI) GetPublicIPs(arg0 context.Context, arg1 ...string) (map[string]string, error) {
 	m.ctrl.T.Helper()
@@ -318,6 +541,21 @@ func (mr *MockAPIMockRecorder) GetPublicIPs(arg0 interface{}, arg1 ...interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublicIPs", reflect.TypeOf((*MockAPI)(nil).GetPublicIPs), varargs...)
 }
 
+// GetRegistryAuth mocks base method
+func (m *MockAPI) GetRegistryAuth(arg0 context.Context) (types.AuthConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRegistryAuth", arg0)
+	ret0, _ := ret[0].(types.AuthConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRegistryAuth indicates an expected call of GetRegistryAuth
+func (mr *MockAPIMockRecorder) GetRegistryAuth(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRegistryAuth", reflect.TypeOf((*MockAPI)(nil).GetRegistryAuth), arg0)
+}
+
 // GetRoleArn mocks base method
 func (m *MockAPI) GetRoleArn(arg0 context.Context, arg1 string) (string, error) {
 	m.ctrl.T.Helper()
@@ -333,6 +571,52 @@ func (mr *MockAPIMockRecorder) GetRoleArn(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoleArn", reflect.TypeOf((*MockAPI)(nil).GetRoleArn), arg0, arg1)
 }
 
+// GetRouteTables mocks base method
+func (m *MockAPI) GetRouteTables(arg0 context.Context, arg1 string, arg2 []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRouteTables", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRouteTables indicates an expected call of GetRouteTables
+func (mr *MockAPIMockRecorder) GetRouteTables(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRouteTables", reflect.TypeOf((*MockAPI)(nil).GetRouteTables), arg0, arg1, arg2)
+}
+
+// GetServiceEvents mocks base method
+func (m *MockAPI) GetServiceEvents(arg0 context.Context, arg1, arg2 string) (string, []*ecs.ServiceEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceEvents", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].([]*ecs.ServiceEvent)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetServiceEvents indicates an expected call of GetServiceEvents
+func (mr *MockAPIMockRecorder) GetServiceEvents(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceEvents", reflect.TypeOf((*MockAPI)(nil).GetServiceEvents), arg0, arg1, arg2)
+}
+
+// GetServiceNames mocks base method
+func (m *MockAPI) GetServiceNames(arg0 context.Context, arg1 string, arg2 []string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceNames", arg0, arg1, arg2)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServiceNames indicates an expected call of GetServiceNames
+func (mr *MockAPIMockRecorder) GetServiceNames(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceNames", reflect.TypeOf((*MockAPI)(nil).GetServiceNames), arg0, arg1, arg2)
+}
+
 // GetServiceTaskDefinition mocks base method
 func (m *MockAPI) GetServiceTaskDefinition(arg0 context.Context, arg1 string, arg2 []string) (map[string]string, error) {
 	m.ctrl.T.Helper()
@@ -393,6 +677,36 @@ func (mr *MockAPIMockRecorder) GetStackID(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStackID", reflect.TypeOf((*MockAPI)(nil).GetStackID), arg0, arg1)
 }
 
+// GetStackTags mocks base method
+func (m *MockAPI) GetStackTags(arg0 context.Context, arg1 string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStackTags", arg0, arg1)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStackTags indicates an expected call of GetStackTags
+func (mr *MockAPIMockRecorder) GetStackTags(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStackTags", reflect.TypeOf((*MockAPI)(nil).GetStackTags), arg0, arg1)
+}
+
+// GetStackTemplate mocks base method
+func (m *MockAPI) GetStackTemplate(arg0 context.Context, arg1 string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStackTemplate", arg0, arg1)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStackTemplate indicates an expected call of GetStackTemplate
+func (mr *MockAPIMockRecorder) GetStackTemplate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStackTemplate", reflect.TypeOf((*MockAPI)(nil).GetStackTemplate), arg0, arg1)
+}
+
 // GetSubNets mocks base method
 func (m *MockAPI) GetSubNets(arg0 context.Context, arg1 string) ([]awsResource, error) {
 	m.ctrl.T.Helper()
@@ -468,6 +782,21 @@ func (mr *MockAPIMockRecorder) ListSecrets(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSecrets", reflect.TypeOf((*MockAPI)(nil).ListSecrets), arg0)
 }
 
+// ListStackOutputs mocks base method
+func (m *MockAPI) ListStackOutputs(arg0 context.Context, arg1 string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStackOutputs", arg0, arg1)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStackOutputs indicates an expected call of ListStackOutputs
+func (mr *MockAPIMockRecorder) ListStackOutputs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStackOutputs", reflect.TypeOf((*MockAPI)(nil).ListStackOutputs), arg0, arg1)
+}
+
 // ListStackParameters mocks base method
 func (m *MockAPI) ListStackParameters(arg0 context.Context, arg1 string) (map[string]string, error) {
 	m.ctrl.T.Helper()
@@ -528,6 +857,21 @@ func (mr *MockAPIMockRecorder) ListStacks(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStacks", reflect.TypeOf((*MockAPI)(nil).ListStacks), arg0, arg1)
 }
 
+// ListTaskDefinitionRevisions mocks base method
+func (m *MockAPI) ListTaskDefinitionRevisions(arg0 context.Context, arg1 string) (map[string][]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTaskDefinitionRevisions", arg0, arg1)
+	ret0, _ := ret[0].(map[string][]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTaskDefinitionRevisions indicates an expected call of ListTaskDefinitionRevisions
+func (mr *MockAPIMockRecorder) ListTaskDefinitionRevisions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTaskDefinitionRevisions", reflect.TypeOf((*MockAPI)(nil).ListTaskDefinitionRevisions), arg0, arg1)
+}
+
 // ListTasks mocks base method
 func (m *MockAPI) ListTasks(arg0 context.Context, arg1, arg2 string) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -543,6 +887,35 @@ func (mr *MockAPIMockRecorder) ListTasks(arg0, arg1, arg2 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTasks", reflect.TypeOf((*MockAPI)(nil).ListTasks), arg0, arg1, arg2)
 }
 
+// PublishNotification mocks base method
+func (m *MockAPI) PublishNotification(arg0 context.Context, arg1, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishNotification", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishNotification indicates an expected call of PublishNotification
+func (mr *MockAPIMockRecorder) PublishNotification(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishNotification", reflect.TypeOf((*MockAPI)(nil).PublishNotification), arg0, arg1, arg2, arg3)
+}
+
+// RegisterTaskDefinitionRevision mocks base method
+func (m *MockAPI) RegisterTaskDefinitionRevision(arg0 context.Context, arg1 string, arg2 map[string]containerUpdate) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterTaskDefinitionRevision", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterTaskDefinitionRevision indicates an expected call of RegisterTaskDefinitionRevision
+func (mr *MockAPIMockRecorder) RegisterTaskDefinitionRevision(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterTaskDefinitionRevision", reflect.TypeOf((*MockAPI)(nil).RegisterTaskDefinitionRevision), arg0, arg1, arg2)
+}
+
 // ResolveCluster mocks base method
 func (m *MockAPI) ResolveCluster(arg0 context.Context, arg1 string) (awsResource, error) {
 	m.ctrl.T.Helper()
@@ -589,6 +962,49 @@ func (mr *MockAPIMockRecorder) ResolveLoadBalancer(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveLoadBalancer", reflect.TypeOf((*MockAPI)(nil).ResolveLoadBalancer), arg0, arg1)
 }
 
+// RestartService mocks base method
+func (m *MockAPI) RestartService(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestartService", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestartService indicates an expected call of RestartService
+func (mr *MockAPIMockRecorder) RestartService(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestartService", reflect.TypeOf((*MockAPI)(nil).RestartService), arg0, arg1, arg2)
+}
+
+// RunTask mocks base method
+func (m *MockAPI) RunTask(arg0 context.Context, arg1, arg2, arg3 string, arg4 []string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunTask", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunTask indicates an expected call of RunTask
+func (mr *MockAPIMockRecorder) RunTask(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunTask", reflect.TypeOf((*MockAPI)(nil).RunTask), arg0, arg1, arg2, arg3, arg4)
+}
+
+// ScaleService mocks base method
+func (m *MockAPI) ScaleService(arg0 context.Context, arg1, arg2 string, arg3 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScaleService", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ScaleService indicates an expected call of ScaleService
+func (mr *MockAPIMockRecorder) ScaleService(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScaleService", reflect.TypeOf((*MockAPI)(nil).ScaleService), arg0, arg1, arg2, arg3)
+}
+
 // SecurityGroupExists mocks base method
 func (m *MockAPI) SecurityGroupExists(arg0 context.Context, arg1 string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -619,6 +1035,49 @@ func (mr *MockAPIMockRecorder) StackExists(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StackExists", reflect.TypeOf((*MockAPI)(nil).StackExists), arg0, arg1)
 }
 
+// StartPortForwardingSession mocks base method
+func (m *MockAPI) StartPortForwardingSession(arg0 context.Context, arg1 string, arg2, arg3 int) (execSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartPortForwardingSession", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(execSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartPortForwardingSession indicates an expected call of StartPortForwardingSession
+func (mr *MockAPIMockRecorder) StartPortForwardingSession(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartPortForwardingSession", reflect.TypeOf((*MockAPI)(nil).StartPortForwardingSession), arg0, arg1, arg2, arg3)
+}
+
+// StopTask mocks base method
+func (m *MockAPI) StopTask(arg0 context.Context, arg1, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StopTask", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StopTask indicates an expected call of StopTask
+func (mr *MockAPIMockRecorder) StopTask(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopTask", reflect.TypeOf((*MockAPI)(nil).StopTask), arg0, arg1, arg2, arg3)
+}
+
+// UpdateServiceTaskDefinition mocks base method
+func (m *MockAPI) UpdateServiceTaskDefinition(arg0 context.Context, arg1, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateServiceTaskDefinition", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateServiceTaskDefinition indicates an expected call of UpdateServiceTaskDefinition
+func (mr *MockAPIMockRecorder) UpdateServiceTaskDefinition(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateServiceTaskDefinition", reflect.TypeOf((*MockAPI)(nil).UpdateServiceTaskDefinition), arg0, arg1, arg2, arg3)
+}
+
 // UpdateStack mocks base method
 func (m *MockAPI) UpdateStack(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -633,6 +1092,48 @@ func (mr *MockAPIMockRecorder) UpdateStack(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStack", reflect.TypeOf((*MockAPI)(nil).UpdateStack), arg0, arg1)
 }
 
+// UpdateStackTags mocks base method
+func (m *MockAPI) UpdateStackTags(arg0 context.Context, arg1 string, arg2 map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStackTags", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStackTags indicates an expected call of UpdateStackTags
+func (mr *MockAPIMockRecorder) UpdateStackTags(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStackTags", reflect.TypeOf((*MockAPI)(nil).UpdateStackTags), arg0, arg1, arg2)
+}
+
+// WaitDeploymentComplete mocks base method
+func (m *MockAPI) WaitDeploymentComplete(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitDeploymentComplete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WaitDeploymentComplete indicates an expected call of WaitDeploymentComplete
+func (mr *MockAPIMockRecorder) WaitDeploymentComplete(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitDeploymentComplete", reflect.TypeOf((*MockAPI)(nil).WaitDeploymentComplete), arg0, arg1)
+}
+
+// WaitServicesStable mocks base method
+func (m *MockAPI) WaitServicesStable(arg0 context.Context, arg1 string, arg2 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitServicesStable", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WaitServicesStable indicates an expected call of WaitServicesStable
+func (mr *MockAPIMockRecorder) WaitServicesStable(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitServicesStable", reflect.TypeOf((*MockAPI)(nil).WaitServicesStable), arg0, arg1, arg2)
+}
+
 // WaitStackComplete mocks base method
 func (m *MockAPI) WaitStackComplete(arg0 context.Context, arg1 string, arg2 int) error {
 	m.ctrl.T.Helper()
@@ -647,6 +1148,21 @@ func (mr *MockAPIMockRecorder) WaitStackComplete(arg0, arg1, arg2 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitStackComplete", reflect.TypeOf((*MockAPI)(nil).WaitStackComplete), arg0, arg1, arg2)
 }
 
+// WaitTaskStopped mocks base method
+func (m *MockAPI) WaitTaskStopped(arg0 context.Context, arg1, arg2, arg3 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitTaskStopped", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WaitTaskStopped indicates an expected call of WaitTaskStopped
+func (mr *MockAPIMockRecorder) WaitTaskStopped(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitTaskStopped", reflect.TypeOf((*MockAPI)(nil).WaitTaskStopped), arg0, arg1, arg2, arg3)
+}
+
 // getURLWithPortMapping mocks base method
 func (m *MockAPI) getURLWithPortMapping(arg0 context.Context, arg1 []string) ([]compose.PortPublisher, error) {
 	m.ctrl.T.Helper()
@@ -18,6 +18,7 @@ package ecs
 
 import (
 	"context"
+	"os"
 
 	"github.com/docker/compose-cli/api/compose"
 	"github.com/docker/compose-cli/api/containers"
@@ -29,8 +30,12 @@ import (
 	"github.com/docker/compose-cli/context/cloud"
 	"github.com/docker/compose-cli/context/store"
 	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/prompt"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/processcreds"
+	"github.com/aws/aws-sdk-go/aws/defaults"
 	"github.com/aws/aws-sdk-go/aws/session"
 )
 
@@ -38,15 +43,49 @@ const backendType = store.EcsContextType
 
 // ContextParams options for creating AWS context
 type ContextParams struct {
-	Description string
-	Region      string
-	Profile     string
+	Description    string
+	Region         string
+	Profile        string
+	CreateProfile  string
+	AccessKey      string
+	SecretKey      string
+	SessionToken   string
+	NonInteractive bool
+	// FromAWSProfile imports region and credentials source from an existing AWS CLI profile, without prompting
+	FromAWSProfile string
+	// CredentialsFromEnv makes context resolve AWS credentials from environment variables on each command
+	CredentialsFromEnv bool
+	// Keychain stores credentials for the created profile in the OS keychain instead of ~/.aws/credentials
+	Keychain bool
+	// TestPermissions checks IAM policies grant all actions required by the ECS backend
+	TestPermissions bool
+	// EndpointURL overrides endpoint for all AWS services, Endpoints overrides it per service ID
+	EndpointURL string
+	Endpoints   map[string]string
+	// Cluster is used by deployments which don't set x-aws-cluster
+	Cluster string
+	// VPC and Subnets are used by deployments which don't set x-aws-vpc
+	VPC     string
+	Subnets []string
+	// Tags are applied to all CloudFormation stacks, and as such to all resources they create
+	Tags map[string]string
+}
+
+func (o ContextParams) hasCustomEndpoints() bool {
+	return o.EndpointURL != "" || len(o.Endpoints) > 0
 }
 
 func init() {
 	backend.Register(backendType, backendType, service, getCloudService)
 }
 
+type regionKey struct{}
+
+// WithRegion overrides the region set by ECS context for backend created with ctx
+func WithRegion(ctx context.Context, region string) context.Context {
+	return context.WithValue(ctx, regionKey{}, region)
+}
+
 func service(ctx context.Context) (backend.Service, error) {
 	contextStore := store.ContextStore(ctx)
 	currentContext := apicontext.CurrentContext(ctx)
@@ -55,34 +94,115 @@ func service(ctx context.Context) (backend.Service, error) {
 	if err := contextStore.GetEndpoint(currentContext, &ecsContext); err != nil {
 		return nil, err
 	}
+	if region, ok := ctx.Value(regionKey{}).(string); ok && region != "" {
+		ecsContext.Region = region
+	}
 
-	return getEcsAPIService(ecsContext)
+	return getEcsAPIService(ecsContext, getAssumeRole(ctx))
 }
 
-func getEcsAPIService(ecsCtx store.EcsContext) (*ecsAPIService, error) {
-	sess, err := session.NewSessionWithOptions(session.Options{
-		Profile:           ecsCtx.Profile,
-		SharedConfigState: session.SharedConfigEnable,
-		Config: aws.Config{
-			Region: aws.String(ecsCtx.Region),
-		},
-	})
+func getEcsAPIService(ecsCtx store.EcsContext, role AssumeRole) (*ecsAPIService, error) {
+	var (
+		sess *session.Session
+		err  error
+	)
+	switch {
+	case len(ecsCtx.Endpoints) > 0:
+		sess, err = newEndpointsSession(ecsCtx)
+	case ecsCtx.CredentialsFromEnv:
+		sess, err = newEnvSession(ecsCtx.Region)
+	case ecsCtx.CredentialsInKeychain:
+		sess, err = newKeychainSession(ecsCtx.Profile, ecsCtx.Region)
+	default:
+		sess, err = newSession(ecsCtx.Profile, ecsCtx.Region, prompt.User{})
+	}
 	if err != nil {
 		return nil, err
 	}
+	if role.ARN != "" {
+		if err := assumeRole(sess, role); err != nil {
+			return nil, err
+		}
+	}
 
 	sdk := newSDK(sess)
+	sdk.tags = ecsCtx.Tags
 	return &ecsAPIService{
 		ctx:    ecsCtx,
-		Region: ecsCtx.Region,
+		Region: aws.StringValue(sess.Config.Region),
 		aws:    sdk,
+		user:   prompt.User{},
 	}, nil
 }
 
+// newEnvSession creates an AWS session using credentials from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and
+// AWS_SESSION_TOKEN, ignoring shared configuration files
+func newEnvSession(region string) (*session.Session, error) {
+	if region == "" {
+		region = regionFromEnv()
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{
+		SharedConfigState: session.SharedConfigDisable,
+		Config: aws.Config{
+			Region:      aws.String(region),
+			Credentials: credentials.AnonymousCredentials,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	sess.Config.Credentials = credentials.NewCredentials(envCredentialsProvider(sess))
+	return sess, nil
+}
+
+func regionFromEnv() string {
+	if region := os.Getenv("AWS_REGION"); region != "" {
+		return region
+	}
+	return os.Getenv("AWS_DEFAULT_REGION")
+}
+
+// newSession creates an AWS session for profile, resolving SSO, credential_process and MFA-protected role credentials
+func newSession(profile string, region string, ui prompt.UI) (*session.Session, error) {
+	serial, err := loadMFASerial(defaults.SharedConfigFilename(), profile)
+	if err != nil {
+		return nil, err
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Profile:                 profile,
+		SharedConfigState:       session.SharedConfigEnable,
+		AssumeRoleTokenProvider: mfaTokenProvider(ui, serial),
+		Config: aws.Config{
+			Region: aws.String(region),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sso, err := loadSSOProfile(defaults.SharedConfigFilename(), profile)
+	if err != nil {
+		return nil, err
+	}
+	if sso != nil {
+		sess.Config.Credentials = newSSOCredentials(sess, *sso)
+	}
+
+	process, err := loadCredentialProcess(defaults.SharedConfigFilename(), defaults.SharedCredentialsFilename(), profile)
+	if err != nil {
+		return nil, err
+	}
+	if process != "" {
+		sess.Config.Credentials = processcreds.NewCredentials(process)
+	}
+	return sess, nil
+}
+
 type ecsAPIService struct {
 	ctx    store.EcsContext
 	Region string
 	aws    API
+	user   prompt.UI
 }
 
 func (b *ecsAPIService) ContainerService() containers.Service {
@@ -0,0 +1,64 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// maxConcurrentRequests bounds AWS API requests sent concurrently, so that large projects don't hit rate limits
+	maxConcurrentRequests = 8
+	// describeServicesBatch is the maximum number of services a DescribeServices request accepts
+	describeServicesBatch = 10
+	// describeTasksBatch is the maximum number of tasks a DescribeTasks request accepts
+	describeTasksBatch = 100
+)
+
+// concurrently calls fn for indexes 0 to n-1, at most maxConcurrentRequests at a time, and returns the first error.
+// Context passed to fn is canceled once a call fails.
+func concurrently(ctx context.Context, n int, fn func(ctx context.Context, i int) error) error {
+	eg, ctx := errgroup.WithContext(ctx)
+	sem := make(chan struct{}, maxConcurrentRequests)
+	for i := 0; i < n; i++ {
+		i := i
+		eg.Go(func() error {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			return fn(ctx, i)
+		})
+	}
+	return eg.Wait()
+}
+
+// batches splits items into slices of at most size items, as accepted by a single API request
+func batches(items []string, size int) [][]string {
+	var b [][]string
+	for len(items) > size {
+		b = append(b, items[:size])
+		items = items[size:]
+	}
+	if len(items) > 0 {
+		b = append(b, items)
+	}
+	return b
+}
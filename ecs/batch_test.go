@@ -0,0 +1,62 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestBatches(t *testing.T) {
+	assert.Equal(t, len(batches(nil, 10)), 0)
+	assert.DeepEqual(t, batches([]string{"a", "b", "c"}, 2), [][]string{{"a", "b"}, {"c"}})
+	assert.DeepEqual(t, batches([]string{"a", "b"}, 2), [][]string{{"a", "b"}})
+}
+
+func TestConcurrently(t *testing.T) {
+	var running, max int32
+	results := make([]int, 50)
+	err := concurrently(context.TODO(), len(results), func(ctx context.Context, i int) error {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&max)
+			if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+				break
+			}
+		}
+		results[i] = i * 2
+		return nil
+	})
+	assert.NilError(t, err)
+	assert.Check(t, max <= maxConcurrentRequests)
+	for i, r := range results {
+		assert.Equal(t, r, i*2)
+	}
+
+	err = concurrently(context.TODO(), 5, func(ctx context.Context, i int) error {
+		if i == 3 {
+			return fmt.Errorf("failed %d", i)
+		}
+		return nil
+	})
+	assert.Error(t, err, "failed 3")
+}
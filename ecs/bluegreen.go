@@ -0,0 +1,423 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	codedeployapi "github.com/aws/aws-sdk-go/service/codedeploy"
+	ecsapi "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/cloudformation/codedeploy"
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"github.com/awslabs/goformation/v4/cloudformation/elasticloadbalancingv2"
+	"github.com/awslabs/goformation/v4/cloudformation/iam"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/progress"
+)
+
+const (
+	blueGreenAllAtOnce = "all_at_once"
+	blueGreenCanary    = "canary"
+	blueGreenLinear    = "linear"
+
+	// DeployStrategyRolling and DeployStrategyBlueGreen are the strategies accepted by WithDeployStrategy
+	DeployStrategyRolling   = "rolling"
+	DeployStrategyBlueGreen = "blue_green"
+
+	codeDeployApplication = "CodeDeployApplication"
+	codeDeployRole        = "CodeDeployRole"
+)
+
+// blueGreenConfig is the CodeDeploy traffic shifting set by x-aws-blue_green. Canary shifts percentage of traffic then
+// the remaining after interval (in minutes), linear shifts percentage of traffic every interval. TestPort exposes the
+// new task set on the load balancer before traffic is shifted.
+type blueGreenConfig struct {
+	Strategy   string   `json:"strategy,omitempty"`
+	Percentage int      `json:"percentage,omitempty"`
+	Interval   int      `json:"interval,omitempty"`
+	Alarms     []string `json:"alarms,omitempty"`
+	TestPort   int      `json:"test_port,omitempty"`
+}
+
+// blueGreenDeployment describes a CodeDeploy deployment of a new task definition for an ECS service
+type blueGreenDeployment struct {
+	Application     string
+	DeploymentGroup string
+	ServiceRole     string
+	Cluster         string
+	Service         string
+	TargetGroups    []string
+	Listener        string
+	TestListener    string
+	TaskDefinition  string
+	ContainerName   string
+	ContainerPort   int
+	Config          blueGreenConfig
+}
+
+// deploymentConfigName returns the name of the CodeDeploy deployment configuration applying traffic shifting
+func (c blueGreenConfig) deploymentConfigName() string {
+	switch c.Strategy {
+	case blueGreenCanary:
+		return fmt.Sprintf("ComposeECSCanary%dPercent%dMinutes", c.Percentage, c.Interval)
+	case blueGreenLinear:
+		return fmt.Sprintf("ComposeECSLinear%dPercentEvery%dMinutes", c.Percentage, c.Interval)
+	default:
+		return "CodeDeployDefault.ECSAllAtOnce"
+	}
+}
+
+type deployStrategyKey struct{}
+
+// WithDeployStrategy overrides the deployment strategy of services for backend created with ctx
+func WithDeployStrategy(ctx context.Context, strategy string) context.Context {
+	return context.WithValue(ctx, deployStrategyKey{}, strategy)
+}
+
+// applyDeployStrategy lets --deploy-strategy flag enable blue/green deployment on all services exposing ports, or
+// disable it regardless of x-aws-blue_green
+func applyDeployStrategy(ctx context.Context, project *types.Project) error {
+	strategy, ok := ctx.Value(deployStrategyKey{}).(string)
+	if !ok || strategy == "" {
+		return nil
+	}
+	for i, service := range project.Services {
+		switch strategy {
+		case DeployStrategyRolling:
+			delete(service.Extensions, extensionBlueGreen)
+		case DeployStrategyBlueGreen:
+			if _, ok := service.Extensions[extensionBlueGreen]; ok || len(service.Ports) == 0 {
+				continue
+			}
+			if service.Extensions == nil {
+				service.Extensions = map[string]interface{}{}
+			}
+			service.Extensions[extensionBlueGreen] = map[string]interface{}{}
+		default:
+			return errors.Wrapf(errdefs.ErrParsingFailed, "unsupported deploy strategy %q, expected %s or %s", strategy, DeployStrategyRolling, DeployStrategyBlueGreen)
+		}
+		project.Services[i] = service
+	}
+	return nil
+}
+
+func getBlueGreenConfig(service types.ServiceConfig) (*blueGreenConfig, error) {
+	v, ok := service.Extensions[extensionBlueGreen]
+	if !ok {
+		return nil, nil
+	}
+	marshalled, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var config blueGreenConfig
+	err = json.Unmarshal(marshalled, &config)
+	if err != nil {
+		return nil, err
+	}
+	switch config.Strategy {
+	case "":
+		config.Strategy = blueGreenAllAtOnce
+	case blueGreenAllAtOnce:
+	case blueGreenCanary, blueGreenLinear:
+		if config.Percentage < 1 || config.Percentage > 99 {
+			return nil, fmt.Errorf("service %q: %s %s strategy requires a percentage between 1 and 99", service.Name, extensionBlueGreen, config.Strategy)
+		}
+		if config.Interval < 1 {
+			return nil, fmt.Errorf("service %q: %s %s strategy requires an interval in minutes", service.Name, extensionBlueGreen, config.Strategy)
+		}
+	default:
+		return nil, fmt.Errorf("service %q: unsupported %s strategy %q", service.Name, extensionBlueGreen, config.Strategy)
+	}
+	if config.TestPort < 0 || config.TestPort > 65535 {
+		return nil, fmt.Errorf("service %q: %s test_port %d is not a valid port", service.Name, extensionBlueGreen, config.TestPort)
+	}
+	return &config, nil
+}
+
+// createBlueGreenTargetGroup configures service for CodeDeploy and adds the green target group, which receives traffic
+// for the new task set during deployment. When a test port is set, a test listener forwards to the green target group.
+func (b *ecsAPIService) createBlueGreenTargetGroup(project *types.Project, service types.ServiceConfig, template *cloudformation.Template, resources awsResources) error {
+	config, err := getBlueGreenConfig(service)
+	if err != nil || config == nil {
+		return err
+	}
+	if len(service.Ports) != 1 {
+		return fmt.Errorf("service %q: %s requires service to expose a single port", service.Name, extensionBlueGreen)
+	}
+	// CodeDeploy shifts traffic progressively by weighting the listener target groups, which network load balancers
+	// don't support
+	_, loadBalancerType := resources.portLoadBalancer(service, service.Ports[0])
+	if config.Strategy != blueGreenAllAtOnce && loadBalancerType == elbv2.LoadBalancerTypeEnumNetwork {
+		return fmt.Errorf("service %q: %s %s strategy requires an application load balancer, set x-aws-loadbalancer_type: application or use all_at_once strategy", service.Name, extensionBlueGreen, config.Strategy)
+	}
+	// ECS only registers tasks in target groups attached to a load balancer, network load balancers can only attach the
+	// green target group to a test listener
+	if loadBalancerType == elbv2.LoadBalancerTypeEnumNetwork && config.TestPort == 0 {
+		return fmt.Errorf("service %q: %s on a network load balancer requires a test_port, or set x-aws-loadbalancer_type: application", service.Name, extensionBlueGreen)
+	}
+	if requireEC2(project, service) {
+		return fmt.Errorf("service %q: %s can't be used with services requiring EC2 instances", service.Name, extensionBlueGreen)
+	}
+	port := service.Ports[0]
+	listenerName := listenerResourceName(service, port)
+	if config.TestPort == int(port.Target) {
+		return fmt.Errorf("service %q: %s test_port must differ from the service port %d", service.Name, extensionBlueGreen, port.Target)
+	}
+	blue := targetGroupResourceName(service, port)
+	targetGroup := *template.Resources[blue].(*elasticloadbalancingv2.TargetGroup)
+	template.Resources[blue+"Green"] = &targetGroup
+
+	s := template.Resources[serviceResourceName(service.Name)].(*ecs.Service)
+	s.DeploymentController.Type = ecsapi.DeploymentControllerTypeCodeDeploy
+
+	if config.TestPort != 0 {
+		testListener := *template.Resources[listenerName].(*elasticloadbalancingv2.Listener)
+		testListener.Port = config.TestPort
+		testListener.DefaultActions = []elasticloadbalancingv2.Listener_Action{
+			{
+				ForwardConfig: &elasticloadbalancingv2.Listener_ForwardConfig{
+					TargetGroups: []elasticloadbalancingv2.Listener_TargetGroupTuple{
+						{
+							TargetGroupArn: cloudformation.Ref(blue + "Green"),
+						},
+					},
+				},
+				Type: elbv2.ActionTypeEnumForward,
+			},
+		}
+		template.Resources[listenerName+"Test"] = &testListener
+		testPort := port
+		testPort.Target = uint32(config.TestPort)
+		for net := range service.Networks {
+			b.createIngress(project, service, net, testPort, template, resources)
+		}
+		s.AWSCloudFormationDependsOn = append(s.AWSCloudFormationDependsOn, listenerName+"Test")
+	}
+	if loadBalancerType == elbv2.LoadBalancerTypeEnumApplication {
+		weightListener(template.Resources[listenerName].(*elasticloadbalancingv2.Listener), blue, blue+"Green")
+	}
+	return nil
+}
+
+// weightListener makes listener forward to both blue and green target groups, all traffic going to blue. CodeDeploy
+// shifts traffic by updating weights during deployment.
+func weightListener(listener *elasticloadbalancingv2.Listener, blue string, green string) {
+	listener.DefaultActions[0].ForwardConfig.TargetGroups = []elasticloadbalancingv2.Listener_TargetGroupTuple{
+		{TargetGroupArn: cloudformation.Ref(blue), Weight: 100},
+		{TargetGroupArn: cloudformation.Ref(green)},
+	}
+	// goformation omits zero weights, which would have the green target group receive as much traffic as blue
+	listener.AWSCloudFormationMetadata = extraProperties(map[string]interface{}{
+		"DefaultActions": []interface{}{
+			map[string]interface{}{
+				"Type": elbv2.ActionTypeEnumForward,
+				"ForwardConfig": map[string]interface{}{
+					"TargetGroups": []interface{}{
+						map[string]interface{}{"TargetGroupArn": cloudformation.Ref(blue), "Weight": 100},
+						map[string]interface{}{"TargetGroupArn": cloudformation.Ref(green), "Weight": 0},
+					},
+				},
+			},
+		},
+	})
+}
+
+// createCodeDeployApplication adds the CodeDeploy application and service role used to deploy blue/green services
+func (b *ecsAPIService) createCodeDeployApplication(project *types.Project, template *cloudformation.Template) {
+	for _, service := range project.Services {
+		if _, ok := service.Extensions[extensionBlueGreen]; !ok {
+			continue
+		}
+		template.Resources[codeDeployApplication] = &codedeploy.Application{
+			ComputePlatform: codedeployapi.ComputePlatformEcs,
+		}
+		template.Resources[codeDeployRole] = &iam.Role{
+			AssumeRolePolicyDocument: codeDeployAssumeRolePolicyDocument,
+			ManagedPolicyArns:        []string{ecsCodeDeployPolicy},
+			Tags:                     projectTags(project),
+		}
+		return
+	}
+}
+
+// keepBlueGreenTaskDefinitions sets blue/green services to their current task definition, as CloudFormation can't
+// update services deployed by CodeDeploy. It returns the current task definitions by service name.
+func (b *ecsAPIService) keepBlueGreenTaskDefinitions(ctx context.Context, project *types.Project, template *cloudformation.Template) (map[string]string, error) {
+	blueGreen := false
+	for _, service := range project.Services {
+		if _, ok := service.Extensions[extensionBlueGreen]; ok {
+			blueGreen = true
+		}
+	}
+	if !blueGreen {
+		return nil, nil
+	}
+
+	resources, err := b.aws.ListStackResources(ctx, project.Name)
+	if err != nil {
+		return nil, err
+	}
+	services := map[string]string{}
+	var arns []string
+	err = resources.apply(awsTypeService, func(r stackResource) error {
+		services[r.LogicalID] = r.ARN
+		arns = append(arns, r.ARN)
+		return nil
+	})
+	if err != nil || len(arns) == 0 {
+		return nil, err
+	}
+	cluster, err := b.aws.GetStackClusterID(ctx, project.Name)
+	if err != nil {
+		return nil, err
+	}
+	definitions, err := b.aws.GetServiceTaskDefinition(ctx, cluster, arns)
+	if err != nil {
+		return nil, err
+	}
+
+	current := map[string]string{}
+	for _, service := range project.Services {
+		if _, ok := service.Extensions[extensionBlueGreen]; !ok {
+			continue
+		}
+		definition, ok := definitions[services[serviceResourceName(service.Name)]]
+		if !ok {
+			// service is created by this update
+			continue
+		}
+		template.Resources[serviceResourceName(service.Name)].(*ecs.Service).TaskDefinition = definition
+		current[service.Name] = definition
+	}
+	return current, nil
+}
+
+// deployBlueGreen runs a CodeDeploy deployment for services which task definition has been updated by stack update
+func (b *ecsAPIService) deployBlueGreen(ctx context.Context, project *types.Project, current map[string]string, detach bool) error {
+	if len(current) == 0 {
+		return nil
+	}
+	resources, err := b.aws.ListStackResources(ctx, project.Name)
+	if err != nil {
+		return err
+	}
+	physicalIDs := map[string]string{}
+	for _, r := range resources {
+		physicalIDs[r.LogicalID] = r.ARN
+	}
+	cluster, err := b.aws.GetStackClusterID(ctx, project.Name)
+	if err != nil {
+		return err
+	}
+	role, err := b.aws.GetRoleArn(ctx, physicalIDs[codeDeployRole])
+	if err != nil {
+		return err
+	}
+
+	w := progress.ContextWriter(ctx)
+	for _, service := range project.Services {
+		definition, ok := current[service.Name]
+		if !ok {
+			continue
+		}
+		update := physicalIDs[taskDefinitionResourceName(service.Name)]
+		if update == definition {
+			continue
+		}
+		config, err := getBlueGreenConfig(service)
+		if err != nil {
+			return err
+		}
+		if len(config.Alarms) == 0 {
+			// roll back on alarms set by x-aws-alarms
+			config.Alarms, err = serviceAlarmNames(project, service)
+			if err != nil {
+				return err
+			}
+		}
+		port := service.Ports[0]
+		blue := targetGroupResourceName(service, port)
+		listener := listenerResourceName(service, port)
+		id, err := b.aws.DeployBlueGreen(ctx, blueGreenDeployment{
+			Application:     physicalIDs[codeDeployApplication],
+			DeploymentGroup: service.Name,
+			ServiceRole:     role,
+			Cluster:         lastSegment(cluster),
+			Service:         lastSegment(physicalIDs[serviceResourceName(service.Name)]),
+			TargetGroups: []string{
+				targetGroupName(physicalIDs[blue]),
+				targetGroupName(physicalIDs[blue+"Green"]),
+			},
+			Listener:       physicalIDs[listener],
+			TestListener:   physicalIDs[listener+"Test"],
+			TaskDefinition: update,
+			ContainerName:  service.Name,
+			ContainerPort:  int(port.Target),
+			Config:         *config,
+		})
+		if err != nil {
+			return err
+		}
+		w.Event(progress.Event{
+			ID:         service.Name,
+			Status:     progress.Working,
+			StatusText: fmt.Sprintf("CodeDeploy deployment %s in progress", id),
+		})
+		if detach {
+			continue
+		}
+		err = waitWithTimeout(ctx, fmt.Sprintf("CodeDeploy deployment %s", id), func(ctx context.Context) error {
+			return b.aws.WaitDeploymentComplete(ctx, id)
+		})
+		if err != nil {
+			w.Event(progress.Event{
+				ID:         service.Name,
+				Status:     progress.Error,
+				StatusText: fmt.Sprintf("CodeDeploy deployment %s failed", id),
+			})
+			return err
+		}
+		w.Event(progress.Event{
+			ID:         service.Name,
+			Status:     progress.Done,
+			StatusText: fmt.Sprintf("CodeDeploy deployment %s succeeded", id),
+		})
+	}
+	return nil
+}
+
+func lastSegment(arn string) string {
+	return arn[strings.LastIndex(arn, "/")+1:]
+}
+
+// targetGroupName returns the name of a target group from its ARN, arn:aws:elasticloadbalancing:region:account:targetgroup/name/id
+func targetGroupName(arn string) string {
+	parts := strings.Split(arn, "/")
+	if len(parts) < 2 {
+		return arn
+	}
+	return parts[1]
+}
@@ -0,0 +1,330 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/cloudformation/cloudwatch"
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"github.com/awslabs/goformation/v4/cloudformation/elasticloadbalancingv2"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestBlueGreenDeploymentController(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  foo:
+    image: nginx
+    ports:
+      - 80:80
+    x-aws-blue_green:
+      strategy: canary
+      percentage: 10
+      interval: 5
+  bar:
+    image: nginx
+`, useDefaultVPC)
+	service := template.Resources["FooService"].(*ecs.Service)
+	assert.Equal(t, service.DeploymentController.Type, "CODE_DEPLOY")
+	service = template.Resources["BarService"].(*ecs.Service)
+	assert.Equal(t, service.DeploymentController.Type, "ECS")
+	for _, r := range []string{"FooTCP80TargetGroupGreen", "CodeDeployApplication", "CodeDeployRole"} {
+		_, ok := template.Resources[r]
+		assert.Check(t, ok, r)
+	}
+}
+
+func TestBlueGreenTestListener(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  foo:
+    image: nginx
+    ports:
+      - 80:80
+    x-aws-blue_green:
+      test_port: 8080
+`, useDefaultVPC)
+	listener := template.Resources["FooTCP80ListenerTest"].(*elasticloadbalancingv2.Listener)
+	assert.Equal(t, listener.Port, 8080)
+	assert.Equal(t, listener.DefaultActions[0].ForwardConfig.TargetGroups[0].TargetGroupArn, cloudformation.Ref("FooTCP80TargetGroupGreen"))
+	prod := template.Resources["FooTCP80Listener"].(*elasticloadbalancingv2.Listener)
+	assert.Equal(t, prod.Port, 80)
+	assert.Equal(t, listener.LoadBalancerArn, prod.LoadBalancerArn)
+	service := template.Resources["FooService"].(*ecs.Service)
+	assert.Check(t, is.Contains(service.AWSCloudFormationDependsOn, "FooTCP80ListenerTest"))
+	_, ok := template.Resources["Default8080Ingress"]
+	assert.Check(t, ok)
+}
+
+func TestBlueGreenWeightedListener(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  foo:
+    image: nginx
+    ports:
+      - 80:80
+    x-aws-blue_green:
+      strategy: canary
+      percentage: 10
+      interval: 5
+`, useDefaultVPC)
+	listener := template.Resources["FooTCP80Listener"].(*elasticloadbalancingv2.Listener)
+	assert.DeepEqual(t, listener.DefaultActions[0].ForwardConfig.TargetGroups, []elasticloadbalancingv2.Listener_TargetGroupTuple{
+		{TargetGroupArn: cloudformation.Ref("FooTCP80TargetGroup"), Weight: 100},
+		{TargetGroupArn: cloudformation.Ref("FooTCP80TargetGroupGreen")},
+	})
+
+	marshalled, err := marshall(template)
+	assert.NilError(t, err)
+	var unmarshalled struct {
+		Resources map[string]struct {
+			Properties struct {
+				DefaultActions []struct {
+					ForwardConfig struct {
+						TargetGroups []map[string]interface{}
+					}
+				}
+			}
+		}
+	}
+	err = json.Unmarshal(marshalled, &unmarshalled)
+	assert.NilError(t, err)
+	actions := unmarshalled.Resources["FooTCP80Listener"].Properties.DefaultActions
+	assert.DeepEqual(t, actions[0].ForwardConfig.TargetGroups, []map[string]interface{}{
+		{"TargetGroupArn": map[string]interface{}{"Ref": "FooTCP80TargetGroup"}, "Weight": float64(100)},
+		{"TargetGroupArn": map[string]interface{}{"Ref": "FooTCP80TargetGroupGreen"}, "Weight": float64(0)},
+	})
+}
+
+func TestBlueGreenNetworkLoadBalancerRequiresTestPort(t *testing.T) {
+	err := convertYamlError(t, `
+services:
+  foo:
+    image: redis
+    ports:
+      - 6379:6379
+    x-aws-blue_green: {}
+`, useDefaultVPC)
+	assert.ErrorContains(t, err, "on a network load balancer requires a test_port")
+}
+
+func TestBlueGreenRollbackAlarms(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  foo:
+    image: nginx
+    ports:
+      - 80:80
+    x-aws-blue_green: {}
+    x-aws-alarms:
+      cpu:
+        threshold: 80
+      http_5xx:
+        threshold: 10
+`)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+	useDefaultVPC(m.EXPECT())
+	backend := &ecsAPIService{aws: m}
+	template, err := backend.convert(context.TODO(), project)
+	assert.NilError(t, err)
+
+	green := template.Resources["FooHttp5xxgreenAlarm"].(*cloudwatch.Alarm)
+	assert.Equal(t, green.AlarmName, "TestBlueGreenRollbackAlarms-foo-http_5xx_green")
+	assert.Equal(t, green.Dimensions[1].Value, cloudformation.GetAtt("FooTCP80TargetGroupGreen", "TargetGroupFullName"))
+
+	alarms, err := serviceAlarmNames(project, project.Services[0])
+	assert.NilError(t, err)
+	assert.DeepEqual(t, alarms, []string{
+		"TestBlueGreenRollbackAlarms-foo-cpu",
+		"TestBlueGreenRollbackAlarms-foo-http_5xx",
+		"TestBlueGreenRollbackAlarms-foo-http_5xx_green",
+	})
+}
+
+func TestBlueGreenCanaryRequiresApplicationLoadBalancer(t *testing.T) {
+	err := convertYamlError(t, `
+services:
+  foo:
+    image: redis
+    ports:
+      - 6379:6379
+    x-aws-blue_green:
+      strategy: canary
+      percentage: 10
+      interval: 5
+`, useDefaultVPC)
+	assert.ErrorContains(t, err, "canary strategy requires an application load balancer")
+}
+
+func TestBlueGreenConfig(t *testing.T) {
+	tests := []struct {
+		yaml     string
+		expected blueGreenConfig
+		err      string
+	}{
+		{
+			yaml:     `x-aws-blue_green: {}`,
+			expected: blueGreenConfig{Strategy: "all_at_once"},
+		},
+		{
+			yaml:     `x-aws-blue_green: {strategy: linear, percentage: 20, interval: 2, alarms: [HighErrorRate]}`,
+			expected: blueGreenConfig{Strategy: "linear", Percentage: 20, Interval: 2, Alarms: []string{"HighErrorRate"}},
+		},
+		{
+			yaml: `x-aws-blue_green: {strategy: canary, interval: 5}`,
+			err:  "requires a percentage between 1 and 99",
+		},
+		{
+			yaml: `x-aws-blue_green: {strategy: linear, percentage: 10}`,
+			err:  "requires an interval in minutes",
+		},
+		{
+			yaml:     `x-aws-blue_green: {test_port: 8080}`,
+			expected: blueGreenConfig{Strategy: "all_at_once", TestPort: 8080},
+		},
+		{
+			yaml: `x-aws-blue_green: {test_port: 70000}`,
+			err:  "test_port 70000 is not a valid port",
+		},
+		{
+			yaml: `x-aws-blue_green: {strategy: shuffle}`,
+			err:  `unsupported x-aws-blue_green strategy "shuffle"`,
+		},
+	}
+	for _, test := range tests {
+		project := loadConfig(t, `
+services:
+  foo:
+    image: nginx
+    `+test.yaml)
+		config, err := getBlueGreenConfig(project.Services[0])
+		if test.err != "" {
+			assert.ErrorContains(t, err, test.err)
+			continue
+		}
+		assert.NilError(t, err)
+		assert.DeepEqual(t, *config, test.expected)
+	}
+}
+
+func TestDeployStrategy(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  foo:
+    image: nginx
+    ports:
+      - 80:80
+  bar:
+    image: nginx
+`)
+	err := applyDeployStrategy(WithDeployStrategy(context.TODO(), DeployStrategyBlueGreen), project)
+	assert.NilError(t, err)
+	for _, service := range project.Services {
+		_, ok := service.Extensions[extensionBlueGreen]
+		assert.Equal(t, ok, service.Name == "foo")
+	}
+
+	err = applyDeployStrategy(WithDeployStrategy(context.TODO(), DeployStrategyRolling), project)
+	assert.NilError(t, err)
+	for _, service := range project.Services {
+		_, ok := service.Extensions[extensionBlueGreen]
+		assert.Check(t, !ok)
+	}
+
+	err = applyDeployStrategy(WithDeployStrategy(context.TODO(), "recreate"), project)
+	assert.ErrorContains(t, err, `unsupported deploy strategy "recreate"`)
+}
+
+func TestBlueGreenDeployment(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  foo:
+    image: nginx
+    ports:
+      - 80:80
+    x-aws-blue_green:
+      test_port: 8080
+      alarms:
+        - HighErrorRate
+`)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+	useDefaultVPC(m.EXPECT())
+	backend := &ecsAPIService{aws: m}
+	template, err := backend.convert(context.TODO(), project)
+	assert.NilError(t, err)
+
+	const (
+		cluster    = "arn:aws:ecs:eu-west-3:123456789012:cluster/TestBlueGreenDeployment"
+		service    = "arn:aws:ecs:eu-west-3:123456789012:service/TestBlueGreenDeployment/TestBlueGreenDeployment-FooService-1A2B3C"
+		current    = "arn:aws:ecs:eu-west-3:123456789012:task-definition/TestBlueGreenDeployment-foo:1"
+		updated    = "arn:aws:ecs:eu-west-3:123456789012:task-definition/TestBlueGreenDeployment-foo:2"
+		blue       = "arn:aws:elasticloadbalancing:eu-west-3:123456789012:targetgroup/blue/1234"
+		green      = "arn:aws:elasticloadbalancing:eu-west-3:123456789012:targetgroup/green/5678"
+		listener   = "arn:aws:elasticloadbalancing:eu-west-3:123456789012:listener/app/lb/1234/5678"
+		test       = "arn:aws:elasticloadbalancing:eu-west-3:123456789012:listener/app/lb/1234/9012"
+		deployment = "d-1234567"
+	)
+	resources := stackResources{
+		{LogicalID: "FooService", Type: awsTypeService, ARN: service},
+		{LogicalID: "FooTCP80TargetGroup", ARN: blue},
+		{LogicalID: "FooTCP80TargetGroupGreen", ARN: green},
+		{LogicalID: "FooTCP80Listener", Type: awsTypeListener, ARN: listener},
+		{LogicalID: "FooTCP80ListenerTest", Type: awsTypeListener, ARN: test},
+		{LogicalID: "CodeDeployApplication", ARN: "application"},
+		{LogicalID: "CodeDeployRole", ARN: "role"},
+	}
+	m.EXPECT().ListStackResources(gomock.Any(), t.Name()).Return(resources, nil)
+	m.EXPECT().GetStackClusterID(gomock.Any(), t.Name()).Return(cluster, nil).Times(2)
+	m.EXPECT().GetServiceTaskDefinition(gomock.Any(), cluster, []string{service}).Return(map[string]string{service: current}, nil)
+
+	deployments, err := backend.keepBlueGreenTaskDefinitions(context.TODO(), project, template)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, deployments, map[string]string{"foo": current})
+	assert.Equal(t, template.Resources["FooService"].(*ecs.Service).TaskDefinition, current)
+
+	resources = append(resources, stackResource{LogicalID: "FooTaskDefinition", ARN: updated})
+	m.EXPECT().ListStackResources(gomock.Any(), t.Name()).Return(resources, nil)
+	m.EXPECT().GetRoleArn(gomock.Any(), "role").Return("arn:aws:iam::123456789012:role/role", nil)
+	m.EXPECT().DeployBlueGreen(gomock.Any(), blueGreenDeployment{
+		Application:     "application",
+		DeploymentGroup: "foo",
+		ServiceRole:     "arn:aws:iam::123456789012:role/role",
+		Cluster:         "TestBlueGreenDeployment",
+		Service:         "TestBlueGreenDeployment-FooService-1A2B3C",
+		TargetGroups:    []string{"blue", "green"},
+		Listener:        listener,
+		TestListener:    test,
+		TaskDefinition:  updated,
+		ContainerName:   "foo",
+		ContainerPort:   80,
+		Config:          blueGreenConfig{Strategy: "all_at_once", Alarms: []string{"HighErrorRate"}, TestPort: 8080},
+	}).Return(deployment, nil)
+	m.EXPECT().WaitDeploymentComplete(gomock.Any(), deployment).Return(nil)
+
+	err = backend.deployBlueGreen(context.TODO(), project, deployments, false)
+	assert.NilError(t, err)
+}
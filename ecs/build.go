@@ -0,0 +1,180 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/progress"
+)
+
+// imageTag is the tag set on images pushed to Amazon ECR. Services are deployed by the digest it resolves to.
+const imageTag = "latest"
+
+type buildKey struct{}
+
+// WithBuild lets backend created with ctx build services images and push them to Amazon ECR on deployment
+func WithBuild(ctx context.Context) context.Context {
+	return context.WithValue(ctx, buildKey{}, true)
+}
+
+func buildEnabled(ctx context.Context) bool {
+	build, ok := ctx.Value(buildKey{}).(bool)
+	return ok && build
+}
+
+// runDocker runs a docker CLI command against the local engine
+var runDocker = func(ctx context.Context, stdin string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "docker", append([]string{"--context", "default"}, args...)...)
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return errors.Wrapf(err, "docker %s: %s", args[0], strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// buildImages builds images of services with a build section, and pushes them to an ECR repository per service.
+// On dry-run, it only reports the images that would be built
+func (b *ecsAPIService) buildImages(ctx context.Context, project *types.Project) error {
+	if !buildEnabled(ctx) {
+		return nil
+	}
+	w := progress.ContextWriter(ctx)
+	loggedIn := false
+	for i, service := range project.Services {
+		if service.Build == nil || !isServiceSelected(ctx, service.Name) {
+			continue
+		}
+		if isDryRun(ctx) {
+			repository := ecrRepositoryName(project, service)
+			if service.Image == "" {
+				service.Image = fmt.Sprintf("%s:%s", repository, imageTag)
+			}
+			service.Build = nil
+			project.Services[i] = service
+			w.Event(progress.Event{
+				ID:         repository,
+				Status:     progress.Done,
+				StatusText: "Would build and push",
+			})
+			continue
+		}
+		if !loggedIn {
+			auth, err := b.aws.GetRegistryAuth(ctx)
+			if err != nil {
+				return err
+			}
+			err = runDocker(ctx, auth.Password, "login", "--username", auth.Username, "--password-stdin", auth.ServerAddress)
+			if err != nil {
+				return err
+			}
+			loggedIn = true
+		}
+
+		repository := ecrRepositoryName(project, service)
+		w.Event(progress.Event{
+			ID:         repository,
+			Status:     progress.Working,
+			StatusText: "Building",
+		})
+		uri, err := b.aws.EnsureRepository(ctx, repository, map[string]string{
+			compose.ProjectTag: project.Name,
+			compose.ServiceTag: service.Name,
+		})
+		if err != nil {
+			return err
+		}
+		image := fmt.Sprintf("%s:%s", uri, imageTag)
+		args, err := buildArgs(service, image)
+		if err != nil {
+			return err
+		}
+		err = runDocker(ctx, "", args...)
+		if err != nil {
+			return err
+		}
+
+		w.Event(progress.Event{
+			ID:         repository,
+			Status:     progress.Working,
+			StatusText: "Pushing",
+		})
+		err = runDocker(ctx, "", "push", image)
+		if err != nil {
+			return err
+		}
+		service.Image = image
+		service.Build = nil
+		project.Services[i] = service
+		w.Event(progress.Event{
+			ID:         repository,
+			Status:     progress.Done,
+			StatusText: "Pushed",
+		})
+	}
+	return nil
+}
+
+// ecrRepositoryName is the ECR repository holding service images, <project>/<service>
+func ecrRepositoryName(project *types.Project, service types.ServiceConfig) string {
+	return strings.ToLower(fmt.Sprintf("%s/%s", project.Name, service.Name))
+}
+
+// buildArgs returns the docker build command line, targeting the platform service runs on
+func buildArgs(service types.ServiceConfig, image string) ([]string, error) {
+	platform, err := servicePlatform(service)
+	if err != nil {
+		return nil, err
+	}
+	build := service.Build
+	args := []string{"build", "--platform", platform, "--tag", image}
+	if build.Dockerfile != "" {
+		dockerfile := build.Dockerfile
+		if !filepath.IsAbs(dockerfile) {
+			dockerfile = filepath.Join(build.Context, dockerfile)
+		}
+		args = append(args, "--file", dockerfile)
+	}
+	if build.Target != "" {
+		args = append(args, "--target", build.Target)
+	}
+	var keys []string
+	for k := range build.Args {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if v := build.Args[k]; v != nil {
+			args = append(args, "--build-arg", fmt.Sprintf("%s=%s", k, *v))
+		} else {
+			args = append(args, "--build-arg", k)
+		}
+	}
+	return append(args, build.Context), nil
+}
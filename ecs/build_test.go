@@ -0,0 +1,111 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func TestBuildImages(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  web:
+    build:
+      context: .
+      args:
+        VERSION: "1.0"
+  db:
+    image: postgres
+`)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+	m.EXPECT().GetRegistryAuth(gomock.Any()).Return(types.AuthConfig{
+		Username:      "AWS",
+		Password:      "s3cr3t",
+		ServerAddress: "https://012345678910.dkr.ecr.us-east-1.amazonaws.com",
+	}, nil)
+	m.EXPECT().EnsureRepository(gomock.Any(), "testbuildimages/web", gomock.Any()).Return("012345678910.dkr.ecr.us-east-1.amazonaws.com/testbuildimages/web", nil)
+
+	web, err := project.GetService("web")
+	assert.NilError(t, err)
+	buildContext := web.Build.Context
+
+	var commands []string
+	defer func(run func(context.Context, string, ...string) error) {
+		runDocker = run
+	}(runDocker)
+	runDocker = func(ctx context.Context, stdin string, args ...string) error {
+		commands = append(commands, strings.Join(args, " "))
+		return nil
+	}
+
+	backend := &ecsAPIService{aws: m}
+	err = backend.buildImages(WithBuild(context.TODO()), project)
+	assert.NilError(t, err)
+
+	web, err = project.GetService("web")
+	assert.NilError(t, err)
+	assert.Equal(t, web.Image, "012345678910.dkr.ecr.us-east-1.amazonaws.com/testbuildimages/web:latest")
+	assert.Check(t, web.Build == nil)
+	db, err := project.GetService("db")
+	assert.NilError(t, err)
+	assert.Equal(t, db.Image, "postgres")
+
+	image := "012345678910.dkr.ecr.us-east-1.amazonaws.com/testbuildimages/web:latest"
+	assert.DeepEqual(t, commands, []string{
+		"login --username AWS --password-stdin https://012345678910.dkr.ecr.us-east-1.amazonaws.com",
+		"build --platform linux/amd64 --tag " + image + " --build-arg VERSION=1.0 " + buildContext,
+		"push " + image,
+	})
+}
+
+func TestBuildImagesDryRun(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  web:
+    build:
+      context: .
+`)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+
+	defer func(run func(context.Context, string, ...string) error) {
+		runDocker = run
+	}(runDocker)
+	runDocker = func(ctx context.Context, stdin string, args ...string) error {
+		t.Fatalf("unexpected docker command in dry-run: %s", strings.Join(args, " "))
+		return nil
+	}
+
+	backend := &ecsAPIService{aws: m}
+	ctx := WithChangeSetReview(WithBuild(context.TODO()), true, false)
+	err := backend.buildImages(ctx, project)
+	assert.NilError(t, err)
+
+	web, err := project.GetService("web")
+	assert.NilError(t, err)
+	assert.Equal(t, web.Image, "testbuildimagesdryrun/web:latest")
+	assert.Check(t, web.Build == nil)
+}
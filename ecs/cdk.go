@@ -0,0 +1,289 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// cdkParameterProperties are the CloudFormation parameter properties CfnParameter supports
+var cdkParameterProperties = map[string]bool{
+	"Type":                  true,
+	"Default":               true,
+	"Description":           true,
+	"AllowedPattern":        true,
+	"AllowedValues":         true,
+	"ConstraintDescription": true,
+	"MaxLength":             true,
+	"MaxValue":              true,
+	"MinLength":             true,
+	"MinValue":              true,
+	"NoEcho":                true,
+}
+
+// renderCDK renders template as a TypeScript CDK stack skeleton, declaring resources as low-level CfnResource which
+// keep CloudFormation logical IDs so that intrinsic functions still apply
+func renderCDK(name string, template rawTemplate) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("import * as cdk from '@aws-cdk/core';\n\n")
+	fmt.Fprintf(&b, "export class %sStack extends cdk.Stack {\n", normalizeResourceName(name))
+	b.WriteString("  constructor(scope: cdk.Construct, id: string, props?: cdk.StackProps) {\n")
+	b.WriteString("    super(scope, id, props);\n")
+	for _, id := range template.parameterIDs() {
+		properties := map[string]interface{}{}
+		for k, v := range template.Parameters[id] {
+			if !cdkParameterProperties[k] {
+				return nil, fmt.Errorf("parameter %s: %w", id, unsupportedConstruct("parameter "+k, FormatCDK))
+			}
+			if k == "NoEcho" {
+				v = fmt.Sprint(v) == "true"
+			}
+			properties[strings.ToLower(k[:1])+k[1:]] = v
+		}
+		props, err := json.MarshalIndent(properties, "    ", "  ")
+		if err != nil {
+			return nil, err
+		}
+		variable := cdkVariable(id, "Parameter")
+		fmt.Fprintf(&b, "\n    const %s = new cdk.CfnParameter(this, '%s', %s);\n", variable, id, props)
+		fmt.Fprintf(&b, "    %s.overrideLogicalId('%s');\n", variable, id)
+	}
+	for _, id := range template.logicalIDs() {
+		resource := template.Resources[id]
+		properties, err := json.MarshalIndent(resource.Properties, "      ", "  ")
+		if err != nil {
+			return nil, err
+		}
+		variable := cdkVariable(id, "Resource")
+		fmt.Fprintf(&b, "\n    const %s = new cdk.CfnResource(this, '%s', {\n", variable, id)
+		fmt.Fprintf(&b, "      type: '%s',\n", resource.Type)
+		if len(resource.Properties) > 0 {
+			fmt.Fprintf(&b, "      properties: %s,\n", properties)
+		}
+		b.WriteString("    });\n")
+		fmt.Fprintf(&b, "    %s.overrideLogicalId('%s');\n", variable, id)
+		for _, override := range resource.overrides() {
+			value, err := json.Marshal(override.value)
+			if err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(&b, "    %s.addOverride('%s', %s);\n", variable, override.name, value)
+		}
+	}
+	for _, id := range template.outputIDs() {
+		output := template.Outputs[id]
+		value, err := json.MarshalIndent(output.Value, "      ", "  ")
+		if err != nil {
+			return nil, err
+		}
+		variable := cdkVariable(id, "Output")
+		fmt.Fprintf(&b, "\n    const %s = new cdk.CfnOutput(this, '%s', {\n", variable, id)
+		fmt.Fprintf(&b, "      value: cdk.Token.asString(%s),\n", value)
+		if output.Description != "" {
+			description, err := json.Marshal(output.Description)
+			if err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(&b, "      description: %s,\n", description)
+		}
+		if export, ok := output.Export.(map[string]interface{}); ok {
+			exportName, err := json.Marshal(export["Name"])
+			if err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(&b, "      exportName: cdk.Token.asString(%s),\n", exportName)
+		}
+		b.WriteString("    });\n")
+		fmt.Fprintf(&b, "    %s.overrideLogicalId('%s');\n", variable, id)
+	}
+	b.WriteString("  }\n}\n")
+	return b.Bytes(), nil
+}
+
+// renderCDKGo renders template as a Go CDK application skeleton, declaring the same CfnResource as renderCDK
+func renderCDKGo(name string, template rawTemplate) ([]byte, error) {
+	stack := normalizeResourceName(name) + "Stack"
+	var b bytes.Buffer
+	b.WriteString("package main\n\n")
+	b.WriteString("import (\n")
+	b.WriteString("\t\"github.com/aws/aws-cdk-go/awscdk\"\n")
+	b.WriteString("\t\"github.com/aws/constructs-go/constructs/v3\"\n")
+	b.WriteString("\t\"github.com/aws/jsii-runtime-go\"\n")
+	b.WriteString(")\n\n")
+	fmt.Fprintf(&b, "func New%s(scope constructs.Construct, id string, props *awscdk.StackProps) awscdk.Stack {\n", stack)
+	b.WriteString("stack := awscdk.NewStack(scope, &id, props)\n")
+	for _, id := range template.parameterIDs() {
+		parameter := template.Parameters[id]
+		variable := cdkVariable(id, "Parameter")
+		fmt.Fprintf(&b, "\n%s := awscdk.NewCfnParameter(stack, jsii.String(%q), &awscdk.CfnParameterProps{\n", variable, id)
+		var keys []string
+		for k := range parameter {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			value, err := goParameterProperty(k, parameter[k])
+			if err != nil {
+				return nil, fmt.Errorf("parameter %s: %w", id, err)
+			}
+			fmt.Fprintf(&b, "%s: %s,\n", k, value)
+		}
+		b.WriteString("})\n")
+		fmt.Fprintf(&b, "%s.OverrideLogicalId(jsii.String(%q))\n", variable, id)
+	}
+	for _, id := range template.logicalIDs() {
+		resource := template.Resources[id]
+		variable := cdkVariable(id, "Resource")
+		fmt.Fprintf(&b, "\n%s := awscdk.NewCfnResource(stack, jsii.String(%q), &awscdk.CfnResourceProps{\n", variable, id)
+		fmt.Fprintf(&b, "Type: jsii.String(%q),\n", resource.Type)
+		if len(resource.Properties) > 0 {
+			fmt.Fprintf(&b, "Properties: &%s,\n", goValue(resource.Properties))
+		}
+		b.WriteString("})\n")
+		fmt.Fprintf(&b, "%s.OverrideLogicalId(jsii.String(%q))\n", variable, id)
+		for _, override := range resource.overrides() {
+			fmt.Fprintf(&b, "%s.AddOverride(jsii.String(%q), %s)\n", variable, override.name, goValue(override.value))
+		}
+	}
+	for _, id := range template.outputIDs() {
+		output := template.Outputs[id]
+		variable := cdkVariable(id, "Output")
+		fmt.Fprintf(&b, "\n%s := awscdk.NewCfnOutput(stack, jsii.String(%q), &awscdk.CfnOutputProps{\n", variable, id)
+		fmt.Fprintf(&b, "Value: awscdk.Token_AsString(%s, nil),\n", goValue(output.Value))
+		if output.Description != "" {
+			fmt.Fprintf(&b, "Description: jsii.String(%q),\n", output.Description)
+		}
+		if export, ok := output.Export.(map[string]interface{}); ok {
+			fmt.Fprintf(&b, "ExportName: awscdk.Token_AsString(%s, nil),\n", goValue(export["Name"]))
+		}
+		b.WriteString("})\n")
+		fmt.Fprintf(&b, "%s.OverrideLogicalId(jsii.String(%q))\n", variable, id)
+	}
+	b.WriteString("\nreturn stack\n}\n\n")
+	b.WriteString("func main() {\n")
+	b.WriteString("app := awscdk.NewApp(nil)\n")
+	fmt.Fprintf(&b, "New%s(app, %q, nil)\n", stack, name)
+	b.WriteString("app.Synth(nil)\n")
+	b.WriteString("}\n")
+	return format.Source(b.Bytes())
+}
+
+type cdkOverride struct {
+	name  string
+	value interface{}
+}
+
+// overrides returns the resource attributes CfnResource doesn't set, added as raw overrides
+func (r rawResource) overrides() []cdkOverride {
+	var overrides []cdkOverride
+	if len(r.DependsOn) > 0 {
+		var dependsOn []interface{}
+		for _, d := range r.DependsOn {
+			dependsOn = append(dependsOn, d)
+		}
+		overrides = append(overrides, cdkOverride{name: "DependsOn", value: dependsOn})
+	}
+	if r.DeletionPolicy != "" {
+		overrides = append(overrides, cdkOverride{name: "DeletionPolicy", value: r.DeletionPolicy})
+	}
+	if r.UpdateReplacePolicy != "" {
+		overrides = append(overrides, cdkOverride{name: "UpdateReplacePolicy", value: r.UpdateReplacePolicy})
+	}
+	return overrides
+}
+
+// goParameterProperty renders a CloudFormation parameter property as the CfnParameterProps field of the same name
+func goParameterProperty(name string, v interface{}) (string, error) {
+	switch name {
+	case "NoEcho":
+		return fmt.Sprintf("jsii.Bool(%t)", fmt.Sprint(v) == "true"), nil
+	case "MaxLength", "MaxValue", "MinLength", "MinValue":
+		n, err := strconv.ParseFloat(fmt.Sprint(v), 64)
+		if err != nil {
+			return "", fmt.Errorf("unexpected %s %v", name, v)
+		}
+		return fmt.Sprintf("jsii.Number(%s)", strconv.FormatFloat(n, 'f', -1, 64)), nil
+	case "AllowedValues":
+		list, ok := v.([]interface{})
+		if !ok {
+			return "", fmt.Errorf("unexpected AllowedValues %v", v)
+		}
+		var values []string
+		for _, item := range list {
+			values = append(values, strconv.Quote(fmt.Sprint(item)))
+		}
+		return fmt.Sprintf("jsii.Strings(%s)", strings.Join(values, ", ")), nil
+	default:
+		if !cdkParameterProperties[name] {
+			return "", unsupportedConstruct("parameter "+name, FormatCDKGo)
+		}
+		return fmt.Sprintf("jsii.String(%q)", fmt.Sprint(v)), nil
+	}
+}
+
+// goValue renders a JSON value as Go literal, passed as is to CloudFormation by CDK
+func goValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "nil"
+	case string:
+		return strconv.Quote(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []interface{}:
+		var b strings.Builder
+		b.WriteString("[]interface{}{\n")
+		for _, item := range v {
+			fmt.Fprintf(&b, "%s,\n", goValue(item))
+		}
+		b.WriteString("}")
+		return b.String()
+	case map[string]interface{}:
+		var keys []string
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var b strings.Builder
+		b.WriteString("map[string]interface{}{\n")
+		for _, k := range keys {
+			fmt.Fprintf(&b, "%s: %s,\n", strconv.Quote(k), goValue(v[k]))
+		}
+		b.WriteString("}")
+		return b.String()
+	default:
+		return fmt.Sprintf("%#v", v)
+	}
+}
+
+// cdkVariable returns a TypeScript or Go identifier for a logical ID, which can't be a reserved word or start with a
+// digit
+func cdkVariable(id string, suffix string) string {
+	variable := strings.ToLower(id[:1]) + id[1:] + suffix
+	if variable[0] >= '0' && variable[0] <= '9' {
+		variable = "_" + variable
+	}
+	return variable
+}
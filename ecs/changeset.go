@@ -0,0 +1,182 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/moby/term"
+	"github.com/morikuni/aec"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+const (
+	changeAdd    = "Add"
+	changeModify = "Modify"
+	changeRemove = "Remove"
+)
+
+// resourceChange is a change to stack resources from a CloudFormation change set
+type resourceChange struct {
+	Action    string
+	LogicalID string
+	Type      string
+	// Replacement is True, False or Conditional when resource is modified
+	Replacement string
+	// Causes are the properties which change requires resource replacement
+	Causes []string
+}
+
+func (c resourceChange) replaced() bool {
+	return c.Action == changeModify && c.Replacement != "" && c.Replacement != "False"
+}
+
+type changeSetReviewKey struct{}
+
+type changeSetReview struct {
+	dryRun      bool
+	autoApprove bool
+}
+
+// WithChangeSetReview makes Up only preview stack changes when dryRun is set, or apply changes replacing or deleting
+// resources without asking for confirmation when autoApprove is set
+func WithChangeSetReview(ctx context.Context, dryRun bool, autoApprove bool) context.Context {
+	return context.WithValue(ctx, changeSetReviewKey{}, changeSetReview{
+		dryRun:      dryRun,
+		autoApprove: autoApprove,
+	})
+}
+
+func isDryRun(ctx context.Context) bool {
+	review, _ := ctx.Value(changeSetReviewKey{}).(changeSetReview)
+	return review.dryRun
+}
+
+// previewStackCreation lists the resources a new stack would create
+func previewStackCreation(template *cloudformation.Template) {
+	var changes []resourceChange
+	for id, r := range template.Resources {
+		changes = append(changes, resourceChange{
+			Action:    changeAdd,
+			LogicalID: id,
+			Type:      r.AWSCloudFormationType(),
+		})
+	}
+	renderChanges(os.Stdout, changes, isTerminal(os.Stdout))
+}
+
+// reviewChangeSet displays the changes stack update will apply and tells if they can be. Dry run never applies
+// changes, and user has to confirm changes which replace or delete resources, as a load balancer or a file system.
+func (b *ecsAPIService) reviewChangeSet(ctx context.Context, changeset string) (bool, error) {
+	review, _ := ctx.Value(changeSetReviewKey{}).(changeSetReview)
+	changes, err := b.aws.DescribeChangeSet(ctx, changeset)
+	if err != nil {
+		return false, err
+	}
+	if review.dryRun {
+		renderChanges(os.Stdout, changes, isTerminal(os.Stdout))
+		return false, nil
+	}
+	if review.autoApprove || !destructiveChanges(changes) {
+		return true, nil
+	}
+
+	// progress writer has nothing to display yet, so that it doesn't interfere with prompt
+	renderChanges(os.Stdout, changes, isTerminal(os.Stdout))
+	if !isTerminal(os.Stdin) {
+		return false, errors.Wrap(errdefs.ErrForbidden, "stack update replaces or deletes resources, use --yes to apply changes in non-interactive mode")
+	}
+	return b.user.Confirm("Apply changes?", false)
+}
+
+func destructiveChanges(changes []resourceChange) bool {
+	for _, c := range changes {
+		if c.Action == changeRemove || c.replaced() {
+			return true
+		}
+	}
+	return false
+}
+
+// renderChanges prints a diff of stack resources, replacements being highlighted as they recreate resources
+func renderChanges(out io.Writer, changes []resourceChange, color bool) {
+	changes = append([]resourceChange{}, changes...)
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].LogicalID < changes[j].LogicalID
+	})
+	if len(changes) == 0 {
+		fmt.Fprintln(out, "No changes to apply.")
+		return
+	}
+	var (
+		added, modified, replaced, removed int
+		table                              bytes.Buffer
+		highlighted                        []bool
+	)
+	w := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+	for _, c := range changes {
+		var symbol, note string
+		switch {
+		case c.Action == changeAdd:
+			symbol = "+"
+			added++
+		case c.Action == changeRemove:
+			symbol = "-"
+			note = "DELETE"
+			removed++
+		case c.replaced():
+			symbol = "-/+"
+			note = "REPLACE"
+			if c.Replacement == "Conditional" {
+				note = "MAY REPLACE"
+			}
+			if len(c.Causes) > 0 {
+				note = fmt.Sprintf("%s (%s)", note, strings.Join(c.Causes, ", "))
+			}
+			replaced++
+		default:
+			symbol = "~"
+			modified++
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", symbol, c.LogicalID, c.Type, note)
+		highlighted = append(highlighted, note != "")
+	}
+	w.Flush() // nolint:errcheck
+	for i, line := range strings.Split(strings.TrimSuffix(table.String(), "\n"), "\n") {
+		line = strings.TrimRight(line, " ")
+		if color && highlighted[i] {
+			line = aec.Apply(line, aec.RedF)
+		}
+		fmt.Fprintln(out, line)
+	}
+	fmt.Fprintf(out, "\n%d to add, %d to modify, %d to replace, %d to delete.\n", added, modified, replaced, removed)
+}
+
+func isTerminal(f *os.File) bool {
+	_, isTerminal := term.GetFdInfo(f)
+	return isTerminal
+}
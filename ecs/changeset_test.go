@@ -0,0 +1,71 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+var testChanges = []resourceChange{
+	{Action: changeModify, LogicalID: "LoadBalancer", Type: "AWS::ElasticLoadBalancingV2::LoadBalancer", Replacement: "True", Causes: []string{"Subnets"}},
+	{Action: changeAdd, LogicalID: "BarService", Type: "AWS::ECS::Service"},
+	{Action: changeModify, LogicalID: "FooService", Type: "AWS::ECS::Service", Replacement: "False"},
+	{Action: changeRemove, LogicalID: "FooFilesystem", Type: "AWS::EFS::FileSystem"},
+}
+
+func TestRenderChanges(t *testing.T) {
+	var b bytes.Buffer
+	renderChanges(&b, testChanges, false)
+	assert.Equal(t, b.String(), `+    BarService     AWS::ECS::Service
+-    FooFilesystem  AWS::EFS::FileSystem                       DELETE
+~    FooService     AWS::ECS::Service
+-/+  LoadBalancer   AWS::ElasticLoadBalancingV2::LoadBalancer  REPLACE (Subnets)
+
+1 to add, 1 to modify, 1 to replace, 1 to delete.
+`)
+}
+
+func TestReviewChangeSet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+	backend := &ecsAPIService{aws: m}
+
+	m.EXPECT().DescribeChangeSet(gomock.Any(), "changeset").Return(testChanges, nil).Times(2)
+	apply, err := backend.reviewChangeSet(WithChangeSetReview(context.TODO(), true, false), "changeset")
+	assert.NilError(t, err)
+	assert.Check(t, !apply)
+
+	apply, err = backend.reviewChangeSet(WithChangeSetReview(context.TODO(), false, true), "changeset")
+	assert.NilError(t, err)
+	assert.Check(t, apply)
+
+	m.EXPECT().DescribeChangeSet(gomock.Any(), "changeset").Return(testChanges[1:3], nil)
+	apply, err = backend.reviewChangeSet(context.TODO(), "changeset")
+	assert.NilError(t, err)
+	assert.Check(t, apply)
+}
+
+func TestDestructiveChanges(t *testing.T) {
+	assert.Check(t, destructiveChanges(testChanges))
+	assert.Check(t, !destructiveChanges(testChanges[1:3]))
+}
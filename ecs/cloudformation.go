@@ -23,6 +23,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/aws/aws-sdk-go/aws/arn"
 	ecsapi "github.com/aws/aws-sdk-go/service/ecs"
 	"github.com/aws/aws-sdk-go/service/elbv2"
 	cloudmapapi "github.com/aws/aws-sdk-go/service/servicediscovery"
@@ -35,15 +36,20 @@ import (
 	"github.com/awslabs/goformation/v4/cloudformation/secretsmanager"
 	cloudmap "github.com/awslabs/goformation/v4/cloudformation/servicediscovery"
 	"github.com/compose-spec/compose-go/types"
+	"github.com/sirupsen/logrus"
 )
 
-func (b *ecsAPIService) Convert(ctx context.Context, project *types.Project) ([]byte, error) {
+func (b *ecsAPIService) Convert(ctx context.Context, project *types.Project, format string) ([]byte, error) {
 	template, err := b.convert(ctx, project)
 	if err != nil {
 		return nil, err
 	}
 
-	return marshall(template)
+	marshalled, err := marshall(template)
+	if err != nil {
+		return nil, err
+	}
+	return renderTemplate(project.Name, marshalled, format)
 }
 
 func (b *ecsAPIService) convert(ctx context.Context, project *types.Project) (*cloudformation.Template, error) {
@@ -52,17 +58,57 @@ func (b *ecsAPIService) convert(ctx context.Context, project *types.Project) (*c
 		return nil, err
 	}
 
+	err = checkVolumesDriverOpts(project)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkWindows(project)
+	if err != nil {
+		return nil, err
+	}
+
 	template := cloudformation.NewTemplate()
 	resources, err := b.parse(ctx, project, template)
 	if err != nil {
 		return nil, err
 	}
 
+	err = checkExternal(project, resources)
+	if err != nil {
+		return nil, err
+	}
+
 	err = b.ensureResources(&resources, project, template)
 	if err != nil {
 		return nil, err
 	}
 
+	err = b.checkDualStack(ctx, project, resources)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = getServiceConnectConfig(project)
+	if err != nil {
+		return nil, err
+	}
+
+	err = b.createVPCEndpoints(ctx, project, template, resources)
+	if err != nil {
+		return nil, err
+	}
+
+	err = b.createSpotStrategy(project, template)
+	if err != nil {
+		return nil, err
+	}
+
+	err = b.createContainerInsights(ctx, project, template, resources)
+	if err != nil {
+		return nil, err
+	}
+
 	for name, secret := range project.Secrets {
 		err := b.createSecret(project, name, secret, template)
 		if err != nil {
@@ -72,8 +118,15 @@ func (b *ecsAPIService) convert(ctx context.Context, project *types.Project) (*c
 
 	b.createLogGroup(project, template)
 
-	// Private DNS namespace will allow DNS name for the services to be <service>.<project>.local
-	b.createCloudMap(project, template, resources.vpc)
+	// Private DNS namespace will allow DNS name for the services to be <service>.<project>.local, or <service>.<x-aws-cloudmap_namespace>
+	if !useExternal(project) {
+		b.createCloudMap(project, template, resources.vpc)
+	}
+
+	err = b.createAppMesh(project, template)
+	if err != nil {
+		return nil, err
+	}
 
 	b.createNFSMountTarget(project, resources, template)
 
@@ -89,6 +142,33 @@ func (b *ecsAPIService) convert(ctx context.Context, project *types.Project) (*c
 		if err != nil {
 			return nil, err
 		}
+
+		err = b.createBlueGreenTargetGroup(project, service, template, resources)
+		if err != nil {
+			return nil, err
+		}
+
+		err = b.createAlarms(project, service, template, resources)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	b.createCodeDeployApplication(project, template)
+
+	err = b.createHTTPRedirect(project, template, resources)
+	if err != nil {
+		return nil, err
+	}
+
+	err = b.createDNSRecord(project, template, resources)
+	if err != nil {
+		return nil, err
+	}
+
+	err = b.createWebACLAssociation(project, template, resources)
+	if err != nil {
+		return nil, err
 	}
 
 	err = b.createCapacityProvider(ctx, project, template, resources)
@@ -101,39 +181,66 @@ func (b *ecsAPIService) convert(ctx context.Context, project *types.Project) (*c
 
 func (b *ecsAPIService) createService(project *types.Project, service types.ServiceConfig, template *cloudformation.Template, resources awsResources) error {
 	taskExecutionRole := b.createTaskExecutionRole(project, service, template)
-	taskRole := b.createTaskRole(project, service, template, resources)
 
 	definition, err := b.createTaskDefinition(project, service, resources)
 	if err != nil {
 		return err
 	}
 	definition.ExecutionRoleArn = cloudformation.Ref(taskExecutionRole)
-	if taskRole != "" {
+	if role, ok := service.Extensions[extensionTaskRole]; ok {
+		_, hasRole := service.Extensions[extensionRole]
+		_, hasPolicies := service.Extensions[extensionManagedPolicies]
+		if hasRole || hasPolicies {
+			return fmt.Errorf("service %q: %s can't be used with %s or %s", service.Name, extensionTaskRole, extensionRole, extensionManagedPolicies)
+		}
+		definition.TaskRoleArn = roleARN(fmt.Sprint(role))
+	} else if taskRole := b.createTaskRole(project, service, template, resources); taskRole != "" {
 		definition.TaskRoleArn = cloudformation.Ref(taskRole)
 	}
 
-	taskDefinition := fmt.Sprintf("%sTaskDefinition", normalizeResourceName(service.Name))
+	taskDefinition := taskDefinitionResourceName(service.Name)
 	template.Resources[taskDefinition] = definition
 
-	var healthCheck *cloudmap.Service_HealthCheckConfig
-	serviceRegistry := b.createServiceRegistry(service, template, healthCheck)
+	if schedule, ok := service.Extensions[extensionSchedule]; ok {
+		return b.createScheduledTask(project, service, fmt.Sprint(schedule), taskDefinition, template, resources)
+	}
+
+	external := useExternal(project)
+	var serviceRegistries []ecs.Service_ServiceRegistry
+	if !useServiceConnect(project, service) && !external {
+		var healthCheck *cloudmap.Service_HealthCheckConfig
+		serviceRegistries = append(serviceRegistries, b.createServiceRegistry(service, template, healthCheck))
+	}
 
 	var (
 		dependsOn []string
 		serviceLB []ecs.Service_LoadBalancer
 	)
-	for _, port := range service.Ports {
+	ports := service.Ports
+	if external {
+		// ports are published by the instances tasks are placed on
+		ports = nil
+	}
+	for _, port := range ports {
 		for net := range service.Networks {
-			b.createIngress(service, net, port, template, resources)
+			b.createIngress(project, service, net, port, template, resources)
 		}
 
+		loadBalancer, loadBalancerType := resources.portLoadBalancer(service, port)
 		protocol := strings.ToUpper(port.Protocol)
-		if resources.loadBalancerType == elbv2.LoadBalancerTypeEnumApplication {
-			// we don't set Https as a certificate must be specified for HTTPS listeners
+		if loadBalancerType == elbv2.LoadBalancerTypeEnumApplication {
+			// TLS is terminated by load balancer, listener only uses HTTPS when a certificate is set
 			protocol = elbv2.ProtocolEnumHttp
 		}
-		targetGroupName := b.createTargetGroup(project, service, port, template, protocol, resources.vpc)
-		listenerName := b.createListener(service, port, template, targetGroupName, resources.loadBalancer, protocol)
+		certificate, err := listenerCertificate(service, port, loadBalancerType)
+		if err != nil {
+			return err
+		}
+		targetGroupName, err := b.createTargetGroup(project, service, port, template, protocol, resources.vpc)
+		if err != nil {
+			return err
+		}
+		listenerName := b.createListener(service, port, template, targetGroupName, loadBalancer, protocol, certificate)
 		dependsOn = append(dependsOn, listenerName)
 		serviceLB = append(serviceLB, ecs.Service_LoadBalancer{
 			ContainerName:  service.Name,
@@ -148,6 +255,10 @@ func (b *ecsAPIService) createService(project *types.Project, service types.Serv
 	}
 
 	for dependency := range service.DependsOn {
+		if isScheduled(project, dependency) {
+			// scheduled tasks don't run as a service to wait for
+			continue
+		}
 		dependsOn = append(dependsOn, serviceResourceName(dependency))
 	}
 
@@ -159,18 +270,48 @@ func (b *ecsAPIService) createService(project *types.Project, service types.Serv
 	if err != nil {
 		return err
 	}
-
-	assignPublicIP := ecsapi.AssignPublicIpEnabled
-	launchType := ecsapi.LaunchTypeFargate
-	platformVersion := "1.4.0" // LATEST which is set to 1.3.0 (?) which doesn’t allow efs volumes.
-	if requireEC2(service) {
-		assignPublicIP = ecsapi.AssignPublicIpDisabled
-		launchType = ecsapi.LaunchTypeEc2
-		platformVersion = "" // The platform version must be null when specifying an EC2 launch type
+	circuitBreaker, err := deploymentCircuitBreaker(service)
+	if err != nil {
+		return err
+	}
+	extra := map[string]interface{}{}
+	if circuitBreaker != nil {
+		extra["DeploymentConfiguration"] = map[string]interface{}{
+			"DeploymentCircuitBreaker": circuitBreaker,
+		}
+	}
+	if execEnabled(service) {
+		extra["EnableExecuteCommand"] = true
+	}
+	if useServiceConnect(project, service) {
+		extra["ServiceConnectConfiguration"] = createServiceConnect(project, service)
+	}
+	spot, err := spotCapacityProviderStrategy(project, service)
+	if err != nil {
+		return err
+	}
+	if spot != nil {
+		extra["CapacityProviderStrategy"] = spot
+	}
+	var metadata map[string]interface{}
+	if len(extra) > 0 {
+		metadata = extraProperties(extra)
 	}
 
+	launchType, platformVersion, assignPublicIP := serviceLaunchType(project, service)
+	networkConfiguration := &ecs.Service_NetworkConfiguration{
+		AwsvpcConfiguration: &ecs.Service_AwsVpcConfiguration{
+			AssignPublicIp: assignPublicIP,
+			SecurityGroups: resources.serviceSecurityGroups(service),
+			Subnets:        resources.subnetsIDs(),
+		},
+	}
+	if external {
+		networkConfiguration = nil
+	}
 	template.Resources[serviceResourceName(service.Name)] = &ecs.Service{
 		AWSCloudFormationDependsOn: dependsOn,
+		AWSCloudFormationMetadata:  metadata,
 		Cluster:                    resources.cluster.ARN(),
 		DesiredCount:               desiredCount,
 		DeploymentController: &ecs.Service_DeploymentController{
@@ -180,35 +321,55 @@ func (b *ecsAPIService) createService(project *types.Project, service types.Serv
 			MaximumPercent:        maxPercent,
 			MinimumHealthyPercent: minPercent,
 		},
-		LaunchType: launchType,
-		// TODO we miss support for https://github.com/aws/containers-roadmap/issues/631 to select a capacity provider
-		LoadBalancers: serviceLB,
-		NetworkConfiguration: &ecs.Service_NetworkConfiguration{
-			AwsvpcConfiguration: &ecs.Service_AwsVpcConfiguration{
-				AssignPublicIp: assignPublicIP,
-				SecurityGroups: resources.serviceSecurityGroups(service),
-				Subnets:        resources.subnetsIDs(),
-			},
-		},
-		PlatformVersion:    platformVersion,
-		PropagateTags:      ecsapi.PropagateTagsService,
-		SchedulingStrategy: ecsapi.SchedulingStrategyReplica,
-		ServiceRegistries:  []ecs.Service_ServiceRegistry{serviceRegistry},
-		Tags:               serviceTags(project, service),
-		TaskDefinition:     cloudformation.Ref(normalizeResourceName(taskDefinition)),
+		LaunchType:           launchType,
+		LoadBalancers:        serviceLB,
+		NetworkConfiguration: networkConfiguration,
+		PlatformVersion:      platformVersion,
+		PropagateTags:        ecsapi.PropagateTagsService,
+		SchedulingStrategy:   ecsapi.SchedulingStrategyReplica,
+		ServiceRegistries:    serviceRegistries,
+		Tags:                 serviceTags(project, service),
+		TaskDefinition:       cloudformation.Ref(normalizeResourceName(taskDefinition)),
 	}
 	return nil
 }
 
+// serviceLaunchType returns the launch type, platform version and public IP assignment tasks for service run with
+func serviceLaunchType(project *types.Project, service types.ServiceConfig) (string, string, string) {
+	assignPublicIP := ecsapi.AssignPublicIpEnabled
+	launchType := ecsapi.LaunchTypeFargate
+	platformVersion := "1.4.0" // LATEST which is set to 1.3.0 (?) which doesn’t allow efs volumes.
+	if useExternal(project) {
+		return launchTypeExternal, "", ""
+	}
+	if requireEC2(project, service) {
+		assignPublicIP = ecsapi.AssignPublicIpDisabled
+		launchType = ecsapi.LaunchTypeEc2
+		platformVersion = "" // The platform version must be null when specifying an EC2 launch type
+		if _, ok := project.Extensions[extensionEC2]; ok {
+			launchType = "" // use cluster default capacity provider strategy, so that managed scaling applies
+		}
+	} else if _, ok := project.Extensions[extensionSpot]; ok {
+		launchType = "" // use x-aws-spot capacity provider strategy
+	}
+	if isWindows(service) && platformVersion != "" {
+		platformVersion = windowsFargatePlatformVersion
+	}
+	if useVPCEndpoints(project) {
+		assignPublicIP = ecsapi.AssignPublicIpDisabled
+	}
+	return launchType, platformVersion, assignPublicIP
+}
+
 const allProtocols = "-1"
 
-func (b *ecsAPIService) createIngress(service types.ServiceConfig, net string, port types.ServicePortConfig, template *cloudformation.Template, resources awsResources) {
+func (b *ecsAPIService) createIngress(project *types.Project, service types.ServiceConfig, net string, port types.ServicePortConfig, template *cloudformation.Template, resources awsResources) {
 	protocol := strings.ToUpper(port.Protocol)
 	if protocol == "" {
 		protocol = allProtocols
 	}
 	ingress := fmt.Sprintf("%s%dIngress", normalizeResourceName(net), port.Target)
-	template.Resources[ingress] = &ec2.SecurityGroupIngress{
+	rule := ec2.SecurityGroupIngress{
 		CidrIp:      "0.0.0.0/0",
 		Description: fmt.Sprintf("%s:%d/%s on %s nextwork", service.Name, port.Target, port.Protocol, net),
 		GroupId:     resources.securityGroups[net],
@@ -216,6 +377,8 @@ func (b *ecsAPIService) createIngress(service types.ServiceConfig, net string, p
 		IpProtocol:  protocol,
 		ToPort:      int(port.Target),
 	}
+	template.Resources[ingress] = &rule
+	createIPv6Ingress(project, template, ingress, rule)
 }
 
 func (b *ecsAPIService) createSecret(project *types.Project, name string, s types.SecretConfig, template *cloudformation.Template) error {
@@ -228,11 +391,15 @@ func (b *ecsAPIService) createSecret(project *types.Project, name string, s type
 	}
 
 	resource := fmt.Sprintf("%sSecret", normalizeResourceName(s.Name))
-	template.Resources[resource] = &secretsmanager.Secret{
+	secret := &secretsmanager.Secret{
 		Description:  fmt.Sprintf("Secret %s", s.Name),
 		SecretString: string(sensitiveData),
 		Tags:         projectTags(project),
 	}
+	if key, ok := projectKMSKey(project, extensionSecretsKMSKey); ok {
+		secret.KmsKeyId = key
+	}
+	template.Resources[resource] = secret
 	s.Name = cloudformation.Ref(resource)
 	project.Secrets[name] = s
 	return nil
@@ -244,10 +411,39 @@ func (b *ecsAPIService) createLogGroup(project *types.Project, template *cloudfo
 		retention = v.(int)
 	}
 	logGroup := fmt.Sprintf("/docker-compose/%s", project.Name)
-	template.Resources["LogGroup"] = &logs.LogGroup{
+	resource := &logs.LogGroup{
 		LogGroupName:    logGroup,
 		RetentionInDays: retention,
 	}
+	if key, ok := projectKMSKey(project, extensionLogsKMSKey); ok {
+		// key policy must allow CloudWatch Logs service to use it
+		resource.AWSCloudFormationMetadata = extraProperties(map[string]interface{}{
+			"KmsKeyId": key,
+		})
+	}
+	template.Resources["LogGroup"] = resource
+}
+
+// projectKMSKey returns the ARN of the KMS key set by extension for a kind of resources, or else by x-aws-kms_key for
+// all resources created by project
+func projectKMSKey(project *types.Project, extension string) (string, bool) {
+	for _, x := range []string{extension, extensionKMSKey} {
+		if key, ok := project.Extensions[x]; ok {
+			return kmsKeyARN(fmt.Sprint(key)), true
+		}
+	}
+	return "", false
+}
+
+// kmsKeyARN returns the ARN of a KMS key set by ARN, ID or alias in the stack account and region
+func kmsKeyARN(key string) string {
+	if arn.IsARN(key) {
+		return key
+	}
+	if !strings.HasPrefix(key, "alias/") {
+		key = "key/" + key
+	}
+	return cloudformation.Sub("arn:${AWS::Partition}:kms:${AWS::Region}:${AWS::AccountId}:" + key)
 }
 
 func computeRollingUpdateLimits(service types.ServiceConfig) (int, int, error) {
@@ -290,18 +486,36 @@ func computeRollingUpdateLimits(service types.ServiceConfig) (int, int, error) {
 	return minPercent, maxPercent, nil
 }
 
+// deploymentCircuitBreaker maps deploy.update_config.failure_action to ECS deployment circuit breaker
+func deploymentCircuitBreaker(service types.ServiceConfig) (map[string]interface{}, error) {
+	if service.Deploy == nil || service.Deploy.UpdateConfig == nil {
+		return nil, nil
+	}
+	action := service.Deploy.UpdateConfig.FailureAction
+	if action == "" || action == "continue" {
+		return nil, nil
+	}
+	if _, ok := service.Extensions[extensionBlueGreen]; ok {
+		return nil, fmt.Errorf("service %q: deploy.update_config.failure_action can't be used with %s, which rolls back failed deployments", service.Name, extensionBlueGreen)
+	}
+	switch action {
+	case "rollback":
+		return map[string]interface{}{"Enable": true, "Rollback": true}, nil
+	case "pause":
+		// ECS can't pause a deployment, but stops it and marks it as failed
+		return map[string]interface{}{"Enable": true, "Rollback": false}, nil
+	default:
+		return nil, fmt.Errorf("service %q: unsupported deploy.update_config.failure_action %q", service.Name, action)
+	}
+}
+
 func (b *ecsAPIService) createListener(service types.ServiceConfig, port types.ServicePortConfig,
 	template *cloudformation.Template,
-	targetGroupName string, loadBalancer awsResource, protocol string) string {
-	listenerName := fmt.Sprintf(
-		"%s%s%dListener",
-		normalizeResourceName(service.Name),
-		strings.ToUpper(port.Protocol),
-		port.Target,
-	)
+	targetGroupName string, loadBalancer awsResource, protocol string, certificate string) string {
+	listenerName := listenerResourceName(service, port)
 	//add listener to dependsOn
 	//https://stackoverflow.com/questions/53971873/the-target-group-does-not-have-an-associated-load-balancer
-	template.Resources[listenerName] = &elasticloadbalancingv2.Listener{
+	listener := &elasticloadbalancingv2.Listener{
 		DefaultActions: []elasticloadbalancingv2.Listener_Action{
 			{
 				ForwardConfig: &elasticloadbalancingv2.Listener_ForwardConfig{
@@ -318,24 +532,48 @@ func (b *ecsAPIService) createListener(service types.ServiceConfig, port types.S
 		Protocol:        protocol,
 		Port:            int(port.Target),
 	}
+	if certificate != "" {
+		listener.Protocol = elbv2.ProtocolEnumHttps
+		listener.Certificates = []elasticloadbalancingv2.Listener_Certificate{
+			{CertificateArn: certificate},
+		}
+	}
+	template.Resources[listenerName] = listener
 	return listenerName
 }
 
-func (b *ecsAPIService) createTargetGroup(project *types.Project, service types.ServiceConfig, port types.ServicePortConfig, template *cloudformation.Template, protocol string, vpc string) string {
-	targetGroupName := fmt.Sprintf(
-		"%s%s%dTargetGroup",
-		normalizeResourceName(service.Name),
-		strings.ToUpper(port.Protocol),
-		port.Published,
-	)
-	template.Resources[targetGroupName] = &elasticloadbalancingv2.TargetGroup{
+func (b *ecsAPIService) createTargetGroup(project *types.Project, service types.ServiceConfig, port types.ServicePortConfig, template *cloudformation.Template, protocol string, vpc string) (string, error) {
+	targetGroupName := targetGroupResourceName(service, port)
+	targetGroup := &elasticloadbalancingv2.TargetGroup{
 		Port:       int(port.Target),
 		Protocol:   protocol,
 		Tags:       projectTags(project),
 		TargetType: elbv2.TargetTypeEnumIp,
 		VpcId:      vpc,
 	}
-	return targetGroupName
+	healthCheck, err := getTargetHealthCheck(service, port)
+	if err != nil {
+		return "", err
+	}
+	targetGroup.TargetGroupAttributes, err = targetGroupAttributes(service, protocol)
+	if err != nil {
+		return "", err
+	}
+	if protocol == elbv2.ProtocolEnumHttp || protocol == elbv2.ProtocolEnumHttps {
+		healthCheck.apply(targetGroup)
+	} else if protocol == elbv2.ProtocolEnumUdp {
+		// UDP targets can only be checked by TCP or HTTP
+		checkPort, err := udpHealthCheckPort(service, port, healthCheck)
+		if err != nil {
+			return "", err
+		}
+		targetGroup.HealthCheckProtocol = elbv2.ProtocolEnumTcp
+		targetGroup.HealthCheckPort = fmt.Sprint(checkPort)
+	} else if _, ok := service.Extensions[extensionHealthCheck]; ok {
+		logrus.Warnf("service %q: %s only applies to application load balancer", service.Name, extensionHealthCheck)
+	}
+	template.Resources[targetGroupName] = targetGroup
+	return targetGroupName, nil
 }
 
 func (b *ecsAPIService) createServiceRegistry(service types.ServiceConfig, template *cloudformation.Template, healthCheck *cloudmap.Service_HealthCheckConfig) ecs.Service_ServiceRegistry {
@@ -390,17 +628,37 @@ func (b *ecsAPIService) createTaskRole(project *types.Project, service types.Ser
 		})
 	}
 	for _, vol := range service.Volumes {
+		if !transitEncryption(project.Volumes[vol.Source]) {
+			// IAM authorization requires transit encryption
+			continue
+		}
 		rolePolicies = append(rolePolicies, iam.Role_Policy{
 			PolicyName:     fmt.Sprintf("%s%sVolumeMountPolicy", normalizeResourceName(project.Name), normalizeResourceName(service.Name)),
 			PolicyDocument: volumeMountPolicyDocument(vol.Source, resources.filesystems[vol.Source].ARN()),
 		})
 	}
+	if execEnabled(service) {
+		rolePolicies = append(rolePolicies, iam.Role_Policy{
+			PolicyName:     fmt.Sprintf("%s%sExecPolicy", normalizeResourceName(project.Name), normalizeResourceName(service.Name)),
+			PolicyDocument: execPolicyDocument,
+		})
+	}
+	if inMesh(project, service) {
+		config, _ := getAppMeshConfig(project)
+		rolePolicies = append(rolePolicies, iam.Role_Policy{
+			PolicyName:     fmt.Sprintf("%s%sAppMeshPolicy", normalizeResourceName(project.Name), normalizeResourceName(service.Name)),
+			PolicyDocument: appMeshPolicyDocument(virtualNodeName(service.Name), config),
+		})
+	}
 	managedPolicies := []string{}
 	if v, ok := service.Extensions[extensionManagedPolicies]; ok {
 		for _, s := range v.([]interface{}) {
 			managedPolicies = append(managedPolicies, s.(string))
 		}
 	}
+	if useXRay(service) {
+		managedPolicies = append(managedPolicies, xrayDaemonPolicy)
+	}
 	if len(rolePolicies) == 0 && len(managedPolicies) == 0 {
 		return ""
 	}
@@ -416,30 +674,56 @@ func (b *ecsAPIService) createTaskRole(project *types.Project, service types.Ser
 func (b *ecsAPIService) createCloudMap(project *types.Project, template *cloudformation.Template, vpc string) {
 	template.Resources["CloudMap"] = &cloudmap.PrivateDnsNamespace{
 		Description: fmt.Sprintf("Service Map for Docker Compose project %s", project.Name),
-		Name:        fmt.Sprintf("%s.local", project.Name),
+		Name:        cloudMapNamespace(project),
 		Vpc:         vpc,
 	}
 }
 
+// cloudMapNamespace returns the private DNS namespace services are registered in
+func cloudMapNamespace(project *types.Project) string {
+	if v, ok := project.Extensions[extensionNamespace]; ok {
+		return strings.TrimSuffix(fmt.Sprint(v), ".")
+	}
+	return fmt.Sprintf("%s.local", project.Name)
+}
+
 func (b *ecsAPIService) createPolicies(project *types.Project, service types.ServiceConfig) []iam.Role_Policy {
 	var arns []string
-	if value, ok := service.Extensions[extensionPullCredentials]; ok {
-		arns = append(arns, value.(string))
+	if value, ok := pullCredentials(project, service); ok {
+		arns = append(arns, value)
+	}
+	var sources []string
+	if v, ok := service.Extensions[extensionPullCredentials]; ok {
+		sources = append(sources, fmt.Sprint(v))
 	}
 	for _, secret := range service.Secrets {
 		arns = append(arns, project.Secrets[secret.Source].Name)
+		sources = append(sources, secret.Source)
+	}
+	var keys []string
+	for _, source := range sources {
+		secret, ok := project.Secrets[source]
+		if !ok || secret.External.External {
+			continue
+		}
+		if key, ok := projectKMSKey(project, extensionSecretsKMSKey); ok {
+			keys = []string{key}
+		}
+	}
+	for _, s := range toSSMSecrets(service) {
+		arns = append(arns, s.ValueFrom)
 	}
 	if len(arns) > 0 {
 		return []iam.Role_Policy{
 			{
 				PolicyDocument: &PolicyDocument{
-					Statement: []PolicyStatement{
+					Statement: append([]PolicyStatement{
 						{
 							Effect:   "Allow",
 							Action:   []string{actionGetSecretValue, actionGetParameters, actionDecrypt},
 							Resource: arns,
 						},
-					},
+					}, kmsDecryptStatements(keys)...),
 				},
 				PolicyName: fmt.Sprintf("%sGrantAccessToSecrets", service.Name),
 			},
@@ -448,6 +732,20 @@ func (b *ecsAPIService) createPolicies(project *types.Project, service types.Ser
 	return nil
 }
 
+// kmsDecryptStatements grant decryption of secrets by the customer managed keys they are encrypted with
+func kmsDecryptStatements(keys []string) []PolicyStatement {
+	if len(keys) == 0 {
+		return nil
+	}
+	return []PolicyStatement{
+		{
+			Effect:   "Allow",
+			Action:   []string{actionDecrypt},
+			Resource: keys,
+		},
+	}
+}
+
 func networkResourceName(network string) string {
 	return fmt.Sprintf("%sNetwork", normalizeResourceName(network))
 }
@@ -456,6 +754,18 @@ func serviceResourceName(service string) string {
 	return fmt.Sprintf("%sService", normalizeResourceName(service))
 }
 
+func taskDefinitionResourceName(service string) string {
+	return fmt.Sprintf("%sTaskDefinition", normalizeResourceName(service))
+}
+
+func targetGroupResourceName(service types.ServiceConfig, port types.ServicePortConfig) string {
+	return fmt.Sprintf("%s%s%dTargetGroup", normalizeResourceName(service.Name), strings.ToUpper(port.Protocol), port.Published)
+}
+
+func listenerResourceName(service types.ServiceConfig, port types.ServicePortConfig) string {
+	return fmt.Sprintf("%s%s%dListener", normalizeResourceName(service.Name), strings.ToUpper(port.Protocol), port.Target)
+}
+
 func volumeResourceName(service string) string {
 	return fmt.Sprintf("%sFilesystem", normalizeResourceName(service))
 }
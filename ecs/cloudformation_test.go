@@ -18,12 +18,16 @@ package ecs
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"path/filepath"
 	"reflect"
 	"testing"
 
 	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/api/secrets"
+	"github.com/docker/compose-cli/context/store"
 
 	"github.com/aws/aws-sdk-go/service/elbv2"
 	"github.com/awslabs/goformation/v4/cloudformation"
@@ -33,10 +37,13 @@ import (
 	"github.com/awslabs/goformation/v4/cloudformation/elasticloadbalancingv2"
 	"github.com/awslabs/goformation/v4/cloudformation/iam"
 	"github.com/awslabs/goformation/v4/cloudformation/logs"
+	"github.com/awslabs/goformation/v4/cloudformation/secretsmanager"
+	cloudmap "github.com/awslabs/goformation/v4/cloudformation/servicediscovery"
 	"github.com/compose-spec/compose-go/loader"
 	"github.com/compose-spec/compose-go/types"
 	"github.com/golang/mock/gomock"
 	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
 	"gotest.tools/v3/golden"
 )
 
@@ -74,6 +81,51 @@ x-aws-logs_retention: 10
 	assert.Equal(t, logGroup.RetentionInDays, 10)
 }
 
+func TestLoggingOptions(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  foo:
+    image: hello_world
+    logging:
+      options:
+        awslogs-multiline-pattern: "^\\d{4}"
+        mode: non-blocking
+        max-buffer-size: 4m
+        tag: ignored
+`, useDefaultVPC)
+	def := template.Resources["FooTaskDefinition"].(*ecs.TaskDefinition)
+	options := getMainContainer(def, t).LogConfiguration.Options
+	assert.Equal(t, options["awslogs-multiline-pattern"], `^\d{4}`)
+	assert.Equal(t, options["mode"], "non-blocking")
+	assert.Equal(t, options["max-buffer-size"], "4m")
+	_, ok := options["tag"]
+	assert.Check(t, !ok)
+}
+
+func TestLogGroupKMSKey(t *testing.T) {
+	const key = "arn:aws:kms:eu-west-3:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"
+	template := convertYaml(t, `
+services:
+  foo:
+    image: hello_world
+x-aws-logs_kms_key: `+key+`
+`, useDefaultVPC)
+	marshalled, err := marshall(template)
+	assert.NilError(t, err)
+	var parsed struct {
+		Resources map[string]struct {
+			Metadata   map[string]interface{}
+			Properties map[string]interface{}
+		}
+	}
+	assert.NilError(t, json.Unmarshal(marshalled, &parsed))
+	logGroup := parsed.Resources["LogGroup"]
+	assert.Check(t, logGroup.Metadata == nil)
+	assert.Equal(t, logGroup.Properties["KmsKeyId"], key)
+
+	assert.Equal(t, kmsKeyARN("alias/logs"), cloudformation.Sub("arn:${AWS::Partition}:kms:${AWS::Region}:${AWS::AccountId}:alias/logs"))
+}
+
 func TestEnvFile(t *testing.T) {
 	template := convertYaml(t, `
 services:
@@ -146,6 +198,62 @@ services:
 	assert.Check(t, service.DeploymentConfiguration.MinimumHealthyPercent == 25)
 }
 
+func TestRollingUpdateCircuitBreaker(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  foo:
+    image: hello_world
+    deploy:
+      update_config:
+        failure_action: rollback
+  bar:
+    image: hello_world
+`, useDefaultVPC)
+	marshalled, err := marshall(template)
+	assert.NilError(t, err)
+	var parsed struct {
+		Resources map[string]struct {
+			Metadata   map[string]interface{}
+			Properties struct {
+				DeploymentConfiguration map[string]interface{}
+			}
+		}
+	}
+	assert.NilError(t, json.Unmarshal(marshalled, &parsed))
+	foo := parsed.Resources["FooService"]
+	assert.Check(t, foo.Metadata == nil)
+	assert.DeepEqual(t, foo.Properties.DeploymentConfiguration["DeploymentCircuitBreaker"], map[string]interface{}{"Enable": true, "Rollback": true})
+	_, ok := parsed.Resources["BarService"].Properties.DeploymentConfiguration["DeploymentCircuitBreaker"]
+	assert.Check(t, !ok)
+}
+
+func TestEphemeralStorage(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  foo:
+    image: hello_world
+    x-aws-ephemeral_storage: 100
+`, useDefaultVPC)
+	marshalled, err := marshall(template)
+	assert.NilError(t, err)
+	var parsed struct {
+		Resources map[string]struct {
+			Properties map[string]interface{}
+		}
+	}
+	assert.NilError(t, json.Unmarshal(marshalled, &parsed))
+	assert.DeepEqual(t, parsed.Resources["FooTaskDefinition"].Properties["EphemeralStorage"], map[string]interface{}{"SizeInGiB": float64(100)})
+
+	project := loadConfig(t, `
+services:
+  foo:
+    image: hello_world
+    x-aws-ephemeral_storage: 10
+`)
+	_, err = ephemeralStorage(project, project.Services[0])
+	assert.ErrorContains(t, err, "must be a size in GiB between 21 and 200")
+}
+
 func TestRolePolicy(t *testing.T) {
 	template := convertYaml(t, `
 services:
@@ -166,6 +274,295 @@ services:
 	assert.DeepEqual(t, []string{"secret"}, policy.Statement[0].Resource)
 }
 
+func TestCreateSecretFromFile(t *testing.T) {
+	dir := fs.NewDir(t, "secrets", fs.WithFile("password.txt", "s3cr3t"))
+	template := convertYaml(t, fmt.Sprintf(`
+services:
+  foo:
+    image: hello_world
+    secrets:
+      - db-password
+secrets:
+  db-password:
+    file: %s
+`, filepath.Join(dir.Path(), "password.txt")), useDefaultVPC)
+	var secret string
+	for name, r := range template.Resources {
+		if s, ok := r.(*secretsmanager.Secret); ok {
+			assert.Equal(t, s.SecretString, "s3cr3t")
+			secret = name
+		}
+	}
+	assert.Assert(t, secret != "")
+
+	def := template.Resources["FooTaskDefinition"].(*ecs.TaskDefinition)
+	sidecar := def.ContainerDefinitions[0]
+	assert.Equal(t, sidecar.Secrets[0].Name, "db-password")
+	assert.Equal(t, sidecar.Secrets[0].ValueFrom, cloudformation.Ref(secret))
+}
+
+func TestPullCredentialsFromSecret(t *testing.T) {
+	dir := fs.NewDir(t, "secrets", fs.WithFile("creds.json", `{"username":"user","password":"s3cr3t"}`))
+	template := convertYaml(t, fmt.Sprintf(`
+services:
+  foo:
+    image: user/private
+    x-aws-pull_credentials: registry
+secrets:
+  registry:
+    file: %s
+`, filepath.Join(dir.Path(), "creds.json")), useDefaultVPC)
+	var secret string
+	for name, r := range template.Resources {
+		if _, ok := r.(*secretsmanager.Secret); ok {
+			secret = name
+		}
+	}
+	assert.Assert(t, secret != "")
+
+	def := template.Resources["FooTaskDefinition"].(*ecs.TaskDefinition)
+	container := getMainContainer(def, t)
+	assert.Equal(t, container.RepositoryCredentials.CredentialsParameter, cloudformation.Ref(secret))
+
+	role := template.Resources["FooTaskExecutionRole"].(*iam.Role)
+	policy := role.Policies[0].PolicyDocument.(*PolicyDocument)
+	assert.DeepEqual(t, []string{cloudformation.Ref(secret)}, policy.Statement[0].Resource)
+}
+
+func TestSecretsKMSKey(t *testing.T) {
+	const key = "arn:aws:kms:eu-west-3:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"
+	dir := fs.NewDir(t, "secrets", fs.WithFile("password.txt", "s3cr3t"))
+	template := convertYaml(t, fmt.Sprintf(`
+services:
+  foo:
+    image: hello_world
+    secrets:
+      - db-password
+secrets:
+  db-password:
+    file: %s
+x-aws-kms_key: arn:aws:kms:eu-west-3:123456789012:alias/compose
+x-aws-secrets_kms_key: %s
+`, filepath.Join(dir.Path(), "password.txt"), key), useDefaultVPC)
+	s := template.Resources["DbpasswordSecret"].(*secretsmanager.Secret)
+	assert.Equal(t, s.KmsKeyId, key)
+
+	role := template.Resources["FooTaskExecutionRole"].(*iam.Role)
+	policy := role.Policies[0].PolicyDocument.(*PolicyDocument)
+	assert.Equal(t, len(policy.Statement), 2)
+	assert.DeepEqual(t, policy.Statement[1].Action, []string{actionDecrypt})
+	assert.DeepEqual(t, policy.Statement[1].Resource, []string{key})
+
+	marshalled, err := marshall(template)
+	assert.NilError(t, err)
+	var parsed struct {
+		Resources map[string]struct {
+			Properties map[string]interface{}
+		}
+	}
+	assert.NilError(t, json.Unmarshal(marshalled, &parsed))
+	assert.Equal(t, parsed.Resources["LogGroup"].Properties["KmsKeyId"], "arn:aws:kms:eu-west-3:123456789012:alias/compose")
+}
+
+func TestVolumeProjectKMSKey(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  test:
+    image: nginx
+volumes:
+  db-data: {}
+  logs:
+    driver_opts:
+      kms_key_id: alias/logs
+x-aws-kms_key: 1234abcd-12ab-34cd-56ef-1234567890ab
+`, useDefaultVPC, func(m *MockAPIMockRecorder) {
+		m.ListFileSystems(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+	})
+	f := template.Resources[volumeResourceName("db-data")].(*efs.FileSystem)
+	assert.Equal(t, f.KmsKeyId, kmsKeyARN("1234abcd-12ab-34cd-56ef-1234567890ab")) //nolint:staticcheck
+	f = template.Resources[volumeResourceName("logs")].(*efs.FileSystem)
+	assert.Equal(t, f.KmsKeyId, "alias/logs") //nolint:staticcheck
+}
+
+func TestPullCredentialsInvalidSecret(t *testing.T) {
+	dir := fs.NewDir(t, "secrets", fs.WithFile("creds.json", "s3cr3t"))
+	project := loadConfig(t, fmt.Sprintf(`
+services:
+  foo:
+    image: user/private
+    x-aws-pull_credentials: registry
+secrets:
+  registry:
+    file: %s
+`, filepath.Join(dir.Path(), "creds.json")))
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+	useDefaultVPC(m.EXPECT())
+	backend := &ecsAPIService{aws: m}
+	_, err := backend.convert(context.TODO(), project)
+	assert.ErrorContains(t, err, "must be a JSON document with username and password")
+}
+
+func TestExternalSecret(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  foo:
+    image: hello_world
+    secrets:
+      - db-password
+      - api-key
+secrets:
+  db-password:
+    external: true
+    name: production/db-password
+  api-key:
+    external: true
+    name: arn:aws:secretsmanager:eu-west-3:123456789012:secret:api-key-123abc
+`, useDefaultVPC, func(m *MockAPIMockRecorder) {
+		m.InspectSecret(gomock.Any(), "production/db-password").Return(secrets.Secret{
+			ID:   "arn:aws:secretsmanager:eu-west-3:123456789012:secret:production/db-password-456def",
+			Name: "production/db-password",
+		}, nil)
+	})
+	for _, r := range template.Resources {
+		_, ok := r.(*secretsmanager.Secret)
+		assert.Check(t, !ok)
+	}
+
+	role := template.Resources["FooTaskExecutionRole"].(*iam.Role)
+	policy := role.Policies[0].PolicyDocument.(*PolicyDocument)
+	assert.DeepEqual(t, policy.Statement[0].Resource, []string{
+		"arn:aws:secretsmanager:eu-west-3:123456789012:secret:production/db-password-456def",
+		"arn:aws:secretsmanager:eu-west-3:123456789012:secret:api-key-123abc",
+	})
+}
+
+func TestSSMParameters(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  foo:
+    image: hello_world
+    x-aws-ssm:
+      DB_PASSWORD: /production/db/password
+      API_KEY: arn:aws:ssm:eu-west-3:123456789012:parameter/api-key
+`, useDefaultVPC)
+	def := template.Resources["FooTaskDefinition"].(*ecs.TaskDefinition)
+	container := getMainContainer(def, t)
+	password := cloudformation.Sub("arn:${AWS::Partition}:ssm:${AWS::Region}:${AWS::AccountId}:parameter/production/db/password")
+	assert.DeepEqual(t, container.Secrets, []ecs.TaskDefinition_Secret{
+		{Name: "API_KEY", ValueFrom: "arn:aws:ssm:eu-west-3:123456789012:parameter/api-key"},
+		{Name: "DB_PASSWORD", ValueFrom: password},
+	})
+
+	role := template.Resources["FooTaskExecutionRole"].(*iam.Role)
+	policy := role.Policies[0].PolicyDocument.(*PolicyDocument)
+	assert.DeepEqual(t, policy.Statement[0].Action, []string{"secretsmanager:GetSecretValue", "ssm:GetParameters", "kms:Decrypt"})
+	assert.DeepEqual(t, policy.Statement[0].Resource, []string{"arn:aws:ssm:eu-west-3:123456789012:parameter/api-key", password})
+}
+
+func TestTaskRolePolicies(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  foo:
+    image: hello_world
+    x-aws-policies:
+      - "arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess"
+    x-aws-role:
+      Version: "2012-10-17"
+      Statement:
+        - Effect: "Allow"
+          Action: "dynamodb:*"
+          Resource: "*"
+`, useDefaultVPC)
+	role := template.Resources["FooTaskRole"].(*iam.Role)
+	assert.DeepEqual(t, role.ManagedPolicyArns, []string{"arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess"})
+	assert.Equal(t, len(role.Policies), 1)
+	def := template.Resources["FooTaskDefinition"].(*ecs.TaskDefinition)
+	assert.Equal(t, def.TaskRoleArn, cloudformation.Ref("FooTaskRole"))
+}
+
+func TestExistingTaskRole(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  foo:
+    image: hello_world
+    x-aws-task_role: application
+  bar:
+    image: hello_world
+    x-aws-task_role: arn:aws:iam::123456789012:role/application
+`, useDefaultVPC)
+	_, ok := template.Resources["FooTaskRole"]
+	assert.Check(t, !ok)
+	def := template.Resources["FooTaskDefinition"].(*ecs.TaskDefinition)
+	assert.Equal(t, def.TaskRoleArn, cloudformation.Sub("arn:${AWS::Partition}:iam::${AWS::AccountId}:role/application"))
+	def = template.Resources["BarTaskDefinition"].(*ecs.TaskDefinition)
+	assert.Equal(t, def.TaskRoleArn, "arn:aws:iam::123456789012:role/application")
+}
+
+const sharedLoadBalancer = "arn:aws:elasticloadbalancing:eu-west-3:123456789012:loadbalancer/app/shared/50dc6c495c0c9188"
+
+func TestExistingLoadBalancer(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  foo:
+    image: hello_world
+    ports:
+      - 80:80
+x-aws-loadbalancer: shared
+`, useDefaultVPC, func(m *MockAPIMockRecorder) {
+		m.ResolveLoadBalancer(gomock.Any(), "shared").Return(existingAWSResource{arn: sharedLoadBalancer, id: "shared"}, "application", nil)
+		m.GetLoadBalancerListeners(gomock.Any(), sharedLoadBalancer).Return(map[int64]string{80: "arn:listener/80", 443: "arn:listener/443"}, nil)
+		m.StackExists(gomock.Any(), t.Name()).Return(true, nil)
+		m.ListStackResources(gomock.Any(), t.Name()).Return(stackResources{
+			{LogicalID: "FooTCP80Listener", Type: awsTypeListener, ARN: "arn:listener/80"},
+		}, nil)
+	})
+	_, ok := template.Resources["LoadBalancer"]
+	assert.Check(t, !ok)
+	listener := template.Resources["FooTCP80Listener"].(*elasticloadbalancingv2.Listener)
+	assert.Equal(t, listener.LoadBalancerArn, sharedLoadBalancer)
+}
+
+func TestExistingLoadBalancerListenerConflict(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  foo:
+    image: hello_world
+    ports:
+      - 80:80
+x-aws-loadbalancer: shared
+`)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+	useDefaultVPC(m.EXPECT())
+	m.EXPECT().ResolveLoadBalancer(gomock.Any(), "shared").Return(existingAWSResource{arn: sharedLoadBalancer, id: "shared"}, "application", nil)
+	m.EXPECT().GetLoadBalancerListeners(gomock.Any(), sharedLoadBalancer).Return(map[int64]string{80: "arn:listener/80"}, nil)
+	m.EXPECT().StackExists(gomock.Any(), t.Name()).Return(false, nil)
+
+	backend := &ecsAPIService{aws: m}
+	_, err := backend.convert(context.TODO(), project)
+	assert.ErrorContains(t, err, `service "foo" listens on target port 80, but load balancer "shared" already has a listener on this port`)
+}
+
+func TestCloudMapNamespace(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  foo:
+    image: hello_world
+x-aws-cloudmap_namespace: corp.internal
+`, useDefaultVPC)
+	namespace := template.Resources["CloudMap"].(*cloudmap.PrivateDnsNamespace)
+	assert.Equal(t, namespace.Name, "corp.internal")
+	def := template.Resources["FooTaskDefinition"].(*ecs.TaskDefinition)
+	assert.Equal(t, def.ContainerDefinitions[0].Command[1], "corp.internal")
+
+	registry := template.Resources["FooServiceDiscoveryEntry"].(*cloudmap.Service)
+	assert.Equal(t, registry.Name, "foo")
+	assert.Equal(t, registry.HealthCheckCustomConfig.FailureThreshold, float64(1))
+}
+
 func TestMapNetworksToSecurityGroups(t *testing.T) {
 	template := convertYaml(t, `
 services:
@@ -336,6 +733,77 @@ services:
 	assert.Check(t, loadBalancer.Type == elbv2.LoadBalancerTypeEnumNetwork)
 }
 
+func TestLoadBalancerTypePerPort(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  web:
+    image: nginx
+    ports:
+      - 80:80
+  game:
+    image: myorg/game
+    x-aws-loadbalancer_type: network
+    ports:
+      - 7777:7777/udp
+      - 7778:7778
+`, useDefaultVPC)
+	loadBalancer := template.Resources["LoadBalancer"].(*elasticloadbalancingv2.LoadBalancer)
+	assert.Equal(t, loadBalancer.Type, elbv2.LoadBalancerTypeEnumApplication)
+	network := template.Resources["NetworkLoadBalancer"].(*elasticloadbalancingv2.LoadBalancer)
+	assert.Equal(t, network.Type, elbv2.LoadBalancerTypeEnumNetwork)
+
+	listener := template.Resources["WebTCP80Listener"].(*elasticloadbalancingv2.Listener)
+	assert.Equal(t, listener.LoadBalancerArn, cloudformation.Ref("LoadBalancer"))
+	assert.Equal(t, listener.Protocol, elbv2.ProtocolEnumHttp)
+
+	listener = template.Resources["GameUDP7777Listener"].(*elasticloadbalancingv2.Listener)
+	assert.Equal(t, listener.LoadBalancerArn, cloudformation.Ref("NetworkLoadBalancer"))
+	assert.Equal(t, listener.Protocol, elbv2.ProtocolEnumUdp)
+	targetGroup := template.Resources["GameUDP7777TargetGroup"].(*elasticloadbalancingv2.TargetGroup)
+	assert.Equal(t, targetGroup.HealthCheckProtocol, elbv2.ProtocolEnumTcp)
+	assert.Equal(t, targetGroup.HealthCheckPort, "7778")
+}
+
+func TestLoadBalancerTypeInvalid(t *testing.T) {
+	for _, test := range []struct {
+		yaml string
+		err  string
+	}{
+		{
+			yaml: `
+    x-aws-loadbalancer_type: application
+    ports:
+      - 53:53/udp`,
+			err: `service "test": UDP port 53 can't be exposed by an application load balancer`,
+		},
+		{
+			yaml: `
+    x-aws-loadbalancer_type: classic
+    ports:
+      - 80:80`,
+			err: `service "test": x-aws-loadbalancer_type must be application or network, got "classic"`,
+		},
+		{
+			yaml: `
+    ports:
+      - 53:53/udp`,
+			err: `service "test": UDP port 53 requires x-aws-healthcheck port to be set to a TCP port for load balancer health check`,
+		},
+	} {
+		project := loadConfig(t, `
+services:
+  test:
+    image: nginx`+test.yaml)
+		ctrl := gomock.NewController(t)
+		m := NewMockAPI(ctrl)
+		useDefaultVPC(m.EXPECT())
+		backend := &ecsAPIService{aws: m}
+		_, err := backend.convert(context.TODO(), project)
+		assert.ErrorContains(t, err, test.err)
+		ctrl.Finish()
+	}
+}
+
 func TestUseExternalNetwork(t *testing.T) {
 	template := convertYaml(t, `
 services:
@@ -355,6 +823,22 @@ networks:
 	assert.Check(t, s.NetworkConfiguration.AwsvpcConfiguration.SecurityGroups[0] == "sg-123abc") //nolint:staticcheck
 }
 
+func TestNetworkSecurityGroup(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  test:
+    image: nginx
+networks:
+  default:
+    x-aws-security_group: sg-123abc
+`, useDefaultVPC, func(m *MockAPIMockRecorder) {
+		m.SecurityGroupExists(gomock.Any(), "sg-123abc").Return(true, nil)
+	})
+	assert.Check(t, template.Resources["DefaultNetwork"] == nil)
+	s := template.Resources["TestService"].(*ecs.Service)
+	assert.DeepEqual(t, s.NetworkConfiguration.AwsvpcConfiguration.SecurityGroups, []string{"sg-123abc"})
+}
+
 func TestUseExternalVolume(t *testing.T) {
 	template := convertYaml(t, `
 services:
@@ -376,6 +860,29 @@ volumes:
 	assert.Equal(t, s.FileSystemId, "fs-123abc") //nolint:staticcheck
 }
 
+func TestUseFileSystemFromDriverOpts(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  test:
+    image: nginx
+volumes:
+  db-data:
+    driver_opts:
+      filesystem_id: fs-123abc
+      uid: 1002
+      gid: 1002
+`, useDefaultVPC, func(m *MockAPIMockRecorder) {
+		m.ResolveFileSystem(gomock.Any(), "fs-123abc").Return(existingAWSResource{id: "fs-123abc"}, nil)
+	})
+	_, ok := template.Resources[volumeResourceName("db-data")]
+	assert.Check(t, !ok)
+
+	s := template.Resources["DbdataNFSMountTargetOnSubnet1"].(*efs.MountTarget)
+	assert.Equal(t, s.FileSystemId, "fs-123abc") //nolint:staticcheck
+	a := template.Resources["DbdataAccessPoint"].(*efs.AccessPoint)
+	assert.Equal(t, a.FileSystemId, "fs-123abc") //nolint:staticcheck
+}
+
 func TestCreateVolume(t *testing.T) {
 	template := convertYaml(t, `
 services:
@@ -428,6 +935,114 @@ volumes:
 	assert.Equal(t, a.PosixUser.Gid, "1002") //nolint:staticcheck
 }
 
+func TestCreateAccessPointDefaultPermissions(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  test:
+    image: nginx
+volumes:
+  db-data:
+    driver_opts:
+      uid: 1002
+      gid: 1002
+      root_directory: /data
+`, useDefaultVPC, func(m *MockAPIMockRecorder) {
+		m.ListFileSystems(gomock.Any(), gomock.Any()).Return(nil, nil)
+	})
+	a := template.Resources["DbdataAccessPoint"].(*efs.AccessPoint)
+	assert.Equal(t, a.RootDirectory.Path, "/data")                    //nolint:staticcheck
+	assert.Equal(t, a.RootDirectory.CreationInfo.Permissions, "0755") //nolint:staticcheck
+}
+
+func TestVolumeWithoutTransitEncryption(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  test:
+    image: nginx
+    volumes:
+      - db-data:/data
+volumes:
+  db-data:
+    driver_opts:
+      transit_encryption: disabled
+      provisioned_throughput: 256
+`, useDefaultVPC, func(m *MockAPIMockRecorder) {
+		m.ListFileSystems(gomock.Any(), gomock.Any()).Return(nil, nil)
+	})
+	_, ok := template.Resources["DbdataAccessPoint"]
+	assert.Check(t, !ok)
+
+	f := template.Resources[volumeResourceName("db-data")].(*efs.FileSystem)
+	assert.Equal(t, f.ThroughputMode, "provisioned") //nolint:staticcheck
+
+	def := template.Resources["TestTaskDefinition"].(*ecs.TaskDefinition)
+	config := def.Volumes[0].EFSVolumeConfiguration
+	assert.Equal(t, config.TransitEncryption, "DISABLED") //nolint:staticcheck
+	assert.Check(t, config.AuthorizationConfig == nil)
+
+	_, ok = template.Resources["TestTaskRole"]
+	assert.Check(t, !ok)
+}
+
+func TestCheckVolumesDriverOpts(t *testing.T) {
+	tests := []struct {
+		opts map[string]string
+		err  string
+	}{
+		{
+			opts: map[string]string{"performance_mode": "fast"},
+			err:  `volume "data": performance_mode must be one of generalPurpose, maxIO`,
+		},
+		{
+			opts: map[string]string{"throughput_mode": "provisioned"},
+			err:  `volume "data": throughput_mode provisioned requires provisioned_throughput`,
+		},
+		{
+			opts: map[string]string{"throughput_mode": "bursting", "provisioned_throughput": "128"},
+			err:  `volume "data": provisioned_throughput requires throughput_mode provisioned`,
+		},
+		{
+			opts: map[string]string{"provisioned_throughput": "-1"},
+			err:  `volume "data": provisioned_throughput must be a throughput in MiB/s`,
+		},
+		{
+			opts: map[string]string{"uid": "1000"},
+			err:  `volume "data": uid and gid must be set together`,
+		},
+		{
+			opts: map[string]string{"uid": "root", "gid": "0"},
+			err:  `volume "data": uid must be a numeric ID`,
+		},
+		{
+			opts: map[string]string{"permissions": "rwx"},
+			err:  `volume "data": permissions must be octal, e.g. 0755`,
+		},
+		{
+			opts: map[string]string{"root_directory": "data"},
+			err:  `volume "data": root_directory must be an absolute path`,
+		},
+		{
+			opts: map[string]string{"transit_encryption": "disabled", "root_directory": "/data"},
+			err:  `volume "data": EFS access points require transit_encryption to set uid, gid or root_directory`,
+		},
+		{
+			opts: map[string]string{"transit_encryption": "enabled", "uid": "1000", "gid": "1000", "permissions": "750"},
+		},
+	}
+	for _, test := range tests {
+		err := checkVolumesDriverOpts(&types.Project{
+			Volumes: types.Volumes{
+				"data": types.VolumeConfig{DriverOpts: test.opts},
+			},
+		})
+		if test.err == "" {
+			assert.NilError(t, err)
+		} else {
+			assert.Error(t, err, test.err)
+		}
+	}
+}
+
 func TestReusePreviousVolume(t *testing.T) {
 	template := convertYaml(t, `
 services:
@@ -466,6 +1081,7 @@ services:
     init: true
     user: "user"
     working_dir: "working_dir"
+    stop_grace_period: 1m
 `, useDefaultVPC)
 	def := template.Resources["TestTaskDefinition"].(*ecs.TaskDefinition)
 	container := getMainContainer(def, t)
@@ -478,6 +1094,102 @@ services:
 	assert.Equal(t, container.LinuxParameters.Capabilities.Drop[0], "SYSLOG")
 	assert.Equal(t, container.User, "user")
 	assert.Equal(t, container.WorkingDirectory, "working_dir")
+	assert.Equal(t, container.StopTimeout, 60)
+}
+
+func TestStopGracePeriod(t *testing.T) {
+	for _, tc := range []struct {
+		period  string
+		timeout int
+	}{
+		{period: "1500ms", timeout: 2},
+		{period: "5m", timeout: 120},
+	} {
+		template := convertYaml(t, fmt.Sprintf(`
+services:
+  test:
+    image: "image"
+    stop_grace_period: %s
+`, tc.period), useDefaultVPC)
+		def := template.Resources["TestTaskDefinition"].(*ecs.TaskDefinition)
+		assert.Equal(t, getMainContainer(def, t).StopTimeout, tc.timeout)
+	}
+}
+
+func TestReadOnlyService(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  test:
+    image: "image"
+    read_only: true
+    init: true
+    tmpfs:
+      - /tmp
+      - /run:size=64m,noexec
+    ulimits:
+      nofile:
+        soft: 1024
+        hard: 2048
+      nproc: 512
+`, useDefaultVPC)
+	def := template.Resources["TestTaskDefinition"].(*ecs.TaskDefinition)
+	container := getMainContainer(def, t)
+	assert.Check(t, container.ReadonlyRootFilesystem)
+	assert.Check(t, container.LinuxParameters.InitProcessEnabled)
+	// Fargate doesn't support tmpfs, nor ulimits but nofile
+	assert.Check(t, container.LinuxParameters.Tmpfs == nil)
+	assert.DeepEqual(t, container.MountPoints, []ecs.TaskDefinition_MountPoint{
+		{ContainerPath: "/tmp", SourceVolume: "TestTmpfs0"},
+		{ContainerPath: "/run", SourceVolume: "TestTmpfs1"},
+	})
+	assert.DeepEqual(t, def.Volumes, []ecs.TaskDefinition_Volume{
+		{Name: "TestTmpfs0"},
+		{Name: "TestTmpfs1"},
+	})
+	assert.DeepEqual(t, container.Ulimits, []ecs.TaskDefinition_Ulimit{
+		{Name: "nofile", SoftLimit: 1024, HardLimit: 2048},
+	})
+}
+
+func TestReadOnlyServiceEC2(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  test:
+    image: "image"
+    read_only: true
+    tmpfs:
+      - /tmp
+      - /run:size=64m,noexec
+    ulimits:
+      nofile:
+        soft: 1024
+        hard: 2048
+      nproc: 512
+x-aws-ec2:
+  instance_type: t3.medium
+`, useDefaultVPC, func(m *MockAPIMockRecorder) {
+		m.GetParameter(gomock.Any(), gomock.Any()).Return("ami123456789", nil)
+	})
+	def := template.Resources["TestTaskDefinition"].(*ecs.TaskDefinition)
+	container := getMainContainer(def, t)
+	assert.Check(t, container.ReadonlyRootFilesystem)
+	assert.Check(t, container.MountPoints == nil)
+	assert.DeepEqual(t, container.LinuxParameters.Tmpfs, []ecs.TaskDefinition_Tmpfs{
+		{ContainerPath: "/tmp", Size: 100},
+		{ContainerPath: "/run", Size: 64, MountOptions: []string{"noexec"}},
+	})
+	assert.DeepEqual(t, container.Ulimits, []ecs.TaskDefinition_Ulimit{
+		{Name: "nofile", SoftLimit: 1024, HardLimit: 2048},
+		{Name: "nproc", SoftLimit: 512, HardLimit: 512},
+	})
+}
+
+func TestTmpfsInvalidSize(t *testing.T) {
+	_, err := toTmpfs(types.ServiceConfig{
+		Name:  "test",
+		Tmpfs: types.StringList{"/tmp:size=lots"},
+	})
+	assert.ErrorContains(t, err, `service "test": invalid tmpfs size for /tmp`)
 }
 
 func get(l []ecs.TaskDefinition_KeyValuePair, name string) string {
@@ -528,6 +1240,91 @@ services:
 	assert.Equal(t, template.Metadata["Cluster"], "arn:aws:ecs:region:account:cluster/name")
 }
 
+func TestContextCluster(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  test:
+    image: nginx
+`)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+	useDefaultVPC(m.EXPECT())
+	m.EXPECT().ResolveCluster(gomock.Any(), "shared").Return(existingAWSResource{
+		arn: "arn:aws:ecs:region:account:cluster/shared",
+		id:  "shared",
+	}, nil)
+
+	backend := &ecsAPIService{
+		ctx: store.EcsContext{Cluster: "shared"},
+		aws: m,
+	}
+	template, err := backend.convert(context.TODO(), project)
+	assert.NilError(t, err)
+	assert.Equal(t, template.Metadata["Cluster"], "arn:aws:ecs:region:account:cluster/shared")
+	_, ok := template.Resources["Cluster"]
+	assert.Check(t, !ok)
+}
+
+func TestContextNetwork(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  test:
+    image: nginx
+`)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+	m.EXPECT().CheckVPC(gomock.Any(), "vpc-789").Return(nil)
+
+	backend := &ecsAPIService{
+		ctx: store.EcsContext{VPC: "vpc-789", Subnets: []string{"subnet-a", "subnet-b"}},
+		aws: m,
+	}
+	template, err := backend.convert(context.TODO(), project)
+	assert.NilError(t, err)
+	service := template.Resources["TestService"].(*ecs.Service)
+	assert.DeepEqual(t, service.NetworkConfiguration.AwsvpcConfiguration.Subnets, []string{"subnet-a", "subnet-b"})
+}
+
+func TestSubnetsExtension(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  test:
+    image: nginx
+x-aws-vpc: vpc-789
+x-aws-subnets:
+  - subnet-a
+  - subnet-b
+`, func(m *MockAPIMockRecorder) {
+		m.CheckVPC(gomock.Any(), "vpc-789").Return(nil)
+		m.CheckSubnets(gomock.Any(), "vpc-789", []string{"subnet-a", "subnet-b"}, true).Return(nil)
+	})
+	service := template.Resources["TestService"].(*ecs.Service)
+	assert.DeepEqual(t, service.NetworkConfiguration.AwsvpcConfiguration.Subnets, []string{"subnet-a", "subnet-b"})
+}
+
+func TestSubnetsExtensionInvalid(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  test:
+    image: nginx
+x-aws-vpc: vpc-789
+x-aws-subnets:
+  - subnet-a
+  - subnet-b
+`)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+	m.EXPECT().CheckVPC(gomock.Any(), "vpc-789").Return(nil)
+	m.EXPECT().CheckSubnets(gomock.Any(), "vpc-789", []string{"subnet-a", "subnet-b"}, true).Return(fmt.Errorf(`subnet "subnet-b" doesn't belong to VPC vpc-789`))
+
+	backend := &ecsAPIService{aws: m}
+	_, err := backend.convert(context.TODO(), project)
+	assert.ErrorContains(t, err, "doesn't belong to VPC vpc-789")
+}
+
 func convertYaml(t *testing.T, yaml string, fn ...func(m *MockAPIMockRecorder)) *cloudformation.Template {
 	project := loadConfig(t, yaml)
 	ctrl := gomock.NewController(t)
@@ -546,6 +1343,25 @@ func convertYaml(t *testing.T, yaml string, fn ...func(m *MockAPIMockRecorder))
 	return template
 }
 
+// convertYamlError returns the error converting yaml fails with
+func convertYamlError(t *testing.T, yaml string, fn ...func(m *MockAPIMockRecorder)) error {
+	project := loadConfig(t, yaml)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := NewMockAPI(ctrl)
+	for _, f := range fn {
+		f(m.EXPECT())
+	}
+
+	backend := &ecsAPIService{
+		aws: m,
+	}
+	_, err := backend.convert(context.TODO(), project)
+	assert.Check(t, err != nil, "convert should fail")
+	return err
+}
+
 func loadConfig(t *testing.T, yaml string) *types.Project {
 	dict, err := loader.ParseYAML([]byte(yaml))
 	assert.NilError(t, err)
@@ -27,9 +27,10 @@ import (
 
 func (b *ecsAPIService) checkCompatibility(project *types.Project) error {
 	var checker compatibility.Checker = &fargateCompatibilityChecker{
-		compatibility.AllowList{
+		AllowList: compatibility.AllowList{
 			Supported: compatibleComposeAttributes,
 		},
+		external: useExternal(project),
 	}
 	compatibility.Check(project, checker)
 	for _, err := range checker.Errors() {
@@ -46,11 +47,11 @@ func (b *ecsAPIService) checkCompatibility(project *types.Project) error {
 
 type fargateCompatibilityChecker struct {
 	compatibility.AllowList
+	external bool
 }
 
 var compatibleComposeAttributes = []string{
 	"services.command",
-	"services.container_name",
 	"services.cap_drop",
 	"services.depends_on",
 	"services.deploy",
@@ -66,6 +67,7 @@ var compatibleComposeAttributes = []string{
 	"services.deploy.resources.reservations.generic_resources",
 	"services.deploy.resources.reservations.generic_resources.discrete_resource_spec",
 	"services.deploy.update_config",
+	"services.deploy.update_config.failure_action",
 	"services.deploy.update_config.parallelism",
 	"services.entrypoint",
 	"services.environment",
@@ -80,14 +82,20 @@ var compatibleComposeAttributes = []string{
 	"services.init",
 	"services.logging",
 	"services.logging.options",
+	"services.network_mode",
 	"services.networks",
+	"services.platform",
 	"services.ports",
 	"services.ports.mode",
 	"services.ports.target",
 	"services.ports.protocol",
+	"services.read_only",
 	"services.secrets",
 	"services.secrets.source",
 	"services.secrets.target",
+	"services.stop_grace_period",
+	"services.tmpfs",
+	"services.ulimits",
 	"services.user",
 	"services.volumes",
 	"services.volumes.read_only",
@@ -106,7 +114,9 @@ var compatibleComposeAttributes = []string{
 }
 
 func (c *fargateCompatibilityChecker) CheckImage(service *types.ServiceConfig) {
-	if service.Image == "" {
+	if service.Image == "" && service.Build != nil {
+		c.Incompatible("service %s doesn't define a Docker image to run, use --build to build and push it to Amazon ECR", service.Name)
+	} else if service.Image == "" {
 		c.Incompatible("service %s doesn't define a Docker image to run", service.Name)
 	}
 }
@@ -115,7 +125,8 @@ func (c *fargateCompatibilityChecker) CheckPortsPublished(p *types.ServicePortCo
 	if p.Published == 0 {
 		p.Published = p.Target
 	}
-	if p.Published != p.Target {
+	if p.Published != p.Target && !c.external {
+		// bridge network on external instances maps container ports to other host ports
 		c.Incompatible("published port can't be set to a distinct value than container port")
 	}
 }
@@ -134,16 +145,16 @@ func (c *fargateCompatibilityChecker) CheckCapAdd(service *types.ServiceConfig)
 }
 
 func (c *fargateCompatibilityChecker) CheckLoggingDriver(config *types.LoggingConfig) {
-	if config.Driver != "" && config.Driver != "awslogs" {
+	if config.Driver != "" && config.Driver != "awslogs" && config.Driver != logDriverFirelens {
 		c.Unsupported("services.logging.driver %s is not supported", config.Driver)
 	}
 }
 
 func (c *fargateCompatibilityChecker) CheckUlimits(service *types.ServiceConfig) {
-	for k := range service.Ulimits {
-		if k != "nofile" {
-			c.Unsupported("services.ulimits.%s is not supported by Fargate", k)
-			delete(service.Ulimits, k)
+	// Fargate only supports nofile, which is checked on conversion as it depends on the launch type
+	for k, v := range service.Ulimits {
+		if v.Single == 0 && v.Soft > v.Hard {
+			c.Incompatible("services.ulimits.%s soft limit can't be greater than hard limit", k)
 		}
 	}
 }
@@ -20,13 +20,21 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/AlecAivazis/survey/v2/terminal"
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/defaults"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/sts"
+	dockercredentials "github.com/docker/cli/cli/config/credentials"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	"gopkg.in/ini.v1"
 
 	"github.com/docker/compose-cli/context/store"
@@ -45,12 +53,12 @@ func newContextCreateHelper() contextCreateAWSHelper {
 }
 
 func (h contextCreateAWSHelper) createProfile(name string) error {
-	accessKey, secretKey, err := h.askCredentials()
+	creds, err := h.askCredentials()
 	if err != nil {
 		return err
 	}
-	if accessKey != "" && secretKey != "" {
-		return h.saveCredentials(name, accessKey, secretKey)
+	if creds.AccessKeyID != "" && creds.SecretAccessKey != "" {
+		return h.saveCredentials(name, creds.AccessKeyID, creds.SecretAccessKey, creds.SessionToken)
 	}
 	return nil
 }
@@ -67,7 +75,69 @@ func (h contextCreateAWSHelper) createContext(profile, region, description strin
 	}, description
 }
 
-func (h contextCreateAWSHelper) createContextData(_ context.Context, opts ContextParams) (interface{}, string, error) {
+func (h contextCreateAWSHelper) createContextData(ctx context.Context, opts ContextParams) (interface{}, string, error) {
+	data, description, err := h.createEndpointData(ctx, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	ecsCtx := data.(store.EcsContext)
+	ecsCtx.Cluster = opts.Cluster
+	ecsCtx.Tags = opts.Tags
+	if opts.VPC != "" {
+		ecsCtx.VPC = opts.VPC
+		ecsCtx.Subnets = opts.Subnets
+	}
+	return ecsCtx, description, nil
+}
+
+// completeContextData runs the optional checks and prompts which require access to the AWS account
+func (h contextCreateAWSHelper) completeContextData(ctx context.Context, sess *session.Session, opts ContextParams, ecsCtx store.EcsContext, description string) (interface{}, string, error) {
+	region := ecsCtx.Region
+	if region == "" {
+		region = aws.StringValue(sess.Config.Region)
+	}
+	if opts.TestPermissions {
+		if err := checkPermissions(ctx, sess, region); err != nil {
+			return nil, "", err
+		}
+	}
+	if opts.VPC == "" && !opts.NonInteractive {
+		vpc, subnets, err := h.chooseNetwork(ctx, sess, region)
+		if err != nil {
+			return nil, "", err
+		}
+		ecsCtx.VPC = vpc
+		ecsCtx.Subnets = subnets
+	}
+	return ecsCtx, description, nil
+}
+
+// createEndpointData resolves how context will access AWS, prompting user for missing values
+func (h contextCreateAWSHelper) createEndpointData(ctx context.Context, opts ContextParams) (interface{}, string, error) {
+	if opts.NonInteractive && !opts.CredentialsFromEnv && !opts.hasCustomEndpoints() && opts.Profile == "" && opts.CreateProfile == "" && opts.FromAWSProfile == "" {
+		// same as AWS CLI, use profile set by environment
+		opts.Profile = os.Getenv("AWS_PROFILE")
+	}
+	if err := checkContextParams(opts); err != nil {
+		return nil, "", err
+	}
+	if opts.NonInteractive {
+		h.user = nonInteractivePrompt{}
+	}
+	if opts.FromAWSProfile != "" {
+		if err := importAWSProfile(&opts); err != nil {
+			return nil, "", err
+		}
+	}
+	if opts.CredentialsFromEnv {
+		return h.createEnvContextData(ctx, opts)
+	}
+	if opts.Keychain {
+		return h.createKeychainContextData(ctx, opts)
+	}
+	if opts.hasCustomEndpoints() {
+		return h.createEndpointsContextData(opts)
+	}
 	profile := opts.Profile
 	region := opts.Region
 
@@ -75,7 +145,16 @@ func (h contextCreateAWSHelper) createContextData(_ context.Context, opts Contex
 	if err != nil {
 		return nil, "", err
 	}
-	if profile != "" {
+	if opts.CreateProfile != "" {
+		if contains(profilesList, opts.CreateProfile) {
+			return nil, "", errors.Wrapf(errdefs.ErrAlreadyExists, "profile %q", opts.CreateProfile)
+		}
+		err = h.saveCredentials(opts.CreateProfile, opts.AccessKey, opts.SecretKey, opts.SessionToken)
+		if err != nil {
+			return nil, "", err
+		}
+		profile = opts.CreateProfile
+	} else if profile != "" {
 		// validate profile
 		if profile != "default" && !contains(profilesList, profile) {
 			return nil, "", errors.Wrapf(errdefs.ErrNotFound, "profile %q", profile)
@@ -87,17 +166,256 @@ func (h contextCreateAWSHelper) createContextData(_ context.Context, opts Contex
 			return nil, "", err
 		}
 	}
+	sso, err := loadSSOProfile(defaults.SharedConfigFilename(), profile)
+	if err != nil {
+		return nil, "", err
+	}
+	if sso != nil {
+		if opts.NonInteractive {
+			loggedIn, err := ssoLoggedIn(*sso)
+			if err != nil {
+				return nil, "", err
+			}
+			if !loggedIn {
+				return nil, "", fmt.Errorf("AWS SSO login is required for profile %q, run `aws sso login --profile %s` first", profile, profile)
+			}
+		} else if err := ssoLogin(ctx, *sso); err != nil {
+			return nil, "", err
+		}
+	}
+	sess, err := newSession(profile, region, h.user)
+	if err != nil {
+		return nil, "", err
+	}
+	if region == "" && opts.NonInteractive {
+		region, err = profileRegion(profile)
+		if err != nil {
+			return nil, "", err
+		}
+		if region == "" {
+			return nil, "", fmt.Errorf("--region is required in non-interactive mode as profile %q doesn't define a region", profile)
+		}
+	}
 	if region == "" {
-		region, err = h.chooseRegion(region, profile)
+		region, err = h.chooseRegion(ctx, sess, region, profile)
 		if err != nil {
 			return nil, "", err
 		}
 	}
+	if err := h.checkCredentials(ctx, sess, profile, region); err != nil {
+		return nil, "", err
+	}
 	ecsCtx, descr := h.createContext(profile, region, opts.Description)
-	return ecsCtx, descr, nil
+	return h.completeContextData(ctx, sess, opts, ecsCtx.(store.EcsContext), descr)
+}
+
+// createEnvContextData creates a context which doesn't persist any credentials, but reads them from
+// environment variables each time a command runs
+func (h contextCreateAWSHelper) createEnvContextData(ctx context.Context, opts ContextParams) (interface{}, string, error) {
+	if !hasEnvCredentials() {
+		return nil, "", fmt.Errorf("either AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY, or AWS_WEB_IDENTITY_TOKEN_FILE and AWS_ROLE_ARN must be set to create a context using credentials from environment")
+	}
+	region := opts.Region
+	if region == "" && regionFromEnv() == "" {
+		return nil, "", fmt.Errorf("--region is required as neither AWS_REGION nor AWS_DEFAULT_REGION is set")
+	}
+	sess, err := newEnvSession(region)
+	if err != nil {
+		return nil, "", err
+	}
+	identity, err := sts.New(sess).GetCallerIdentityWithContext(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to validate AWS credentials from environment")
+	}
+	fmt.Fprintf(os.Stderr, "Using AWS account %s as %s\n", aws.StringValue(identity.Account), aws.StringValue(identity.Arn))
+
+	description := opts.Description
+	if region != "" {
+		description = fmt.Sprintf("%s (%s)", description, region)
+	}
+	return h.completeContextData(ctx, sess, opts, store.EcsContext{
+		Region:             region,
+		CredentialsFromEnv: true,
+	}, strings.TrimSpace(description))
+}
+
+// createKeychainContextData creates a profile which credentials are stored in the OS keychain
+func (h contextCreateAWSHelper) createKeychainContextData(ctx context.Context, opts ContextParams) (interface{}, string, error) {
+	keychain, err := newKeychain()
+	if err != nil {
+		return nil, "", err
+	}
+	profile := opts.CreateProfile
+	if err := h.checkKeychainProfile(keychain, profile); err != nil {
+		return nil, "", err
+	}
+	region := opts.Region
+	// credentials are validated before they are stored, so that keychain never keeps invalid ones
+	sess, err := session.NewSessionWithOptions(session.Options{
+		SharedConfigState: session.SharedConfigDisable,
+		Config: aws.Config{
+			Region:      aws.String(region),
+			Credentials: credentials.NewStaticCredentials(opts.AccessKey, opts.SecretKey, ""),
+		},
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	if region == "" {
+		if opts.NonInteractive {
+			return nil, "", fmt.Errorf("--region is required in non-interactive mode")
+		}
+		region, err = h.chooseRegion(ctx, sess, region, profile)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	if err := h.checkCredentials(ctx, sess, profile, region); err != nil {
+		return nil, "", err
+	}
+	if err := saveKeychainCredentials(keychain, profile, opts.AccessKey, opts.SecretKey); err != nil {
+		return nil, "", err
+	}
+	sess, err = newKeychainSession(profile, region)
+	if err != nil {
+		return nil, "", err
+	}
+	return h.completeContextData(ctx, sess, opts, store.EcsContext{
+		Profile:               profile,
+		Region:                region,
+		CredentialsInKeychain: true,
+	}, strings.TrimSpace(fmt.Sprintf("%s (%s)", opts.Description, region)))
+}
+
+// checkKeychainProfile refuses to overwrite credentials already stored in keychain for profile, unless user confirms
+func (h contextCreateAWSHelper) checkKeychainProfile(keychain dockercredentials.Store, profile string) error {
+	existing, err := keychain.Get(keychainServer(profile))
+	if err != nil {
+		return err
+	}
+	if existing.Username == "" {
+		return nil
+	}
+	overwrite, err := h.user.Confirm(fmt.Sprintf("Overwrite AWS credentials stored in keychain for profile %q?", profile), false)
+	if err != nil || !overwrite {
+		return errors.Wrapf(errdefs.ErrAlreadyExists, "credentials for profile %q in keychain", profile)
+	}
+	return nil
 }
 
-func (h contextCreateAWSHelper) saveCredentials(profile string, accessKeyID string, secretAccessKey string) error {
+// createEndpointsContextData creates a context targeting custom endpoints, such as LocalStack or ECS local
+// endpoints, so that deployments can be exercised without an AWS account
+func (h contextCreateAWSHelper) createEndpointsContextData(opts ContextParams) (interface{}, string, error) {
+	overrides := map[string]string{}
+	for service, url := range opts.Endpoints {
+		overrides[strings.ToLower(service)] = url
+	}
+	if opts.EndpointURL != "" {
+		overrides[allServicesEndpointKey] = opts.EndpointURL
+	}
+	region := opts.Region
+	if region == "" {
+		region = endpoints.UsEast1RegionID
+	}
+	return store.EcsContext{
+		Profile:   opts.Profile,
+		Region:    region,
+		Endpoints: overrides,
+	}, strings.TrimSpace(fmt.Sprintf("%s (%s)", opts.Description, region)), nil
+}
+
+// checkContextParams validates flags combination before any prompt or change to AWS configuration files
+func checkContextParams(opts ContextParams) error {
+	hasCredentials := opts.AccessKey != "" || opts.SecretKey != "" || opts.SessionToken != ""
+	if opts.FromAWSProfile != "" {
+		if opts.Profile != "" || opts.CreateProfile != "" || hasCredentials || opts.CredentialsFromEnv || opts.Keychain || opts.hasCustomEndpoints() {
+			return fmt.Errorf("--from-aws-profile can't be used with other credentials flags")
+		}
+		return nil
+	}
+	if opts.CreateProfile == "" && hasCredentials {
+		return fmt.Errorf("AWS credentials can only be set to create a new profile with --profile-create")
+	}
+	if opts.CreateProfile != "" {
+		if opts.Profile != "" {
+			return fmt.Errorf("--profile and --profile-create can't be used together")
+		}
+		if opts.AccessKey == "" || opts.SecretKey == "" {
+			return fmt.Errorf("--access-key and --secret-key are required to create profile %q", opts.CreateProfile)
+		}
+	}
+	if opts.hasCustomEndpoints() {
+		if opts.CreateProfile != "" || opts.CredentialsFromEnv || opts.Keychain {
+			return fmt.Errorf("custom endpoints can only be used with dummy credentials or an existing --profile")
+		}
+		return nil
+	}
+	if opts.CredentialsFromEnv {
+		if opts.Profile != "" || opts.CreateProfile != "" {
+			return fmt.Errorf("--from-env can't be used with --profile or --profile-create")
+		}
+		return nil
+	}
+	if opts.Keychain {
+		if opts.CreateProfile == "" {
+			return fmt.Errorf("--keychain requires --profile-create")
+		}
+		if opts.SessionToken != "" {
+			return fmt.Errorf("short-lived session tokens can't be stored in keychain")
+		}
+	}
+	if opts.NonInteractive && opts.Profile == "" && opts.CreateProfile == "" {
+		return fmt.Errorf("--profile or --profile-create is required in non-interactive mode")
+	}
+	return nil
+}
+
+// importAWSProfile sets context profile and region from an existing AWS CLI profile
+func importAWSProfile(opts *ContextParams) error {
+	section, err := loadProfileSection(defaults.SharedConfigFilename(), configSectionName(opts.FromAWSProfile))
+	if err != nil {
+		return err
+	}
+	if section == nil {
+		credentials, err := loadProfileSection(defaults.SharedCredentialsFilename(), credentialsSectionName(opts.FromAWSProfile))
+		if err != nil {
+			return err
+		}
+		if credentials == nil {
+			return errors.Wrapf(errdefs.ErrNotFound, "profile %q", opts.FromAWSProfile)
+		}
+	}
+	opts.Profile = opts.FromAWSProfile
+	if opts.Region == "" && section != nil {
+		opts.Region = section.Key("region").String()
+	}
+	if opts.Region == "" {
+		return fmt.Errorf("profile %q doesn't define a region, set one with --region", opts.FromAWSProfile)
+	}
+	return nil
+}
+
+// profileRegion returns the region configured for profile in ~/.aws/config, if any
+func profileRegion(profile string) (string, error) {
+	section, err := loadProfileSection(defaults.SharedConfigFilename(), configSectionName(profile))
+	if err != nil || section == nil {
+		return "", err
+	}
+	return section.Key("region").String(), nil
+}
+
+// checkCredentials resolves the AWS identity for profile, so that invalid credentials are reported
+// before context is created. User is prompted for a MFA token code if profile requires one.
+func (h contextCreateAWSHelper) checkCredentials(ctx context.Context, sess *session.Session, profile string, region string) error {
+	identity, err := sts.New(sess, aws.NewConfig().WithRegion(region)).GetCallerIdentityWithContext(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to validate AWS credentials for profile %q", profile)
+	}
+	fmt.Fprintf(os.Stderr, "Using AWS account %s as %s\n", aws.StringValue(identity.Account), aws.StringValue(identity.Arn))
+	return nil
+}
+
+func (h contextCreateAWSHelper) saveCredentials(profile string, accessKeyID string, secretAccessKey string, sessionToken string) error {
 	p := credentials.SharedCredentialsProvider{Profile: profile}
 	_, err := p.Retrieve()
 	if err == nil {
@@ -126,6 +444,12 @@ func (h contextCreateAWSHelper) saveCredentials(profile string, accessKeyID stri
 	if err != nil {
 		return err
 	}
+	if sessionToken != "" {
+		_, err = section.NewKey("aws_session_token", sessionToken)
+		if err != nil {
+			return err
+		}
+	}
 	return credIni.SaveTo(p.Filename)
 }
 
@@ -155,8 +479,7 @@ func (h contextCreateAWSHelper) getProfiles() ([]string, error) {
 }
 
 func (h contextCreateAWSHelper) chooseProfile(profiles []string) (string, error) {
-	options := []string{"new profile"}
-	options = append(options, profiles...)
+	options := profileOptions(profiles, os.Getenv("AWS_PROFILE"))
 
 	selected, err := h.user.Select("Select AWS Profile", options)
 	if err != nil {
@@ -183,7 +506,7 @@ func (h contextCreateAWSHelper) chooseProfile(profiles []string) (string, error)
 	return profile, nil
 }
 
-func (h contextCreateAWSHelper) chooseRegion(region string, profile string) (string, error) {
+func (h contextCreateAWSHelper) chooseRegion(ctx context.Context, sess *session.Session, region string, profile string) (string, error) {
 	suggestion := region
 
 	// only load ~/.aws/config
@@ -196,15 +519,13 @@ func (h contextCreateAWSHelper) chooseRegion(region string, profile string) (str
 		}
 		configIni = ini.Empty()
 	}
-	if profile != "default" {
-		profile = fmt.Sprintf("profile %s", profile)
-	}
-	section, err := configIni.GetSection(profile)
+	sectionName := configSectionName(profile)
+	section, err := configIni.GetSection(sectionName)
 	if err != nil {
 		if !strings.Contains(err.Error(), "does not exist") {
 			return "", err
 		}
-		section, err = configIni.NewSection(profile)
+		section, err = configIni.NewSection(sectionName)
 		if err != nil {
 			return "", err
 		}
@@ -214,10 +535,22 @@ func (h contextCreateAWSHelper) chooseRegion(region string, profile string) (str
 		suggestion = reg.Value()
 	}
 	// promp user for region
-	region, err = h.user.Input("Region", suggestion)
+	options := regionOptions(h.listRegions(ctx, sess, suggestion), suggestion)
+	selected, err := h.user.Select("Region", options)
 	if err != nil {
+		if err == terminal.InterruptErr {
+			return "", errdefs.ErrCanceled
+		}
 		return "", err
 	}
+	region = options[selected]
+	if region == otherRegion {
+		// opt-in regions might not be listed yet
+		region, err = h.user.Input("Region", suggestion)
+		if err != nil {
+			return "", err
+		}
+	}
 	if region == "" {
 		return "", fmt.Errorf("region cannot be empty")
 	}
@@ -229,28 +562,223 @@ func (h contextCreateAWSHelper) chooseRegion(region string, profile string) (str
 	return region, configIni.SaveTo(awsConfig)
 }
 
-func (h contextCreateAWSHelper) askCredentials() (string, string, error) {
+// profileOptions lists profiles as select options, with suggested profile selected by default
+func profileOptions(profiles []string, suggestion string) []string {
+	var options []string
+	if contains(profiles, suggestion) {
+		options = append(options, suggestion)
+	}
+	options = append(options, "new profile")
+	for _, p := range profiles {
+		if p != suggestion {
+			options = append(options, p)
+		}
+	}
+	return options
+}
+
+const accountDefaultVPC = "account default VPC"
+
+// chooseNetwork lets user select the VPC and subnets to deploy to. Empty VPC means account's default VPC
+func (h contextCreateAWSHelper) chooseNetwork(ctx context.Context, sess *session.Session, region string) (string, []string, error) {
+	client := ec2.New(sess, aws.NewConfig().WithRegion(region))
+	output, err := client.DescribeVpcsWithContext(ctx, &ec2.DescribeVpcsInput{})
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to list VPCs")
+	}
+	options, vpcs := vpcOptions(output.Vpcs)
+	if len(vpcs) == 0 || (len(vpcs) == 1 && vpcs[0] == "") {
+		// nothing to choose from
+		return "", nil, nil
+	}
+	selected, err := h.user.Select("VPC", options)
+	if err != nil {
+		if err == terminal.InterruptErr {
+			return "", nil, errdefs.ErrCanceled
+		}
+		return "", nil, err
+	}
+	vpc := vpcs[selected]
+	if vpc == "" {
+		return "", nil, nil
+	}
+
+	subnets, err := client.DescribeSubnetsWithContext(ctx, &ec2.DescribeSubnetsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("vpc-id"),
+				Values: []*string{aws.String(vpc)},
+			},
+		},
+	})
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "failed to list subnets of VPC %s", vpc)
+	}
+	var all []string
+	for _, s := range subnets.Subnets {
+		all = append(all, aws.StringValue(s.SubnetId))
+	}
+	sort.Strings(all)
+	tables, err := client.DescribeRouteTablesWithContext(ctx, &ec2.DescribeRouteTablesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("vpc-id"),
+				Values: []*string{aws.String(vpc)},
+			},
+		},
+	})
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "failed to list route tables of VPC %s", vpc)
+	}
+	input, err := h.user.Input("Subnets (comma separated)", strings.Join(suggestSubnets(all, tables.RouteTables), ","))
+	if err != nil {
+		return "", nil, err
+	}
+	var ids []string
+	for _, id := range strings.Split(input, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) < 2 {
+		return "", nil, fmt.Errorf("at least 2 subnets in different availability zones are required")
+	}
+	return vpc, ids, nil
+}
+
+// suggestSubnets suggests subnets with a route to the internet, as deployments check tasks can pull images, or all
+// subnets if none has, for VPCs with endpoints to pull images privately
+func suggestSubnets(subnets []string, tables []*ec2.RouteTable) []string {
+	hasInternet := subnetsInternetRoutes(tables, subnets)
+	var public []string
+	for _, subnet := range subnets {
+		if hasInternet[subnet] {
+			public = append(public, subnet)
+		}
+	}
+	if len(public) == 0 {
+		return subnets
+	}
+	return public
+}
+
+// vpcOptions lists VPCs as select options, with account default VPC first. VPC IDs are returned in the same
+// order, default VPC being an empty ID
+func vpcOptions(vpcs []*ec2.Vpc) ([]string, []string) {
+	var options, ids []string
+	for _, v := range vpcs {
+		if aws.BoolValue(v.IsDefault) {
+			options = append(options, fmt.Sprintf("%s (%s)", accountDefaultVPC, aws.StringValue(v.VpcId)))
+			ids = append(ids, "")
+		}
+	}
+	for _, v := range vpcs {
+		if aws.BoolValue(v.IsDefault) {
+			continue
+		}
+		label := aws.StringValue(v.VpcId)
+		for _, tag := range v.Tags {
+			if aws.StringValue(tag.Key) == "Name" {
+				label = fmt.Sprintf("%s (%s)", label, aws.StringValue(tag.Value))
+			}
+		}
+		options = append(options, fmt.Sprintf("%s %s", label, aws.StringValue(v.CidrBlock)))
+		ids = append(ids, aws.StringValue(v.VpcId))
+	}
+	return options, ids
+}
+
+const otherRegion = "other region"
+
+// listRegions retrieves regions enabled for account, or regions known by the SDK if EC2 can't be queried
+func (h contextCreateAWSHelper) listRegions(ctx context.Context, sess *session.Session, region string) []string {
+	if region == "" {
+		region = endpoints.UsEast1RegionID
+	}
+	var regions []string
+	output, err := ec2.New(sess, aws.NewConfig().WithRegion(region)).DescribeRegionsWithContext(ctx, &ec2.DescribeRegionsInput{})
+	if err == nil {
+		for _, r := range output.Regions {
+			regions = append(regions, aws.StringValue(r.RegionName))
+		}
+	} else {
+		logrus.Debugf("failed to retrieve regions from EC2, using SDK regions list: %s", err)
+		for id := range partitionOf(region).Regions() {
+			regions = append(regions, id)
+		}
+	}
+	sort.Strings(regions)
+	return regions
+}
+
+// regionOptions lists regions as select options, with suggested region first
+func regionOptions(regions []string, suggestion string) []string {
+	var options []string
+	if contains(regions, suggestion) {
+		options = append(options, suggestion)
+	}
+	for _, r := range regions {
+		if r != suggestion {
+			options = append(options, r)
+		}
+	}
+	return append(options, otherRegion)
+}
+
+func (h contextCreateAWSHelper) askCredentials() (credentials.Value, error) {
 	confirm, err := h.user.Confirm("Enter AWS credentials", false)
 	if err != nil {
-		return "", "", err
+		return credentials.Value{}, err
 	}
 	if !confirm {
-		return "", "", nil
+		return credentials.Value{}, nil
 	}
 
 	accessKeyID, err := h.user.Input("AWS Access Key ID", "")
 	if err != nil {
-		return "", "", err
+		return credentials.Value{}, err
 	}
 	secretAccessKey, err := h.user.Password("Enter AWS Secret Access Key")
 	if err != nil {
-		return "", "", err
+		return credentials.Value{}, err
 	}
 	// validate access ID and password
 	if len(accessKeyID) < 3 || len(secretAccessKey) < 3 {
-		return "", "", fmt.Errorf("AWS Access/Secret Access Key must have more than 3 characters")
+		return credentials.Value{}, fmt.Errorf("AWS Access/Secret Access Key must have more than 3 characters")
 	}
-	return accessKeyID, secretAccessKey, nil
+	// temporary credentials, as issued by STS or a federated login, also require a session token
+	sessionToken, err := h.user.Password("Enter AWS Session Token (leave empty for long-term credentials)")
+	if err != nil {
+		return credentials.Value{}, err
+	}
+	return credentials.Value{
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: secretAccessKey,
+		SessionToken:    sessionToken,
+	}, nil
+}
+
+// nonInteractivePrompt fails any attempt to prompt user, so that scripts never hang waiting for input
+type nonInteractivePrompt struct{}
+
+func (nonInteractivePrompt) Select(message string, options []string) (int, error) {
+	return 0, errNonInteractive(message)
+}
+
+func (nonInteractivePrompt) Input(message string, defaultValue string) (string, error) {
+	return "", errNonInteractive(message)
+}
+
+func (nonInteractivePrompt) Confirm(message string, defaultValue bool) (bool, error) {
+	return false, errNonInteractive(message)
+}
+
+func (nonInteractivePrompt) Password(message string) (string, error) {
+	return "", errNonInteractive(message)
+}
+
+func errNonInteractive(message string) error {
+	return fmt.Errorf("can't prompt for %q in non-interactive mode", message)
 }
 
 func contains(values []string, value string) bool {
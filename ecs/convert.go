@@ -19,6 +19,7 @@ package ecs
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"sort"
@@ -33,19 +34,24 @@ import (
 	"github.com/awslabs/goformation/v4/cloudformation/ecs"
 	"github.com/compose-spec/compose-go/types"
 	"github.com/docker/cli/opts"
+	"github.com/docker/go-units"
 	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
 )
 
 const secretsInitContainerImage = "docker/ecs-secrets-sidecar"
 const searchDomainInitContainerImage = "docker/ecs-searchdomain-sidecar"
 
 func (b *ecsAPIService) createTaskDefinition(project *types.Project, service types.ServiceConfig, resources awsResources) (*ecs.TaskDefinition, error) {
-	cpu, mem, err := toLimits(service)
+	cpu, mem, err := toLimits(project, service)
 	if err != nil {
 		return nil, err
 	}
 	_, memReservation := toContainerReservation(service)
-	credential := getRepoCredentials(service)
+	credential, err := getRepoCredentials(project, service)
+	if err != nil {
+		return nil, err
+	}
 
 	logConfiguration := getLogConfiguration(service, project)
 
@@ -64,13 +70,22 @@ func (b *ecsAPIService) createTaskDefinition(project *types.Project, service typ
 		mounts = append(mounts, secretsMount)
 	}
 
-	initContainers = append(initContainers, ecs.TaskDefinition_ContainerDefinition{
-		Name:             fmt.Sprintf("%s_ResolvConf_InitContainer", normalizeResourceName(service.Name)),
-		Image:            searchDomainInitContainerImage,
-		Essential:        false,
-		Command:          []string{b.Region + ".compute.internal", project.Name + ".local"},
-		LogConfiguration: logConfiguration,
-	})
+	if !isWindows(service) {
+		// sidecar images only run on Linux
+		initContainers = append(initContainers, ecs.TaskDefinition_ContainerDefinition{
+			Name:             fmt.Sprintf("%s_ResolvConf_InitContainer", normalizeResourceName(service.Name)),
+			Image:            searchDomainInitContainerImage,
+			Essential:        false,
+			Command:          []string{b.Region + ".compute.internal", cloudMapNamespace(project)},
+			LogConfiguration: logConfiguration,
+		})
+	}
+
+	waitFor, err := createWaitForHealthyContainers(project, service, logConfiguration)
+	if err != nil {
+		return nil, err
+	}
+	initContainers = append(initContainers, waitFor...)
 
 	var dependencies []ecs.TaskDefinition_ContainerDependency
 	for _, c := range initContainers {
@@ -80,18 +95,81 @@ func (b *ecsAPIService) createTaskDefinition(project *types.Project, service typ
 		})
 	}
 
+	containerLogConfiguration := logConfiguration
+	if useFirelens(service) {
+		logRouter := createLogRouter(service, logConfiguration)
+		initContainers = append(initContainers, logRouter)
+		dependencies = append(dependencies, ecs.TaskDefinition_ContainerDependency{
+			Condition:     ecsapi.ContainerConditionStart,
+			ContainerName: logRouter.Name,
+		})
+		containerLogConfiguration = getFirelensLogConfiguration(service)
+	}
+
+	if useXRay(service) {
+		daemon := createXRayDaemon(service, logConfiguration)
+		initContainers = append(initContainers, daemon)
+		dependencies = append(dependencies, ecs.TaskDefinition_ContainerDependency{
+			Condition:     ecsapi.ContainerConditionStart,
+			ContainerName: daemon.Name,
+		})
+	}
+
+	var (
+		envoy *ecs.TaskDefinition_ContainerDefinition
+		proxy *ecs.TaskDefinition_ProxyConfiguration
+	)
+	if inMesh(project, service) {
+		var container ecs.TaskDefinition_ContainerDefinition
+		container, proxy = createEnvoyContainer(service, logConfiguration)
+		envoy = &container
+		// task traffic is redirected to Envoy, so that all containers must wait for it
+		for i := range initContainers {
+			initContainers[i].DependsOnProp = append(initContainers[i].DependsOnProp, envoyDependency())
+		}
+		dependencies = append(dependencies, envoyDependency())
+	}
+
+	tmpfs, err := toTmpfs(service)
+	if err != nil {
+		return nil, err
+	}
+	if len(tmpfs) > 0 && !requireEC2(project, service) {
+		// Fargate doesn't support tmpfs mounts, task storage keeps those paths writable with read_only
+		for i, t := range tmpfs {
+			if t.Size != defaultTmpfsSize || len(t.MountOptions) > 0 {
+				logrus.Warnf("service %q: Fargate doesn't support tmpfs, options for %s are ignored", service.Name, t.ContainerPath)
+			}
+			name := fmt.Sprintf("%sTmpfs%d", normalizeResourceName(service.Name), i)
+			volumes = append(volumes, ecs.TaskDefinition_Volume{
+				Name: name,
+			})
+			mounts = append(mounts, ecs.TaskDefinition_MountPoint{
+				ContainerPath: t.ContainerPath,
+				SourceVolume:  name,
+			})
+		}
+		tmpfs = nil
+	}
+
 	for _, v := range service.Volumes {
 		n := fmt.Sprintf("%sAccessPoint", normalizeResourceName(v.Source))
-		volumes = append(volumes, ecs.TaskDefinition_Volume{
-			EFSVolumeConfiguration: &ecs.TaskDefinition_EFSVolumeConfiguration{
-				AuthorizationConfig: &ecs.TaskDefinition_AuthorizationConfig{
-					AccessPointId: cloudformation.Ref(n),
-					IAM:           "ENABLED",
-				},
-				FilesystemId:      resources.filesystems[v.Source].ID(),
-				TransitEncryption: "ENABLED",
+		config := &ecs.TaskDefinition_EFSVolumeConfiguration{
+			AuthorizationConfig: &ecs.TaskDefinition_AuthorizationConfig{
+				AccessPointId: cloudformation.Ref(n),
+				IAM:           "ENABLED",
 			},
-			Name: v.Source,
+			FilesystemId:      resources.filesystems[v.Source].ID(),
+			TransitEncryption: "ENABLED",
+		}
+		if !transitEncryption(project.Volumes[v.Source]) {
+			// access point and IAM authorization require transit encryption
+			config.AuthorizationConfig = nil
+			config.TransitEncryption = "DISABLED"
+		}
+		volumes = append(volumes, ecs.TaskDefinition_Volume{
+			EFSVolumeConfiguration: config,
+			Name:                   v.Source,
 		})
 		mounts = append(mounts, ecs.TaskDefinition_MountPoint{
 			ContainerPath: v.Target,
@@ -104,11 +182,23 @@ func (b *ecsAPIService) createTaskDefinition(project *types.Project, service typ
 	if err != nil {
 		return nil, err
 	}
+	ssmSecrets := toSSMSecrets(service)
+	for _, s := range ssmSecrets {
+		for _, p := range pairs {
+			if p.Name == s.Name {
+				return nil, fmt.Errorf("service %q sets %s both as environment variable and by %s", service.Name, s.Name, extensionSSM)
+			}
+		}
+	}
+	if useXRay(service) {
+		pairs = withXRayDaemonAddress(pairs)
+	}
 	var reservations *types.Resource
 	if service.Deploy != nil && service.Deploy.Resources.Reservations != nil {
 		reservations = service.Deploy.Resources.Reservations
 	}
 
+	mainContainer := len(initContainers)
 	containers := append(initContainers, ecs.TaskDefinition_ContainerDefinition{
 		Command:                service.Command,
 		DisableNetworking:      service.NetworkMode == "none",
@@ -126,8 +216,8 @@ func (b *ecsAPIService) createTaskDefinition(project *types.Project, service typ
 		Image:                  service.Image,
 		Interactive:            false,
 		Links:                  nil,
-		LinuxParameters:        toLinuxParameters(service),
-		LogConfiguration:       logConfiguration,
+		LinuxParameters:        toLinuxParameters(service, tmpfs),
+		LogConfiguration:       containerLogConfiguration,
 		MemoryReservation:      memReservation,
 		MountPoints:            mounts,
 		Name:                   service.Name,
@@ -137,35 +227,90 @@ func (b *ecsAPIService) createTaskDefinition(project *types.Project, service typ
 		ReadonlyRootFilesystem: service.ReadOnly,
 		RepositoryCredentials:  credential,
 		ResourceRequirements:   toTaskResourceRequirements(reservations),
+		Secrets:                ssmSecrets,
 		StartTimeout:           0,
-		StopTimeout:            durationToInt(service.StopGracePeriod),
+		StopTimeout:            stopTimeout(project, service),
 		SystemControls:         toSystemControls(service.Sysctls),
-		Ulimits:                toUlimits(service.Ulimits),
+		Ulimits:                toUlimits(project, service),
 		User:                   service.User,
 		VolumesFrom:            nil,
 		WorkingDirectory:       service.WorkingDir,
 	})
+	if envoy != nil {
+		containers = append(containers, *envoy)
+	}
 
 	launchType := ecsapi.LaunchTypeFargate
-	if requireEC2(service) {
+	if useExternal(project) {
+		launchType = launchTypeExternal
+	} else if requireEC2(project, service) {
 		launchType = ecsapi.LaunchTypeEc2
 	}
 
-	return &ecs.TaskDefinition{
+	definition := &ecs.TaskDefinition{
 		ContainerDefinitions: containers,
 		Cpu:                  cpu,
 		Family:               fmt.Sprintf("%s-%s", project.Name, service.Name),
 		IpcMode:              service.Ipc,
 		Memory:               mem,
-		NetworkMode:          ecsapi.NetworkModeAwsvpc, // FIXME could be set by service.NetworkMode, Fargate only supports network mode ‘awsvpc’.
+		NetworkMode:          taskNetworkMode(project, service),
 		PidMode:              service.Pid,
 		PlacementConstraints: toPlacementConstraints(service.Deploy),
-		ProxyConfiguration:   nil,
+		ProxyConfiguration:   proxy,
 		RequiresCompatibilities: []string{
 			launchType,
 		},
+		// tags let superseded revisions be deregistered, including those registered by fast updates
+		Tags:    serviceTags(project, service),
 		Volumes: volumes,
-	}, nil
+	}
+
+	// goformation doesn't support RuntimePlatform and EphemeralStorage yet
+	extra := map[string]interface{}{}
+	platform, err := runtimePlatform(service)
+	if err != nil {
+		return nil, err
+	}
+	if platform != nil {
+		extra["RuntimePlatform"] = platform
+	}
+	storage, err := ephemeralStorage(project, service)
+	if err != nil {
+		return nil, err
+	}
+	if storage > 0 {
+		extra["EphemeralStorage"] = map[string]interface{}{
+			"SizeInGiB": storage,
+		}
+	}
+	if useServiceConnect(project, service) && len(service.Ports) > 0 {
+		// goformation doesn't support port mappings Name either
+		definitions := make([]interface{}, len(containers))
+		definitions[mainContainer] = map[string]interface{}{
+			"PortMappings": serviceConnectPortMappings(service),
+		}
+		extra["ContainerDefinitions"] = definitions
+	}
+	if len(extra) > 0 {
+		definition.AWSCloudFormationMetadata = extraProperties(extra)
+	}
+	return definition, nil
+}
+
+// ephemeralStorage returns the Fargate task storage size set by x-aws-ephemeral_storage, in GiB
+func ephemeralStorage(project *types.Project, service types.ServiceConfig) (int, error) {
+	v, ok := service.Extensions[extensionStorage]
+	if !ok {
+		return 0, nil
+	}
+	size, ok := v.(int)
+	if !ok || size < 21 || size > 200 {
+		return 0, fmt.Errorf("service %q: %s must be a size in GiB between 21 and 200", service.Name, extensionStorage)
+	}
+	if requireEC2(project, service) {
+		return 0, fmt.Errorf("service %q: %s is only supported by Fargate", service.Name, extensionStorage)
+	}
+	return size, nil
 }
 
 func toTaskResourceRequirements(reservations *types.Resource) []ecs.TaskDefinition_ResourceRequirement {
@@ -297,10 +442,17 @@ func getLogConfiguration(service types.ServiceConfig, project *types.Project) *e
 		"awslogs-group":         cloudformation.Ref("LogGroup"),
 		"awslogs-stream-prefix": project.Name,
 	}
-	if service.Logging != nil {
+	if service.Logging != nil && !useFirelens(service) {
 		for k, v := range service.Logging.Options {
-			if strings.HasPrefix(k, "awslogs-") {
+			if strings.HasPrefix(k, "awslogs-") || k == "mode" || k == "max-buffer-size" {
 				options[k] = v
+				continue
+			}
+			logrus.Warnf("service %q: logging option %s is not supported by awslogs driver", service.Name, k)
+		}
+		if _, ok := options["awslogs-multiline-pattern"]; ok {
+			if _, ok := options["awslogs-datetime-format"]; ok {
+				logrus.Warnf("service %q: awslogs-multiline-pattern is ignored as awslogs-datetime-format is set", service.Name)
 			}
 		}
 	}
@@ -324,56 +476,58 @@ func toSystemControls(sysctls types.Mapping) []ecs.TaskDefinition_SystemControl
 
 const miB = 1024 * 1024
 
-func toLimits(service types.ServiceConfig) (string, string, error) {
+func toLimits(project *types.Project, service types.ServiceConfig) (string, string, error) {
 	mem, cpu, err := getConfiguredLimits(service)
 	if err != nil {
 		return "", "", err
 	}
-	if requireEC2(service) {
+	proxyCPU, proxyMem, err := serviceConnectProxySize(project, service)
+	if err != nil {
+		return "", "", err
+	}
+	if requireEC2(project, service) {
 		// just return configured limits expressed in Mb and CPU units
 		var cpuLimit, memLimit string
 		if cpu > 0 {
-			cpuLimit = fmt.Sprint(cpu)
+			cpuLimit = fmt.Sprint(cpu + proxyCPU)
 		}
 		if mem > 0 {
-			memLimit = fmt.Sprint(mem / miB)
+			memLimit = fmt.Sprint((mem + proxyMem) / miB)
 		}
 		return cpuLimit, memLimit, nil
 	}
 
-	// All possible cpu/mem values for Fargate
-	fargateCPUToMem := map[int64][]types.UnitBytes{
-		256:  {512, 1024, 2048},
-		512:  {1024, 2048, 3072, 4096},
-		1024: {2048, 3072, 4096, 5120, 6144, 7168, 8192},
-		2048: {4096, 5120, 6144, 7168, 8192, 9216, 10240, 11264, 12288, 13312, 14336, 15360, 16384},
-		4096: {8192, 9216, 10240, 11264, 12288, 13312, 14336, 15360, 16384, 17408, 18432, 19456, 20480, 21504, 22528, 23552, 24576, 25600, 26624, 27648, 28672, 29696, 30720},
+	if proxyCPU > 0 || proxyMem > 0 {
+		if mem == 0 && cpu == 0 {
+			// default task size
+			cpu, mem = 250, 512*miB
+		}
+		// Service Connect proxy shares task resources, so that task size is rounded up to also provide them
+		size, ok := roundUpTaskSize(toCPUUnits(cpu+proxyCPU), mem+proxyMem)
+		if !ok {
+			return "", "", taskSizeError(service.Name, cpu+proxyCPU, mem+proxyMem)
+		}
+		return strconv.FormatInt(size.cpu, 10), strconv.FormatInt(size.mem, 10), nil
 	}
-	cpuLimit := "256"
-	memLimit := "512"
-	if mem == 0 && cpu == 0 {
-		return cpuLimit, memLimit, nil
+	windows := isWindows(service)
+	if mem == 0 && cpu == 0 && windows {
+		return "1024", "2048", nil
 	}
-
-	var cpus []int64
-	for k := range fargateCPUToMem {
-		cpus = append(cpus, k)
+	if mem == 0 && cpu == 0 {
+		return "256", "512", nil
 	}
-	sort.Slice(cpus, func(i, j int) bool { return cpus[i] < cpus[j] })
-
-	for _, fargateCPU := range cpus {
-		options := fargateCPUToMem[fargateCPU]
-		if cpu <= fargateCPU {
-			for _, m := range options {
-				if mem <= m*miB {
-					cpuLimit = strconv.FormatInt(fargateCPU, 10)
-					memLimit = strconv.FormatInt(int64(m), 10)
-					return cpuLimit, memLimit, nil
-				}
-			}
+	size, ok := fargateTaskSize(toCPUUnits(cpu), mem)
+	if !ok {
+		size, ok = roundUpTaskSize(toCPUUnits(cpu), mem)
+		if !ok {
+			return "", "", taskSizeError(service.Name, cpu, mem)
 		}
+		logrus.Warnf("service %q: resources limits don't match a Fargate task size, rounded up to %s", service.Name, size)
 	}
-	return "", "", fmt.Errorf("the resources requested are not supported by ECS/Fargate")
+	if windows && size.cpu < windowsMinTaskCPU {
+		return "", "", fmt.Errorf("service %q: Windows tasks on Fargate require at least 1 vCPU and 2 GiB, resources limits are %s", service.Name, size)
+	}
+	return strconv.FormatInt(size.cpu, 10), strconv.FormatInt(size.mem, 10), nil
 }
 
 func getConfiguredLimits(service types.ServiceConfig) (types.UnitBytes, int64, error) {
@@ -441,22 +595,41 @@ func toPortMappings(ports []types.ServicePortConfig) []ecs.TaskDefinition_PortMa
 	return m
 }
 
-func toUlimits(ulimits map[string]*types.UlimitsConfig) []ecs.TaskDefinition_Ulimit {
-	if len(ulimits) == 0 {
+func toUlimits(project *types.Project, service types.ServiceConfig) []ecs.TaskDefinition_Ulimit {
+	if len(service.Ulimits) == 0 {
 		return nil
 	}
+	names := make([]string, 0, len(service.Ulimits))
+	for k := range service.Ulimits {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	fargate := !requireEC2(project, service)
 	u := []ecs.TaskDefinition_Ulimit{}
-	for k, v := range ulimits {
+	for _, k := range names {
+		if fargate && k != "nofile" {
+			logrus.Warnf("service %q: services.ulimits.%s is not supported by Fargate", service.Name, k)
+			continue
+		}
+		v := service.Ulimits[k]
+		soft, hard := v.Soft, v.Hard
+		if v.Single != 0 {
+			soft, hard = v.Single, v.Single
+		}
 		u = append(u, ecs.TaskDefinition_Ulimit{
 			Name:      k,
-			SoftLimit: v.Soft,
-			HardLimit: v.Hard,
+			SoftLimit: soft,
+			HardLimit: hard,
 		})
 	}
+	if len(u) == 0 {
+		return nil
+	}
 	return u
 }
 
-func toLinuxParameters(service types.ServiceConfig) *ecs.TaskDefinition_LinuxParameters {
+func toLinuxParameters(service types.ServiceConfig, tmpfs []ecs.TaskDefinition_Tmpfs) *ecs.TaskDefinition_LinuxParameters {
 	return &ecs.TaskDefinition_LinuxParameters{
 		Capabilities:       toKernelCapabilities(service.CapAdd, service.CapDrop),
 		Devices:            nil,
@@ -464,22 +637,45 @@ func toLinuxParameters(service types.ServiceConfig) *ecs.TaskDefinition_LinuxPar
 		MaxSwap:            0,
 		// FIXME SharedMemorySize:   service.ShmSize,
 		Swappiness: 0,
-		Tmpfs:      toTmpfs(service.Tmpfs),
+		Tmpfs:      tmpfs,
 	}
 }
 
-func toTmpfs(tmpfs types.StringList) []ecs.TaskDefinition_Tmpfs {
-	if tmpfs == nil || len(tmpfs) == 0 {
-		return nil
+// size is required on ECS, unlimited by the compose spec
+const defaultTmpfsSize = 100
+
+// toTmpfs parses tmpfs mounts using the `path[:options]` syntax of docker run --tmpfs
+func toTmpfs(service types.ServiceConfig) ([]ecs.TaskDefinition_Tmpfs, error) {
+	if len(service.Tmpfs) == 0 {
+		return nil, nil
 	}
 	o := []ecs.TaskDefinition_Tmpfs{}
-	for _, path := range tmpfs {
-		o = append(o, ecs.TaskDefinition_Tmpfs{
+	for _, t := range service.Tmpfs {
+		path, options := t, ""
+		if i := strings.Index(t, ":"); i >= 0 {
+			path, options = t[:i], t[i+1:]
+		}
+		mount := ecs.TaskDefinition_Tmpfs{
 			ContainerPath: path,
-			Size:          100, // size is required on ECS, unlimited by the compose spec
-		})
+			Size:          defaultTmpfsSize,
+		}
+		if options != "" {
+			for _, opt := range strings.Split(options, ",") {
+				if !strings.HasPrefix(opt, "size=") {
+					mount.MountOptions = append(mount.MountOptions, opt)
+					continue
+				}
+				size, err := units.RAMInBytes(strings.TrimPrefix(opt, "size="))
+				if err != nil || size <= 0 {
+					return nil, fmt.Errorf("service %q: invalid tmpfs size for %s", service.Name, path)
+				}
+				// ECS expects the tmpfs size in MiB
+				mount.Size = int((size + units.MiB - 1) / units.MiB)
+			}
+		}
+		o = append(o, mount)
 	}
-	return o
+	return o, nil
 }
 
 func toKernelCapabilities(add []string, drop []string) *ecs.TaskDefinition_KernelCapabilities {
@@ -518,6 +714,22 @@ func durationToInt(interval *types.Duration) int {
 	return v
 }
 
+// Fargate doesn't allow a longer stop timeout, in seconds
+const fargateMaxStopTimeout = 120
+
+func stopTimeout(project *types.Project, service types.ServiceConfig) int {
+	if service.StopGracePeriod == nil {
+		return 0
+	}
+	// round up so that a sub-second grace period doesn't fall back to the ECS default
+	timeout := int(math.Ceil(time.Duration(*service.StopGracePeriod).Seconds()))
+	if timeout > fargateMaxStopTimeout && !requireEC2(project, service) {
+		logrus.Warnf("service %q: stop_grace_period is limited to %ds by Fargate", service.Name, fargateMaxStopTimeout)
+		return fargateMaxStopTimeout
+	}
+	return timeout
+}
+
 func toHostEntryPtr(hosts types.HostsList) []ecs.TaskDefinition_HostEntry {
 	if hosts == nil || len(hosts) == 0 {
 		return nil
@@ -533,14 +745,14 @@ func toHostEntryPtr(hosts types.HostsList) []ecs.TaskDefinition_HostEntry {
 	return e
 }
 
-func getRepoCredentials(service types.ServiceConfig) *ecs.TaskDefinition_RepositoryCredentials {
-	if value, ok := service.Extensions[extensionPullCredentials]; ok {
-		return &ecs.TaskDefinition_RepositoryCredentials{CredentialsParameter: value.(string)}
+func requireEC2(project *types.Project, s types.ServiceConfig) bool {
+	if _, ok := project.Extensions[extensionEC2]; ok {
+		return true
+	}
+	if useExternal(project) {
+		// Fargate restrictions don't apply to external instances either
+		return true
 	}
-	return nil
-}
-
-func requireEC2(s types.ServiceConfig) bool {
 	return gpuRequirements(s) > 0
 }
 
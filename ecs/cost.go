@@ -0,0 +1,239 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/cloudformation/ec2"
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"github.com/awslabs/goformation/v4/cloudformation/efs"
+	"github.com/awslabs/goformation/v4/cloudformation/elasticloadbalancingv2"
+	"github.com/awslabs/goformation/v4/cloudformation/logs"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// hoursPerMonth is the average number of hours in a month, as used by AWS pricing
+const hoursPerMonth = 730
+
+func (b *ecsAPIService) Cost(ctx context.Context, project *types.Project) ([]compose.CostEstimate, error) {
+	template, err := b.convert(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	return b.estimateCost(ctx, project, template)
+}
+
+type maxMonthlyCostKey struct{}
+
+// WithMaxMonthlyCost lets backend created with ctx abort deployments estimated to cost more than max USD a month
+func WithMaxMonthlyCost(ctx context.Context, maxCost float64) context.Context {
+	return context.WithValue(ctx, maxMonthlyCostKey{}, maxCost)
+}
+
+// checkMaxMonthlyCost fails if the estimated monthly cost of template exceeds the maximum set by context
+func (b *ecsAPIService) checkMaxMonthlyCost(ctx context.Context, project *types.Project, template *cloudformation.Template) error {
+	maxCost, ok := ctx.Value(maxMonthlyCostKey{}).(float64)
+	if !ok {
+		return nil
+	}
+	estimates, err := b.estimateCost(ctx, project, template)
+	if err != nil {
+		return errors.Wrap(err, "estimating monthly cost")
+	}
+	var total float64
+	for _, estimate := range estimates {
+		if !estimate.PerGB {
+			total += estimate.Monthly
+		}
+	}
+	if total > maxCost {
+		return errors.Wrapf(errdefs.ErrForbidden, "estimated monthly cost of %.2f USD exceeds maximum of %.2f USD, run docker compose alpha cost for details", total, maxCost)
+	}
+	logrus.Debugf("estimated monthly cost of %.2f USD", total)
+	return nil
+}
+
+func (b *ecsAPIService) estimateCost(ctx context.Context, project *types.Project, template *cloudformation.Template) ([]compose.CostEstimate, error) {
+	region, ok := partitionOf(b.Region).Regions()[b.Region]
+	if !ok {
+		return nil, errors.Wrapf(errdefs.ErrNotFound, "unknown region %q", b.Region)
+	}
+	estimator := costEstimator{
+		api: b.aws,
+		// Price List API names locations as endpoints describe regions, but for "EU" ones
+		location: strings.Replace(region.Description(), "Europe", "EU", 1),
+		prices:   map[string]map[string]float64{},
+	}
+
+	var estimates []compose.CostEstimate
+	for _, service := range project.Services {
+		estimate, ok, err := estimator.serviceCost(ctx, project, service, template)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			estimates = append(estimates, estimate)
+		}
+	}
+
+	var names []string
+	for name := range template.Resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		estimate, ok, err := estimator.resourceCost(ctx, name, template.Resources[name])
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			estimates = append(estimates, estimate)
+		}
+	}
+	return estimates, nil
+}
+
+// costEstimator prices resources in a location, caching Price List API results
+type costEstimator struct {
+	api      API
+	location string
+	prices   map[string]map[string]float64
+}
+
+// serviceCost estimates Fargate cost of running desired count of service tasks
+func (e costEstimator) serviceCost(ctx context.Context, project *types.Project, service types.ServiceConfig, template *cloudformation.Template) (compose.CostEstimate, bool, error) {
+	if requireEC2(project, service) {
+		logrus.Warnf("service %q: cost of EC2 instances is not estimated", service.Name)
+		return compose.CostEstimate{}, false, nil
+	}
+	if isWindows(service) {
+		logrus.Warnf("service %q: cost of Windows tasks is not estimated", service.Name)
+		return compose.CostEstimate{}, false, nil
+	}
+	ecsService, ok := template.Resources[serviceResourceName(service.Name)].(*ecs.Service)
+	if !ok {
+		// scheduled tasks don't run a service
+		return compose.CostEstimate{}, false, nil
+	}
+	definition := template.Resources[taskDefinitionResourceName(service.Name)].(*ecs.TaskDefinition)
+	cpu, err := strconv.ParseFloat(definition.Cpu, 64)
+	if err != nil {
+		return compose.CostEstimate{}, false, err
+	}
+	mem, err := strconv.ParseFloat(definition.Memory, 64)
+	if err != nil {
+		return compose.CostEstimate{}, false, err
+	}
+
+	usageType := "Fargate"
+	if platform, err := servicePlatform(service); err == nil && platform == platformARM64 {
+		usageType = "Fargate-ARM"
+	}
+	cpuPrice, err := e.price(ctx, "AmazonECS", "Compute", usageType+"-vCPU-Hours:perCPU")
+	if err != nil {
+		return compose.CostEstimate{}, false, err
+	}
+	memPrice, err := e.price(ctx, "AmazonECS", "Compute", usageType+"-GB-Hours")
+	if err != nil {
+		return compose.CostEstimate{}, false, err
+	}
+	vCPU, gb := cpu/1024, mem/1024
+	return compose.CostEstimate{
+		Service:  service.Name,
+		Resource: fmt.Sprintf("%d Fargate task(s) %g vCPU/%g GB", ecsService.DesiredCount, vCPU, gb),
+		Monthly:  float64(ecsService.DesiredCount) * (vCPU*cpuPrice + gb*memPrice) * hoursPerMonth,
+	}, true, nil
+}
+
+// resourceCost estimates cost of resources shared by services
+func (e costEstimator) resourceCost(ctx context.Context, name string, resource cloudformation.Resource) (compose.CostEstimate, bool, error) {
+	var (
+		description string
+		price       float64
+		perGB       bool
+		err         error
+	)
+	switch r := resource.(type) {
+	case *elasticloadbalancingv2.LoadBalancer:
+		family := "Load Balancer-Application"
+		if r.Type == "network" {
+			family = "Load Balancer-Network"
+		}
+		description = fmt.Sprintf("%s %s load balancer", name, r.Type)
+		price, err = e.price(ctx, "AWSELB", family, "LoadBalancerUsage")
+		price *= hoursPerMonth
+	case *ec2.NatGateway:
+		description = fmt.Sprintf("%s NAT gateway", name)
+		price, err = e.price(ctx, "AmazonEC2", "NAT Gateway", "NatGateway-Hours")
+		price *= hoursPerMonth
+	case *efs.FileSystem:
+		description = fmt.Sprintf("%s EFS storage", name)
+		price, err = e.price(ctx, "AmazonEFS", "Storage", "TimedStorage-ByteHrs")
+		perGB = true
+	case *logs.LogGroup:
+		description = fmt.Sprintf("%s CloudWatch logs ingestion", name)
+		price, err = e.price(ctx, "AmazonCloudWatch", "Data Payload", "DataProcessing-Bytes")
+		perGB = true
+	default:
+		return compose.CostEstimate{}, false, nil
+	}
+	if err != nil {
+		return compose.CostEstimate{}, false, err
+	}
+	return compose.CostEstimate{
+		Resource: description,
+		Monthly:  price,
+		PerGB:    perGB,
+	}, true, nil
+}
+
+// price returns the on-demand USD price of a product usage type
+func (e costEstimator) price(ctx context.Context, serviceCode, productFamily, usageType string) (float64, error) {
+	key := serviceCode + "/" + productFamily
+	prices, ok := e.prices[key]
+	if !ok {
+		var err error
+		prices, err = e.api.GetPrices(ctx, serviceCode, map[string]string{
+			"location":      e.location,
+			"productFamily": productFamily,
+		})
+		if err != nil {
+			return 0, err
+		}
+		e.prices[key] = prices
+	}
+	// usage types are prefixed by a region code, but in us-east-1
+	pattern := regexp.MustCompile(`^([A-Z]+[0-9]*-)?` + regexp.QuoteMeta(usageType) + `$`)
+	for name, price := range prices {
+		if pattern.MatchString(name) {
+			return price, nil
+		}
+	}
+	return 0, errors.Wrapf(errdefs.ErrNotFound, "no %s price for %s in %s", serviceCode, usageType, e.location)
+}
@@ -0,0 +1,104 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func TestCost(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  web:
+    image: nginx
+    ports:
+      - 80:80
+    deploy:
+      replicas: 2
+`)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+	useDefaultVPC(m.EXPECT())
+	usePrices(m.EXPECT())
+
+	backend := &ecsAPIService{aws: m, Region: "eu-west-3"}
+	estimates, err := backend.Cost(context.TODO(), project)
+	assert.NilError(t, err)
+	assert.Equal(t, len(estimates), 3)
+
+	assert.Equal(t, estimates[0].Service, "web")
+	assert.Equal(t, estimates[0].Resource, "2 Fargate task(s) 0.25 vCPU/0.5 GB")
+	assert.Equal(t, fmt.Sprintf("%.2f", estimates[0].Monthly), "21.90")
+
+	assert.Equal(t, estimates[1].Service, "")
+	assert.Equal(t, estimates[1].Resource, "LoadBalancer application load balancer")
+	assert.Equal(t, fmt.Sprintf("%.2f", estimates[1].Monthly), "18.25")
+
+	assert.Equal(t, estimates[2].Resource, "LogGroup CloudWatch logs ingestion")
+	assert.Equal(t, estimates[2].Monthly, 0.5)
+	assert.Check(t, estimates[2].PerGB)
+}
+
+func TestMaxMonthlyCost(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  web:
+    image: nginx
+    ports:
+      - 80:80
+    deploy:
+      replicas: 2
+`)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+	useDefaultVPC(m.EXPECT())
+	usePrices(m.EXPECT())
+	backend := &ecsAPIService{aws: m, Region: "eu-west-3"}
+	template, err := backend.convert(context.TODO(), project)
+	assert.NilError(t, err)
+
+	err = backend.checkMaxMonthlyCost(WithMaxMonthlyCost(context.TODO(), 50), project, template)
+	assert.NilError(t, err)
+
+	err = backend.checkMaxMonthlyCost(WithMaxMonthlyCost(context.TODO(), 30), project, template)
+	assert.ErrorContains(t, err, "estimated monthly cost of 40.15 USD exceeds maximum of 30.00 USD")
+}
+
+func usePrices(m *MockAPIMockRecorder) {
+	filters := func(family string) map[string]string {
+		return map[string]string{"location": "EU (Paris)", "productFamily": family}
+	}
+	m.GetPrices(gomock.Any(), "AmazonECS", filters("Compute")).Return(map[string]float64{
+		"EUW3-Fargate-vCPU-Hours:perCPU":           0.05,
+		"EUW3-Fargate-GB-Hours":                    0.005,
+		"EUW3-Fargate-ARM-vCPU-Hours:perCPU":       0.04,
+		"EUW3-SpotUsage-Fargate-vCPU-Hours:perCPU": 0.015,
+	}, nil).AnyTimes()
+	m.GetPrices(gomock.Any(), "AWSELB", filters("Load Balancer-Application")).Return(map[string]float64{
+		"EUW3-LoadBalancerUsage": 0.025,
+	}, nil).AnyTimes()
+	m.GetPrices(gomock.Any(), "AmazonCloudWatch", filters("Data Payload")).Return(map[string]float64{
+		"EUW3-DataProcessing-Bytes": 0.5,
+	}, nil).AnyTimes()
+}
@@ -0,0 +1,65 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"github.com/compose-spec/compose-go/types"
+)
+
+const waitForInitContainerImage = "busybox"
+
+// createWaitForHealthyContainers creates an init container for each `service_healthy` dependency which blocks until
+// dependency is registered in Cloud Map. ECS only registers tasks once container healthcheck has passed.
+func createWaitForHealthyContainers(project *types.Project, service types.ServiceConfig, logConfiguration *ecs.TaskDefinition_LogConfiguration) ([]ecs.TaskDefinition_ContainerDefinition, error) {
+	var names []string
+	for name, dependency := range service.DependsOn {
+		if dependency.Condition == types.ServiceConditionHealthy {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var containers []ecs.TaskDefinition_ContainerDefinition
+	for _, name := range names {
+		dependency, err := project.GetService(name)
+		if err != nil {
+			return nil, err
+		}
+		if isScheduled(project, name) {
+			return nil, fmt.Errorf("service %q can't wait for scheduled task %q to be healthy", service.Name, name)
+		}
+		if dependency.HealthCheck == nil || dependency.HealthCheck.Disable || len(dependency.HealthCheck.Test) == 0 {
+			return nil, fmt.Errorf("service %q depends on %q to be healthy, but %q has no healthcheck", service.Name, name, name)
+		}
+		host := fmt.Sprintf("%s.%s", name, cloudMapNamespace(project))
+		containers = append(containers, ecs.TaskDefinition_ContainerDefinition{
+			Name:      fmt.Sprintf("%s_WaitFor%s_InitContainer", normalizeResourceName(service.Name), normalizeResourceName(name)),
+			Image:     waitForInitContainerImage,
+			Essential: false,
+			Command: []string{
+				"sh", "-c",
+				fmt.Sprintf("until nslookup %s > /dev/null 2>&1; do echo waiting for %s to be healthy; sleep 5; done", host, name),
+			},
+			LogConfiguration: logConfiguration,
+		})
+	}
+	return containers, nil
+}
@@ -0,0 +1,73 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestDependsOnServiceHealthy(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  db:
+    image: postgres
+    healthcheck:
+      test: ["CMD", "pg_isready"]
+  web:
+    image: nginx
+    depends_on:
+      db:
+        condition: service_healthy
+`, useDefaultVPC)
+	def := template.Resources["WebTaskDefinition"].(*ecs.TaskDefinition)
+	var waitFor *ecs.TaskDefinition_ContainerDefinition
+	for i, c := range def.ContainerDefinitions {
+		if c.Name == "Web_WaitForDb_InitContainer" {
+			waitFor = &def.ContainerDefinitions[i]
+		}
+	}
+	assert.Assert(t, waitFor != nil)
+	assert.Check(t, !waitFor.Essential)
+	assert.Equal(t, waitFor.Image, "busybox")
+
+	main := getMainContainer(def, t)
+	assert.Check(t, is.Contains(main.DependsOnProp, ecs.TaskDefinition_ContainerDependency{
+		Condition:     "SUCCESS",
+		ContainerName: "Web_WaitForDb_InitContainer",
+	}))
+
+	web := template.Resources["WebService"].(*ecs.Service)
+	assert.Check(t, is.Contains(web.AWSCloudFormationDependsOn, "DbService"))
+}
+
+func TestDependsOnServiceHealthyWithoutHealthcheck(t *testing.T) {
+	err := convertYamlError(t, `
+services:
+  db:
+    image: postgres
+  web:
+    image: nginx
+    depends_on:
+      db:
+        condition: service_healthy
+`, useDefaultVPC)
+	assert.ErrorContains(t, err, `service "web" depends on "db" to be healthy, but "db" has no healthcheck`)
+}
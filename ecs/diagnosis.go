@@ -0,0 +1,187 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+const (
+	// diagnosisTasks is the number of stopped tasks reported for each failing service
+	diagnosisTasks = 3
+	// diagnosisLogLines is the number of log lines reported for each failing service
+	diagnosisLogLines = 20
+)
+
+// stackFailure is a failed deployment, with the diagnosis collected before rollback removed failing resources
+type stackFailure struct {
+	err       error
+	diagnosis string
+}
+
+func (e stackFailure) Error() string {
+	if e.diagnosis == "" {
+		return e.err.Error()
+	}
+	return fmt.Sprintf("%s\n\n%s", e.err.Error(), e.diagnosis)
+}
+
+func (e stackFailure) Cause() error {
+	return e.err
+}
+
+func (e stackFailure) Unwrap() error {
+	return e.err
+}
+
+// serviceDiagnosis holds the stopped tasks and latest logs of a service which failed to run
+type serviceDiagnosis struct {
+	name    string
+	stopped []string
+	logs    []string
+}
+
+// diagnoseServices collects stopped tasks reasons of services which don't run, and their logs since deployment
+// started, while the stack still exists
+func (b *ecsAPIService) diagnoseServices(ctx context.Context, name string, since time.Time) []serviceDiagnosis {
+	cluster, err := b.aws.GetStackClusterID(ctx, name)
+	if err != nil {
+		logrus.Debugf("can't diagnose stack %s: %s", name, err)
+		return nil
+	}
+	arns, err := b.aws.ListStackServices(ctx, name)
+	if err != nil {
+		logrus.Debugf("can't diagnose stack %s: %s", name, err)
+		return nil
+	}
+	// services are inspected concurrently, results are stored by index to keep diagnosis ordered
+	inspected := make([]*serviceDiagnosis, len(arns))
+	_ = concurrently(ctx, len(arns), func(ctx context.Context, i int) error {
+		inspected[i] = b.diagnoseService(ctx, cluster, arns[i])
+		return nil
+	})
+	var diagnosis []serviceDiagnosis
+	for _, d := range inspected {
+		if d != nil {
+			diagnosis = append(diagnosis, *d)
+		}
+	}
+	if len(diagnosis) == 0 {
+		return nil
+	}
+
+	logs := map[string][]string{}
+	err = b.aws.GetLogs(ctx, name, func(service, container, message string, timestamp time.Time) {
+		logs[service] = append(logs[service], message)
+	}, compose.LogOptions{
+		Tail:  diagnosisLogLines,
+		Since: since,
+	})
+	if err != nil {
+		logrus.Debugf("can't collect logs of stack %s: %s", name, err)
+	}
+	for i, d := range diagnosis {
+		lines := logs[d.name]
+		if len(lines) > diagnosisLogLines {
+			lines = lines[len(lines)-diagnosisLogLines:]
+		}
+		diagnosis[i].logs = lines
+	}
+	return diagnosis
+}
+
+// diagnoseService collects last stopped tasks of a service, or nil if none did stop
+func (b *ecsAPIService) diagnoseService(ctx context.Context, cluster string, arn string) *serviceDiagnosis {
+	stopped, err := b.aws.GetServiceTasks(ctx, cluster, arn, true)
+	if err != nil || len(stopped) == 0 {
+		return nil
+	}
+	service, _, err := b.aws.GetServiceEvents(ctx, cluster, arn)
+	if err != nil {
+		return nil
+	}
+	sort.Slice(stopped, func(i, j int) bool {
+		return aws.TimeValue(stopped[i].StoppedAt).After(aws.TimeValue(stopped[j].StoppedAt))
+	})
+	if len(stopped) > diagnosisTasks {
+		stopped = stopped[:diagnosisTasks]
+	}
+	d := serviceDiagnosis{name: service}
+	for _, task := range stopped {
+		d.stopped = append(d.stopped, stoppedTaskReason(task))
+	}
+	return &d
+}
+
+// stoppedTaskReason describes why a task stopped, with the reason and exit code of its failed containers
+func stoppedTaskReason(task *ecs.Task) string {
+	reason := fmt.Sprintf("task %s %s: %s", lastSegment(aws.StringValue(task.TaskArn)), aws.StringValue(task.StopCode), aws.StringValue(task.StoppedReason))
+	for _, c := range task.Containers {
+		switch {
+		case c.Reason != nil:
+			reason += fmt.Sprintf("\n    container %s: %s", aws.StringValue(c.Name), aws.StringValue(c.Reason))
+		case aws.Int64Value(c.ExitCode) != 0:
+			reason += fmt.Sprintf("\n    container %s exited with code %d", aws.StringValue(c.Name), aws.Int64Value(c.ExitCode))
+		}
+	}
+	return reason
+}
+
+// formatDiagnosis renders failed resources events and services diagnosis
+func formatDiagnosis(failed []*cloudformation.StackEvent, services []serviceDiagnosis) string {
+	var b strings.Builder
+	if len(failed) > 0 {
+		b.WriteString("Failed resources:\n")
+		for _, event := range failed {
+			fmt.Fprintf(&b, "  %s (%s) %s: %s\n", aws.StringValue(event.LogicalResourceId), aws.StringValue(event.ResourceType),
+				aws.StringValue(event.ResourceStatus), aws.StringValue(event.ResourceStatusReason))
+		}
+	}
+	for _, service := range services {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "Service %s stopped tasks:\n", service.name)
+		for _, reason := range service.stopped {
+			fmt.Fprintf(&b, "  %s\n", reason)
+		}
+		if len(service.logs) > 0 {
+			fmt.Fprintf(&b, "Service %s latest logs:\n", service.name)
+			for _, line := range service.logs {
+				fmt.Fprintf(&b, "  %s\n", line)
+			}
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// isCancellation tells if a failed resource event is only a consequence of another resource failure
+func isCancellation(event *cloudformation.StackEvent) bool {
+	reason := aws.StringValue(event.ResourceStatusReason)
+	return strings.HasPrefix(reason, "Resource creation cancelled") || strings.HasPrefix(reason, "Resource update cancelled")
+}
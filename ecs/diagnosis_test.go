@@ -0,0 +1,79 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func TestDiagnoseServices(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+
+	since := time.Now()
+	m.EXPECT().GetStackClusterID(gomock.Any(), "test").Return("cluster", nil)
+	m.EXPECT().ListStackServices(gomock.Any(), "test").Return([]string{"web-arn", "db-arn"}, nil)
+	m.EXPECT().GetServiceTasks(gomock.Any(), "cluster", "web-arn", true).Return([]*ecs.Task{
+		{
+			TaskArn:       aws.String("arn:aws:ecs:us-east-1:012345678910:task/cluster/0123"),
+			StopCode:      aws.String("EssentialContainerExited"),
+			StoppedReason: aws.String("Essential container in task exited"),
+			StoppedAt:     aws.Time(since.Add(time.Minute)),
+			Containers: []*ecs.Container{
+				{Name: aws.String("Web_ResolvConf_InitContainer"), ExitCode: aws.Int64(0)},
+				{Name: aws.String("web"), ExitCode: aws.Int64(1)},
+			},
+		},
+	}, nil)
+	m.EXPECT().GetServiceEvents(gomock.Any(), "cluster", "web-arn").Return("web", nil, nil)
+	m.EXPECT().GetServiceTasks(gomock.Any(), "cluster", "db-arn", true).Return(nil, nil)
+	m.EXPECT().GetLogs(gomock.Any(), "test", gomock.Any(), compose.LogOptions{Tail: diagnosisLogLines, Since: since}).DoAndReturn(
+		func(ctx context.Context, name string, consumer func(service, container, message string, timestamp time.Time), opts compose.LogOptions) error {
+			consumer("db", "0456", "ready to accept connections", since)
+			consumer("web", "0123", "Error: DATABASE_URL is not set", since)
+			return nil
+		})
+
+	backend := &ecsAPIService{aws: m}
+	diagnosis := backend.diagnoseServices(context.TODO(), "test", since)
+	assert.Equal(t, formatDiagnosis([]*cloudformation.StackEvent{
+		{
+			LogicalResourceId:    aws.String("WebService"),
+			ResourceType:         aws.String("AWS::ECS::Service"),
+			ResourceStatus:       aws.String("CREATE_FAILED"),
+			ResourceStatusReason: aws.String("ECS Deployment Circuit Breaker was triggered"),
+		},
+	}, diagnosis), `Failed resources:
+  WebService (AWS::ECS::Service) CREATE_FAILED: ECS Deployment Circuit Breaker was triggered
+
+Service web stopped tasks:
+  task 0123 EssentialContainerExited: Essential container in task exited
+    container web exited with code 1
+Service web latest logs:
+  Error: DATABASE_URL is not set`)
+}
@@ -0,0 +1,73 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	"github.com/sirupsen/logrus"
+)
+
+// imageMetadata is the task definition metadata recording the image reference services were pinned from
+const imageMetadata = "Image"
+
+// pinImageDigests replaces services image tags in task definitions by the manifest digest they resolve to, so that all
+// tasks run the same image whatever the tag is later pushed to
+func (b *ecsAPIService) pinImageDigests(ctx context.Context, project *types.Project, template *cloudformation.Template) error {
+	for _, service := range project.Services {
+		definition, ok := template.Resources[taskDefinitionResourceName(service.Name)].(*ecs.TaskDefinition)
+		if !ok || !isServiceSelected(ctx, service.Name) {
+			continue
+		}
+		ref, err := reference.ParseNormalizedNamed(service.Image)
+		if err != nil {
+			return err
+		}
+		if _, ok := ref.(reference.Canonical); ok {
+			continue
+		}
+		platform, err := servicePlatform(service)
+		if err != nil {
+			return err
+		}
+		dgst, err := b.aws.GetImageDigest(ctx, service.Image, platform)
+		if err != nil {
+			logrus.Warnf("service %q: can't resolve digest of image %s, deploying by tag: %v", service.Name, service.Image, err)
+			continue
+		}
+		pinned, err := reference.WithDigest(reference.TrimNamed(ref), digest.Digest(dgst))
+		if err != nil {
+			return err
+		}
+		for i, container := range definition.ContainerDefinitions {
+			if container.Name == service.Name {
+				definition.ContainerDefinitions[i].Image = reference.FamiliarString(pinned)
+			}
+		}
+		if definition.AWSCloudFormationMetadata == nil {
+			definition.AWSCloudFormationMetadata = map[string]interface{}{}
+		}
+		definition.AWSCloudFormationMetadata[imageMetadata] = reference.FamiliarString(reference.TagNameOnly(ref))
+		logrus.Debugf("service %q: image %s pinned to %s", service.Name, service.Image, dgst)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func TestPinImageDigests(t *testing.T) {
+	yaml := `
+services:
+  web:
+    image: nginx:1.19
+  arm:
+    image: myorg/app
+    platform: linux/arm64
+  pinned:
+    image: redis@sha256:0123456789012345678901234567890123456789012345678901234567890123
+  private:
+    image: private.registry/app:1.0
+`
+	project := loadConfig(t, yaml)
+	template := convertYaml(t, yaml, useDefaultVPC)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+	m.EXPECT().GetImageDigest(gomock.Any(), "nginx:1.19", "linux/amd64").Return("sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", nil)
+	m.EXPECT().GetImageDigest(gomock.Any(), "myorg/app", "linux/arm64").Return("sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", nil)
+	m.EXPECT().GetImageDigest(gomock.Any(), "private.registry/app:1.0", "linux/amd64").Return("", fmt.Errorf("unauthorized"))
+
+	backend := &ecsAPIService{aws: m}
+	err := backend.pinImageDigests(context.TODO(), project, template)
+	assert.NilError(t, err)
+
+	tests := []struct {
+		service  string
+		image    string
+		metadata interface{}
+	}{
+		{"Web", "nginx@sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", "nginx:1.19"},
+		{"Arm", "myorg/app@sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", "myorg/app:latest"},
+		{"Pinned", "redis@sha256:0123456789012345678901234567890123456789012345678901234567890123", nil},
+		{"Private", "private.registry/app:1.0", nil},
+	}
+	for _, test := range tests {
+		def := template.Resources[test.service+"TaskDefinition"].(*ecs.TaskDefinition)
+		assert.Equal(t, getMainContainer(def, t).Image, test.image)
+		assert.Equal(t, def.AWSCloudFormationMetadata[imageMetadata], test.metadata)
+	}
+}
@@ -18,16 +18,44 @@ package ecs
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"strings"
 
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/errdefs"
 	"github.com/docker/compose-cli/progress"
 )
 
+type removeVolumesKey struct{}
+
+type removeVolumes struct {
+	autoApprove bool
+}
+
+// WithRemoveVolumes makes Down also delete EFS file systems and other resources CloudFormation retains on stack
+// deletion. Unless autoApprove is set, user has to confirm twice as data is lost.
+func WithRemoveVolumes(ctx context.Context, autoApprove bool) context.Context {
+	return context.WithValue(ctx, removeVolumesKey{}, removeVolumes{autoApprove: autoApprove})
+}
+
 func (b *ecsAPIService) Down(ctx context.Context, project string) error {
 	resources, err := b.aws.ListStackResources(ctx, project)
 	if err != nil {
 		return err
 	}
 
+	retained := retainedResources(resources)
+	remove, removeVolumes := ctx.Value(removeVolumesKey{}).(removeVolumes)
+	if removeVolumes && len(retained) > 0 && !remove.autoApprove {
+		err = b.confirmRemoveVolumes(project, retained)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = resources.apply(awsTypeCapacityProvider, doDelete(ctx, b.aws.DeleteCapacityProvider))
 	if err != nil {
 		return err
@@ -47,7 +75,83 @@ func (b *ecsAPIService) Down(ctx context.Context, project string) error {
 	if err != nil {
 		return err
 	}
-	return b.WaitStackCompletion(ctx, project, stackDelete, previousEvents...)
+	err = b.WaitStackCompletion(ctx, project, stackDelete, previousEvents...)
+	if err != nil {
+		return err
+	}
+	// stack deletion deregisters the task definitions it manages, but not those registered by fast updates
+	err = b.pruneTaskDefinitions(ctx, project, nil)
+	if err != nil {
+		logrus.Warnf("failed to deregister task definitions: %s", err)
+	}
+
+	w := progress.ContextWriter(ctx)
+	if !removeVolumes {
+		for _, r := range retained {
+			w.Event(progress.Event{
+				ID:         r.LogicalID,
+				Text:       r.ARN,
+				Status:     progress.Done,
+				StatusText: "RETAINED, use --volumes to delete",
+			})
+		}
+		return nil
+	}
+	return retained.apply(awsTypeFileSystem, func(r stackResource) error {
+		err := doDelete(ctx, b.aws.DeleteFileSystem)(r)
+		if err != nil {
+			w.Event(progress.Event{
+				ID:         r.LogicalID,
+				Status:     progress.Error,
+				StatusText: err.Error(),
+			})
+			return err
+		}
+		w.Event(progress.Event{
+			ID:         r.LogicalID,
+			Status:     progress.Done,
+			StatusText: "DELETE_COMPLETE",
+		})
+		return nil
+	})
+}
+
+// retainedResources selects the resources CloudFormation keeps on stack deletion, as their DeletionPolicy is Retain
+func retainedResources(resources stackResources) stackResources {
+	var retained stackResources
+	for _, r := range resources {
+		if r.Type == awsTypeFileSystem && r.ARN != "" {
+			retained = append(retained, r)
+		}
+	}
+	return retained
+}
+
+// confirmRemoveVolumes asks user to confirm deletion of retained resources, then to type project name, as deleted
+// data can't be recovered
+func (b *ecsAPIService) confirmRemoveVolumes(project string, retained stackResources) error {
+	var names []string
+	for _, r := range retained {
+		names = append(names, fmt.Sprintf("%s (%s)", r.LogicalID, r.ARN))
+	}
+	if !isTerminal(os.Stdin) {
+		return errors.Wrapf(errdefs.ErrForbidden, "can't confirm deletion of %s in non-interactive mode, use --yes", strings.Join(names, ", "))
+	}
+	confirm, err := b.user.Confirm(fmt.Sprintf("Delete %s and all their data?", strings.Join(names, ", ")), false)
+	if err != nil {
+		return err
+	}
+	if !confirm {
+		return errors.Wrap(errdefs.ErrCanceled, "down canceled")
+	}
+	name, err := b.user.Input(fmt.Sprintf("Type the project name %q to confirm", project), "")
+	if err != nil {
+		return err
+	}
+	if name != project {
+		return errors.Wrap(errdefs.ErrCanceled, "project name doesn't match, down canceled")
+	}
+	return nil
 }
 
 func (b *ecsAPIService) previousStackEvents(ctx context.Context, project string) ([]string, error) {
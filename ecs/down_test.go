@@ -0,0 +1,53 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func TestDownRetainedResources(t *testing.T) {
+	resources := stackResources{
+		{LogicalID: "FooService", Type: awsTypeService, ARN: "arn:aws:ecs:foo"},
+		{LogicalID: "DataFilesystem", Type: awsTypeFileSystem, ARN: "fs-123abc"},
+	}
+	assert.DeepEqual(t, retainedResources(resources), stackResources{resources[1]})
+
+	for _, removeVolumes := range []bool{false, true} {
+		ctrl := gomock.NewController(t)
+		m := NewMockAPI(ctrl)
+		m.EXPECT().ListStackResources(gomock.Any(), "test").Return(resources, nil)
+		m.EXPECT().DescribeStackEvents(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+		m.EXPECT().DeleteStack(gomock.Any(), "test").Return(nil)
+		m.EXPECT().GetStackID(gomock.Any(), "test").Return("stack-id", nil)
+		m.EXPECT().WaitStackComplete(gomock.Any(), "stack-id", stackDelete).Return(nil)
+
+		ctx := context.TODO()
+		if removeVolumes {
+			ctx = WithRemoveVolumes(ctx, true)
+			m.EXPECT().DeleteFileSystem(gomock.Any(), "fs-123abc").Return(nil)
+		}
+		backend := &ecsAPIService{aws: m}
+		err := backend.Down(ctx, "test")
+		assert.NilError(t, err)
+		ctrl.Finish()
+	}
+}
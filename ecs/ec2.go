@@ -19,11 +19,14 @@ package ecs
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/awslabs/goformation/v4/cloudformation"
 	"github.com/awslabs/goformation/v4/cloudformation/autoscaling"
+	ec2api "github.com/awslabs/goformation/v4/cloudformation/ec2"
 	"github.com/awslabs/goformation/v4/cloudformation/ecs"
 	"github.com/awslabs/goformation/v4/cloudformation/iam"
 	"github.com/compose-spec/compose-go/types"
@@ -32,41 +35,112 @@ import (
 const (
 	placementConstraintAMI     = "node.ami == "
 	placementConstraintMachine = "node.machine == "
+
+	defaultEC2MachineType      = "m5.large"
+	defaultEC2ARM64MachineType = "m6g.large"
 )
 
+// ec2Config is the Auto Scaling Group set by x-aws-ec2 to run services on EC2 instances. Instances above
+// on_demand_base run on Spot for spot_percent of them.
+type ec2Config struct {
+	InstanceType string `json:"instance_type,omitempty"`
+	AMI          string `json:"ami,omitempty"`
+	Min          int    `json:"min,omitempty"`
+	Max          int    `json:"max,omitempty"`
+	OnDemandBase int    `json:"on_demand_base,omitempty"`
+	SpotPercent  int    `json:"spot_percent,omitempty"`
+}
+
+func getEC2Config(project *types.Project) (ec2Config, error) {
+	config := ec2Config{
+		Min: 1,
+		Max: 10,
+	}
+	v, ok := project.Extensions[extensionEC2]
+	if !ok {
+		return config, nil
+	}
+	marshalled, err := json.Marshal(v)
+	if err != nil {
+		return config, err
+	}
+	err = json.Unmarshal(marshalled, &config)
+	if err != nil {
+		return config, err
+	}
+	if config.Min < 0 || config.Max < 1 || config.Min > config.Max {
+		return config, fmt.Errorf("%s min (%d) and max (%d) instances are invalid", extensionEC2, config.Min, config.Max)
+	}
+	if config.SpotPercent < 0 || config.SpotPercent > 100 || config.OnDemandBase < 0 {
+		return config, fmt.Errorf("%s spot_percent must be between 0 and 100, and on_demand_base can't be negative", extensionEC2)
+	}
+	return config, nil
+}
+
 func (b *ecsAPIService) createCapacityProvider(ctx context.Context, project *types.Project, template *cloudformation.Template, resources awsResources) error {
 	var (
 		ec2         bool
+		gpu         bool
 		ami         string
 		machineType string
 	)
 	for _, service := range project.Services {
-		if requireEC2(service) {
+		if requireEC2(project, service) {
 			ec2 = true
+		}
+		if gpuRequirements(service) > 0 {
+			gpu = true
 			// TODO once we can assign a service to a CapacityProvider, we could run this _per service_
 			ami, machineType = getUserDefinedMachine(service)
 			break
 		}
 	}
 
-	if !ec2 {
+	if !ec2 || useExternal(project) {
 		return nil
 	}
+	windows := requireWindows(project)
+	if windows && (gpu || requireARM64(project) || !allServices(project.Services, isWindows)) {
+		return fmt.Errorf("%s instances can't run both Windows and Linux services", extensionEC2)
+	}
 
+	config, err := getEC2Config(project)
+	if err != nil {
+		return err
+	}
 	if ami == "" {
-		recommended, err := b.aws.GetParameter(ctx, "/aws/service/ecs/optimized-ami/amazon-linux-2/gpu/recommended")
+		ami = config.AMI
+	}
+	if machineType == "" {
+		machineType = config.InstanceType
+	}
+
+	if ami == "" {
+		parameter := "/aws/service/ecs/optimized-ami/amazon-linux-2/recommended"
+		if gpu {
+			parameter = "/aws/service/ecs/optimized-ami/amazon-linux-2/gpu/recommended"
+		} else if windows {
+			parameter = windowsAMIParameter
+		} else if requireARM64(project) {
+			parameter = "/aws/service/ecs/optimized-ami/amazon-linux-2/arm64/recommended"
+		}
+		recommended, err := b.aws.GetParameter(ctx, parameter)
 		if err != nil {
 			return err
 		}
 		ami = recommended
 	}
 
-	if machineType == "" {
+	if machineType == "" && gpu {
 		t, err := guessMachineType(project)
 		if err != nil {
 			return err
 		}
 		machineType = t
+	} else if machineType == "" && requireARM64(project) {
+		machineType = defaultEC2ARM64MachineType
+	} else if machineType == "" {
+		machineType = defaultEC2MachineType
 	}
 
 	template.Resources["CapacityProvider"] = &ecs.CapacityProvider{
@@ -79,23 +153,64 @@ func (b *ecsAPIService) createCapacityProvider(ctx context.Context, project *typ
 		Tags: projectTags(project),
 	}
 
-	template.Resources["AutoscalingGroup"] = &autoscaling.AutoScalingGroup{
-		LaunchConfigurationName: cloudformation.Ref("LaunchConfiguration"),
-		MaxSize:                 "10", //TODO
-		MinSize:                 "1",
-		VPCZoneIdentifier:       resources.subnetsIDs(),
-	}
-
 	userData := base64.StdEncoding.EncodeToString([]byte(
 		fmt.Sprintf("#!/bin/bash\necho ECS_CLUSTER=%s >> /etc/ecs/ecs.config", project.Name)))
+	if windows {
+		userData = base64.StdEncoding.EncodeToString([]byte(
+			fmt.Sprintf("<powershell>\nImport-Module ECSTools\nInitialize-ECSAgent -Cluster %s -EnableTaskIAMRole\n</powershell>", project.Name)))
+	}
 
-	template.Resources["LaunchConfiguration"] = &autoscaling.LaunchConfiguration{
-		ImageId:            ami,
-		InstanceType:       machineType,
-		SecurityGroups:     resources.allSecurityGroups(),
-		IamInstanceProfile: cloudformation.Ref("EC2InstanceProfile"),
-		UserData:           userData,
+	autoscalingGroup := &autoscaling.AutoScalingGroup{
+		MaxSize:           strconv.Itoa(config.Max),
+		MinSize:           strconv.Itoa(config.Min),
+		VPCZoneIdentifier: resources.subnetsIDs(),
 	}
+	if config.SpotPercent > 0 || config.OnDemandBase > 0 {
+		// mixing on-demand and Spot instances requires a launch template
+		template.Resources["LaunchTemplate"] = &ec2api.LaunchTemplate{
+			LaunchTemplateData: &ec2api.LaunchTemplate_LaunchTemplateData{
+				ImageId:      ami,
+				InstanceType: machineType,
+				IamInstanceProfile: &ec2api.LaunchTemplate_IamInstanceProfile{
+					Arn: cloudformation.GetAtt("EC2InstanceProfile", "Arn"),
+				},
+				SecurityGroupIds: resources.allSecurityGroups(),
+				UserData:         userData,
+			},
+		}
+		autoscalingGroup.MixedInstancesPolicy = &autoscaling.AutoScalingGroup_MixedInstancesPolicy{
+			InstancesDistribution: &autoscaling.AutoScalingGroup_InstancesDistribution{
+				OnDemandBaseCapacity:                config.OnDemandBase,
+				OnDemandPercentageAboveBaseCapacity: 100 - config.SpotPercent,
+			},
+			LaunchTemplate: &autoscaling.AutoScalingGroup_LaunchTemplate{
+				LaunchTemplateSpecification: &autoscaling.AutoScalingGroup_LaunchTemplateSpecification{
+					LaunchTemplateId: cloudformation.Ref("LaunchTemplate"),
+					Version:          cloudformation.GetAtt("LaunchTemplate", "LatestVersionNumber"),
+				},
+			},
+		}
+		if config.SpotPercent == 100 {
+			// goformation omits zero values, while CloudFormation defaults to 100% on-demand
+			autoscalingGroup.AWSCloudFormationMetadata = extraProperties(map[string]interface{}{
+				"MixedInstancesPolicy": map[string]interface{}{
+					"InstancesDistribution": map[string]interface{}{
+						"OnDemandPercentageAboveBaseCapacity": 0,
+					},
+				},
+			})
+		}
+	} else {
+		template.Resources["LaunchConfiguration"] = &autoscaling.LaunchConfiguration{
+			ImageId:            ami,
+			InstanceType:       machineType,
+			SecurityGroups:     resources.allSecurityGroups(),
+			IamInstanceProfile: cloudformation.Ref("EC2InstanceProfile"),
+			UserData:           userData,
+		}
+		autoscalingGroup.LaunchConfigurationName = cloudformation.Ref("LaunchConfiguration")
+	}
+	template.Resources["AutoscalingGroup"] = autoscalingGroup
 
 	template.Resources["EC2InstanceProfile"] = &iam.InstanceProfile{
 		Roles: []string{cloudformation.Ref("EC2InstanceRole")},
@@ -113,6 +228,14 @@ func (b *ecsAPIService) createCapacityProvider(ctx context.Context, project *typ
 	cluster.CapacityProviders = []string{
 		cloudformation.Ref("CapacityProvider"),
 	}
+	if _, ok := project.Extensions[extensionEC2]; ok {
+		cluster.DefaultCapacityProviderStrategy = []ecs.Cluster_CapacityProviderStrategyItem{
+			{
+				CapacityProvider: cloudformation.Ref("CapacityProvider"),
+				Weight:           1,
+			},
+		}
+	}
 
 	return nil
 }
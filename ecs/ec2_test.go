@@ -19,7 +19,11 @@ package ecs
 import (
 	"testing"
 
+	"github.com/awslabs/goformation/v4/cloudformation"
 	"github.com/awslabs/goformation/v4/cloudformation/autoscaling"
+	"github.com/awslabs/goformation/v4/cloudformation/ec2"
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"github.com/golang/mock/gomock"
 	"gotest.tools/v3/assert"
 )
 
@@ -47,3 +51,51 @@ services:
 	assert.Check(t, lc.ImageId == "ami123456789")
 	assert.Check(t, lc.InstanceType == "t0.femto")
 }
+
+func TestEC2Mode(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  test:
+    image: "image"
+    deploy:
+      resources:
+        limits:
+          memory: 12Gb
+x-aws-ec2:
+  instance_type: r5.large
+  max: 4
+  on_demand_base: 1
+  spot_percent: 75
+`, useDefaultVPC, func(m *MockAPIMockRecorder) {
+		m.GetParameter(gomock.Any(), "/aws/service/ecs/optimized-ami/amazon-linux-2/recommended").Return("ami123456789", nil)
+	})
+	lt := template.Resources["LaunchTemplate"].(*ec2.LaunchTemplate)
+	assert.Equal(t, lt.LaunchTemplateData.ImageId, "ami123456789")
+	assert.Equal(t, lt.LaunchTemplateData.InstanceType, "r5.large")
+	asg := template.Resources["AutoscalingGroup"].(*autoscaling.AutoScalingGroup)
+	assert.Equal(t, asg.MaxSize, "4")
+	assert.Equal(t, asg.MinSize, "1")
+	assert.Equal(t, asg.MixedInstancesPolicy.InstancesDistribution.OnDemandBaseCapacity, 1)
+	assert.Equal(t, asg.MixedInstancesPolicy.InstancesDistribution.OnDemandPercentageAboveBaseCapacity, 25)
+
+	cluster := template.Resources["Cluster"].(*ecs.Cluster)
+	assert.Equal(t, cluster.DefaultCapacityProviderStrategy[0].CapacityProvider, cloudformation.Ref("CapacityProvider"))
+	service := template.Resources["TestService"].(*ecs.Service)
+	assert.Equal(t, service.LaunchType, "")
+	def := template.Resources["TestTaskDefinition"].(*ecs.TaskDefinition)
+	assert.DeepEqual(t, def.RequiresCompatibilities, []string{"EC2"})
+	assert.Equal(t, def.Memory, "12288")
+}
+
+func TestEC2ModeInvalidSize(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  test:
+    image: "image"
+x-aws-ec2:
+  min: 5
+  max: 2
+`)
+	_, err := getEC2Config(project)
+	assert.ErrorContains(t, err, "min (5) and max (2) instances are invalid")
+}
@@ -0,0 +1,70 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/session"
+
+	"github.com/docker/compose-cli/context/store"
+)
+
+const (
+	// allServicesEndpointKey is the endpoints key to override URL for all AWS services, as a single LocalStack edge port does
+	allServicesEndpointKey = "*"
+	// simulatorCredentials are the dummy credentials accepted by local simulators
+	simulatorCredentials = "test"
+)
+
+// endpointsResolver resolves AWS services endpoints using overrides, indexed by service ID, before SDK defaults
+func endpointsResolver(overrides map[string]string) endpoints.Resolver {
+	return endpoints.ResolverFunc(func(service, region string, opts ...func(*endpoints.Options)) (endpoints.ResolvedEndpoint, error) {
+		url, ok := overrides[strings.ToLower(service)]
+		if !ok {
+			url, ok = overrides[allServicesEndpointKey]
+		}
+		if !ok {
+			return endpoints.DefaultResolver().EndpointFor(service, region, opts...)
+		}
+		return endpoints.ResolvedEndpoint{
+			URL:           url,
+			SigningRegion: region,
+		}, nil
+	})
+}
+
+// newEndpointsSession creates an AWS session sending requests to the custom endpoints set by context, typically
+// a local simulator. Dummy credentials are used unless context explicitly sets a profile
+func newEndpointsSession(ecsCtx store.EcsContext) (*session.Session, error) {
+	var creds *credentials.Credentials
+	if ecsCtx.Profile == "" {
+		creds = credentials.NewStaticCredentials(simulatorCredentials, simulatorCredentials, "")
+	}
+	return session.NewSessionWithOptions(session.Options{
+		Profile:           ecsCtx.Profile,
+		SharedConfigState: session.SharedConfigEnable,
+		Config: aws.Config{
+			Region:           aws.String(ecsCtx.Region),
+			Credentials:      creds,
+			EndpointResolver: endpointsResolver(ecsCtx.Endpoints),
+		},
+	})
+}
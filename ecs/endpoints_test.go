@@ -0,0 +1,45 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"gotest.tools/v3/assert"
+)
+
+func TestEndpointsResolver(t *testing.T) {
+	resolver := endpointsResolver(map[string]string{
+		"ecs": "http://localhost:51679",
+	})
+	resolved, err := resolver.EndpointFor(endpoints.EcsServiceID, "us-east-1")
+	assert.NilError(t, err)
+	assert.Equal(t, resolved.URL, "http://localhost:51679")
+	assert.Equal(t, resolved.SigningRegion, "us-east-1")
+
+	resolved, err = resolver.EndpointFor(endpoints.CloudformationServiceID, "us-east-1")
+	assert.NilError(t, err)
+	assert.Equal(t, resolved.URL, "https://cloudformation.us-east-1.amazonaws.com")
+
+	resolver = endpointsResolver(map[string]string{
+		allServicesEndpointKey: "http://localhost:4566",
+	})
+	resolved, err = resolver.EndpointFor(endpoints.CloudformationServiceID, "us-east-1")
+	assert.NilError(t, err)
+	assert.Equal(t, resolved.URL, "http://localhost:4566")
+}
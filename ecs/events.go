@@ -0,0 +1,232 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+// eventsPollInterval is the delay between two polls of ECS services and tasks state
+var eventsPollInterval = 5 * time.Second
+
+// serviceEventActions maps ECS service event messages to an event action, first match wins
+var serviceEventActions = []struct {
+	pattern string
+	action  string
+}{
+	{"has reached a steady state", "steady"},
+	{"was unable to place a task", "placement-failure"},
+	{"failed container health checks", "health-failure"},
+	{"is unhealthy in", "health-failure"},
+	{"deployment failed", "deployment-failure"},
+	{"has started", "scale-up"},
+	{"has stopped", "scale-down"},
+	{"registered", "register"},
+	{"has begun draining", "drain"},
+}
+
+func (b *ecsAPIService) Events(ctx context.Context, project string, consumer func(compose.Event)) error {
+	cluster, err := b.aws.GetStackClusterID(ctx, project)
+	if err != nil {
+		return err
+	}
+	w := newEventsWatcher(b.aws, cluster, consumer)
+	for {
+		err := w.poll(ctx, project)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(eventsPollInterval):
+		}
+	}
+}
+
+type taskState struct {
+	status string
+	health string
+}
+
+// eventsWatcher turns changes observed between two polls of ECS services into events
+type eventsWatcher struct {
+	aws      API
+	cluster  string
+	consumer func(compose.Event)
+	since    time.Time
+	// services maps ECS service ARNs to the date of their latest event sent
+	services map[string]time.Time
+	tasks    map[string]taskState
+	// started is set once a first poll recorded the initial state of tasks
+	started bool
+}
+
+func newEventsWatcher(api API, cluster string, consumer func(compose.Event)) *eventsWatcher {
+	return &eventsWatcher{
+		aws:      api,
+		cluster:  cluster,
+		consumer: consumer,
+		since:    time.Now(),
+		services: map[string]time.Time{},
+		tasks:    map[string]taskState{},
+	}
+}
+
+func (w *eventsWatcher) poll(ctx context.Context, project string) error {
+	arns, err := w.aws.ListStackServices(ctx, project)
+	if err != nil {
+		return err
+	}
+	// services are fetched concurrently, then consumed in order so that watcher state isn't shared
+	polled := make([]servicePoll, len(arns))
+	err = concurrently(ctx, len(arns), func(ctx context.Context, i int) error {
+		p, err := w.fetch(ctx, arns[i])
+		polled[i] = p
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	for i, p := range polled {
+		w.serviceEvents(arns[i], p.name, p.events)
+		for _, task := range p.tasks {
+			w.taskEvents(p.name, task)
+		}
+	}
+	w.started = true
+	return nil
+}
+
+// servicePoll holds the events and tasks of a service fetched by a poll
+type servicePoll struct {
+	name   string
+	events []*ecs.ServiceEvent
+	tasks  []*ecs.Task
+}
+
+func (w *eventsWatcher) fetch(ctx context.Context, arn string) (servicePoll, error) {
+	name, events, err := w.aws.GetServiceEvents(ctx, w.cluster, arn)
+	if err != nil {
+		return servicePoll{}, err
+	}
+	p := servicePoll{name: name, events: events}
+	for _, stopped := range []bool{false, true} {
+		tasks, err := w.aws.GetServiceTasks(ctx, w.cluster, lastSegment(arn), stopped)
+		if err != nil {
+			return servicePoll{}, err
+		}
+		p.tasks = append(p.tasks, tasks...)
+	}
+	return p, nil
+}
+
+func (w *eventsWatcher) serviceEvents(arn string, name string, events []*ecs.ServiceEvent) {
+	latest, ok := w.services[arn]
+	if !ok {
+		latest = w.since
+	}
+	// ECS returns service events newest first
+	for i := len(events) - 1; i >= 0; i-- {
+		event := events[i]
+		created := aws.TimeValue(event.CreatedAt)
+		if !created.After(latest) {
+			continue
+		}
+		message := aws.StringValue(event.Message)
+		w.consumer(compose.Event{
+			Timestamp: created,
+			Type:      "service",
+			Action:    serviceEventAction(message),
+			ID:        lastSegment(arn),
+			Service:   name,
+			Attributes: map[string]string{
+				"name":    name,
+				"message": message,
+			},
+		})
+		latest = created
+	}
+	w.services[arn] = latest
+}
+
+func serviceEventAction(message string) string {
+	for _, a := range serviceEventActions {
+		if strings.Contains(message, a.pattern) {
+			return a.action
+		}
+	}
+	return "update"
+}
+
+func (w *eventsWatcher) taskEvents(service string, task *ecs.Task) {
+	arn := aws.StringValue(task.TaskArn)
+	state := taskState{
+		status: aws.StringValue(task.LastStatus),
+		health: aws.StringValue(task.HealthStatus),
+	}
+	previous, known := w.tasks[arn]
+	w.tasks[arn] = state
+	if !w.started || state == previous {
+		return
+	}
+	attributes := map[string]string{
+		"name": service,
+	}
+	if group := aws.StringValue(task.Group); group != "" {
+		attributes["group"] = group
+	}
+	if !known || state.status != previous.status {
+		action := strings.ToLower(state.status)
+		timestamp := time.Now()
+		switch state.status {
+		case ecs.DesiredStatusRunning:
+			timestamp = aws.TimeValue(task.StartedAt)
+		case ecs.DesiredStatusStopped:
+			timestamp = aws.TimeValue(task.StoppedAt)
+			attributes["reason"] = aws.StringValue(task.StoppedReason)
+		}
+		if timestamp.IsZero() {
+			timestamp = time.Now()
+		}
+		w.emitTask(service, arn, action, timestamp, attributes)
+	}
+	if known && state.health != previous.health && state.health != ecs.HealthStatusUnknown {
+		w.emitTask(service, arn, "health_status: "+strings.ToLower(state.health), time.Now(), attributes)
+	}
+}
+
+func (w *eventsWatcher) emitTask(service, arn, action string, timestamp time.Time, attributes map[string]string) {
+	w.consumer(compose.Event{
+		Timestamp:  timestamp,
+		Type:       "container",
+		Action:     action,
+		ID:         lastSegment(arn),
+		Service:    service,
+		Attributes: attributes,
+	})
+}
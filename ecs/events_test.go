@@ -0,0 +1,94 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func TestServiceEventAction(t *testing.T) {
+	assert.Equal(t, serviceEventAction("(service web) has reached a steady state."), "steady")
+	assert.Equal(t, serviceEventAction("(service web) was unable to place a task because no container instance met all of its requirements."), "placement-failure")
+	assert.Equal(t, serviceEventAction("(service web) (task 0123) failed container health checks."), "health-failure")
+	assert.Equal(t, serviceEventAction("(service web) has started 1 tasks: (task 0123)."), "scale-up")
+	assert.Equal(t, serviceEventAction("(service web) updated computedDesiredCount for taskSet ecs-svc/123 to 2."), "update")
+}
+
+func TestEventsWatcher(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+
+	arn := "arn:aws:ecs:eu-west-3:123456789012:service/demo/web"
+	start := time.Now()
+	task := func(id, status, health string) *ecs.Task {
+		return &ecs.Task{
+			TaskArn:       aws.String("arn:aws:ecs:eu-west-3:123456789012:task/demo/" + id),
+			LastStatus:    aws.String(status),
+			HealthStatus:  aws.String(health),
+			StoppedReason: aws.String("Task failed ELB health checks"),
+		}
+	}
+
+	m.EXPECT().ListStackServices(gomock.Any(), "demo").Return([]string{arn}, nil).Times(2)
+	gomock.InOrder(
+		m.EXPECT().GetServiceEvents(gomock.Any(), "cluster", arn).Return("web", []*ecs.ServiceEvent{
+			{CreatedAt: aws.Time(start.Add(-time.Minute)), Message: aws.String("(service web) has reached a steady state.")},
+		}, nil),
+		m.EXPECT().GetServiceEvents(gomock.Any(), "cluster", arn).Return("web", []*ecs.ServiceEvent{
+			{CreatedAt: aws.Time(start.Add(time.Minute)), Message: aws.String("(service web) was unable to place a task.")},
+			{CreatedAt: aws.Time(start.Add(-time.Minute)), Message: aws.String("(service web) has reached a steady state.")},
+		}, nil),
+	)
+	gomock.InOrder(
+		m.EXPECT().GetServiceTasks(gomock.Any(), "cluster", "web", false).Return([]*ecs.Task{task("1", "RUNNING", "HEALTHY")}, nil),
+		m.EXPECT().GetServiceTasks(gomock.Any(), "cluster", "web", true).Return(nil, nil),
+		m.EXPECT().GetServiceTasks(gomock.Any(), "cluster", "web", false).Return([]*ecs.Task{task("2", "PENDING", "UNKNOWN")}, nil),
+		m.EXPECT().GetServiceTasks(gomock.Any(), "cluster", "web", true).Return([]*ecs.Task{task("1", "STOPPED", "UNHEALTHY")}, nil),
+	)
+
+	var events []compose.Event
+	w := newEventsWatcher(m, "cluster", func(event compose.Event) {
+		events = append(events, event)
+	})
+	w.since = start
+
+	assert.NilError(t, w.poll(context.TODO(), "demo"))
+	assert.Equal(t, len(events), 0)
+
+	assert.NilError(t, w.poll(context.TODO(), "demo"))
+	var actions []string
+	for _, e := range events {
+		actions = append(actions, e.Type+" "+e.Action+" "+e.ID)
+	}
+	assert.DeepEqual(t, actions, []string{
+		"service placement-failure web",
+		"container pending 2",
+		"container stopped 1",
+		"container health_status: unhealthy 1",
+	})
+	assert.Equal(t, events[2].Attributes["reason"], "Task failed ELB health checks")
+}
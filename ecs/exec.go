@@ -0,0 +1,174 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+const sessionManagerPlugin = "session-manager-plugin"
+
+// execSession is an SSM session opened by ECS Exec, passed to session manager plugin as AWS CLI does
+type execSession struct {
+	SessionID  string `json:"sessionId"`
+	StreamURL  string `json:"streamUrl"`
+	TokenValue string `json:"tokenValue"`
+	Region     string `json:"-"`
+	Endpoint   string `json:"-"`
+}
+
+// execEnabled tells if x-aws-exec enables ECS Exec on service, so that docker compose exec can run commands in its
+// containers
+func execEnabled(service types.ServiceConfig) bool {
+	v, ok := service.Extensions[extensionExec]
+	if !ok {
+		return false
+	}
+	enabled, ok := v.(bool)
+	return ok && enabled
+}
+
+func (b *ecsAPIService) Exec(ctx context.Context, projectName string, opts compose.ExecOptions) error {
+	if !isTerminal(os.Stdin) {
+		return errors.Wrap(errdefs.ErrNotImplemented, "ECS Exec only supports interactive commands, run from a terminal")
+	}
+	plugin, err := lookupSessionManagerPlugin()
+	if err != nil {
+		return err
+	}
+	args, err := b.execSessionArgs(ctx, projectName, opts)
+	if err != nil {
+		return err
+	}
+
+	// session manager plugin switches terminal to raw mode and forwards window size changes to the session
+	cmd := exec.Command(plugin, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func lookupSessionManagerPlugin() (string, error) {
+	plugin, err := exec.LookPath(sessionManagerPlugin)
+	if err != nil {
+		return "", fmt.Errorf("%s is required to execute commands in ECS containers, see https://docs.aws.amazon.com/systems-manager/latest/userguide/session-manager-working-with-install-plugin.html", sessionManagerPlugin)
+	}
+	return plugin, nil
+}
+
+// execSessionArgs opens an ECS Exec session in a running container of service and returns the session manager
+// plugin arguments to attach to it
+func (b *ecsAPIService) execSessionArgs(ctx context.Context, projectName string, opts compose.ExecOptions) ([]string, error) {
+	cluster, task, err := b.serviceTask(ctx, projectName, opts.Service, opts.Index)
+	if err != nil {
+		return nil, err
+	}
+	command := strings.Join(opts.Command, " ")
+	if command == "" {
+		command = "/bin/sh"
+	}
+	return b.taskSessionArgs(ctx, cluster, task, opts.Service, command)
+}
+
+// serviceTask returns the cluster and running task of service selected by index, starting at 1
+func (b *ecsAPIService) serviceTask(ctx context.Context, projectName string, name string, index int) (string, *ecs.Task, error) {
+	cluster, err := b.aws.GetStackClusterID(ctx, projectName)
+	if err != nil {
+		return "", nil, err
+	}
+	resources, err := b.aws.ListStackResources(ctx, projectName)
+	if err != nil {
+		return "", nil, err
+	}
+	var service string
+	for _, r := range resources {
+		if r.LogicalID == serviceResourceName(name) {
+			service = r.ARN
+		}
+	}
+	if service == "" {
+		return "", nil, errors.Wrapf(errdefs.ErrNotFound, "service %q isn't running in %s", name, projectName)
+	}
+	tasks, err := b.aws.GetServiceTasks(ctx, cluster, service, false)
+	if err != nil {
+		return "", nil, err
+	}
+	if index == 0 {
+		index = 1
+	}
+	if index < 1 || index > len(tasks) {
+		return "", nil, errors.Wrapf(errdefs.ErrNotFound, "service %q has %d running tasks, can't select container %d", name, len(tasks), index)
+	}
+	return cluster, tasks[index-1], nil
+}
+
+// taskSessionArgs opens an ECS Exec session running command in container of task and returns the session manager
+// plugin arguments to attach to it
+func (b *ecsAPIService) taskSessionArgs(ctx context.Context, cluster string, task *ecs.Task, container string, command string) ([]string, error) {
+	target, err := sessionTarget(cluster, task, container)
+	if err != nil {
+		return nil, err
+	}
+	session, err := b.aws.ExecuteCommand(ctx, cluster, aws.StringValue(task.TaskArn), container, command)
+	if err != nil {
+		return nil, errors.Wrapf(err, "can't execute command in service %q, check %s is enabled", container, extensionExec)
+	}
+	return b.sessionManagerArgs(session, map[string]interface{}{
+		"Target": target,
+	})
+}
+
+// sessionTarget returns the SSM target of container in task, as managed by ECS Exec
+func sessionTarget(cluster string, task *ecs.Task, container string) (string, error) {
+	var runtimeID string
+	for _, c := range task.Containers {
+		if aws.StringValue(c.Name) == container {
+			runtimeID = aws.StringValue(c.RuntimeId)
+		}
+	}
+	if runtimeID == "" {
+		return "", fmt.Errorf("container %s isn't running in task %s", container, aws.StringValue(task.TaskArn))
+	}
+	return fmt.Sprintf("ecs:%s_%s_%s", lastSegment(cluster), lastSegment(aws.StringValue(task.TaskArn)), runtimeID), nil
+}
+
+// sessionManagerArgs returns the session manager plugin arguments to attach to session, started by request
+func (b *ecsAPIService) sessionManagerArgs(session execSession, request map[string]interface{}) ([]string, error) {
+	sessionJSON, err := json.Marshal(session)
+	if err != nil {
+		return nil, err
+	}
+	requestJSON, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	return []string{string(sessionJSON), session.Region, "StartSession", b.ctx.Profile, string(requestJSON), session.Endpoint}, nil
+}
@@ -0,0 +1,141 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/ecs/ecsiface"
+	"github.com/awslabs/goformation/v4/cloudformation/iam"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func TestExecEnabled(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  foo:
+    image: hello_world
+    x-aws-exec: true
+  bar:
+    image: hello_world
+`, useDefaultVPC)
+	role := template.Resources["FooTaskRole"].(*iam.Role)
+	assert.Equal(t, len(role.Policies), 1)
+	assert.DeepEqual(t, role.Policies[0].PolicyDocument, execPolicyDocument)
+	_, ok := template.Resources["BarTaskRole"]
+	assert.Check(t, !ok)
+
+	marshalled, err := marshall(template)
+	assert.NilError(t, err)
+	var parsed struct {
+		Resources map[string]struct {
+			Properties map[string]interface{}
+		}
+	}
+	assert.NilError(t, json.Unmarshal(marshalled, &parsed))
+	assert.Equal(t, parsed.Resources["FooService"].Properties["EnableExecuteCommand"], true)
+	_, ok = parsed.Resources["BarService"].Properties["EnableExecuteCommand"]
+	assert.Check(t, !ok)
+}
+
+func TestExecSessionArgs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+
+	task := "arn:aws:ecs:us-east-1:012345678910:task/cluster/0123456789abcdef"
+	m.EXPECT().GetStackClusterID(gomock.Any(), "test").Return("arn:aws:ecs:us-east-1:012345678910:cluster/cluster", nil)
+	m.EXPECT().ListStackResources(gomock.Any(), "test").Return(stackResources{
+		{LogicalID: "FooService", Type: awsTypeService, ARN: "arn:aws:ecs:us-east-1:012345678910:service/cluster/foo"},
+	}, nil)
+	m.EXPECT().GetServiceTasks(gomock.Any(), gomock.Any(), "arn:aws:ecs:us-east-1:012345678910:service/cluster/foo", false).Return([]*ecs.Task{
+		{
+			TaskArn: aws.String(task),
+			Containers: []*ecs.Container{
+				{Name: aws.String("foo_LogRouter"), RuntimeId: aws.String("router")},
+				{Name: aws.String("foo"), RuntimeId: aws.String("0123456789abcdef-1234")},
+			},
+		},
+	}, nil)
+	m.EXPECT().ExecuteCommand(gomock.Any(), gomock.Any(), task, "foo", "ls -l").Return(execSession{
+		SessionID:  "session",
+		StreamURL:  "wss://ssmmessages.us-east-1.amazonaws.com/v1/data-channel/session",
+		TokenValue: "token",
+		Region:     "us-east-1",
+		Endpoint:   "https://ssm.us-east-1.amazonaws.com",
+	}, nil)
+
+	backend := &ecsAPIService{aws: m}
+	args, err := backend.execSessionArgs(context.TODO(), "test", compose.ExecOptions{
+		Service: "foo",
+		Command: []string{"ls", "-l"},
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, args, []string{
+		`{"sessionId":"session","streamUrl":"wss://ssmmessages.us-east-1.amazonaws.com/v1/data-channel/session","tokenValue":"token"}`,
+		"us-east-1",
+		"StartSession",
+		"",
+		`{"Target":"ecs:cluster_0123456789abcdef_0123456789abcdef-1234"}`,
+		"https://ssm.us-east-1.amazonaws.com",
+	})
+}
+
+type executeCommandClient struct {
+	ecsiface.ECSAPI
+	input *ecs.ExecuteCommandInput
+}
+
+func (c *executeCommandClient) ExecuteCommandWithContext(ctx aws.Context, input *ecs.ExecuteCommandInput, opts ...request.Option) (*ecs.ExecuteCommandOutput, error) {
+	c.input = input
+	return &ecs.ExecuteCommandOutput{
+		Session: &ecs.Session{
+			SessionId:  aws.String("session"),
+			StreamUrl:  aws.String("wss://ssmmessages.eu-west-3.amazonaws.com/v1/data-channel/session"),
+			TokenValue: aws.String("token"),
+		},
+	}, nil
+}
+
+func TestExecuteCommand(t *testing.T) {
+	client := &executeCommandClient{}
+	s := sdk{ECS: client, region: "eu-west-3"}
+	session, err := s.ExecuteCommand(context.TODO(), "cluster", "task", "foo", "ls -l")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, client.input, &ecs.ExecuteCommandInput{
+		Cluster:     aws.String("cluster"),
+		Command:     aws.String("ls -l"),
+		Container:   aws.String("foo"),
+		Interactive: aws.Bool(true),
+		Task:        aws.String("task"),
+	})
+	assert.DeepEqual(t, session, execSession{
+		SessionID:  "session",
+		StreamURL:  "wss://ssmmessages.eu-west-3.amazonaws.com/v1/data-channel/session",
+		TokenValue: "token",
+		Region:     "eu-west-3",
+		Endpoint:   "https://ssm.eu-west-3.amazonaws.com",
+	})
+}
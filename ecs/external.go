@@ -0,0 +1,107 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"fmt"
+
+	ecsapi "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/sirupsen/logrus"
+)
+
+// launchTypeExternal runs tasks on external instances registered to the cluster with ECS Anywhere
+const launchTypeExternal = "EXTERNAL"
+
+// useExternal tells if x-aws-external runs services on external instances rather than on AWS infrastructure
+func useExternal(project *types.Project) bool {
+	v, ok := project.Extensions[extensionExternal]
+	if !ok {
+		return false
+	}
+	enabled, ok := v.(bool)
+	return ok && enabled
+}
+
+// externalConflicts are extensions relying on AWS infrastructure external instances don't run in
+var externalConflicts = []string{
+	extensionEC2,
+	extensionSpot,
+	extensionLoadBalancer,
+	extensionBalancerType,
+	extensionBlueGreen,
+	extensionDNS,
+	extensionWAF,
+	extensionIPv6,
+	extensionVPCEndpoints,
+	extensionAppMesh,
+	extensionServiceConnect,
+	extensionStorage,
+	extensionSchedule,
+}
+
+// checkExternal makes sure project can run on instances registered to an existing cluster with ECS Anywhere
+func checkExternal(project *types.Project, resources awsResources) error {
+	if !useExternal(project) {
+		return nil
+	}
+	if resources.cluster == nil {
+		return fmt.Errorf("%s requires %s to be set to the cluster external instances are registered to", extensionExternal, extensionCluster)
+	}
+	for _, x := range externalConflicts {
+		if _, ok := project.Extensions[x]; ok {
+			return fmt.Errorf("%s can't be used with %s", x, extensionExternal)
+		}
+	}
+	if len(project.Volumes) > 0 {
+		return fmt.Errorf("%s doesn't support volumes, as EFS file systems can't be mounted by external instances", extensionExternal)
+	}
+	for _, service := range project.Services {
+		for _, x := range externalConflicts {
+			if _, ok := service.Extensions[x]; ok {
+				return fmt.Errorf("service %q: %s can't be used with %s", service.Name, x, extensionExternal)
+			}
+		}
+		switch service.NetworkMode {
+		case ecsapi.NetworkModeHost:
+			for _, port := range service.Ports {
+				if port.Published != port.Target {
+					return fmt.Errorf("service %q: published port can't be set to a distinct value than container port with host network mode", service.Name)
+				}
+			}
+		case "", ecsapi.NetworkModeBridge, ecsapi.NetworkModeNone:
+		default:
+			return fmt.Errorf("service %q: network_mode must be bridge, host or none on external instances", service.Name)
+		}
+	}
+	return nil
+}
+
+// taskNetworkMode returns the network mode of service tasks. Fargate tasks have their own network interface, while
+// containers on external instances use the bridge network by default, or the host one
+func taskNetworkMode(project *types.Project, service types.ServiceConfig) string {
+	if !useExternal(project) {
+		if service.NetworkMode != "" && service.NetworkMode != ecsapi.NetworkModeNone {
+			logrus.Warnf("service %q: network_mode %s is ignored, tasks use %s network mode", service.Name, service.NetworkMode, ecsapi.NetworkModeAwsvpc)
+		}
+		return ecsapi.NetworkModeAwsvpc
+	}
+	if service.NetworkMode == "" {
+		return ecsapi.NetworkModeBridge
+	}
+	return service.NetworkMode
+}
@@ -0,0 +1,132 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func useSharedCluster(m *MockAPIMockRecorder) {
+	m.ResolveCluster(gomock.Any(), "shared").Return(existingAWSResource{
+		arn: "arn:aws:ecs:region:account:cluster/shared",
+		id:  "shared",
+	}, nil)
+}
+
+func TestExternal(t *testing.T) {
+	template := convertYaml(t, `
+x-aws-cluster: shared
+x-aws-external: true
+services:
+  web:
+    image: nginx
+    ports:
+      - 8080:80
+  agent:
+    image: agent
+    network_mode: host
+    ports:
+      - 9100:9100
+`, useDefaultVPC, useSharedCluster)
+	_, ok := template.Resources["LoadBalancer"]
+	assert.Check(t, !ok)
+	_, ok = template.Resources["CloudMap"]
+	assert.Check(t, !ok)
+
+	s := template.Resources["WebService"].(*ecs.Service)
+	assert.Equal(t, s.LaunchType, "EXTERNAL")
+	assert.Equal(t, s.PlatformVersion, "")
+	assert.Check(t, s.NetworkConfiguration == nil)
+	assert.Equal(t, len(s.LoadBalancers), 0)
+	assert.Equal(t, len(s.ServiceRegistries), 0)
+
+	def := template.Resources["WebTaskDefinition"].(*ecs.TaskDefinition)
+	assert.Equal(t, def.NetworkMode, "bridge")
+	assert.DeepEqual(t, def.RequiresCompatibilities, []string{"EXTERNAL"})
+	container := getMainContainer(def, t)
+	assert.Equal(t, container.PortMappings[0].ContainerPort, 80)
+	assert.Equal(t, container.PortMappings[0].HostPort, 8080)
+
+	def = template.Resources["AgentTaskDefinition"].(*ecs.TaskDefinition)
+	assert.Equal(t, def.NetworkMode, "host")
+}
+
+func TestExternalErrors(t *testing.T) {
+	tests := []struct {
+		yaml string
+		err  string
+	}{
+		{
+			yaml: `
+x-aws-external: true
+services:
+  web:
+    image: nginx
+`,
+			err: "x-aws-external requires x-aws-cluster to be set to the cluster external instances are registered to",
+		},
+		{
+			yaml: `
+x-aws-cluster: shared
+x-aws-external: true
+x-aws-spot: {}
+services:
+  web:
+    image: nginx
+`,
+			err: "x-aws-spot can't be used with x-aws-external",
+		},
+		{
+			yaml: `
+x-aws-cluster: shared
+x-aws-external: true
+services:
+  web:
+    image: nginx
+    volumes:
+      - data:/data
+volumes:
+  data: {}
+`,
+			err: "x-aws-external doesn't support volumes, as EFS file systems can't be mounted by external instances",
+		},
+		{
+			yaml: `
+x-aws-cluster: shared
+x-aws-external: true
+services:
+  web:
+    image: nginx
+    network_mode: host
+    ports:
+      - 8080:80
+`,
+			err: `service "web": published port can't be set to a distinct value than container port with host network mode`,
+		},
+	}
+	for _, test := range tests {
+		err := convertYamlError(t, test.yaml, useDefaultVPC, func(m *MockAPIMockRecorder) {
+			m.ResolveCluster(gomock.Any(), "shared").Return(existingAWSResource{id: "shared"}, nil).AnyTimes()
+			m.ListFileSystems(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+		})
+		assert.Error(t, err, test.err)
+	}
+}
@@ -0,0 +1,361 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/progress"
+)
+
+// containerUpdate holds the container settings a fast update can change without a stack update
+type containerUpdate struct {
+	Image       string
+	Environment []*ecs.KeyValuePair
+}
+
+// fastUpdate deploys a change limited to images or environment of a single service by registering a new revision of
+// its task definition and updating the ECS service directly, which takes seconds when a stack update takes minutes.
+// It returns false when stack has to be updated.
+func (b *ecsAPIService) fastUpdate(ctx context.Context, project *types.Project, template *cloudformation.Template, detach bool) (bool, error) {
+	if isDryRun(ctx) {
+		return false, nil
+	}
+	previous, err := b.aws.GetStackTemplate(ctx, project.Name)
+	if err != nil {
+		return false, err
+	}
+	marshalled, err := marshallTemplate(ctx, project, template, previous)
+	if err != nil {
+		return false, err
+	}
+	taskDefinition, updates, ok := taskDefinitionChanges(previous, marshalled)
+	if !ok {
+		return false, nil
+	}
+	if taskDefinition == "" {
+		// template didn't change, but services may still run a revision registered by a previous fast update, which
+		// an empty change set wouldn't restore
+		return b.restoreTaskDefinitions(ctx, project, detach)
+	}
+	var service types.ServiceConfig
+	for _, s := range project.Services {
+		if taskDefinitionResourceName(s.Name) == taskDefinition {
+			service = s
+		}
+	}
+	if _, blueGreen := service.Extensions[extensionBlueGreen]; service.Name == "" || blueGreen {
+		return false, nil
+	}
+
+	resources, err := b.aws.ListStackResources(ctx, project.Name)
+	if err != nil {
+		return false, err
+	}
+	var arn string
+	for _, r := range resources {
+		if r.Type == awsTypeService && r.LogicalID == serviceResourceName(service.Name) {
+			arn = r.ARN
+		}
+	}
+	if arn == "" {
+		return false, nil
+	}
+	cluster, err := b.aws.GetStackClusterID(ctx, project.Name)
+	if err != nil {
+		return false, err
+	}
+	definitions, err := b.aws.GetServiceTaskDefinition(ctx, cluster, []string{arn})
+	if err != nil {
+		return false, err
+	}
+
+	logrus.Debugf("only %s image or environment changed, updating ECS service without stack update", service.Name)
+	w := progress.ContextWriter(ctx)
+	revision, err := b.aws.RegisterTaskDefinitionRevision(ctx, definitions[arn], updates)
+	if err != nil {
+		return false, err
+	}
+	err = b.aws.UpdateServiceTaskDefinition(ctx, cluster, arn, revision)
+	if err != nil {
+		return false, err
+	}
+	w.Event(progress.Event{
+		ID:         service.Name,
+		Status:     progress.Working,
+		StatusText: fmt.Sprintf("Deploying task definition %s", lastSegment(revision)),
+	})
+	if detach {
+		return true, nil
+	}
+	err = b.waitServicesStable(ctx, cluster, []string{arn})
+	if err != nil {
+		w.Event(progress.Event{
+			ID:         service.Name,
+			Status:     progress.Error,
+			StatusText: "Service didn't reach a steady state",
+		})
+		return true, err
+	}
+	w.Event(progress.Event{
+		ID:         service.Name,
+		Status:     progress.Done,
+		StatusText: fmt.Sprintf("Deployed task definition %s", lastSegment(revision)),
+	})
+	return true, nil
+}
+
+// restoreTaskDefinitions restores services running a task definition registered by a fast update to the one managed
+// by the stack. It returns false when all services already run the stack task definitions.
+func (b *ecsAPIService) restoreTaskDefinitions(ctx context.Context, project *types.Project, detach bool) (bool, error) {
+	restored, err := b.reconcileTaskDefinitions(ctx, project)
+	if err != nil || len(restored) == 0 {
+		return false, err
+	}
+	w := progress.ContextWriter(ctx)
+	for _, name := range sortedKeys(restored) {
+		w.Event(progress.Event{
+			ID:         name,
+			Status:     progress.Working,
+			StatusText: fmt.Sprintf("Restoring task definition %s", lastSegment(restored[name])),
+		})
+	}
+	if detach {
+		return true, nil
+	}
+	cluster, err := b.aws.GetStackClusterID(ctx, project.Name)
+	if err != nil {
+		return true, err
+	}
+	resources, err := b.aws.ListStackResources(ctx, project.Name)
+	if err != nil {
+		return true, err
+	}
+	var arns []string
+	for name := range restored {
+		for _, r := range resources {
+			if r.Type == awsTypeService && r.LogicalID == serviceResourceName(name) {
+				arns = append(arns, r.ARN)
+			}
+		}
+	}
+	sort.Strings(arns)
+	err = b.waitServicesStable(ctx, cluster, arns)
+	if err != nil {
+		return true, err
+	}
+	for _, name := range sortedKeys(restored) {
+		w.Event(progress.Event{
+			ID:         name,
+			Status:     progress.Done,
+			StatusText: fmt.Sprintf("Restored task definition %s", lastSegment(restored[name])),
+		})
+	}
+	return true, nil
+}
+
+// reconcileTaskDefinitions updates ECS services still running a task definition registered by a fast update to the
+// one managed by the stack, as a stack update only updates services when their task definition resource changed.
+// It returns the task definitions restored, by service name.
+func (b *ecsAPIService) reconcileTaskDefinitions(ctx context.Context, project *types.Project) (map[string]string, error) {
+	resources, err := b.aws.ListStackResources(ctx, project.Name)
+	if err != nil {
+		return nil, err
+	}
+	physicalIDs := map[string]string{}
+	for _, r := range resources {
+		physicalIDs[r.LogicalID] = r.ARN
+	}
+	var arns []string
+	for _, service := range project.Services {
+		if _, blueGreen := service.Extensions[extensionBlueGreen]; blueGreen {
+			continue
+		}
+		if arn, ok := physicalIDs[serviceResourceName(service.Name)]; ok {
+			arns = append(arns, arn)
+		}
+	}
+	if len(arns) == 0 {
+		return nil, nil
+	}
+	sort.Strings(arns)
+	cluster, err := b.aws.GetStackClusterID(ctx, project.Name)
+	if err != nil {
+		return nil, err
+	}
+	definitions, err := b.aws.GetServiceTaskDefinition(ctx, cluster, arns)
+	if err != nil {
+		return nil, err
+	}
+	restored := map[string]string{}
+	for _, service := range project.Services {
+		arn := physicalIDs[serviceResourceName(service.Name)]
+		current, ok := definitions[arn]
+		expected := physicalIDs[taskDefinitionResourceName(service.Name)]
+		if !ok || expected == "" || current == expected {
+			continue
+		}
+		logrus.Debugf("service %s runs task definition %s, restoring %s", service.Name, current, expected)
+		err := b.aws.UpdateServiceTaskDefinition(ctx, cluster, arn, expected)
+		if err != nil {
+			return nil, err
+		}
+		restored[service.Name] = expected
+	}
+	return restored, nil
+}
+
+// taskDefinitionChanges compares stack templates and, if they only differ by images or environment of containers of
+// a single task definition, returns its logical ID and updated containers. ok is false when other changes require a
+// stack update.
+func taskDefinitionChanges(previous, next []byte) (taskDefinition string, updates map[string]containerUpdate, ok bool) {
+	var before, after map[string]interface{}
+	if json.Unmarshal(previous, &before) != nil || json.Unmarshal(next, &after) != nil {
+		return "", nil, false
+	}
+	beforeResources, _ := before["Resources"].(map[string]interface{})
+	afterResources, _ := after["Resources"].(map[string]interface{})
+	delete(before, "Resources")
+	delete(after, "Resources")
+	if !reflect.DeepEqual(before, after) || len(beforeResources) != len(afterResources) {
+		return "", nil, false
+	}
+	for name, resource := range afterResources {
+		old, exists := beforeResources[name]
+		if !exists {
+			return "", nil, false
+		}
+		if reflect.DeepEqual(old, resource) {
+			continue
+		}
+		if taskDefinition != "" {
+			// more than one service changed
+			return "", nil, false
+		}
+		containers, ok := containerUpdates(old, resource)
+		if !ok {
+			return "", nil, false
+		}
+		taskDefinition, updates = name, containers
+	}
+	return taskDefinition, updates, true
+}
+
+// containerUpdates returns images and environment of containers of a task definition resource, if it doesn't differ
+// from previous by anything else
+func containerUpdates(previous, resource interface{}) (map[string]containerUpdate, bool) {
+	if hasUnregistrableProperties(resource) {
+		return nil, false
+	}
+	before, ok := containerDefinitions(previous)
+	if !ok {
+		return nil, false
+	}
+	after, ok := containerDefinitions(resource)
+	if !ok {
+		return nil, false
+	}
+	updates := map[string]containerUpdate{}
+	for _, def := range after {
+		name, _ := def["Name"].(string)
+		image, ok := def["Image"].(string)
+		if !ok {
+			return nil, false
+		}
+		update := containerUpdate{Image: image}
+		environment, _ := def["Environment"].([]interface{})
+		for _, e := range environment {
+			kv, _ := e.(map[string]interface{})
+			key, okKey := kv["Name"].(string)
+			value, okValue := kv["Value"].(string)
+			if !okKey || !okValue {
+				return nil, false
+			}
+			update.Environment = append(update.Environment, &ecs.KeyValuePair{Name: aws.String(key), Value: aws.String(value)})
+		}
+		updates[name] = update
+		delete(def, "Image")
+		delete(def, "Environment")
+	}
+	for _, def := range before {
+		delete(def, "Image")
+		delete(def, "Environment")
+	}
+	// image reference digests were pinned from
+	for _, r := range []interface{}{previous, resource} {
+		if metadata, ok := r.(map[string]interface{})["Metadata"].(map[string]interface{}); ok {
+			delete(metadata, imageMetadata)
+		}
+	}
+	return updates, reflect.DeepEqual(previous, resource)
+}
+
+// containerDefinitions returns container definitions of a task definition resource, which containerUpdates modifies
+func containerDefinitions(resource interface{}) ([]map[string]interface{}, bool) {
+	r, ok := resource.(map[string]interface{})
+	if !ok || r["Type"] != "AWS::ECS::TaskDefinition" {
+		return nil, false
+	}
+	properties, _ := r["Properties"].(map[string]interface{})
+	defs, _ := properties["ContainerDefinitions"].([]interface{})
+	var containers []map[string]interface{}
+	for _, d := range defs {
+		def, ok := d.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		containers = append(containers, def)
+	}
+	return containers, true
+}
+
+// unregistrableProperties are task definition properties RegisterTaskDefinition of the AWS SDK doesn't support yet,
+// which a new revision would silently drop
+var unregistrableProperties = []string{"RuntimePlatform", "EphemeralStorage"}
+
+// hasUnregistrableProperties returns true when a task definition resource uses properties set through
+// extraProperties, which only a stack update can deploy
+func hasUnregistrableProperties(resource interface{}) bool {
+	r, _ := resource.(map[string]interface{})
+	properties, _ := r["Properties"].(map[string]interface{})
+	for _, p := range unregistrableProperties {
+		if _, ok := properties[p]; ok {
+			return true
+		}
+	}
+	containers, _ := containerDefinitions(resource)
+	for _, def := range containers {
+		mappings, _ := def["PortMappings"].([]interface{})
+		for _, m := range mappings {
+			// Service Connect port names
+			if mapping, ok := m.(map[string]interface{}); ok && mapping["Name"] != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,162 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+const fastUpdateTemplate = `{
+  "Resources": {
+    "WebTaskDefinition": {
+      "Type": "AWS::ECS::TaskDefinition",
+      "Metadata": {"Image": "%s"},
+      "Properties": {
+        "Cpu": "%s",
+        "ContainerDefinitions": [
+          {"Name": "web", "Image": "%s", "Environment": [{"Name": "DEBUG", "Value": "%s"}]}
+        ]
+      }
+    },
+    "WebService": {"Type": "AWS::ECS::Service", "Properties": {"TaskDefinition": {"Ref": "WebTaskDefinition"}}}
+  }
+}`
+
+func fastUpdateTestTemplate(image, cpu, debug string) []byte {
+	return []byte(fmt.Sprintf(fastUpdateTemplate, image, cpu, image, debug))
+}
+
+func TestTaskDefinitionChanges(t *testing.T) {
+	previous := fastUpdateTestTemplate("nginx:1", "256", "false")
+
+	name, updates, ok := taskDefinitionChanges(previous, fastUpdateTestTemplate("nginx:2", "256", "true"))
+	assert.Check(t, ok)
+	assert.Equal(t, name, "WebTaskDefinition")
+	assert.DeepEqual(t, updates, map[string]containerUpdate{
+		"web": {
+			Image:       "nginx:2",
+			Environment: []*ecs.KeyValuePair{{Name: aws.String("DEBUG"), Value: aws.String("true")}},
+		},
+	})
+
+	name, _, ok = taskDefinitionChanges(previous, previous)
+	assert.Check(t, ok)
+	assert.Equal(t, name, "")
+
+	_, _, ok = taskDefinitionChanges(previous, fastUpdateTestTemplate("nginx:2", "512", "false"))
+	assert.Check(t, !ok)
+}
+
+func TestReconcileTaskDefinitions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+
+	project := loadConfig(t, `
+services:
+  web:
+    image: nginx
+  worker:
+    image: busybox
+`)
+	m.EXPECT().ListStackResources(gomock.Any(), project.Name).Return(stackResources{
+		{LogicalID: "WebService", Type: awsTypeService, ARN: "arn:service/web"},
+		{LogicalID: "WebTaskDefinition", ARN: "arn:task-definition/web:1"},
+		{LogicalID: "WorkerService", Type: awsTypeService, ARN: "arn:service/worker"},
+		{LogicalID: "WorkerTaskDefinition", ARN: "arn:task-definition/worker:3"},
+	}, nil)
+	m.EXPECT().GetStackClusterID(gomock.Any(), project.Name).Return("cluster", nil)
+	m.EXPECT().GetServiceTaskDefinition(gomock.Any(), "cluster", []string{"arn:service/web", "arn:service/worker"}).Return(map[string]string{
+		"arn:service/web":    "arn:task-definition/web:2",
+		"arn:service/worker": "arn:task-definition/worker:3",
+	}, nil)
+	m.EXPECT().UpdateServiceTaskDefinition(gomock.Any(), "cluster", "arn:service/web", "arn:task-definition/web:1").Return(nil)
+
+	backend := &ecsAPIService{aws: m}
+	restored, err := backend.reconcileTaskDefinitions(context.TODO(), project)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, restored, map[string]string{"web": "arn:task-definition/web:1"})
+}
+
+func TestTaskDefinitionChangesUnregistrableProperties(t *testing.T) {
+	withProperty := func(image, property string) []byte {
+		return []byte(fmt.Sprintf(`{
+  "Resources": {
+    "WebTaskDefinition": {
+      "Type": "AWS::ECS::TaskDefinition",
+      "Properties": {
+        %s,
+        "ContainerDefinitions": [{"Name": "web", "Image": "%s"}]
+      }
+    }
+  }
+}`, property, image))
+	}
+	for _, property := range []string{
+		`"RuntimePlatform": {"CpuArchitecture": "ARM64"}`,
+		`"EphemeralStorage": {"SizeInGiB": 50}`,
+	} {
+		_, _, ok := taskDefinitionChanges(withProperty("nginx:1", property), withProperty("nginx:2", property))
+		assert.Check(t, !ok, property)
+	}
+
+	portName := `{
+  "Resources": {
+    "WebTaskDefinition": {
+      "Type": "AWS::ECS::TaskDefinition",
+      "Properties": {
+        "ContainerDefinitions": [{"Name": "web", "Image": "%s", "PortMappings": [{"ContainerPort": 80, "Name": "web-80"}]}]
+      }
+    }
+  }
+}`
+	_, _, ok := taskDefinitionChanges([]byte(fmt.Sprintf(portName, "nginx:1")), []byte(fmt.Sprintf(portName, "nginx:2")))
+	assert.Check(t, !ok)
+}
+
+func TestFastUpdateRestoresStackTaskDefinition(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+
+	project := loadConfig(t, `
+services:
+  web:
+    image: nginx
+`)
+	m.EXPECT().ListStackResources(gomock.Any(), project.Name).Return(stackResources{
+		{LogicalID: "WebService", Type: awsTypeService, ARN: "arn:service/web"},
+		{LogicalID: "WebTaskDefinition", ARN: "arn:task-definition/web:1"},
+	}, nil)
+	m.EXPECT().GetStackClusterID(gomock.Any(), project.Name).Return("cluster", nil)
+	m.EXPECT().GetServiceTaskDefinition(gomock.Any(), "cluster", []string{"arn:service/web"}).Return(map[string]string{
+		"arn:service/web": "arn:task-definition/web:2",
+	}, nil)
+	m.EXPECT().UpdateServiceTaskDefinition(gomock.Any(), "cluster", "arn:service/web", "arn:task-definition/web:1").Return(nil)
+
+	backend := &ecsAPIService{aws: m}
+	restored, err := backend.restoreTaskDefinitions(context.TODO(), project, true)
+	assert.NilError(t, err)
+	assert.Check(t, restored)
+}
@@ -0,0 +1,60 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"fmt"
+
+	ecsapi "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"github.com/compose-spec/compose-go/types"
+)
+
+const (
+	logDriverFirelens   = "awsfirelens"
+	firelensRouterImage = "amazon/aws-for-fluent-bit:stable"
+)
+
+func useFirelens(service types.ServiceConfig) bool {
+	return service.Logging != nil && service.Logging.Driver == logDriverFirelens
+}
+
+// createLogRouter creates the Fluent Bit container FireLens routes service logs to. Router's own logs are sent to
+// CloudWatch with logConfiguration.
+func createLogRouter(service types.ServiceConfig, logConfiguration *ecs.TaskDefinition_LogConfiguration) ecs.TaskDefinition_ContainerDefinition {
+	return ecs.TaskDefinition_ContainerDefinition{
+		Name:      fmt.Sprintf("%s_LogRouter", normalizeResourceName(service.Name)),
+		Image:     firelensRouterImage,
+		Essential: true,
+		FirelensConfiguration: &ecs.TaskDefinition_FirelensConfiguration{
+			Type: ecsapi.FirelensConfigurationTypeFluentbit,
+			Options: map[string]string{
+				"enable-ecs-log-metadata": "true",
+			},
+		},
+		LogConfiguration:  logConfiguration,
+		MemoryReservation: 50,
+	}
+}
+
+// getFirelensLogConfiguration passes logging options to Fluent Bit as output plugin configuration
+func getFirelensLogConfiguration(service types.ServiceConfig) *ecs.TaskDefinition_LogConfiguration {
+	return &ecs.TaskDefinition_LogConfiguration{
+		LogDriver: ecsapi.LogDriverAwsfirelens,
+		Options:   service.Logging.Options,
+	}
+}
@@ -0,0 +1,56 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"gotest.tools/v3/assert"
+)
+
+func TestFirelensLogging(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  foo:
+    image: hello_world
+    logging:
+      driver: awsfirelens
+      options:
+        Name: es
+        Host: search.example.com
+        Port: "443"
+`, useDefaultVPC)
+	def := template.Resources["FooTaskDefinition"].(*ecs.TaskDefinition)
+	var router, main ecs.TaskDefinition_ContainerDefinition
+	for _, c := range def.ContainerDefinitions {
+		switch c.Name {
+		case "Foo_LogRouter":
+			router = c
+		case "foo":
+			main = c
+		}
+	}
+	assert.Equal(t, router.FirelensConfiguration.Type, "fluentbit")
+	assert.Equal(t, router.LogConfiguration.LogDriver, "awslogs")
+	assert.Equal(t, main.LogConfiguration.LogDriver, "awsfirelens")
+	assert.DeepEqual(t, main.LogConfiguration.Options, map[string]string{"Name": "es", "Host": "search.example.com", "Port": "443"})
+	assert.DeepEqual(t, main.DependsOnProp[len(main.DependsOnProp)-1], ecs.TaskDefinition_ContainerDependency{
+		Condition:     "START",
+		ContainerName: "Foo_LogRouter",
+	})
+}
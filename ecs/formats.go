@@ -0,0 +1,168 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/sanathkr/go-yaml"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// Formats supported by Convert
+const (
+	FormatJSON      = "json"
+	FormatYAML      = "yaml"
+	FormatCDK       = "cdk"
+	FormatCDKGo     = "cdk-go"
+	FormatTerraform = "terraform"
+)
+
+// rawTemplate is the marshalled CloudFormation template other formats are rendered from
+type rawTemplate struct {
+	Parameters map[string]rawParameter
+	Conditions map[string]interface{}
+	Mappings   map[string]interface{}
+	Transform  interface{}
+	Resources  map[string]rawResource
+	Outputs    map[string]rawOutput
+}
+
+type rawParameter map[string]interface{}
+
+type rawResource struct {
+	Type                string
+	Properties          map[string]interface{}
+	DependsOn           []string
+	Condition           string
+	DeletionPolicy      string
+	UpdateReplacePolicy string
+	CreationPolicy      interface{}
+	UpdatePolicy        interface{}
+}
+
+type rawOutput struct {
+	Description string
+	Value       interface{}
+	Export      interface{}
+	Condition   string
+}
+
+// checkSupported rejects the template sections and resource attributes CDK and Terraform outputs don't render
+func (t rawTemplate) checkSupported(format string) error {
+	unsupported := func(construct string) error {
+		return unsupportedConstruct(construct, format)
+	}
+	if len(t.Conditions) > 0 {
+		return unsupported("Conditions section")
+	}
+	if len(t.Mappings) > 0 {
+		return unsupported("Mappings section")
+	}
+	if t.Transform != nil {
+		return unsupported("Transform section")
+	}
+	for _, id := range t.logicalIDs() {
+		resource := t.Resources[id]
+		switch {
+		case resource.Condition != "":
+			return errors.Wrapf(unsupported("Condition"), "resource %s", id)
+		case resource.CreationPolicy != nil:
+			return errors.Wrapf(unsupported("CreationPolicy"), "resource %s", id)
+		case resource.UpdatePolicy != nil:
+			return errors.Wrapf(unsupported("UpdatePolicy"), "resource %s", id)
+		}
+	}
+	for _, id := range t.outputIDs() {
+		if t.Outputs[id].Condition != "" {
+			return errors.Wrapf(unsupported("Condition"), "output %s", id)
+		}
+	}
+	return nil
+}
+
+// unsupportedConstruct is the error rendering a CloudFormation construct format has no equivalent for fails with
+func unsupportedConstruct(construct string, format string) error {
+	return errors.Wrapf(errdefs.ErrNotImplemented, "CloudFormation %s is not supported by %s format", construct, format)
+}
+
+func (t rawTemplate) logicalIDs() []string {
+	var ids []string
+	for id := range t.Resources {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (t rawTemplate) parameterIDs() []string {
+	var ids []string
+	for id := range t.Parameters {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (t rawTemplate) outputIDs() []string {
+	var ids []string
+	for id := range t.Outputs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// renderTemplate renders a marshalled CloudFormation template in one of the supported formats
+func renderTemplate(name string, marshalled []byte, format string) ([]byte, error) {
+	switch format {
+	case "", FormatJSON:
+		return marshalled, nil
+	case FormatYAML:
+		var unmarshalled interface{}
+		err := json.Unmarshal(marshalled, &unmarshalled)
+		if err != nil {
+			return nil, err
+		}
+		return yaml.Marshal(unmarshalled)
+	}
+
+	var render func(name string, template rawTemplate) ([]byte, error)
+	switch format {
+	case FormatCDK:
+		render = renderCDK
+	case FormatCDKGo:
+		render = renderCDKGo
+	case FormatTerraform:
+		render = renderTerraform
+	default:
+		return nil, errors.Wrapf(errdefs.ErrParsingFailed, "unsupported format %q, expected one of %s, %s, %s, %s or %s", format, FormatJSON, FormatYAML, FormatCDK, FormatCDKGo, FormatTerraform)
+	}
+	var template rawTemplate
+	err := json.Unmarshal(marshalled, &template)
+	if err != nil {
+		return nil, err
+	}
+	err = template.checkSupported(format)
+	if err != nil {
+		return nil, err
+	}
+	return render(name, template)
+}
@@ -0,0 +1,290 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+const formatsTemplate = `{
+  "Resources": {
+    "Cluster": {
+      "Type": "AWS::ECS::Cluster",
+      "Properties": {
+        "ClusterName": "test"
+      }
+    },
+    "FooTaskExecutionRole": {
+      "Type": "AWS::IAM::Role",
+      "Properties": {
+        "AssumeRolePolicyDocument": {
+          "Statement": [{"Action": ["sts:AssumeRole"], "Effect": "Allow"}]
+        }
+      }
+    },
+    "FooService": {
+      "Type": "AWS::ECS::Service",
+      "DependsOn": ["Cluster"],
+      "Properties": {
+        "Cluster": {"Fn::GetAtt": ["Cluster", "Arn"]},
+        "ServiceName": {"Fn::Sub": "${AWS::StackName}-foo-${Cluster}"},
+        "Role": {"Ref": "FooTaskExecutionRole"},
+        "Options": {"awslogs-region": {"Ref": "AWS::Region"}},
+        "Command": ["echo ${HOME}"]
+      }
+    }
+  }
+}`
+
+func TestConvertFormatYAML(t *testing.T) {
+	out, err := renderTemplate("test", []byte(formatsTemplate), FormatYAML)
+	assert.NilError(t, err)
+	assert.Check(t, is.Contains(string(out), "Type: AWS::ECS::Cluster"))
+}
+
+func TestConvertFormatCDK(t *testing.T) {
+	out, err := renderTemplate("test", []byte(formatsTemplate), FormatCDK)
+	assert.NilError(t, err)
+	s := string(out)
+	assert.Check(t, is.Contains(s, "export class TestStack extends cdk.Stack {"))
+	assert.Check(t, is.Contains(s, "const fooServiceResource = new cdk.CfnResource(this, 'FooService', {"))
+	assert.Check(t, is.Contains(s, "fooServiceResource.overrideLogicalId('FooService');"))
+	assert.Check(t, is.Contains(s, `fooServiceResource.addOverride('DependsOn', ["Cluster"]);`))
+}
+
+func TestConvertFormatTerraform(t *testing.T) {
+	out, err := renderTemplate("test", []byte(formatsTemplate), FormatTerraform)
+	assert.NilError(t, err)
+	s := string(out)
+	assert.Check(t, is.Contains(s, `resource "awscc_ecs_service" "foo_service" {`))
+	assert.Check(t, is.Contains(s, "  cluster = awscc_ecs_cluster.cluster.arn\n"))
+	assert.Check(t, is.Contains(s, `  service_name = "${local.project}-foo-${awscc_ecs_cluster.cluster.id}"`))
+	assert.Check(t, is.Contains(s, "  role = awscc_iam_role.foo_task_execution_role.id\n"))
+	assert.Check(t, is.Contains(s, `    "awslogs-region" = data.aws_region.current.name`))
+	assert.Check(t, is.Contains(s, `    "echo $${HOME}",`))
+	assert.Check(t, is.Contains(s, "  assume_role_policy_document = jsonencode({\n"))
+	assert.Check(t, is.Contains(s, `        "Effect" = "Allow"`))
+	assert.Check(t, is.Contains(s, "  depends_on = [awscc_ecs_cluster.cluster]\n"))
+}
+
+func TestConvertFormatUnsupported(t *testing.T) {
+	_, err := renderTemplate("test", []byte(formatsTemplate), "pulumi")
+	assert.Check(t, errdefs.IsErrParsingFailed(err))
+}
+
+func TestConvertFormatTerraformProject(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  foo:
+    image: hello_world
+    ports:
+      - 80:80
+`, useDefaultVPC)
+	marshalled, err := marshall(template)
+	assert.NilError(t, err)
+	out, err := renderTemplate("test", marshalled, FormatTerraform)
+	assert.NilError(t, err)
+	assert.Check(t, strings.Contains(string(out), `resource "awscc_ecs_task_definition" "foo_task_definition" {`))
+}
+
+const formatsParametersTemplate = `{
+  "Parameters": {
+    "ClusterName": {"Type": "String", "Default": "", "Description": "Cluster to deploy to"},
+    "Subnets": {"Type": "CommaDelimitedList", "Default": "subnet-1, subnet-2"},
+    "Mode": {"Type": "String", "AllowedValues": ["a", "b"], "NoEcho": "true"}
+  },
+  "Resources": {
+    "Cluster": {
+      "Type": "AWS::ECS::Cluster",
+      "Properties": {
+        "ClusterName": {"Ref": "ClusterName"},
+        "Zone": {"Fn::Select": [0, {"Fn::GetAZs": ""}]},
+        "Subnet": {"Fn::Select": ["1", {"Ref": "Subnets"}]}
+      }
+    }
+  },
+  "Outputs": {
+    "ClusterArn": {
+      "Description": "Cluster ARN",
+      "Value": {"Fn::GetAtt": ["Cluster", "Arn"]},
+      "Export": {"Name": {"Fn::Sub": "${AWS::StackName}-cluster"}}
+    }
+  }
+}`
+
+func TestConvertFormatParametersCDK(t *testing.T) {
+	out, err := renderTemplate("test", []byte(formatsParametersTemplate), FormatCDK)
+	assert.NilError(t, err)
+	s := string(out)
+	assert.Check(t, is.Contains(s, "const clusterNameParameter = new cdk.CfnParameter(this, 'ClusterName', {\n"))
+	assert.Check(t, is.Contains(s, `      "description": "Cluster to deploy to",`))
+	assert.Check(t, is.Contains(s, `      "noEcho": true,`))
+	assert.Check(t, is.Contains(s, "clusterNameParameter.overrideLogicalId('ClusterName');"))
+	assert.Check(t, is.Contains(s, "const clusterArnOutput = new cdk.CfnOutput(this, 'ClusterArn', {\n"))
+	assert.Check(t, is.Contains(s, `      exportName: cdk.Token.asString({"Fn::Sub":"${AWS::StackName}-cluster"}),`))
+}
+
+func TestConvertFormatParametersCDKGo(t *testing.T) {
+	out, err := renderTemplate("test", []byte(formatsParametersTemplate), FormatCDKGo)
+	assert.NilError(t, err)
+	s := string(out)
+	assert.Check(t, is.Contains(s, "func NewTestStack(scope constructs.Construct, id string, props *awscdk.StackProps) awscdk.Stack {"))
+	assert.Check(t, is.Contains(s, `clusterNameParameter := awscdk.NewCfnParameter(stack, jsii.String("ClusterName"), &awscdk.CfnParameterProps{`))
+	assert.Check(t, is.Contains(s, `		AllowedValues: jsii.Strings("a", "b"),`))
+	assert.Check(t, is.Contains(s, `		NoEcho:        jsii.Bool(true),`))
+	assert.Check(t, is.Contains(s, `		"ClusterName": map[string]interface{}{`))
+	assert.Check(t, is.Contains(s, `			"Ref": "ClusterName",`))
+	assert.Check(t, is.Contains(s, `		ExportName: awscdk.Token_AsString(map[string]interface{}{`))
+	assert.Check(t, is.Contains(s, `	NewTestStack(app, "test", nil)`))
+}
+
+func TestConvertFormatParametersTerraform(t *testing.T) {
+	_, err := renderTemplate("test", []byte(formatsParametersTemplate), FormatTerraform)
+	assert.Check(t, errdefs.IsErrNotImplemented(err))
+	assert.ErrorContains(t, err, "output ClusterArn: CloudFormation Export is not supported by terraform format")
+
+	out, err := renderTemplate("test", []byte(strings.Replace(formatsParametersTemplate, `,
+      "Export": {"Name": {"Fn::Sub": "${AWS::StackName}-cluster"}}`, "", 1)), FormatTerraform)
+	assert.NilError(t, err)
+	s := string(out)
+	assert.Check(t, is.Contains(s, "variable \"cluster_name\" {\n  type = string\n  default = \"\"\n  description = \"Cluster to deploy to\"\n}\n"))
+	assert.Check(t, is.Contains(s, "variable \"subnets\" {\n  type = list(string)\n  default = [\n    \"subnet-1\",\n    \"subnet-2\",\n  ]\n}\n"))
+	assert.Check(t, is.Contains(s, "  sensitive = true\n"))
+	assert.Check(t, is.Contains(s, "    condition = contains([\n"))
+	assert.Check(t, is.Contains(s, "  cluster_name = var.cluster_name\n"))
+	assert.Check(t, is.Contains(s, "data \"aws_availability_zones\" \"available\" {}\n"))
+	assert.Check(t, is.Contains(s, "  zone = element(data.aws_availability_zones.available.names, 0)\n"))
+	assert.Check(t, is.Contains(s, "  subnet = element(var.subnets, 1)\n"))
+	assert.Check(t, is.Contains(s, "output \"cluster_arn\" {\n  value = awscc_ecs_cluster.cluster.arn\n  description = \"Cluster ARN\"\n}\n"))
+}
+
+func TestConvertFormatUnsupportedConstructs(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		formats  []string
+		err      string
+	}{
+		{
+			name: "conditions",
+			template: `{
+  "Conditions": {"CreateCluster": {"Fn::Equals": ["", ""]}},
+  "Resources": {"Cluster": {"Type": "AWS::ECS::Cluster", "Condition": "CreateCluster"}}
+}`,
+			formats: []string{FormatCDK, FormatCDKGo, FormatTerraform},
+			err:     "CloudFormation Conditions section is not supported",
+		},
+		{
+			name:     "resource condition",
+			template: `{"Resources": {"Cluster": {"Type": "AWS::ECS::Cluster", "Condition": "CreateCluster"}}}`,
+			formats:  []string{FormatCDK, FormatCDKGo, FormatTerraform},
+			err:      "resource Cluster: CloudFormation Condition is not supported",
+		},
+		{
+			name:     "mappings",
+			template: `{"Mappings": {"Regions": {"eu-west-3": {"AMI": "ami-123"}}}, "Resources": {}}`,
+			formats:  []string{FormatCDK, FormatCDKGo, FormatTerraform},
+			err:      "CloudFormation Mappings section is not supported",
+		},
+		{
+			name: "if",
+			template: `{"Resources": {"Cluster": {"Type": "AWS::ECS::Cluster", "Properties": {
+  "ClusterName": {"Fn::If": ["CreateCluster", "a", "b"]}
+}}}}`,
+			formats: []string{FormatTerraform},
+			err:     "Cluster.ClusterName: CloudFormation Fn::If is not supported by terraform format",
+		},
+		{
+			name: "availability zones of another region",
+			template: `{"Resources": {"Cluster": {"Type": "AWS::ECS::Cluster", "Properties": {
+  "Zones": {"Fn::GetAZs": "us-east-1"}
+}}}}`,
+			formats: []string{FormatTerraform},
+			err:     "CloudFormation Fn::GetAZs for region us-east-1 is not supported by terraform format",
+		},
+		{
+			name: "ssm parameter",
+			template: `{
+  "Parameters": {"AMI": {"Type": "AWS::SSM::Parameter::Value<String>"}},
+  "Resources": {}
+}`,
+			formats: []string{FormatTerraform},
+			err:     "parameter AMI: CloudFormation AWS::SSM::Parameter::Value<String> parameter type is not supported",
+		},
+	}
+	for _, tt := range tests {
+		for _, format := range tt.formats {
+			t.Run(tt.name+" "+format, func(t *testing.T) {
+				_, err := renderTemplate("test", []byte(tt.template), format)
+				assert.Check(t, errdefs.IsErrNotImplemented(err))
+				assert.ErrorContains(t, err, tt.err)
+			})
+		}
+	}
+}
+
+func TestConvertFormatsProject(t *testing.T) {
+	template := convertYaml(t, `
+x-aws-cluster: "arn:aws:ecs:region:account:cluster/name"
+services:
+  foo:
+    image: hello_world
+    ports:
+      - 80:80
+    volumes:
+      - data:/data
+volumes:
+  data: {}
+`, useDefaultVPC, func(m *MockAPIMockRecorder) {
+		m.ResolveCluster(gomock.Any(), "arn:aws:ecs:region:account:cluster/name").Return(existingAWSResource{
+			arn: "arn:aws:ecs:region:account:cluster/name",
+			id:  "name",
+		}, nil)
+		m.ListFileSystems(gomock.Any(), gomock.Any()).Return(nil, nil)
+	})
+	marshalled, err := marshall(template)
+	assert.NilError(t, err)
+	filesystem := volumeResourceName("data")
+
+	out, err := renderTemplate("test", marshalled, FormatTerraform)
+	assert.NilError(t, err)
+	s := string(out)
+	assert.Check(t, is.Contains(s, "  cluster = \"arn:aws:ecs:region:account:cluster/name\"\n"))
+	assert.Check(t, is.Contains(s, "resource \"awscc_efs_file_system\" \""+terraformName(filesystem)+"\" {\n"))
+	assert.Check(t, is.Contains(s, "  lifecycle {\n    prevent_destroy = true\n  }\n"))
+
+	out, err = renderTemplate("test", marshalled, FormatCDK)
+	assert.NilError(t, err)
+	assert.Check(t, is.Contains(string(out), cdkVariable(filesystem, "Resource")+`.addOverride('DeletionPolicy', "Retain");`))
+
+	out, err = renderTemplate("test", marshalled, FormatCDKGo)
+	assert.NilError(t, err)
+	assert.Check(t, is.Contains(string(out), cdkVariable(filesystem, "Resource")+`.AddOverride(jsii.String("DeletionPolicy"), "Retain")`))
+}
+
+func TestSnakeCase(t *testing.T) {
+	assert.Equal(t, snakeCase("VPCZoneIdentifier"), "vpc_zone_identifier")
+	assert.Equal(t, snakeCase("FooTCP80Listener"), "foo_tcp80_listener")
+	assert.Equal(t, snakeCase("ClusterName"), "cluster_name")
+}
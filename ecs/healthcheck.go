@@ -0,0 +1,214 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/awslabs/goformation/v4/cloudformation/elasticloadbalancingv2"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/sirupsen/logrus"
+)
+
+// load balancer health check interval and timeout of HTTP targets when not set, in seconds
+const (
+	defaultHealthCheckInterval = 30
+	defaultHealthCheckTimeout  = 5
+)
+
+// targetHealthCheck is the load balancer health check of service targets, as set by x-aws-healthcheck
+type targetHealthCheck struct {
+	Path               string `json:"path,omitempty"`
+	Interval           int    `json:"interval,omitempty"`
+	Timeout            int    `json:"timeout,omitempty"`
+	HealthyThreshold   int    `json:"healthy_threshold,omitempty"`
+	UnhealthyThreshold int    `json:"unhealthy_threshold,omitempty"`
+	Matcher            string `json:"matcher,omitempty"`
+	// Port is the TCP port UDP targets are checked on
+	Port int `json:"port,omitempty"`
+}
+
+// healthCheckURL matches the URL a healthcheck command requests to the container itself, as with curl or wget
+var healthCheckURL = regexp.MustCompile(`https?://(?:localhost|127\.0\.0\.1|0\.0\.0\.0)(?::(\d+))?(/[^\s'"|;&]*)?`)
+
+// getTargetHealthCheck derives the target group health check of port from service healthcheck, so that load balancer
+// checks the same endpoint, then applies x-aws-healthcheck
+func getTargetHealthCheck(service types.ServiceConfig, port types.ServicePortConfig) (*targetHealthCheck, error) {
+	check := &targetHealthCheck{}
+	if hc := service.HealthCheck; hc != nil && !hc.Disable {
+		check.Path = healthCheckPath(hc.Test, port.Target)
+		if interval := durationToInt(hc.Interval); interval > 0 {
+			check.Interval = clamp(interval, 5, 300)
+		}
+		if timeout := durationToInt(hc.Timeout); timeout > 0 {
+			check.Timeout = clamp(timeout, 2, 120)
+		}
+		if hc.Retries != nil {
+			check.UnhealthyThreshold = clamp(int(*hc.Retries), 2, 10)
+		}
+		if interval := check.interval(); check.Timeout >= interval {
+			// load balancer requires timeout to be lower than interval
+			logrus.Warnf("service %q: healthcheck timeout %ds isn't lower than interval %ds, load balancer health check timeout is set to %ds",
+				service.Name, check.Timeout, interval, interval-1)
+			check.Timeout = interval - 1
+		}
+	}
+
+	if v, ok := service.Extensions[extensionHealthCheck]; ok {
+		marshalled, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		var override targetHealthCheck
+		err = json.Unmarshal(marshalled, &override)
+		if err != nil {
+			return nil, fmt.Errorf("service %q: invalid %s: %w", service.Name, extensionHealthCheck, err)
+		}
+		err = override.validate()
+		if err != nil {
+			return nil, fmt.Errorf("service %q: %s %w", service.Name, extensionHealthCheck, err)
+		}
+		if override.Path != "" {
+			check.Path = override.Path
+		}
+		if override.Interval != 0 {
+			check.Interval = override.Interval
+		}
+		if override.Timeout != 0 {
+			check.Timeout = override.Timeout
+		}
+		if override.HealthyThreshold != 0 {
+			check.HealthyThreshold = override.HealthyThreshold
+		}
+		if override.UnhealthyThreshold != 0 {
+			check.UnhealthyThreshold = override.UnhealthyThreshold
+		}
+		if override.Matcher != "" {
+			check.Matcher = override.Matcher
+		}
+		check.Port = override.Port
+	}
+	if check.timeout() >= check.interval() {
+		return nil, fmt.Errorf("service %q: %s timeout (%ds) must be lower than interval (%ds)", service.Name, extensionHealthCheck, check.timeout(), check.interval())
+	}
+	return check, nil
+}
+
+// interval returns the health check interval, load balancer default when not set
+func (c targetHealthCheck) interval() int {
+	if c.Interval == 0 {
+		return defaultHealthCheckInterval
+	}
+	return c.Interval
+}
+
+// timeout returns the health check timeout, load balancer default when not set
+func (c targetHealthCheck) timeout() int {
+	if c.Timeout == 0 {
+		return defaultHealthCheckTimeout
+	}
+	return c.Timeout
+}
+
+func (c targetHealthCheck) validate() error {
+	if c.Path != "" && !strings.HasPrefix(c.Path, "/") {
+		return fmt.Errorf("path %q must start with /", c.Path)
+	}
+	for _, r := range []struct {
+		name     string
+		value    int
+		min, max int
+	}{
+		{"interval", c.Interval, 5, 300},
+		{"timeout", c.Timeout, 2, 120},
+		{"healthy_threshold", c.HealthyThreshold, 2, 10},
+		{"unhealthy_threshold", c.UnhealthyThreshold, 2, 10},
+		{"port", c.Port, 1, 65535},
+	} {
+		if r.value != 0 && (r.value < r.min || r.value > r.max) {
+			return fmt.Errorf("%s must be between %d and %d, got %d", r.name, r.min, r.max, r.value)
+		}
+	}
+	return nil
+}
+
+// apply sets health check on an HTTP(S) target group
+func (c targetHealthCheck) apply(targetGroup *elasticloadbalancingv2.TargetGroup) {
+	targetGroup.HealthCheckPath = c.Path
+	targetGroup.HealthCheckIntervalSeconds = c.Interval
+	targetGroup.HealthCheckTimeoutSeconds = c.Timeout
+	targetGroup.HealthyThresholdCount = c.HealthyThreshold
+	targetGroup.UnhealthyThresholdCount = c.UnhealthyThreshold
+	if c.Matcher != "" {
+		targetGroup.Matcher = &elasticloadbalancingv2.TargetGroup_Matcher{
+			HttpCode: c.Matcher,
+		}
+	}
+}
+
+// healthCheckPath returns the path a healthcheck command requests on port, or empty if it doesn't request one
+func healthCheckPath(test types.HealthCheckTest, port uint32) string {
+	for _, match := range healthCheckURL.FindAllStringSubmatch(strings.Join(test, " "), -1) {
+		p := uint32(80)
+		if strings.HasPrefix(match[0], "https") {
+			p = 443
+		}
+		if match[1] != "" {
+			v, err := strconv.ParseUint(match[1], 10, 32)
+			if err != nil {
+				continue
+			}
+			p = uint32(v)
+		}
+		if p != port {
+			continue
+		}
+		if match[2] == "" {
+			return "/"
+		}
+		return match[2]
+	}
+	return ""
+}
+
+// udpHealthCheckPort returns the TCP port to check UDP targets on, x-aws-healthcheck port or the first TCP port service
+// publishes
+func udpHealthCheckPort(service types.ServiceConfig, port types.ServicePortConfig, check *targetHealthCheck) (int, error) {
+	if check.Port != 0 {
+		return check.Port, nil
+	}
+	for _, p := range service.Ports {
+		if !strings.EqualFold(p.Protocol, "udp") {
+			return int(p.Target), nil
+		}
+	}
+	return 0, fmt.Errorf("service %q: UDP port %d requires %s port to be set to a TCP port for load balancer health check", service.Name, port.Target, extensionHealthCheck)
+}
+
+func clamp(value int, min int, max int) int {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
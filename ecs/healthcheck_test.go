@@ -0,0 +1,119 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+
+	"github.com/awslabs/goformation/v4/cloudformation/elasticloadbalancingv2"
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestTargetGroupHealthCheck(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  foo:
+    image: nginx
+    ports:
+      - target: 8080
+        published: 8080
+        x-aws-protocol: http
+    healthcheck:
+      test: ["CMD-SHELL", "curl -f http://localhost:8080/health || exit 1"]
+      interval: 10s
+      timeout: 30s
+      retries: 3
+`, useDefaultVPC)
+	var tg *elasticloadbalancingv2.TargetGroup
+	for _, r := range template.Resources {
+		if g, ok := r.(*elasticloadbalancingv2.TargetGroup); ok {
+			tg = g
+		}
+	}
+	assert.Assert(t, tg != nil)
+	assert.Equal(t, tg.Protocol, "HTTP")
+	assert.Equal(t, tg.HealthCheckPath, "/health")
+	assert.Equal(t, tg.HealthCheckIntervalSeconds, 10)
+	assert.Equal(t, tg.HealthCheckTimeoutSeconds, 9)
+	assert.Equal(t, tg.UnhealthyThresholdCount, 3)
+}
+
+func TestTargetGroupHealthCheckOverride(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  foo:
+    image: nginx
+    ports:
+      - 80:80
+    healthcheck:
+      test: ["CMD", "wget", "-q", "http://127.0.0.1/ping"]
+    x-aws-healthcheck:
+      path: /ready
+      healthy_threshold: 2
+      matcher: 200-299
+`, useDefaultVPC)
+	tg := template.Resources["FooTCP80TargetGroup"].(*elasticloadbalancingv2.TargetGroup)
+	assert.Equal(t, tg.HealthCheckPath, "/ready")
+	assert.Equal(t, tg.HealthyThresholdCount, 2)
+	assert.Equal(t, tg.Matcher.HttpCode, "200-299")
+}
+
+func TestHealthCheckPath(t *testing.T) {
+	tests := []struct {
+		test     types.HealthCheckTest
+		port     uint32
+		expected string
+	}{
+		{test: types.HealthCheckTest{"CMD", "curl", "-f", "http://localhost/"}, port: 80, expected: "/"},
+		{test: types.HealthCheckTest{"CMD", "curl", "http://localhost:3000/api/health?full=1"}, port: 3000, expected: "/api/health?full=1"},
+		{test: types.HealthCheckTest{"CMD-SHELL", "curl -f http://localhost:9090/metrics && curl http://localhost:8080/up"}, port: 8080, expected: "/up"},
+		{test: types.HealthCheckTest{"CMD", "curl", "http://localhost:3000/health"}, port: 80, expected: ""},
+		{test: types.HealthCheckTest{"CMD", "pg_isready"}, port: 5432, expected: ""},
+	}
+	for _, test := range tests {
+		assert.Equal(t, healthCheckPath(test.test, test.port), test.expected)
+	}
+}
+
+func TestTargetHealthCheckInvalid(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  foo:
+    image: nginx
+    ports:
+      - 80:80
+    x-aws-healthcheck:
+      interval: 1
+`)
+	_, err := getTargetHealthCheck(project.Services[0], project.Services[0].Ports[0])
+	assert.ErrorContains(t, err, "interval must be between 5 and 300, got 1")
+}
+
+func TestTargetHealthCheckTimeoutExceedsInterval(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  foo:
+    image: nginx
+    ports:
+      - 80:80
+    x-aws-healthcheck:
+      timeout: 60
+`)
+	_, err := getTargetHealthCheck(project.Services[0], project.Services[0].Ports[0])
+	assert.ErrorContains(t, err, `service "foo": x-aws-healthcheck timeout (60s) must be lower than interval (30s)`)
+}
@@ -0,0 +1,103 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/cloudformation/ec2"
+	"github.com/awslabs/goformation/v4/cloudformation/elasticloadbalancingv2"
+	"github.com/compose-spec/compose-go/types"
+)
+
+const (
+	httpPort  = 80
+	httpsPort = 443
+)
+
+// listenerCertificate returns the ACM certificate set by x-aws-certificate for HTTPS port, if any
+func listenerCertificate(service types.ServiceConfig, port types.ServicePortConfig, loadBalancerType string) (string, error) {
+	certificate, ok := service.Extensions[extensionCertificate]
+	https := port.Target == httpsPort
+	if v, set := port.Extensions[extensionProtocol]; set {
+		https = v == "https"
+		if https && !ok {
+			return "", fmt.Errorf("service %q requires %s to be set for HTTPS port %d", service.Name, extensionCertificate, port.Target)
+		}
+	}
+	if !ok || !https {
+		return "", nil
+	}
+	if loadBalancerType != elbv2.LoadBalancerTypeEnumApplication {
+		return "", fmt.Errorf("service %q sets %s, but HTTPS requires an application load balancer", service.Name, extensionCertificate)
+	}
+	return fmt.Sprint(certificate), nil
+}
+
+// createHTTPRedirect creates an HTTP listener on load balancer to redirect all requests to HTTPS
+func (b *ecsAPIService) createHTTPRedirect(project *types.Project, template *cloudformation.Template, resources awsResources) error {
+	if v, ok := project.Extensions[extensionHTTPRedirect]; !ok || v != true {
+		return nil
+	}
+	loadBalancer := resources.loadBalancer
+	if resources.extraLoadBalancerType == elbv2.LoadBalancerTypeEnumApplication {
+		loadBalancer = resources.extraLoadBalancer
+	} else if resources.loadBalancerType != elbv2.LoadBalancerTypeEnumApplication {
+		return fmt.Errorf("%s requires services to be exposed by an application load balancer", extensionHTTPRedirect)
+	}
+	for _, service := range project.Services {
+		for _, port := range service.Ports {
+			if port.Target == httpPort {
+				return fmt.Errorf("%s can't be set as service %q publishes port %d", extensionHTTPRedirect, service.Name, httpPort)
+			}
+		}
+	}
+	for name, network := range project.Networks {
+		if network.Internal {
+			continue
+		}
+		ingress := fmt.Sprintf("%s%dIngress", normalizeResourceName(name), httpPort)
+		rule := ec2.SecurityGroupIngress{
+			CidrIp:      "0.0.0.0/0",
+			Description: fmt.Sprintf("HTTP redirect on %s network", name),
+			GroupId:     resources.securityGroups[name],
+			FromPort:    httpPort,
+			IpProtocol:  "TCP",
+			ToPort:      httpPort,
+		}
+		template.Resources[ingress] = &rule
+		createIPv6Ingress(project, template, ingress, rule)
+	}
+	template.Resources["HTTPRedirectListener"] = &elasticloadbalancingv2.Listener{
+		DefaultActions: []elasticloadbalancingv2.Listener_Action{
+			{
+				RedirectConfig: &elasticloadbalancingv2.Listener_RedirectConfig{
+					Port:       fmt.Sprint(httpsPort),
+					Protocol:   elbv2.ProtocolEnumHttps,
+					StatusCode: elbv2.RedirectActionStatusCodeEnumHttp301,
+				},
+				Type: elbv2.ActionTypeEnumRedirect,
+			},
+		},
+		LoadBalancerArn: loadBalancer.ARN(),
+		Protocol:        elbv2.ProtocolEnumHttp,
+		Port:            httpPort,
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+
+	"github.com/awslabs/goformation/v4/cloudformation/elasticloadbalancingv2"
+	"gotest.tools/v3/assert"
+)
+
+const certificate = "arn:aws:acm:eu-west-3:123456789012:certificate/12345678-1234-1234-1234-123456789012"
+
+func TestHTTPSListener(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  foo:
+    image: nginx
+    ports:
+      - 443:443
+    x-aws-certificate: `+certificate+`
+x-aws-http_redirect: true
+`, useDefaultVPC)
+	listener := template.Resources["FooTCP443Listener"].(*elasticloadbalancingv2.Listener)
+	assert.Equal(t, listener.Protocol, "HTTPS")
+	assert.DeepEqual(t, listener.Certificates, []elasticloadbalancingv2.Listener_Certificate{{CertificateArn: certificate}})
+	targetGroup := template.Resources["FooTCP443TargetGroup"].(*elasticloadbalancingv2.TargetGroup)
+	assert.Equal(t, targetGroup.Protocol, "HTTP")
+
+	redirect := template.Resources["HTTPRedirectListener"].(*elasticloadbalancingv2.Listener)
+	assert.Equal(t, redirect.Port, 80)
+	assert.Equal(t, redirect.DefaultActions[0].Type, "redirect")
+	assert.Equal(t, redirect.DefaultActions[0].RedirectConfig.Protocol, "HTTPS")
+	_, ok := template.Resources["Default80Ingress"]
+	assert.Check(t, ok)
+}
+
+func TestListenerCertificate(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  foo:
+    image: nginx
+    ports:
+      - 80:80
+      - target: 8443
+        x-aws-protocol: https
+    x-aws-certificate: `+certificate+`
+  bar:
+    image: nginx
+    ports:
+      - target: 8443
+        x-aws-protocol: https
+`)
+	foo, bar := project.Services[0], project.Services[1]
+	if foo.Name != "foo" {
+		foo, bar = bar, foo
+	}
+	c, err := listenerCertificate(foo, foo.Ports[0], "application")
+	assert.NilError(t, err)
+	assert.Equal(t, c, "")
+	c, err = listenerCertificate(foo, foo.Ports[1], "application")
+	assert.NilError(t, err)
+	assert.Equal(t, c, certificate)
+	_, err = listenerCertificate(foo, foo.Ports[1], "network")
+	assert.ErrorContains(t, err, "requires an application load balancer")
+
+	_, err = listenerCertificate(bar, bar.Ports[0], "application")
+	assert.ErrorContains(t, err, "x-aws-certificate to be set")
+}
@@ -19,14 +19,11 @@ package ecs
 import (
 	"fmt"
 
+	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/awslabs/goformation/v4/cloudformation"
 )
 
 const (
-	ecsTaskExecutionPolicy = "arn:aws:iam::aws:policy/service-role/AmazonECSTaskExecutionRolePolicy"
-	ecrReadOnlyPolicy      = "arn:aws:iam::aws:policy/AmazonEC2ContainerRegistryReadOnly"
-	ecsEC2InstanceRole     = "arn:aws:iam::aws:policy/service-role/AmazonEC2ContainerServiceforEC2Role"
-
 	actionGetSecretValue  = "secretsmanager:GetSecretValue"
 	actionGetParameters   = "ssm:GetParameters"
 	actionDecrypt         = "kms:Decrypt"
@@ -34,12 +31,38 @@ const (
 	actionGetMetrics      = "cloudwatch:GetMetricStatistics"
 	actionDescribeService = "ecs:DescribeServices"
 	actionUpdateService   = "ecs:UpdateService"
+	actionSSMMessages     = "ssmmessages:*"
+	actionAppMeshStream   = "appmesh:StreamAggregatedResources"
+	actionExportCert      = "acm:ExportCertificate"
+	actionGetCACert       = "acm-pca:GetCertificateAuthorityCertificate"
 )
 
 var (
-	ecsTaskAssumeRolePolicyDocument     = policyDocument("ecs-tasks.amazonaws.com")
-	ec2InstanceAssumeRolePolicyDocument = policyDocument("ec2.amazonaws.com")
+	// managed policies ARNs are resolved by CloudFormation, so that templates also apply to GovCloud and China partitions
+	ecsTaskExecutionPolicy = cloudformation.Sub("arn:${AWS::Partition}:iam::aws:policy/service-role/AmazonECSTaskExecutionRolePolicy")
+	ecrReadOnlyPolicy      = cloudformation.Sub("arn:${AWS::Partition}:iam::aws:policy/AmazonEC2ContainerRegistryReadOnly")
+	ecsEC2InstanceRole     = cloudformation.Sub("arn:${AWS::Partition}:iam::aws:policy/service-role/AmazonEC2ContainerServiceforEC2Role")
+	ecsCodeDeployPolicy    = cloudformation.Sub("arn:${AWS::Partition}:iam::aws:policy/AWSCodeDeployRoleForECS")
+	ecsEventsPolicy        = cloudformation.Sub("arn:${AWS::Partition}:iam::aws:policy/service-role/AmazonEC2ContainerServiceEventsRole")
+	xrayDaemonPolicy       = cloudformation.Sub("arn:${AWS::Partition}:iam::aws:policy/AWSXRayDaemonWriteAccess")
+
+	ecsTaskAssumeRolePolicyDocument = policyDocument("ecs-tasks.amazonaws.com")
+	// EC2 service principal has a distinct domain in China regions
+	ec2InstanceAssumeRolePolicyDocument = policyDocument(cloudformation.Sub("ec2.${AWS::URLSuffix}"))
 	ausocalingAssumeRolePolicyDocument  = policyDocument("application-autoscaling.amazonaws.com")
+	codeDeployAssumeRolePolicyDocument  = policyDocument("codedeploy.amazonaws.com")
+	eventsAssumeRolePolicyDocument      = policyDocument("events.amazonaws.com")
+
+	// ECS Exec agent opens SSM Session Manager channels from the task
+	execPolicyDocument = PolicyDocument{
+		Statement: []PolicyStatement{
+			{
+				Effect:   "Allow",
+				Action:   []string{actionSSMMessages},
+				Resource: []string{"*"},
+			},
+		},
+	}
 )
 
 func policyDocument(service string) PolicyDocument {
@@ -82,6 +105,32 @@ func volumeMountPolicyDocument(volume string, filesystem string) PolicyDocument
 	}
 }
 
+// appMeshPolicyDocument lets Envoy get virtual node configuration, and certificates for TLS
+func appMeshPolicyDocument(virtualNode string, config *appMeshConfig) PolicyDocument {
+	statements := []PolicyStatement{
+		{
+			Effect:   "Allow",
+			Action:   []string{actionAppMeshStream},
+			Resource: []string{cloudformation.Ref(virtualNode)},
+		},
+	}
+	if config.Certificate != "" {
+		statements = append(statements, PolicyStatement{
+			Effect:   "Allow",
+			Action:   []string{actionExportCert},
+			Resource: []string{config.Certificate},
+		}, PolicyStatement{
+			Effect:   "Allow",
+			Action:   []string{actionGetCACert},
+			Resource: []string{config.CertificateAuthority},
+		})
+	}
+	return PolicyDocument{
+		Version:   "2012-10-17", // https://docs.aws.amazon.com/IAM/latest/UserGuide/reference_policies_elements_version.html
+		Statement: statements,
+	}
+}
+
 // PolicyDocument describes an IAM policy document
 // could alternatively depend on https://github.com/kubernetes-sigs/cluster-api-provider-aws/blob/master/cmd/clusterawsadm/api/iam/v1alpha1/types.go
 type PolicyDocument struct {
@@ -108,3 +157,11 @@ type Condition struct {
 	StringEquals map[string]string `json:",omitempty"`
 	Bool         map[string]string `json:",omitempty"`
 }
+
+// roleARN returns the ARN of an existing IAM role set by name in the stack account
+func roleARN(role string) string {
+	if arn.IsARN(role) {
+		return role
+	}
+	return cloudformation.Sub("arn:${AWS::Partition}:iam::${AWS::AccountId}:role/" + role)
+}
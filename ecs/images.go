@@ -0,0 +1,74 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func (b *ecsAPIService) Images(ctx context.Context, projectName string) ([]compose.ImageSummary, error) {
+	cluster, err := b.aws.GetStackClusterID(ctx, projectName)
+	if err != nil {
+		return nil, err
+	}
+	arns, err := b.aws.ListStackServices(ctx, projectName)
+	if err != nil {
+		return nil, err
+	}
+	summaries := []compose.ImageSummary{}
+	for _, arn := range arns {
+		service, _, err := b.aws.GetServiceEvents(ctx, cluster, arn)
+		if err != nil {
+			return nil, err
+		}
+		tasks, err := b.aws.GetServiceTasks(ctx, cluster, arn, false)
+		if err != nil {
+			return nil, err
+		}
+		for _, task := range tasks {
+			for _, container := range task.Containers {
+				// sidecars are managed by compose-cli, only report the service image
+				if aws.StringValue(container.Name) != service {
+					continue
+				}
+				summaries = append(summaries, compose.ImageSummary{
+					ID:       lastSegment(aws.StringValue(task.TaskArn)),
+					Service:  service,
+					Image:    aws.StringValue(container.Image),
+					Digest:   aws.StringValue(container.ImageDigest),
+					Platform: taskPlatform(task),
+				})
+			}
+		}
+	}
+	return summaries, nil
+}
+
+// taskPlatform returns the platform a task runs on, from the CPU architecture attribute set by ECS
+func taskPlatform(task *ecs.Task) string {
+	for _, attribute := range task.Attributes {
+		if aws.StringValue(attribute.Name) == "ecs.cpu-architecture" && aws.StringValue(attribute.Value) == "arm64" {
+			return "linux/arm64"
+		}
+	}
+	return "linux/amd64"
+}
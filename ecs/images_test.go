@@ -0,0 +1,68 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func TestImages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+
+	m.EXPECT().GetStackClusterID(gomock.Any(), "test").Return("cluster", nil)
+	m.EXPECT().ListStackServices(gomock.Any(), "test").Return([]string{"arn:aws:ecs:us-east-1:012345678910:service/cluster/foo"}, nil)
+	m.EXPECT().GetServiceEvents(gomock.Any(), "cluster", "arn:aws:ecs:us-east-1:012345678910:service/cluster/foo").Return("foo", nil, nil)
+	m.EXPECT().GetServiceTasks(gomock.Any(), "cluster", "arn:aws:ecs:us-east-1:012345678910:service/cluster/foo", false).Return([]*ecs.Task{
+		{
+			TaskArn: aws.String("arn:aws:ecs:us-east-1:012345678910:task/cluster/0123456789abcdef"),
+			Attributes: []*ecs.Attribute{
+				{Name: aws.String("ecs.cpu-architecture"), Value: aws.String("arm64")},
+			},
+			Containers: []*ecs.Container{
+				{Name: aws.String("Foo_ResolvConf_InitContainer"), Image: aws.String("docker/ecs-searchdomain-sidecar:1.0")},
+				{
+					Name:        aws.String("foo"),
+					Image:       aws.String("012345678910.dkr.ecr.us-east-1.amazonaws.com/foo@sha256:0123"),
+					ImageDigest: aws.String("sha256:0123"),
+				},
+			},
+		},
+	}, nil)
+
+	backend := &ecsAPIService{aws: m}
+	images, err := backend.Images(context.TODO(), "test")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, images, []compose.ImageSummary{
+		{
+			ID:       "0123456789abcdef",
+			Service:  "foo",
+			Image:    "012345678910.dkr.ecr.us-east-1.amazonaws.com/foo@sha256:0123",
+			Digest:   "sha256:0123",
+			Platform: "linux/arm64",
+		},
+	})
+}
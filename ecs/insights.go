@@ -0,0 +1,69 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+
+	ecsapi "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// createContainerInsights applies x-aws-container_insights to the cluster created for the application, so that
+// CloudWatch Container Insights collects tasks CPU and memory metrics. An existing cluster is left unchanged, with a
+// warning if Container Insights isn't enabled on it.
+func (b *ecsAPIService) createContainerInsights(ctx context.Context, project *types.Project, template *cloudformation.Template, resources awsResources) error {
+	v, ok := project.Extensions[extensionContainerInsights]
+	if !ok {
+		return nil
+	}
+	enabled, ok := v.(bool)
+	if !ok {
+		return errors.Wrapf(errdefs.ErrParsingFailed, "%s must be a boolean, got %v", extensionContainerInsights, v)
+	}
+	if r, ok := template.Resources["Cluster"]; ok {
+		value := "disabled"
+		if enabled {
+			value = "enabled"
+		}
+		r.(*ecs.Cluster).ClusterSettings = []ecs.Cluster_ClusterSettings{
+			{
+				Name:  ecsapi.ClusterSettingNameContainerInsights,
+				Value: value,
+			},
+		}
+		return nil
+	}
+	if !enabled {
+		return nil
+	}
+	insights, err := b.aws.ContainerInsightsEnabled(ctx, resources.cluster.ARN())
+	if err != nil {
+		return err
+	}
+	if !insights {
+		logrus.Warnf("Container Insights isn't enabled on cluster %s, %s is ignored for existing cluster. Run `aws ecs update-cluster-settings --cluster %s --settings name=containerInsights,value=enabled` to enable it",
+			resources.cluster.ID(), extensionContainerInsights, resources.cluster.ID())
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func TestContainerInsights(t *testing.T) {
+	template := convertYaml(t, `
+x-aws-container_insights: true
+services:
+  test:
+    image: nginx
+`, useDefaultVPC)
+	cluster := template.Resources["Cluster"].(*ecs.Cluster)
+	assert.DeepEqual(t, cluster.ClusterSettings, []ecs.Cluster_ClusterSettings{
+		{Name: "containerInsights", Value: "enabled"},
+	})
+}
+
+func TestContainerInsightsExistingCluster(t *testing.T) {
+	template := convertYaml(t, `
+x-aws-cluster: shared
+x-aws-container_insights: true
+services:
+  test:
+    image: nginx
+`, useDefaultVPC, func(m *MockAPIMockRecorder) {
+		m.ResolveCluster(gomock.Any(), "shared").Return(existingAWSResource{
+			arn: "arn:aws:ecs:region:account:cluster/shared",
+			id:  "shared",
+		}, nil)
+		m.ContainerInsightsEnabled(gomock.Any(), "arn:aws:ecs:region:account:cluster/shared").Return(false, nil)
+	})
+	_, ok := template.Resources["Cluster"]
+	assert.Check(t, !ok)
+}
@@ -0,0 +1,62 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/cloudformation/ec2"
+	"github.com/compose-spec/compose-go/types"
+)
+
+const ipAddressTypeDualStack = "dualstack"
+
+// dualStack tells if x-aws-ipv6 makes the application reachable by IPv6 clients
+func dualStack(project *types.Project) bool {
+	v, ok := project.Extensions[extensionIPv6]
+	if !ok {
+		return false
+	}
+	enabled, ok := v.(bool)
+	return ok && enabled
+}
+
+// checkDualStack makes sure ports are exposed by an application load balancer, which can be dual-stack, and that
+// subnets it is created in have an IPv6 CIDR block
+func (b *ecsAPIService) checkDualStack(ctx context.Context, project *types.Project, resources awsResources) error {
+	if !dualStack(project) {
+		return nil
+	}
+	if resources.loadBalancerType == elbv2.LoadBalancerTypeEnumNetwork || resources.extraLoadBalancerType == elbv2.LoadBalancerTypeEnumNetwork {
+		return fmt.Errorf("%s requires services to be exposed by an application load balancer", extensionIPv6)
+	}
+	return b.aws.CheckSubnetsIPv6(ctx, resources.subnetsIDs())
+}
+
+// createIPv6Ingress creates the counterpart of an ingress rule open to all IPv4 addresses for IPv6 clients
+func createIPv6Ingress(project *types.Project, template *cloudformation.Template, name string, ingress ec2.SecurityGroupIngress) {
+	if !dualStack(project) {
+		return
+	}
+	ingress.CidrIp = ""
+	ingress.CidrIpv6 = "::/0"
+	template.Resources[strings.TrimSuffix(name, "Ingress")+"IPv6Ingress"] = &ingress
+}
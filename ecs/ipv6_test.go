@@ -0,0 +1,81 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+
+	"github.com/awslabs/goformation/v4/cloudformation/ec2"
+	"github.com/awslabs/goformation/v4/cloudformation/elasticloadbalancingv2"
+	"github.com/awslabs/goformation/v4/cloudformation/route53"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func TestDualStack(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  foo:
+    image: nginx
+    ports:
+      - 80:80
+x-aws-ipv6: true
+x-aws-dns:
+  zone: example.com
+  name: app.example.com
+`, useDefaultVPC, func(m *MockAPIMockRecorder) {
+		m.CheckSubnetsIPv6(gomock.Any(), []string{"subnet1", "subnet2"}).Return(nil)
+	})
+	lb := template.Resources["LoadBalancer"].(*elasticloadbalancingv2.LoadBalancer)
+	assert.Equal(t, lb.IpAddressType, "dualstack")
+
+	ingress := template.Resources["Default80IPv6Ingress"].(*ec2.SecurityGroupIngress)
+	assert.Equal(t, ingress.CidrIpv6, "::/0")
+	assert.Equal(t, ingress.CidrIp, "")
+	assert.Equal(t, ingress.FromPort, 80)
+
+	record := template.Resources["LoadBalancerIPv6DNSRecord"].(*route53.RecordSet)
+	assert.Equal(t, record.Type, "AAAA")
+	assert.Equal(t, record.Name, "app.example.com")
+	assert.Equal(t, template.Resources["LoadBalancerDNSRecord"].(*route53.RecordSet).Type, "A")
+}
+
+func TestDualStackDisabled(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  foo:
+    image: nginx
+    ports:
+      - 80:80
+`, useDefaultVPC)
+	lb := template.Resources["LoadBalancer"].(*elasticloadbalancingv2.LoadBalancer)
+	assert.Equal(t, lb.IpAddressType, "")
+	_, ok := template.Resources["Default80IPv6Ingress"]
+	assert.Check(t, !ok)
+}
+
+func TestDualStackRequiresApplicationLoadBalancer(t *testing.T) {
+	err := convertYamlError(t, `
+services:
+  foo:
+    image: redis
+    ports:
+      - 6379:6379
+x-aws-ipv6: true
+`, useDefaultVPC)
+	assert.ErrorContains(t, err, "x-aws-ipv6 requires services to be exposed by an application load balancer")
+}
@@ -0,0 +1,99 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/docker/cli/cli/config"
+	dockercredentials "github.com/docker/cli/cli/config/credentials"
+	"github.com/docker/cli/cli/config/types"
+)
+
+const keychainProviderName = "KeychainProvider"
+
+// keychainServer is the key AWS credentials for profile are stored under in the docker credentials store
+func keychainServer(profile string) string {
+	return "ecs-credentials://" + profile
+}
+
+// newKeychain returns the credential helper configured by `credsStore` in docker CLI config file, so
+// that AWS credentials are kept in the OS keychain rather than in plaintext files
+func newKeychain() (dockercredentials.Store, error) {
+	cfg := config.LoadDefaultConfigFile(os.Stderr)
+	if cfg.CredentialsStore == "" {
+		return nil, fmt.Errorf("no credentials store is configured in %s, set \"credsStore\" to use the OS keychain", cfg.Filename)
+	}
+	return dockercredentials.NewNativeStore(cfg, cfg.CredentialsStore), nil
+}
+
+func saveKeychainCredentials(store dockercredentials.Store, profile string, accessKeyID string, secretAccessKey string) error {
+	return store.Store(types.AuthConfig{
+		ServerAddress: keychainServer(profile),
+		Username:      accessKeyID,
+		Password:      secretAccessKey,
+	})
+}
+
+// newKeychainSession creates an AWS session for profile with credentials retrieved from the OS keychain
+func newKeychainSession(profile string, region string) (*session.Session, error) {
+	store, err := newKeychain()
+	if err != nil {
+		return nil, err
+	}
+	return session.NewSessionWithOptions(session.Options{
+		SharedConfigState: session.SharedConfigDisable,
+		Config: aws.Config{
+			Region: aws.String(region),
+			Credentials: credentials.NewCredentials(&keychainCredentialsProvider{
+				profile: profile,
+				store:   store,
+			}),
+		},
+	})
+}
+
+// keychainCredentialsProvider retrieves long-lived AWS credentials from the docker credentials store
+type keychainCredentialsProvider struct {
+	profile   string
+	store     dockercredentials.Store
+	retrieved bool
+}
+
+func (p *keychainCredentialsProvider) Retrieve() (credentials.Value, error) {
+	auth, err := p.store.Get(keychainServer(p.profile))
+	if err != nil {
+		return credentials.Value{}, err
+	}
+	if auth.Username == "" || auth.Password == "" {
+		return credentials.Value{}, fmt.Errorf("no AWS credentials found in keychain for profile %q", p.profile)
+	}
+	p.retrieved = true
+	return credentials.Value{
+		AccessKeyID:     auth.Username,
+		SecretAccessKey: auth.Password,
+		ProviderName:    keychainProviderName,
+	}, nil
+}
+
+func (p *keychainCredentialsProvider) IsExpired() bool {
+	return !p.retrieved
+}
@@ -69,9 +69,18 @@ func (b *ecsAPIService) checkStackState(ctx context.Context, name string) error
 	if err != nil {
 		return err
 	}
-	for service, taskDef := range services {
-		if err := b.checkServiceState(ctx, cluster, service, taskDef); err != nil {
-			return fmt.Errorf("%s %s", svcNames[service], err.Error())
+	arns := sortedKeys(services)
+	errs := make([]error, len(arns))
+	err = concurrently(ctx, len(arns), func(ctx context.Context, i int) error {
+		errs[i] = b.checkServiceState(ctx, cluster, arns[i], services[arns[i]])
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for i, err := range errs {
+		if err != nil {
+			return fmt.Errorf("%s %s", svcNames[arns[i]], err.Error())
 		}
 	}
 	return nil
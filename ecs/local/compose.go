@@ -25,13 +25,16 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	types2 "github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
 
 	"github.com/docker/compose-cli/api/compose"
 	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/utils"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/compose-spec/compose-go/types"
@@ -57,7 +60,7 @@ func (e ecsLocalSimulation) Up(ctx context.Context, project *types.Project, deta
 		return fmt.Errorf("ECS simulation mode require Docker-compose 1.27, found %s", version)
 	}
 
-	converted, err := e.Convert(ctx, project)
+	converted, err := e.Convert(ctx, project, "")
 	if err != nil {
 		return err
 	}
@@ -69,7 +72,11 @@ func (e ecsLocalSimulation) Up(ctx context.Context, project *types.Project, deta
 	return cmd.Run()
 }
 
-func (e ecsLocalSimulation) Convert(ctx context.Context, project *types.Project) ([]byte, error) {
+func (e ecsLocalSimulation) Convert(ctx context.Context, project *types.Project, format string) ([]byte, error) {
+	if format != "" && format != "yaml" {
+		return nil, errors.Wrapf(errdefs.ErrParsingFailed, "unsupported format %q, ECS simulation mode converts to a compose yaml file", format)
+	}
+
 	project.Networks["credentials_network"] = types.NetworkConfig{
 		Driver: "bridge",
 		Ipam: types.IPAMConfig{
@@ -152,7 +159,7 @@ services:
 	return cmd.Run()
 }
 
-func (e ecsLocalSimulation) Logs(ctx context.Context, projectName string, w io.Writer) error {
+func (e ecsLocalSimulation) Logs(ctx context.Context, projectName string, w io.Writer, opts compose.LogOptions) error {
 	list, err := e.moby.ContainerList(ctx, types2.ContainerListOptions{
 		Filters: filters.NewArgs(filters.Arg("label", "com.docker.compose.project="+projectName)),
 	})
@@ -172,7 +179,17 @@ func (e ecsLocalSimulation) Logs(ctx context.Context, projectName string, w io.W
 	if err != nil {
 		return err
 	}
-	cmd := exec.Command("docker-compose", "--context", "default", "--project-name", projectName, "-f", "-", "logs", "-f")
+	args := []string{"--context", "default", "--project-name", projectName, "-f", "-", "logs"}
+	if opts.Follow {
+		args = append(args, "--follow")
+	}
+	if opts.Tail >= 0 {
+		args = append(args, "--tail", strconv.Itoa(opts.Tail))
+	}
+	if opts.Timestamps {
+		args = append(args, "--timestamps")
+	}
+	cmd := exec.Command("docker-compose", args...)
 	cmd.Stdin = strings.NewReader(string(marshal))
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -185,3 +202,174 @@ func (e ecsLocalSimulation) Ps(ctx context.Context, projectName string) ([]compo
 func (e ecsLocalSimulation) List(ctx context.Context, projectName string) ([]compose.Stack, error) {
 	return nil, errors.Wrap(errdefs.ErrNotImplemented, "use docker-compose ls")
 }
+func (e ecsLocalSimulation) RunOneOffContainer(ctx context.Context, project *types.Project, opts compose.RunOptions) (int, error) {
+	return 0, errors.Wrap(errdefs.ErrNotImplemented, "use docker-compose run")
+}
+
+func (e ecsLocalSimulation) Exec(ctx context.Context, projectName string, opts compose.ExecOptions) error {
+	return errors.Wrap(errdefs.ErrNotImplemented, "use docker-compose exec")
+}
+
+func (e ecsLocalSimulation) PortForward(ctx context.Context, projectName string, opts compose.PortForwardOptions) error {
+	return errors.Wrap(errdefs.ErrNotImplemented, "publish ports with docker-compose")
+}
+
+func (e ecsLocalSimulation) Stop(ctx context.Context, projectName string) error {
+	list, err := e.moby.ContainerList(ctx, types2.ContainerListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", "com.docker.compose.project="+projectName)),
+	})
+	if err != nil {
+		return err
+	}
+	for _, c := range list {
+		err := e.moby.ContainerStop(ctx, c.ID, nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e ecsLocalSimulation) Start(ctx context.Context, projectName string) error {
+	list, err := e.moby.ContainerList(ctx, types2.ContainerListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", "com.docker.compose.project="+projectName)),
+	})
+	if err != nil {
+		return err
+	}
+	for _, c := range list {
+		err := e.moby.ContainerStart(ctx, c.ID, types2.ContainerStartOptions{})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e ecsLocalSimulation) Restart(ctx context.Context, projectName string, services []string) error {
+	list, err := e.moby.ContainerList(ctx, types2.ContainerListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", "com.docker.compose.project="+projectName)),
+	})
+	if err != nil {
+		return err
+	}
+	for _, c := range list {
+		if len(services) > 0 && !utils.StringContains(services, c.Labels["com.docker.compose.service"]) {
+			continue
+		}
+		err := e.moby.ContainerRestart(ctx, c.ID, nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e ecsLocalSimulation) Kill(ctx context.Context, projectName string, opts compose.KillOptions) error {
+	list, err := e.moby.ContainerList(ctx, types2.ContainerListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", "com.docker.compose.project="+projectName)),
+	})
+	if err != nil {
+		return err
+	}
+	for _, c := range list {
+		if len(opts.Services) > 0 && !utils.StringContains(opts.Services, c.Labels["com.docker.compose.service"]) {
+			continue
+		}
+		err := e.moby.ContainerKill(ctx, c.ID, opts.Signal)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e ecsLocalSimulation) Scale(ctx context.Context, projectName string, replicas map[string]int) error {
+	return errors.Wrap(errdefs.ErrNotImplemented, "use docker compose up --scale with local simulation")
+}
+
+func (e ecsLocalSimulation) Top(ctx context.Context, projectName string, services []string) ([]compose.ContainerProcSummary, error) {
+	list, err := e.moby.ContainerList(ctx, types2.ContainerListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", "com.docker.compose.project="+projectName)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	summaries := []compose.ContainerProcSummary{}
+	for _, c := range list {
+		service := c.Labels["com.docker.compose.service"]
+		if len(services) > 0 && !utils.StringContains(services, service) {
+			continue
+		}
+		top, err := e.moby.ContainerTop(ctx, c.ID, nil)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, compose.ContainerProcSummary{
+			ID:        c.ID,
+			Service:   service,
+			Titles:    top.Titles,
+			Processes: top.Processes,
+		})
+	}
+	return summaries, nil
+}
+
+func (e ecsLocalSimulation) Images(ctx context.Context, projectName string) ([]compose.ImageSummary, error) {
+	list, err := e.moby.ContainerList(ctx, types2.ContainerListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", "com.docker.compose.project="+projectName)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	summaries := []compose.ImageSummary{}
+	for _, c := range list {
+		inspect, _, err := e.moby.ImageInspectWithRaw(ctx, c.ImageID)
+		if err != nil {
+			return nil, err
+		}
+		digest := ""
+		if len(inspect.RepoDigests) > 0 {
+			digest = inspect.RepoDigests[0][strings.Index(inspect.RepoDigests[0], "@")+1:]
+		}
+		summaries = append(summaries, compose.ImageSummary{
+			ID:       c.ID,
+			Service:  c.Labels["com.docker.compose.service"],
+			Image:    c.Image,
+			Digest:   digest,
+			Platform: inspect.Os + "/" + inspect.Architecture,
+		})
+	}
+	return summaries, nil
+}
+
+func (e ecsLocalSimulation) Events(ctx context.Context, projectName string, consumer func(compose.Event)) error {
+	messages, errs := e.moby.Events(ctx, types2.EventsOptions{
+		Filters: filters.NewArgs(filters.Arg("label", "com.docker.compose.project="+projectName)),
+	})
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-errs:
+			if err == io.EOF || ctx.Err() != nil {
+				return nil
+			}
+			return err
+		case m := <-messages:
+			consumer(compose.Event{
+				Timestamp:  time.Unix(0, m.TimeNano),
+				Type:       m.Type,
+				Action:     m.Action,
+				ID:         m.Actor.ID,
+				Service:    m.Actor.Attributes["com.docker.compose.service"],
+				Attributes: m.Actor.Attributes,
+			})
+		}
+	}
+}
+
+func (e ecsLocalSimulation) Cost(ctx context.Context, project *types.Project) ([]compose.CostEstimate, error) {
+	return nil, errors.Wrap(errdefs.ErrNotImplemented, "local simulation has no cost")
+}
@@ -23,19 +23,23 @@ import (
 	"io"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/docker/compose-cli/api/compose"
 )
 
-func (b *ecsAPIService) Logs(ctx context.Context, project string, w io.Writer) error {
+func (b *ecsAPIService) Logs(ctx context.Context, project string, w io.Writer, opts compose.LogOptions) error {
 	consumer := logConsumer{
-		colors: map[string]colorFunc{},
-		width:  0,
-		writer: w,
+		colors:     map[string]colorFunc{},
+		width:      0,
+		writer:     w,
+		timestamps: opts.Timestamps,
 	}
-	err := b.aws.GetLogs(ctx, project, consumer.Log)
+	err := b.aws.GetLogs(ctx, project, consumer.Log, opts)
 	return err
 }
 
-func (l *logConsumer) Log(service, container, message string) {
+func (l *logConsumer) Log(service, container, message string, timestamp time.Time) {
 	cf, ok := l.colors[service]
 	if !ok {
 		cf = <-loop
@@ -43,6 +47,9 @@ func (l *logConsumer) Log(service, container, message string) {
 		l.computeWidth()
 	}
 	prefix := fmt.Sprintf("%-"+strconv.Itoa(l.width)+"s |", service)
+	if l.timestamps {
+		prefix = fmt.Sprintf("%s %s", prefix, timestamp.UTC().Format(time.RFC3339Nano))
+	}
 
 	for _, line := range strings.Split(message, "\n") {
 		buf := bytes.NewBufferString(fmt.Sprintf("%s %s\n", cf(prefix), line))
@@ -61,7 +68,8 @@ func (l *logConsumer) computeWidth() {
 }
 
 type logConsumer struct {
-	colors map[string]colorFunc
-	width  int
-	writer io.Writer
+	colors     map[string]colorFunc
+	width      int
+	writer     io.Writer
+	timestamps bool
 }
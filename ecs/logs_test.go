@@ -0,0 +1,143 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func TestLogConsumerTimestamps(t *testing.T) {
+	var out bytes.Buffer
+	consumer := logConsumer{
+		colors:     map[string]colorFunc{"web": func(s string) string { return s }},
+		width:      6,
+		writer:     &out,
+		timestamps: true,
+	}
+	consumer.Log("web", "0123456789abcdef", "hello\nworld", time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, out.String(), ""+
+		"web    | 2020-10-01T12:00:00Z hello\n"+
+		"web    | 2020-10-01T12:00:00Z world\n")
+}
+
+type tailLogsClient struct {
+	cloudwatchlogsiface.CloudWatchLogsAPI
+	streams []*cloudwatchlogs.LogStream
+	events  map[string][]*cloudwatchlogs.OutputLogEvent
+}
+
+func (c tailLogsClient) DescribeLogStreamsWithContext(ctx aws.Context, input *cloudwatchlogs.DescribeLogStreamsInput, opts ...request.Option) (*cloudwatchlogs.DescribeLogStreamsOutput, error) {
+	return &cloudwatchlogs.DescribeLogStreamsOutput{LogStreams: c.streams}, nil
+}
+
+func (c tailLogsClient) GetLogEventsWithContext(ctx aws.Context, input *cloudwatchlogs.GetLogEventsInput, opts ...request.Option) (*cloudwatchlogs.GetLogEventsOutput, error) {
+	events := c.events[aws.StringValue(input.LogStreamName)]
+	if limit := int(aws.Int64Value(input.Limit)); len(events) > limit {
+		events = events[len(events)-limit:]
+	}
+	return &cloudwatchlogs.GetLogEventsOutput{Events: events}, nil
+}
+
+func TestGetLogsTail(t *testing.T) {
+	event := func(message string, timestamp int64) *cloudwatchlogs.OutputLogEvent {
+		return &cloudwatchlogs.OutputLogEvent{Message: aws.String(message), Timestamp: aws.Int64(timestamp)}
+	}
+	s := sdk{CW: tailLogsClient{
+		streams: []*cloudwatchlogs.LogStream{
+			{LogStreamName: aws.String("test/web/1234"), LastEventTimestamp: aws.Int64(40)},
+			{LogStreamName: aws.String("test/db/5678"), LastEventTimestamp: aws.Int64(30)},
+			{LogStreamName: aws.String("test/web/0000"), LastEventTimestamp: aws.Int64(5)},
+		},
+		events: map[string][]*cloudwatchlogs.OutputLogEvent{
+			"test/web/1234": {event("web 1", 10), event("web 2", 20), event("web 3", 40)},
+			"test/db/5678":  {event("db 1", 15), event("db 2", 30)},
+			"test/web/0000": {event("old", 5)},
+		},
+	}}
+
+	var got []string
+	err := s.GetLogs(context.Background(), "test", func(service, container, message string, timestamp time.Time) {
+		got = append(got, service+" "+container+" "+message)
+	}, compose.LogOptions{Tail: 2, Since: time.Unix(0, 10*int64(time.Millisecond))})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, got, []string{
+		"db 5678 db 1",
+		"web 1234 web 2",
+		"db 5678 db 2",
+		"web 1234 web 3",
+	})
+}
+
+type followLogsClient struct {
+	cloudwatchlogsiface.CloudWatchLogsAPI
+	polls      [][]*cloudwatchlogs.FilteredLogEvent
+	startTimes []int64
+	cancel     func()
+}
+
+func (c *followLogsClient) FilterLogEventsWithContext(ctx aws.Context, input *cloudwatchlogs.FilterLogEventsInput, opts ...request.Option) (*cloudwatchlogs.FilterLogEventsOutput, error) {
+	c.startTimes = append(c.startTimes, aws.Int64Value(input.StartTime))
+	if len(c.polls) == 0 {
+		c.cancel()
+		return nil, ctx.Err()
+	}
+	events := c.polls[0]
+	c.polls = c.polls[1:]
+	return &cloudwatchlogs.FilterLogEventsOutput{Events: events}, nil
+}
+
+func TestGetLogsFollowLateEvent(t *testing.T) {
+	event := func(id string, message string, timestamp int64) *cloudwatchlogs.FilteredLogEvent {
+		return &cloudwatchlogs.FilteredLogEvent{
+			EventId:       aws.String(id),
+			LogStreamName: aws.String("test/web/1234"),
+			Message:       aws.String(message),
+			Timestamp:     aws.Int64(timestamp),
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := &followLogsClient{
+		polls: [][]*cloudwatchlogs.FilteredLogEvent{
+			{event("1", "first", 100000)},
+			// "late" has an older timestamp than "first", but was ingested after it
+			{event("1", "first", 100000), event("2", "late", 90000)},
+			{event("1", "first", 100000), event("2", "late", 90000), event("3", "next", 101000)},
+		},
+		cancel: cancel,
+	}
+	s := sdk{CW: client}
+
+	var got []string
+	err := s.GetLogs(ctx, "test", func(service, container, message string, timestamp time.Time) {
+		got = append(got, message)
+	}, compose.LogOptions{Follow: true, Tail: -1, Since: time.Unix(0, 1000*int64(time.Millisecond))})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, got, []string{"first", "late", "next"})
+	assert.DeepEqual(t, client.startTimes, []int64{1000, 100000 - logsIngestionDelay, 100000 - logsIngestionDelay, 101000 - logsIngestionDelay})
+}
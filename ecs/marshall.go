@@ -48,6 +48,7 @@ func marshall(template *cloudformation.Template) ([]byte, error) {
 							}
 						}
 					}
+					mergeExtraProperties(resource)
 				}
 			}
 		}
@@ -59,3 +60,65 @@ func marshall(template *cloudformation.Template) ([]byte, error) {
 	}
 	return raw, err
 }
+
+// extraPropertiesMetadata is the metadata key for resource properties goformation doesn't support yet
+const extraPropertiesMetadata = "ComposeExtraProperties"
+
+// extraProperties returns resource metadata holding properties which marshall merges into resource properties
+func extraProperties(properties map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		extraPropertiesMetadata: properties,
+	}
+}
+
+func mergeExtraProperties(resource map[string]interface{}) {
+	metadata, ok := resource["Metadata"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	extra, ok := metadata[extraPropertiesMetadata].(map[string]interface{})
+	if !ok {
+		return
+	}
+	properties, ok := resource["Properties"].(map[string]interface{})
+	if !ok {
+		properties = map[string]interface{}{}
+		resource["Properties"] = properties
+	}
+	mergeProperties(properties, extra)
+	delete(metadata, extraPropertiesMetadata)
+	if len(metadata) == 0 {
+		delete(resource, "Metadata")
+	}
+}
+
+func mergeProperties(properties map[string]interface{}, extra map[string]interface{}) {
+	for k, v := range extra {
+		nested, ok := v.(map[string]interface{})
+		if current, isMap := properties[k].(map[string]interface{}); ok && isMap {
+			mergeProperties(current, nested)
+			continue
+		}
+		// lists of the same length are merged item by item, null items leaving the current ones unchanged
+		items, ok := v.([]interface{})
+		if current, isList := properties[k].([]interface{}); ok && isList && len(current) == len(items) {
+			mergeItems(current, items)
+			continue
+		}
+		properties[k] = v
+	}
+}
+
+func mergeItems(current []interface{}, extra []interface{}) {
+	for i, item := range extra {
+		if item == nil {
+			continue
+		}
+		nested, ok := item.(map[string]interface{})
+		if properties, isMap := current[i].(map[string]interface{}); ok && isMap {
+			mergeProperties(properties, nested)
+			continue
+		}
+		current[i] = item
+	}
+}
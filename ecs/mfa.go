@@ -0,0 +1,55 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"fmt"
+
+	"github.com/AlecAivazis/survey/v2/terminal"
+
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/prompt"
+)
+
+// loadMFASerial returns the MFA device serial required to assume the role configured for profile, if any
+func loadMFASerial(configFile string, profile string) (string, error) {
+	section, err := loadProfileSection(configFile, configSectionName(profile))
+	if err != nil || section == nil {
+		return "", err
+	}
+	if !section.HasKey("role_arn") {
+		return "", nil
+	}
+	return section.Key("mfa_serial").String(), nil
+}
+
+// mfaTokenProvider prompts user for the MFA token code required to assume a role
+func mfaTokenProvider(ui prompt.UI, serial string) func() (string, error) {
+	return func() (string, error) {
+		code, err := ui.Input(fmt.Sprintf("MFA token code for %s", serial), "")
+		if err != nil {
+			if err == terminal.InterruptErr {
+				return "", errdefs.ErrCanceled
+			}
+			return "", err
+		}
+		if code == "" {
+			return "", fmt.Errorf("MFA token code cannot be empty")
+		}
+		return code, nil
+	}
+}
@@ -0,0 +1,175 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// notificationsConfig is the x-aws-notifications project configuration, to publish deployment events
+type notificationsConfig struct {
+	// SNS is the ARN of the topic events are published to
+	SNS string `json:"sns,omitempty"`
+	// Webhook is the URL events are posted to, as JSON compatible with Slack incoming webhooks
+	Webhook string `json:"webhook,omitempty"`
+}
+
+const (
+	deploymentStarted   = "started"
+	deploymentSubmitted = "submitted"
+	deploymentSucceeded = "succeeded"
+	deploymentFailed    = "failed"
+	deploymentCanceled  = "canceled"
+)
+
+// deploymentEvent is a deployment state change published to notification targets
+type deploymentEvent struct {
+	// Text is a human readable summary, as displayed by Slack
+	Text     string   `json:"text"`
+	Project  string   `json:"project"`
+	Stack    string   `json:"stack"`
+	Region   string   `json:"region"`
+	Status   string   `json:"status"`
+	Services []string `json:"services"`
+	// Duration is the deployment duration in seconds, once it completed
+	Duration float64 `json:"duration,omitempty"`
+	Error    string  `json:"error,omitempty"`
+}
+
+func getNotificationsConfig(project *types.Project) (*notificationsConfig, error) {
+	v, ok := project.Extensions[extensionNotifications]
+	if !ok {
+		return nil, nil
+	}
+	marshalled, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	config := notificationsConfig{}
+	err = json.Unmarshal(marshalled, &config)
+	if err != nil {
+		return nil, fmt.Errorf("%s must set an sns topic or a webhook: %w", extensionNotifications, err)
+	}
+	if config.SNS == "" && config.Webhook == "" {
+		return nil, fmt.Errorf("%s must set an sns topic or a webhook", extensionNotifications)
+	}
+	return &config, nil
+}
+
+// notifyDeployment publishes deployment events around up, so that teams get notified of deployments run by CI
+func (b *ecsAPIService) notifyDeployment(ctx context.Context, project *types.Project, detach bool, up func() error) error {
+	config, err := getNotificationsConfig(project)
+	if err != nil {
+		return err
+	}
+	if config == nil || isDryRun(ctx) {
+		return up()
+	}
+
+	services := getServices(ctx)
+	if len(services) == 0 {
+		for _, service := range project.Services {
+			services = append(services, service.Name)
+		}
+	}
+	event := deploymentEvent{
+		Project:  project.Name,
+		Stack:    project.Name,
+		Region:   b.Region,
+		Status:   deploymentStarted,
+		Services: services,
+	}
+	b.publishDeploymentEvent(ctx, config, event)
+
+	start := time.Now()
+	err = up()
+	event.Duration = time.Since(start).Round(time.Second).Seconds()
+	switch {
+	case errdefs.IsErrCanceled(err):
+		event.Status = deploymentCanceled
+	case err != nil:
+		event.Status = deploymentFailed
+		event.Error = err.Error()
+	case detach:
+		event.Status = deploymentSubmitted
+	default:
+		event.Status = deploymentSucceeded
+	}
+	b.publishDeploymentEvent(ctx, config, event)
+	return err
+}
+
+// publishDeploymentEvent sends event to notification targets, only warning on failure as notifications must not
+// break deployments
+func (b *ecsAPIService) publishDeploymentEvent(ctx context.Context, config *notificationsConfig, event deploymentEvent) {
+	event.Text = fmt.Sprintf("Deployment of %s in %s %s", event.Project, event.Region, event.Status)
+	if event.Duration > 0 {
+		event.Text += fmt.Sprintf(" after %s", time.Duration(event.Duration)*time.Second)
+	}
+	if event.Error != "" {
+		event.Text += ": " + event.Error
+	}
+	message, err := json.Marshal(event)
+	if err != nil {
+		logrus.Warnf("failed to publish deployment notification: %s", err)
+		return
+	}
+	if config.SNS != "" {
+		// SNS subjects are limited to 100 characters
+		subject := fmt.Sprintf("Deployment of %s %s", event.Project, event.Status)
+		if len(subject) > 100 {
+			subject = subject[:100]
+		}
+		if err := b.aws.PublishNotification(ctx, config.SNS, subject, string(message)); err != nil {
+			logrus.Warnf("failed to publish deployment notification to %s: %s", config.SNS, err)
+		}
+	}
+	if config.Webhook != "" {
+		if err := postWebhook(ctx, config.Webhook, message); err != nil {
+			logrus.Warnf("failed to post deployment notification to webhook: %s", err)
+		}
+	}
+}
+
+func postWebhook(ctx context.Context, url string, message []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(message))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() // nolint:errcheck
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded %s", resp.Status)
+	}
+	return nil
+}
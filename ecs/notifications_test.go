@@ -0,0 +1,88 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func TestNotificationsConfig(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  test:
+    image: nginx
+x-aws-notifications:
+  sns: arn:aws:sns:eu-west-3:012345678910:deployments
+`)
+	config, err := getNotificationsConfig(project)
+	assert.NilError(t, err)
+	assert.Equal(t, config.SNS, "arn:aws:sns:eu-west-3:012345678910:deployments")
+
+	project = loadConfig(t, `
+services:
+  test:
+    image: nginx
+x-aws-notifications: {}
+`)
+	_, err = getNotificationsConfig(project)
+	assert.ErrorContains(t, err, "x-aws-notifications must set an sns topic or a webhook")
+}
+
+func TestNotifyDeployment(t *testing.T) {
+	var events []deploymentEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event deploymentEvent
+		assert.NilError(t, json.NewDecoder(r.Body).Decode(&event))
+		events = append(events, event)
+	}))
+	defer server.Close()
+
+	project := loadConfig(t, fmt.Sprintf(`
+services:
+  web:
+    image: nginx
+x-aws-notifications:
+  sns: arn:aws:sns:eu-west-3:012345678910:deployments
+  webhook: %s
+`, server.URL))
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+	m.EXPECT().PublishNotification(gomock.Any(), "arn:aws:sns:eu-west-3:012345678910:deployments", "Deployment of TestNotifyDeployment started", gomock.Any()).Return(nil)
+	m.EXPECT().PublishNotification(gomock.Any(), "arn:aws:sns:eu-west-3:012345678910:deployments", "Deployment of TestNotifyDeployment failed", gomock.Any()).Return(fmt.Errorf("not authorized"))
+
+	backend := &ecsAPIService{aws: m, Region: "eu-west-3"}
+	err := backend.notifyDeployment(context.TODO(), project, false, func() error {
+		return fmt.Errorf("stack rolled back")
+	})
+	assert.ErrorContains(t, err, "stack rolled back")
+
+	assert.Equal(t, len(events), 2)
+	assert.Equal(t, events[0].Status, deploymentStarted)
+	assert.DeepEqual(t, events[0].Services, []string{"web"})
+	assert.Equal(t, events[1].Status, deploymentFailed)
+	assert.Equal(t, events[1].Error, "stack rolled back")
+	assert.Equal(t, events[1].Text, "Deployment of TestNotifyDeployment in eu-west-3 failed: stack rolled back")
+}
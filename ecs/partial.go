@@ -0,0 +1,206 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+type servicesKey struct{}
+
+// WithServices makes Up only deploy or update named services and their dependencies, leaving other services as
+// currently deployed
+func WithServices(ctx context.Context, services []string) context.Context {
+	return context.WithValue(ctx, servicesKey{}, services)
+}
+
+func getServices(ctx context.Context) []string {
+	services, _ := ctx.Value(servicesKey{}).([]string)
+	return services
+}
+
+// isServiceSelected tells if service is deployed by Up, as all services are unless some are set by WithServices
+func isServiceSelected(ctx context.Context, service string) bool {
+	services := getServices(ctx)
+	return len(services) == 0 || contains(services, service)
+}
+
+// selectServices returns services set by WithServices and the services they depend on, or nil to deploy all services
+func selectServices(ctx context.Context, project *types.Project) ([]string, error) {
+	names := getServices(ctx)
+	if len(names) == 0 {
+		return nil, nil
+	}
+	selected := map[string]bool{}
+	var visit func(name string) error
+	visit = func(name string) error {
+		if selected[name] {
+			return nil
+		}
+		service, err := project.GetService(name)
+		if err != nil {
+			return errors.Wrapf(errdefs.ErrNotFound, "no such service: %s", name)
+		}
+		selected[name] = true
+		var dependencies []string
+		for dependency := range service.DependsOn {
+			dependencies = append(dependencies, dependency)
+		}
+		for _, link := range service.Links {
+			dependencies = append(dependencies, strings.SplitN(link, ":", 2)[0])
+		}
+		for _, dependency := range dependencies {
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+	var services []string
+	for name := range selected {
+		services = append(services, name)
+	}
+	sort.Strings(services)
+	return services, nil
+}
+
+// marshallTemplate marshalls template. When services are set by WithServices, resources of other services are set as
+// in the previous stack template, so they're left unchanged.
+func marshallTemplate(ctx context.Context, project *types.Project, template *cloudformation.Template, previous []byte) ([]byte, error) {
+	marshalled, err := marshall(template)
+	if err != nil {
+		return nil, err
+	}
+	services := getServices(ctx)
+	if len(services) == 0 {
+		return marshalled, nil
+	}
+	return partialTemplate(project, services, previous, marshalled)
+}
+
+// sharedResourceTypes are resources types used by all services, even when named after one
+var sharedResourceTypes = []string{
+	"AWS::EC2::",
+	"AWS::EFS::",
+	"AWS::Logs::",
+	"AWS::ECS::Cluster",
+	"AWS::ElasticLoadBalancingV2::LoadBalancer",
+}
+
+// partialTemplate merges next template with previous one, keeping previous resources of services which are not
+// selected. previous is nil when stack doesn't exist yet, then only selected services are created.
+func partialTemplate(project *types.Project, selected []string, previous, next []byte) ([]byte, error) {
+	var before, after map[string]interface{}
+	if previous != nil {
+		if err := json.Unmarshal(previous, &before); err != nil {
+			return nil, errors.Wrap(err, "invalid stack template")
+		}
+	}
+	if err := json.Unmarshal(next, &after); err != nil {
+		return nil, err
+	}
+	beforeResources, _ := before["Resources"].(map[string]interface{})
+	afterResources, _ := after["Resources"].(map[string]interface{})
+
+	// services removed from compose file but still deployed are also left unchanged
+	var services []string
+	for _, service := range project.Services {
+		services = append(services, service.Name)
+	}
+	for _, resource := range beforeResources {
+		if name := deployedServiceName(resource); name != "" && !contains(services, name) {
+			services = append(services, name)
+		}
+	}
+
+	resources := map[string]interface{}{}
+	for name, resource := range afterResources {
+		owner := resourceService(services, name, resource)
+		if owner == "" || contains(selected, owner) {
+			resources[name] = resource
+		}
+	}
+	for name, resource := range beforeResources {
+		owner := resourceService(services, name, resource)
+		if owner != "" && !contains(selected, owner) {
+			resources[name] = resource
+		}
+	}
+	after["Resources"] = resources
+	return json.MarshalIndent(after, "", "  ")
+}
+
+// resourceService returns the service a resource has been created for, based on its logical ID being prefixed by the
+// service name, or an empty string for resources shared by services
+func resourceService(services []string, logicalID string, resource interface{}) string {
+	if r, ok := resource.(map[string]interface{}); ok {
+		kind, _ := r["Type"].(string)
+		for _, shared := range sharedResourceTypes {
+			if strings.HasPrefix(kind, shared) {
+				return ""
+			}
+		}
+	}
+	owner := ""
+	for _, service := range services {
+		prefix := normalizeResourceName(service)
+		if len(prefix) <= len(normalizeResourceName(owner)) || !strings.HasPrefix(logicalID, prefix) {
+			continue
+		}
+		// service "web" doesn't own resources of service "webapp"
+		suffix := []rune(strings.TrimPrefix(logicalID, prefix))
+		if len(suffix) > 0 && unicode.IsLower(suffix[0]) {
+			continue
+		}
+		owner = service
+	}
+	return owner
+}
+
+// deployedServiceName returns the compose service name an ECS service resource is tagged with
+func deployedServiceName(resource interface{}) string {
+	r, ok := resource.(map[string]interface{})
+	if !ok || r["Type"] != "AWS::ECS::Service" {
+		return ""
+	}
+	properties, _ := r["Properties"].(map[string]interface{})
+	tags, _ := properties["Tags"].([]interface{})
+	for _, t := range tags {
+		tag, _ := t.(map[string]interface{})
+		if tag["Key"] == compose.ServiceTag {
+			name, _ := tag["Value"].(string)
+			return name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,121 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestSelectServices(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			{Name: "front", DependsOn: types.DependsOnConfig{"back": {Condition: types.ServiceConditionHealthy}}},
+			{Name: "back", Links: []string{"db:database"}},
+			{Name: "db"},
+			{Name: "worker"},
+		},
+	}
+	selected, err := selectServices(context.Background(), project)
+	assert.NilError(t, err)
+	assert.Check(t, selected == nil)
+
+	selected, err = selectServices(WithServices(context.Background(), []string{"front"}), project)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, selected, []string{"back", "db", "front"})
+
+	_, err = selectServices(WithServices(context.Background(), []string{"unknown"}), project)
+	assert.Check(t, errdefs.IsNotFoundError(err))
+}
+
+func TestResourceService(t *testing.T) {
+	services := []string{"web", "webapp", "data"}
+	assert.Equal(t, resourceService(services, "WebService", nil), "web")
+	assert.Equal(t, resourceService(services, "WebappTaskDefinition", nil), "webapp")
+	assert.Equal(t, resourceService(services, "WebTCP80TargetGroupGreen", nil), "web")
+	assert.Equal(t, resourceService(services, "LogGroup", nil), "")
+	assert.Equal(t, resourceService(services, "DataFilesystem", map[string]interface{}{"Type": "AWS::EFS::FileSystem"}), "")
+}
+
+func TestPartialTemplate(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			{Name: "web"},
+			{Name: "db"},
+		},
+	}
+	resource := func(kind, version string) map[string]interface{} {
+		return map[string]interface{}{"Type": kind, "Properties": map[string]interface{}{"Version": version}}
+	}
+	removedService := map[string]interface{}{
+		"Type": "AWS::ECS::Service",
+		"Properties": map[string]interface{}{
+			"Tags": []interface{}{map[string]interface{}{"Key": "com.docker.compose.service", "Value": "old"}},
+		},
+	}
+	previous, err := json.Marshal(map[string]interface{}{
+		"Resources": map[string]interface{}{
+			"Cluster":          resource("AWS::ECS::Cluster", "1"),
+			"WebService":       resource("AWS::ECS::Service", "1"),
+			"DbService":        resource("AWS::ECS::Service", "1"),
+			"DbTaskDefinition": resource("AWS::ECS::TaskDefinition", "1"),
+			"OldService":       removedService,
+		},
+	})
+	assert.NilError(t, err)
+	next, err := json.Marshal(map[string]interface{}{
+		"Resources": map[string]interface{}{
+			"Cluster":          resource("AWS::ECS::Cluster", "2"),
+			"WebService":       resource("AWS::ECS::Service", "2"),
+			"WebTaskRole":      resource("AWS::IAM::Role", "2"),
+			"DbService":        resource("AWS::ECS::Service", "2"),
+			"DbTaskDefinition": resource("AWS::ECS::TaskDefinition", "2"),
+			"DbTaskRole":       resource("AWS::IAM::Role", "2"),
+		},
+	})
+	assert.NilError(t, err)
+
+	merged, err := partialTemplate(project, []string{"web"}, previous, next)
+	assert.NilError(t, err)
+	var template map[string]interface{}
+	assert.NilError(t, json.Unmarshal(merged, &template))
+	versions := map[string]interface{}{}
+	for name, r := range template["Resources"].(map[string]interface{}) {
+		properties, _ := r.(map[string]interface{})["Properties"].(map[string]interface{})
+		versions[name] = properties["Version"]
+	}
+	assert.DeepEqual(t, versions, map[string]interface{}{
+		"Cluster":          "2",
+		"WebService":       "2",
+		"WebTaskRole":      "2",
+		"DbService":        "1",
+		"DbTaskDefinition": "1",
+		"OldService":       nil,
+	})
+
+	created, err := partialTemplate(project, []string{"web"}, nil, next)
+	assert.NilError(t, err)
+	template = map[string]interface{}{}
+	assert.NilError(t, json.Unmarshal(created, &template))
+	assert.Equal(t, len(template["Resources"].(map[string]interface{})), 3)
+}
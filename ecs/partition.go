@@ -0,0 +1,81 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// partitionOf returns the AWS partition region belongs to, i.e. standard, GovCloud (us-gov-*) or China (cn-*)
+func partitionOf(region string) endpoints.Partition {
+	if p, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), region); ok {
+		return p
+	}
+	return endpoints.AwsPartition()
+}
+
+// consoleURL returns the AWS management console URL for region
+func consoleURL(region string) string {
+	switch partitionOf(region).ID() {
+	case endpoints.AwsUsGovPartitionID:
+		return "https://console.amazonaws-us-gov.com"
+	case endpoints.AwsCnPartitionID:
+		return "https://console.amazonaws.cn"
+	default:
+		return fmt.Sprintf("https://%s.console.aws.amazon.com", region)
+	}
+}
+
+// requiredServices lists the AWS services the CloudFormation template for project relies on
+func requiredServices(project *types.Project) []string {
+	services := []string{
+		endpoints.CloudformationServiceID,
+		endpoints.EcsServiceID,
+		endpoints.ElasticloadbalancingServiceID,
+		endpoints.LogsServiceID,
+		endpoints.ServicediscoveryServiceID,
+	}
+	if len(project.Volumes) > 0 {
+		services = append(services, endpoints.ElasticfilesystemServiceID)
+	}
+	return services
+}
+
+// checkServicesAvailability reports services which are not offered in region. Regions unknown to the SDK
+// are not checked, as the SDK endpoints model might just be older than the region
+func checkServicesAvailability(region string, services ...string) error {
+	partition := partitionOf(region)
+	if _, ok := partition.Regions()[region]; !ok {
+		return nil
+	}
+	for _, id := range services {
+		service, ok := partition.Services()[id]
+		if ok {
+			_, ok = service.Regions()[region]
+		}
+		if !ok {
+			return errors.Wrapf(errdefs.ErrNotImplemented, "service %q is not available in region %s (partition %s)", id, region, partition.ID())
+		}
+	}
+	return nil
+}
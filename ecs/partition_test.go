@@ -0,0 +1,48 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestPartitionOf(t *testing.T) {
+	assert.Equal(t, partitionOf("eu-west-3").ID(), endpoints.AwsPartitionID)
+	assert.Equal(t, partitionOf("us-gov-west-1").ID(), endpoints.AwsUsGovPartitionID)
+	assert.Equal(t, partitionOf("cn-north-1").ID(), endpoints.AwsCnPartitionID)
+}
+
+func TestConsoleURL(t *testing.T) {
+	assert.Equal(t, consoleURL("eu-west-3"), "https://eu-west-3.console.aws.amazon.com")
+	assert.Equal(t, consoleURL("us-gov-east-1"), "https://console.amazonaws-us-gov.com")
+	assert.Equal(t, consoleURL("cn-northwest-1"), "https://console.amazonaws.cn")
+}
+
+func TestCheckServicesAvailability(t *testing.T) {
+	assert.NilError(t, checkServicesAvailability("eu-west-3", endpoints.EcsServiceID, endpoints.CloudformationServiceID))
+	// regions unknown to the SDK are not checked
+	assert.NilError(t, checkServicesAvailability("xx-future-1", "unknown"))
+
+	err := checkServicesAvailability("cn-north-1", "unknown")
+	assert.Assert(t, errdefs.IsErrNotImplemented(err))
+	assert.ErrorContains(t, err, `service "unknown" is not available in region cn-north-1 (partition aws-cn)`)
+}
@@ -0,0 +1,113 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+const (
+	platformARM64 = "linux/arm64"
+	platformAMD64 = "linux/amd64"
+)
+
+// servicePlatform returns the normalized platform set by service, linux/amd64 by default
+func servicePlatform(service types.ServiceConfig) (string, error) {
+	switch p := strings.ToLower(service.Platform); p {
+	case "", "linux", platformAMD64:
+		return platformAMD64, nil
+	case platformARM64, "linux/arm64/v8":
+		return platformARM64, nil
+	case "windows", platformWindows:
+		return platformWindows, nil
+	default:
+		return "", errors.Wrapf(errdefs.ErrNotImplemented, "service %q: platform %s is not supported by ECS", service.Name, service.Platform)
+	}
+}
+
+// runtimePlatform returns the task definition RuntimePlatform property for services running on ARM64 or Windows
+func runtimePlatform(service types.ServiceConfig) (map[string]interface{}, error) {
+	platform, err := servicePlatform(service)
+	if err != nil {
+		return nil, err
+	}
+	switch platform {
+	case platformARM64:
+		return map[string]interface{}{
+			"CpuArchitecture":       "ARM64",
+			"OperatingSystemFamily": "LINUX",
+		}, nil
+	case platformWindows:
+		family, err := windowsOSFamily(service)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"CpuArchitecture":       "X86_64",
+			"OperatingSystemFamily": family,
+		}, nil
+	}
+	return nil, nil
+}
+
+func requireARM64(project *types.Project) bool {
+	for _, service := range project.Services {
+		if p, err := servicePlatform(service); err == nil && p == platformARM64 {
+			return true
+		}
+	}
+	return false
+}
+
+// checkImagesPlatform checks images of services running on ARM64 or Windows have a variant for their platform
+func (b *ecsAPIService) checkImagesPlatform(ctx context.Context, project *types.Project) error {
+	for _, service := range project.Services {
+		platform, err := servicePlatform(service)
+		if err != nil {
+			return err
+		}
+		if platform == platformAMD64 || !isServiceSelected(ctx, service.Name) {
+			continue
+		}
+		platforms, err := b.aws.GetImagePlatforms(ctx, service.Image)
+		if err == nil && len(platforms) == 0 {
+			err = fmt.Errorf("image %s doesn't declare its platform", service.Image)
+		}
+		if err != nil {
+			logrus.Warnf("service %q: can't check image %s supports %s: %v", service.Name, service.Image, platform, err)
+			continue
+		}
+		supported := false
+		for _, p := range platforms {
+			if p == platform || strings.HasPrefix(p, platform+"/") {
+				supported = true
+			}
+		}
+		if !supported {
+			return fmt.Errorf("service %q: image %s has no %s variant, available platforms are %s", service.Name, service.Image, platform, strings.Join(platforms, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func TestRuntimePlatformARM64(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  foo:
+    image: hello_world
+    platform: linux/arm64
+  bar:
+    image: hello_world
+`, useDefaultVPC)
+	marshalled, err := marshall(template)
+	assert.NilError(t, err)
+	var parsed struct {
+		Resources map[string]struct {
+			Properties map[string]interface{}
+		}
+	}
+	assert.NilError(t, json.Unmarshal(marshalled, &parsed))
+	assert.DeepEqual(t, parsed.Resources["FooTaskDefinition"].Properties["RuntimePlatform"], map[string]interface{}{
+		"CpuArchitecture":       "ARM64",
+		"OperatingSystemFamily": "LINUX",
+	})
+	_, ok := parsed.Resources["BarTaskDefinition"].Properties["RuntimePlatform"]
+	assert.Check(t, !ok)
+}
+
+func TestUnsupportedPlatform(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  foo:
+    image: hello_world
+    platform: linux/s390x
+`)
+	_, err := servicePlatform(project.Services[0])
+	assert.ErrorContains(t, err, "platform linux/s390x is not supported by ECS")
+}
+
+func TestCheckImagesPlatform(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  foo:
+    image: arm_ready
+    platform: linux/arm64
+  bar:
+    image: amd64_only
+`)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+	m.EXPECT().GetImagePlatforms(gomock.Any(), "arm_ready").Return([]string{"linux/amd64", "linux/arm64/v8"}, nil)
+	backend := &ecsAPIService{aws: m}
+	assert.NilError(t, backend.checkImagesPlatform(context.TODO(), project))
+
+	setImage := func(image string) {
+		for i, service := range project.Services {
+			if service.Name == "foo" {
+				project.Services[i].Image = image
+			}
+		}
+	}
+	setImage("amd64_only")
+	m.EXPECT().GetImagePlatforms(gomock.Any(), "amd64_only").Return([]string{"linux/amd64"}, nil)
+	err := backend.checkImagesPlatform(context.TODO(), project)
+	assert.ErrorContains(t, err, "image amd64_only has no linux/arm64 variant")
+
+	setImage("unknown_platform")
+	m.EXPECT().GetImagePlatforms(gomock.Any(), "unknown_platform").Return(nil, nil)
+	assert.NilError(t, backend.checkImagesPlatform(context.TODO(), project))
+}
@@ -0,0 +1,81 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+// portForwardingDocument is the SSM document forwarding a local port to a port of the session target
+const portForwardingDocument = "AWS-StartPortForwardingSession"
+
+func (b *ecsAPIService) PortForward(ctx context.Context, projectName string, opts compose.PortForwardOptions) error {
+	plugin, err := lookupSessionManagerPlugin()
+	if err != nil {
+		return err
+	}
+	args, err := b.portForwardSessionArgs(ctx, projectName, opts)
+	if err != nil {
+		return err
+	}
+
+	// session manager plugin listens on local port until interrupted
+	cmd := exec.CommandContext(ctx, plugin, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err = cmd.Run()
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// portForwardSessionArgs opens an SSM port forwarding session to a running container of service and returns the
+// session manager plugin arguments to attach to it
+func (b *ecsAPIService) portForwardSessionArgs(ctx context.Context, projectName string, opts compose.PortForwardOptions) ([]string, error) {
+	cluster, task, err := b.serviceTask(ctx, projectName, opts.Service, opts.Index)
+	if err != nil {
+		return nil, err
+	}
+	target, err := sessionTarget(cluster, task, opts.Service)
+	if err != nil {
+		return nil, err
+	}
+	localPort := opts.LocalPort
+	if localPort == 0 {
+		localPort = opts.Port
+	}
+	session, err := b.aws.StartPortForwardingSession(ctx, target, opts.Port, localPort)
+	if err != nil {
+		return nil, errors.Wrapf(err, "can't forward port %d of service %q, check %s is enabled", opts.Port, opts.Service, extensionExec)
+	}
+	return b.sessionManagerArgs(session, map[string]interface{}{
+		"DocumentName": portForwardingDocument,
+		"Parameters": map[string][]string{
+			"portNumber":      {strconv.Itoa(opts.Port)},
+			"localPortNumber": {strconv.Itoa(localPort)},
+		},
+		"Target": target,
+	})
+}
@@ -0,0 +1,71 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func TestPortForwardSessionArgs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+
+	m.EXPECT().GetStackClusterID(gomock.Any(), "test").Return("arn:aws:ecs:us-east-1:012345678910:cluster/cluster", nil)
+	m.EXPECT().ListStackResources(gomock.Any(), "test").Return(stackResources{
+		{LogicalID: "DbService", Type: awsTypeService, ARN: "arn:aws:ecs:us-east-1:012345678910:service/cluster/db"},
+	}, nil)
+	m.EXPECT().GetServiceTasks(gomock.Any(), gomock.Any(), "arn:aws:ecs:us-east-1:012345678910:service/cluster/db", false).Return([]*ecs.Task{
+		{
+			TaskArn: aws.String("arn:aws:ecs:us-east-1:012345678910:task/cluster/0123456789abcdef"),
+			Containers: []*ecs.Container{
+				{Name: aws.String("db"), RuntimeId: aws.String("0123456789abcdef-1234")},
+			},
+		},
+	}, nil)
+	m.EXPECT().StartPortForwardingSession(gomock.Any(), "ecs:cluster_0123456789abcdef_0123456789abcdef-1234", 5432, 15432).Return(execSession{
+		SessionID:  "session",
+		StreamURL:  "wss://ssmmessages.us-east-1.amazonaws.com/v1/data-channel/session",
+		TokenValue: "token",
+		Region:     "us-east-1",
+		Endpoint:   "https://ssm.us-east-1.amazonaws.com",
+	}, nil)
+
+	backend := &ecsAPIService{aws: m}
+	args, err := backend.portForwardSessionArgs(context.TODO(), "test", compose.PortForwardOptions{
+		Service:   "db",
+		Port:      5432,
+		LocalPort: 15432,
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, args, []string{
+		`{"sessionId":"session","streamUrl":"wss://ssmmessages.us-east-1.amazonaws.com/v1/data-channel/session","tokenValue":"token"}`,
+		"us-east-1",
+		"StartSession",
+		"",
+		`{"DocumentName":"AWS-StartPortForwardingSession","Parameters":{"localPortNumber":["15432"],"portNumber":["5432"]},"Target":"ecs:cluster_0123456789abcdef_0123456789abcdef-1234"}`,
+		"https://ssm.us-east-1.amazonaws.com",
+	})
+}
@@ -0,0 +1,168 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// requiredActions are the IAM actions used to deploy, inspect and remove a compose application
+var requiredActions = []string{
+	"cloudformation:CreateChangeSet",
+	"cloudformation:CreateStack",
+	"cloudformation:DeleteStack",
+	"cloudformation:DescribeStackEvents",
+	"cloudformation:DescribeStackResources",
+	"cloudformation:DescribeStacks",
+	"cloudformation:ExecuteChangeSet",
+	"cloudformation:UpdateStack",
+	"cloudformation:ValidateTemplate",
+	"ec2:AuthorizeSecurityGroupIngress",
+	"ec2:CreateSecurityGroup",
+	"ec2:DeleteSecurityGroup",
+	"ec2:DescribeSubnets",
+	"ec2:DescribeVpcAttribute",
+	"ec2:DescribeVpcs",
+	"ecs:CreateCluster",
+	"ecs:CreateService",
+	"ecs:DeleteCluster",
+	"ecs:DeleteService",
+	"ecs:DeregisterTaskDefinition",
+	"ecs:DescribeClusters",
+	"ecs:DescribeServices",
+	"ecs:DescribeTaskDefinition",
+	"ecs:DescribeTasks",
+	"ecs:ListAccountSettings",
+	"ecs:ListTaskDefinitionFamilies",
+	"ecs:ListTaskDefinitions",
+	"ecs:ListTasks",
+	"ecs:RegisterTaskDefinition",
+	"ecs:UpdateService",
+	"elasticfilesystem:CreateAccessPoint",
+	"elasticfilesystem:CreateFileSystem",
+	"elasticfilesystem:CreateMountTarget",
+	"elasticfilesystem:DeleteAccessPoint",
+	"elasticfilesystem:DeleteFileSystem",
+	"elasticfilesystem:DeleteMountTarget",
+	"elasticfilesystem:DescribeFileSystems",
+	"elasticloadbalancing:CreateListener",
+	"elasticloadbalancing:CreateLoadBalancer",
+	"elasticloadbalancing:CreateTargetGroup",
+	"elasticloadbalancing:DeleteListener",
+	"elasticloadbalancing:DeleteLoadBalancer",
+	"elasticloadbalancing:DeleteTargetGroup",
+	"elasticloadbalancing:DescribeLoadBalancers",
+	"iam:AttachRolePolicy",
+	"iam:CreateRole",
+	"iam:DeleteRole",
+	"iam:DeleteRolePolicy",
+	"iam:DetachRolePolicy",
+	"iam:PassRole",
+	"iam:PutRolePolicy",
+	"logs:CreateLogGroup",
+	"logs:DeleteLogGroup",
+	"logs:FilterLogEvents",
+	"servicediscovery:CreatePrivateDnsNamespace",
+	"servicediscovery:CreateService",
+	"servicediscovery:DeleteNamespace",
+	"servicediscovery:DeleteService",
+}
+
+// checkPermissions simulates IAM policies attached to the current identity, and reports actions required by
+// the ECS backend which aren't allowed
+func checkPermissions(ctx context.Context, sess *session.Session, region string) error {
+	identity, err := sts.New(sess, aws.NewConfig().WithRegion(region)).GetCallerIdentityWithContext(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return err
+	}
+	principal, err := principalARN(aws.StringValue(identity.Arn))
+	if err != nil {
+		return err
+	}
+	if principal == "" {
+		logrus.Debugf("skipping permissions check for root account")
+		return nil
+	}
+
+	var denied []string
+	err = iam.New(sess).SimulatePrincipalPolicyPagesWithContext(ctx, &iam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: aws.String(principal),
+		ActionNames:     aws.StringSlice(requiredActions),
+	}, func(output *iam.SimulatePolicyResponse, last bool) bool {
+		denied = append(denied, deniedActions(output.EvaluationResults)...)
+		return true
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to simulate IAM permissions for %s", principal)
+	}
+	if len(denied) > 0 {
+		sort.Strings(denied)
+		return errors.Wrapf(errdefs.ErrForbidden, "%s is missing permissions required to deploy on ECS:\n  %s",
+			principal, strings.Join(denied, "\n  "))
+	}
+	fmt.Printf("%s has all permissions required to deploy on ECS\n", principal)
+	return nil
+}
+
+// principalARN converts a caller identity into the IAM principal policies can be simulated for. Root account
+// isn't subject to IAM policies, so no principal is returned
+func principalARN(identity string) (string, error) {
+	a, err := arn.Parse(identity)
+	if err != nil {
+		return "", err
+	}
+	resource := strings.Split(a.Resource, "/")
+	switch {
+	case a.Resource == "root":
+		return "", nil
+	case a.Service == "iam":
+		return identity, nil
+	case a.Service == "sts" && resource[0] == "assumed-role" && len(resource) > 1:
+		// role path is not part of the assumed role ARN, which is fine for roles created without a custom path
+		return arn.ARN{
+			Partition: a.Partition,
+			Service:   "iam",
+			AccountID: a.AccountID,
+			Resource:  "role/" + resource[1],
+		}.String(), nil
+	default:
+		return "", errors.Wrapf(errdefs.ErrNotImplemented, "permissions can't be checked for %s", identity)
+	}
+}
+
+func deniedActions(results []*iam.EvaluationResult) []string {
+	var denied []string
+	for _, r := range results {
+		if aws.StringValue(r.EvalDecision) != iam.PolicyEvaluationDecisionTypeAllowed {
+			denied = append(denied, aws.StringValue(r.EvalActionName))
+		}
+	}
+	return denied
+}
@@ -0,0 +1,53 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestPrincipalARN(t *testing.T) {
+	principal, err := principalARN("arn:aws:iam::123456789012:user/ci")
+	assert.NilError(t, err)
+	assert.Equal(t, principal, "arn:aws:iam::123456789012:user/ci")
+
+	principal, err = principalARN("arn:aws-us-gov:sts::123456789012:assumed-role/deployer/session")
+	assert.NilError(t, err)
+	assert.Equal(t, principal, "arn:aws-us-gov:iam::123456789012:role/deployer")
+
+	principal, err = principalARN("arn:aws:iam::123456789012:root")
+	assert.NilError(t, err)
+	assert.Equal(t, principal, "")
+
+	_, err = principalARN("arn:aws:sts::123456789012:federated-user/bob")
+	assert.Assert(t, errdefs.IsErrNotImplemented(err))
+}
+
+func TestDeniedActions(t *testing.T) {
+	denied := deniedActions([]*iam.EvaluationResult{
+		{EvalActionName: aws.String("ecs:CreateCluster"), EvalDecision: aws.String(iam.PolicyEvaluationDecisionTypeAllowed)},
+		{EvalActionName: aws.String("iam:PassRole"), EvalDecision: aws.String(iam.PolicyEvaluationDecisionTypeImplicitDeny)},
+		{EvalActionName: aws.String("logs:DeleteLogGroup"), EvalDecision: aws.String(iam.PolicyEvaluationDecisionTypeExplicitDeny)},
+	})
+	assert.DeepEqual(t, denied, []string{"iam:PassRole", "logs:DeleteLogGroup"})
+}
@@ -0,0 +1,95 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/pricing"
+	"github.com/sirupsen/logrus"
+)
+
+// priceListItem is the subset of a Price List API product we rely on
+type priceListItem struct {
+	Product struct {
+		Attributes map[string]string `json:"attributes"`
+	} `json:"product"`
+	Terms struct {
+		OnDemand map[string]struct {
+			PriceDimensions map[string]struct {
+				BeginRange   string            `json:"beginRange"`
+				PricePerUnit map[string]string `json:"pricePerUnit"`
+			} `json:"priceDimensions"`
+		} `json:"OnDemand"`
+	} `json:"terms"`
+}
+
+// GetPrices returns on-demand USD prices of the products of serviceCode matching filters, indexed by usage type.
+// Tiered prices are the first tier's.
+func (s sdk) GetPrices(ctx context.Context, serviceCode string, filters map[string]string) (map[string]float64, error) {
+	input := &pricing.GetProductsInput{
+		ServiceCode: aws.String(serviceCode),
+	}
+	var fields []string
+	for field := range filters {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		input.Filters = append(input.Filters, &pricing.Filter{
+			Field: aws.String(field),
+			Type:  aws.String(pricing.FilterTypeTermMatch),
+			Value: aws.String(filters[field]),
+		})
+	}
+	prices := map[string]float64{}
+	err := s.PR.GetProductsPagesWithContext(ctx, input, func(page *pricing.GetProductsOutput, lastPage bool) bool {
+		for _, product := range page.PriceList {
+			marshalled, err := json.Marshal(product)
+			if err != nil {
+				continue
+			}
+			var item priceListItem
+			if err := json.Unmarshal(marshalled, &item); err != nil {
+				logrus.Debugf("unexpected price list item: %s", err)
+				continue
+			}
+			usageType := item.Product.Attributes["usagetype"]
+			for _, term := range item.Terms.OnDemand {
+				for _, dimension := range term.PriceDimensions {
+					if dimension.BeginRange != "0" {
+						continue
+					}
+					price, err := strconv.ParseFloat(dimension.PricePerUnit["USD"], 64)
+					if err != nil {
+						continue
+					}
+					prices[usageType] = price
+				}
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
@@ -0,0 +1,164 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws/defaults"
+	"github.com/pkg/errors"
+	"gopkg.in/ini.v1"
+
+	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// configSectionName returns the ~/.aws/config section name for profile
+func configSectionName(profile string) string {
+	if profile == "" || profile == "default" {
+		return "default"
+	}
+	return fmt.Sprintf("profile %s", profile)
+}
+
+// credentialsSectionName returns the ~/.aws/credentials section name for profile
+func credentialsSectionName(profile string) string {
+	if profile == "" {
+		return "default"
+	}
+	return profile
+}
+
+// loadProfileSection returns the ini section named section from file, or nil if file or section doesn't exist
+func loadProfileSection(file string, section string) (*ini.Section, error) {
+	f, err := ini.Load(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	s, err := f.GetSection(section)
+	if err != nil {
+		return nil, nil
+	}
+	return s, nil
+}
+
+// loadCredentialProcess returns the external command configured by profile to source credentials, if any
+func loadCredentialProcess(configFile string, credentialsFile string, profile string) (string, error) {
+	// shared credentials file takes precedence over shared config, as it does for the SDK
+	sections := [][2]string{
+		{credentialsFile, credentialsSectionName(profile)},
+		{configFile, configSectionName(profile)},
+	}
+	for _, s := range sections {
+		section, err := loadProfileSection(s[0], s[1])
+		if err != nil {
+			return "", err
+		}
+		if section != nil && section.HasKey("credential_process") {
+			return section.Key("credential_process").String(), nil
+		}
+	}
+	return "", nil
+}
+
+// ExportAWSProfile writes an AWS CLI profile matching ECS context, so that both tools target the same account and region
+func ExportAWSProfile(ecsCtx store.EcsContext, name string) error {
+	return exportAWSProfile(defaults.SharedConfigFilename(), defaults.SharedCredentialsFilename(), ecsCtx, name)
+}
+
+func exportAWSProfile(configFile string, credentialsFile string, ecsCtx store.EcsContext, name string) error {
+	switch {
+	case ecsCtx.CredentialsInKeychain:
+		return errors.Wrap(errdefs.ErrNotImplemented, "credentials stored in keychain can't be exported to an AWS CLI profile")
+	case len(ecsCtx.Endpoints) > 0:
+		return errors.Wrap(errdefs.ErrNotImplemented, "contexts using custom endpoints can't be exported to an AWS CLI profile")
+	}
+	config, err := loadOrCreateIni(configFile)
+	if err != nil {
+		return err
+	}
+	section := configSectionName(name)
+	if _, err := config.GetSection(section); err == nil {
+		return errors.Wrapf(errdefs.ErrAlreadyExists, "profile %q", name)
+	}
+	target, err := config.NewSection(section)
+	if err != nil {
+		return err
+	}
+
+	if !ecsCtx.CredentialsFromEnv {
+		// copy credentials source (role, SSO, credential_process...) from context profile
+		if err := copySection(configFile, configSectionName(ecsCtx.Profile), target); err != nil {
+			return err
+		}
+		source, err := loadProfileSection(credentialsFile, credentialsSectionName(ecsCtx.Profile))
+		if err != nil {
+			return err
+		}
+		if source != nil {
+			credentials, err := loadOrCreateIni(credentialsFile)
+			if err != nil {
+				return err
+			}
+			if _, err := credentials.GetSection(name); err == nil {
+				return errors.Wrapf(errdefs.ErrAlreadyExists, "credentials for profile %q", name)
+			}
+			s, err := credentials.NewSection(name)
+			if err != nil {
+				return err
+			}
+			if err := copySection(credentialsFile, credentialsSectionName(ecsCtx.Profile), s); err != nil {
+				return err
+			}
+			if err := credentials.SaveTo(credentialsFile); err != nil {
+				return err
+			}
+		}
+	}
+	if ecsCtx.Region != "" {
+		target.Key("region").SetValue(ecsCtx.Region)
+	}
+	return config.SaveTo(configFile)
+}
+
+func copySection(file string, name string, target *ini.Section) error {
+	source, err := loadProfileSection(file, name)
+	if err != nil || source == nil {
+		return err
+	}
+	for _, key := range source.Keys() {
+		if _, err := target.NewKey(key.Name(), key.Value()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func loadOrCreateIni(file string) (*ini.File, error) {
+	f, err := ini.Load(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ini.Empty(), nil
+		}
+		return nil, err
+	}
+	return f, nil
+}
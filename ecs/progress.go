@@ -0,0 +1,186 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/compose-spec/compose-go/types"
+
+	"github.com/docker/compose-cli/progress"
+)
+
+type progressTreeKey struct{}
+
+// progressTree renders stack events as a tree, with resources grouped below the compose service they belong to and
+// shared resources below the stack
+type progressTree struct {
+	stack string
+	// services maps resources names prefixes to compose services names
+	services map[string]string
+	// prefixes are sorted longest first, so that service "webapp" resources don't match service "web"
+	prefixes []string
+	// pending lists template resources, in display order, to render before stack events are received
+	pending []string
+	status  map[string]map[string]progress.EventStatus
+}
+
+// withProgressTree lets stack events of project be rendered as a tree. Resources are listed upfront when the stack is
+// created, as all of them are then expected to complete, so that progress estimates remaining time
+func withProgressTree(ctx context.Context, project *types.Project, template *cloudformation.Template, create bool) context.Context {
+	return context.WithValue(ctx, progressTreeKey{}, newProgressTree(project, template, create))
+}
+
+func getProgressTree(ctx context.Context) *progressTree {
+	tree, _ := ctx.Value(progressTreeKey{}).(*progressTree)
+	return tree
+}
+
+func newProgressTree(project *types.Project, template *cloudformation.Template, create bool) *progressTree {
+	tree := &progressTree{
+		stack:    project.Name,
+		services: map[string]string{},
+		status:   map[string]map[string]progress.EventStatus{},
+	}
+	for _, service := range project.Services {
+		prefix := normalizeResourceName(service.Name)
+		tree.services[prefix] = service.Name
+		tree.prefixes = append(tree.prefixes, prefix)
+	}
+	sort.Slice(tree.prefixes, func(i, j int) bool {
+		return len(tree.prefixes[i]) > len(tree.prefixes[j])
+	})
+	if !create {
+		return tree
+	}
+
+	// services are loaded in no particular order, group them by name for a stable display
+	names := project.ServiceNames()
+	sort.Strings(names)
+	groups := map[string]int{project.Name: 0}
+	for i, name := range names {
+		groups[name] = i + 1
+	}
+	for name := range template.Resources {
+		tree.pending = append(tree.pending, name)
+	}
+	sort.Slice(tree.pending, func(i, j int) bool {
+		a, b := tree.pending[i], tree.pending[j]
+		if ga, gb := groups[tree.group(a)], groups[tree.group(b)]; ga != gb {
+			return ga < gb
+		}
+		if ra, rb := resourceRank(template.Resources[a]), resourceRank(template.Resources[b]); ra != rb {
+			return ra < rb
+		}
+		return a < b
+	})
+	return tree
+}
+
+// resourceRank sorts service resources as they are chained: task definition, service, target group, then listener
+func resourceRank(resource cloudformation.Resource) int {
+	switch resource.AWSCloudFormationType() {
+	case "AWS::ECS::TaskDefinition":
+		return 0
+	case "AWS::ECS::Service":
+		return 1
+	case "AWS::ElasticLoadBalancingV2::TargetGroup":
+		return 2
+	case "AWS::ElasticLoadBalancingV2::Listener":
+		return 3
+	default:
+		return 4
+	}
+}
+
+// group returns the compose service a resource belongs to, or the stack name for shared resources
+func (t *progressTree) group(resource string) string {
+	for _, prefix := range t.prefixes {
+		if strings.HasPrefix(resource, prefix) {
+			return t.services[prefix]
+		}
+	}
+	return t.stack
+}
+
+// start renders resources to be created as pending
+func (t *progressTree) start(w progress.Writer) {
+	if t == nil || len(t.pending) == 0 {
+		return
+	}
+	w.Event(progress.Event{
+		ID:         t.stack,
+		Status:     progress.Working,
+		StatusText: "PENDING",
+	})
+	for _, resource := range t.pending {
+		t.event(w, progress.Event{
+			ID:         resource,
+			Status:     progress.Working,
+			StatusText: "PENDING",
+		})
+	}
+}
+
+// event renders a stack event below the service its resource belongs to, and updates the service status from the
+// status of its resources
+func (t *progressTree) event(w progress.Writer, e progress.Event) {
+	if t == nil || e.ID == t.stack {
+		w.Event(e)
+		return
+	}
+	e.ParentID = t.group(e.ID)
+	if e.ParentID == t.stack {
+		w.Event(e)
+		return
+	}
+	resources, ok := t.status[e.ParentID]
+	if !ok {
+		resources = map[string]progress.EventStatus{}
+		t.status[e.ParentID] = resources
+	}
+	resources[e.ID] = e.Status
+	w.Event(serviceProgress(e.ParentID, resources))
+	w.Event(e)
+}
+
+// serviceProgress summarizes the status of a service resources
+func serviceProgress(service string, resources map[string]progress.EventStatus) progress.Event {
+	status := progress.Done
+	done := 0
+	for _, s := range resources {
+		switch s {
+		case progress.Done:
+			done++
+		case progress.Error:
+			status = progress.Error
+		case progress.Working:
+			if status == progress.Done {
+				status = progress.Working
+			}
+		}
+	}
+	return progress.Event{
+		ID:         service,
+		Status:     status,
+		StatusText: fmt.Sprintf("%d/%d", done, len(resources)),
+	}
+}
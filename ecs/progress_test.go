@@ -0,0 +1,111 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/progress"
+)
+
+type recordingWriter struct {
+	events []progress.Event
+}
+
+func (w *recordingWriter) Start(context.Context) error {
+	return nil
+}
+
+func (w *recordingWriter) Stop() {
+}
+
+func (w *recordingWriter) Event(e progress.Event) {
+	w.events = append(w.events, e)
+}
+
+func TestProgressTree(t *testing.T) {
+	yaml := `
+services:
+  web:
+    image: nginx
+    ports:
+      - 80:80
+  webapp:
+    image: app
+`
+	project := loadConfig(t, yaml)
+	template := convertYaml(t, yaml, useDefaultVPC)
+	tree := newProgressTree(project, template, true)
+
+	assert.Equal(t, tree.group("WebService"), "web")
+	assert.Equal(t, tree.group("WebappService"), "webapp")
+	assert.Equal(t, tree.group("Cluster"), t.Name())
+	assert.Equal(t, len(tree.pending), len(template.Resources))
+
+	index := map[string]int{}
+	for i, r := range tree.pending {
+		index[r] = i
+	}
+	assert.Check(t, index["Cluster"] < index["WebTaskDefinition"])
+	assert.Check(t, index["WebTaskDefinition"] < index["WebService"])
+	assert.Check(t, index["WebService"] < index["WebTCP80TargetGroup"])
+	assert.Check(t, index["WebTCP80TargetGroup"] < index["WebTCP80Listener"])
+	assert.Check(t, index["WebTCP80Listener"] < index["WebappTaskDefinition"])
+
+	w := &recordingWriter{}
+	tree.event(w, progress.Event{ID: "WebTaskDefinition", Status: progress.Done})
+	tree.event(w, progress.Event{ID: "WebService", Status: progress.Working})
+	assert.DeepEqual(t, w.events, []progress.Event{
+		{ID: "web", Status: progress.Done, StatusText: "1/1"},
+		{ID: "WebTaskDefinition", ParentID: "web", Status: progress.Done},
+		{ID: "web", Status: progress.Working, StatusText: "1/2"},
+		{ID: "WebService", ParentID: "web", Status: progress.Working},
+	}, cmpopts.IgnoreUnexported(progress.Event{}))
+
+	w = &recordingWriter{}
+	tree.event(w, progress.Event{ID: "Cluster", Status: progress.Done})
+	tree.event(w, progress.Event{ID: t.Name(), Status: progress.Working})
+	tree.event(w, progress.Event{ID: "WebService", Status: progress.Error})
+	assert.DeepEqual(t, w.events, []progress.Event{
+		{ID: "Cluster", ParentID: t.Name(), Status: progress.Done},
+		{ID: t.Name(), Status: progress.Working},
+		{ID: "web", Status: progress.Error, StatusText: "1/2"},
+		{ID: "WebService", ParentID: "web", Status: progress.Error},
+	}, cmpopts.IgnoreUnexported(progress.Event{}))
+}
+
+func TestProgressTreeUpdate(t *testing.T) {
+	yaml := `
+services:
+  web:
+    image: nginx
+`
+	tree := newProgressTree(loadConfig(t, yaml), convertYaml(t, yaml, useDefaultVPC), false)
+	assert.Equal(t, len(tree.pending), 0)
+
+	w := &recordingWriter{}
+	tree.start(w)
+	assert.Equal(t, len(w.events), 0)
+
+	var nilTree *progressTree
+	nilTree.event(w, progress.Event{ID: "WebService"})
+	assert.DeepEqual(t, w.events, []progress.Event{{ID: "WebService"}}, cmpopts.IgnoreUnexported(progress.Event{}))
+}
@@ -38,12 +38,11 @@ func (b *ecsAPIService) Ps(ctx context.Context, project string) ([]compose.Servi
 		return nil, nil
 	}
 
-	status := []compose.ServiceStatus{}
-	for _, arn := range servicesARN {
-		state, err := b.aws.DescribeService(ctx, cluster, arn)
-		if err != nil {
-			return nil, err
-		}
+	status, err := b.aws.DescribeServices(ctx, cluster, servicesARN)
+	if err != nil {
+		return nil, err
+	}
+	for i, state := range status {
 		ports := []string{}
 		for _, lb := range state.Publishers {
 			ports = append(ports, fmt.Sprintf(
@@ -53,8 +52,7 @@ func (b *ecsAPIService) Ps(ctx context.Context, project string) ([]compose.Servi
 				lb.TargetPort,
 				strings.ToLower(lb.Protocol)))
 		}
-		state.Ports = ports
-		status = append(status, state)
+		status[i].Ports = ports
 	}
 	return status, nil
 }
@@ -0,0 +1,75 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// pullCredentials resolves x-aws-pull_credentials to the Secrets Manager secret holding registry credentials. Value
+// is either a secret ARN or the name of a compose secret, created in Secrets Manager on deployment.
+func pullCredentials(project *types.Project, service types.ServiceConfig) (string, bool) {
+	value, ok := service.Extensions[extensionPullCredentials]
+	if !ok {
+		return "", false
+	}
+	name := fmt.Sprint(value)
+	if secret, ok := project.Secrets[name]; ok {
+		return secret.Name, true
+	}
+	return name, true
+}
+
+func getRepoCredentials(project *types.Project, service types.ServiceConfig) (*ecs.TaskDefinition_RepositoryCredentials, error) {
+	parameter, ok := pullCredentials(project, service)
+	if !ok {
+		return nil, nil
+	}
+	secret, ok := project.Secrets[fmt.Sprint(service.Extensions[extensionPullCredentials])]
+	if ok && !secret.External.External {
+		err := checkRegistryCredentials(secret.File)
+		if err != nil {
+			return nil, errors.Wrapf(err, "service %q: %s", service.Name, extensionPullCredentials)
+		}
+	}
+	return &ecs.TaskDefinition_RepositoryCredentials{CredentialsParameter: parameter}, nil
+}
+
+// checkRegistryCredentials validates secret file has the JSON format ECS expects for private registry authentication
+func checkRegistryCredentials(file string) error {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	var credentials struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+	err = json.Unmarshal(content, &credentials)
+	if err != nil || credentials.Username == "" || credentials.Password == "" {
+		return errors.Wrapf(errdefs.ErrParsingFailed, "%s must be a JSON document with username and password", file)
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/docker/distribution/reference"
+	"github.com/docker/distribution/registry/client"
+	"github.com/docker/distribution/registry/client/auth"
+	"github.com/docker/distribution/registry/client/transport"
+	dockertypes "github.com/docker/docker/api/types"
+	registrytypes "github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/registry"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/internal"
+)
+
+const dockerHubAuthKey = "https://index.docker.io/v1/"
+
+// GetImagePlatforms returns the os/architecture[/variant] platforms an image is available for
+func (s sdk) GetImagePlatforms(ctx context.Context, image string) ([]string, error) {
+	manifests, err := s.getManifests(ctx, image)
+	if err != nil {
+		return nil, err
+	}
+	return imagePlatforms(image, manifests)
+}
+
+// imagePlatforms returns the platforms of image manifests. Platform of a single manifest image is read from its
+// config by getManifests.
+func imagePlatforms(image string, manifests []manifestlist.ManifestDescriptor) ([]string, error) {
+	var platforms []string
+	for _, m := range manifests {
+		if platform := manifestPlatform(m); platform != "" {
+			platforms = append(platforms, platform)
+		}
+	}
+	if len(platforms) == 0 {
+		return nil, errors.Wrapf(errdefs.ErrNotFound, "image %s doesn't declare its platform", image)
+	}
+	return platforms, nil
+}
+
+// manifestPlatform returns the os/architecture[/variant] platform of a manifest, empty if unknown
+func manifestPlatform(m manifestlist.ManifestDescriptor) string {
+	p := m.Platform
+	if p.OS == "" || p.Architecture == "" {
+		return ""
+	}
+	platform := p.OS + "/" + p.Architecture
+	if p.Variant != "" {
+		platform += "/" + p.Variant
+	}
+	return platform
+}
+
+// GetImageDigest returns the digest of image manifest for platform
+func (s sdk) GetImageDigest(ctx context.Context, image string, platform string) (string, error) {
+	manifests, err := s.getManifests(ctx, image)
+	if err != nil {
+		return "", err
+	}
+	if len(manifests) == 1 && manifestPlatform(manifests[0]) == "" {
+		return manifests[0].Digest.String(), nil
+	}
+	for _, m := range manifests {
+		if m.Platform.OS+"/"+m.Platform.Architecture == platform {
+			return m.Digest.String(), nil
+		}
+	}
+	return "", errors.Wrapf(errdefs.ErrNotFound, "image %s has no %s variant", image, platform)
+}
+
+// getManifests returns image manifests, one per platform for multi-platform images
+func (s sdk) getManifests(ctx context.Context, image string) ([]manifestlist.ManifestDescriptor, error) {
+	ref, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return nil, err
+	}
+	ref = reference.TagNameOnly(ref)
+	repo, err := s.repository(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	manifestService, err := repo.Manifests(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var options []distribution.ManifestServiceOption
+	if tagged, ok := ref.(reference.Tagged); ok {
+		options = append(options, distribution.WithTag(tagged.Tag()))
+	}
+	var dgst digest.Digest
+	if digested, ok := ref.(reference.Digested); ok {
+		dgst = digested.Digest()
+	}
+	manifest, err := manifestService.Get(ctx, dgst, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	switch m := manifest.(type) {
+	case *manifestlist.DeserializedManifestList:
+		return m.Manifests, nil
+	case *schema2.DeserializedManifest:
+		_, payload, err := m.Payload()
+		if err != nil {
+			return nil, err
+		}
+		descriptor := manifestlist.ManifestDescriptor{
+			Descriptor: distribution.Descriptor{
+				MediaType: m.MediaType,
+				Digest:    digest.FromBytes(payload),
+				Size:      int64(len(payload)),
+			},
+		}
+		config, err := repo.Blobs(ctx).Get(ctx, m.Config.Digest)
+		if err != nil {
+			return nil, err
+		}
+		// image config declares os, architecture and variant as a manifest list platform does
+		if err := json.Unmarshal(config, &descriptor.Platform); err != nil {
+			return nil, err
+		}
+		return []manifestlist.ManifestDescriptor{descriptor}, nil
+	default:
+		return nil, errors.Wrapf(errdefs.ErrNotImplemented, "unsupported manifest format for image %s", image)
+	}
+}
+
+// repository returns a client to the registry repository of ref, authenticated with registryAuth
+func (s sdk) repository(ctx context.Context, ref reference.Named) (distribution.Repository, error) {
+	repoInfo, err := registry.ParseRepositoryInfo(ref)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := &url.URL{Scheme: "https", Host: reference.Domain(ref)}
+	if repoInfo.Index.Official {
+		endpoint = registry.DefaultV2Registry
+	}
+
+	modifiers := registry.Headers(internal.ECSUserAgentName+"/"+internal.Version, http.Header{})
+	base := transport.NewTransport(http.DefaultTransport, modifiers...)
+	challengeManager, _, err := registry.PingV2Registry(endpoint, base)
+	if err != nil {
+		return nil, err
+	}
+	authConfig := s.registryAuth(ctx, repoInfo.Index)
+	creds := registry.NewStaticCredentialStore(&authConfig)
+	repoName := reference.Path(ref)
+	authorizer := auth.NewAuthorizer(challengeManager,
+		auth.NewTokenHandler(base, creds, repoName, "pull"),
+		auth.NewBasicHandler(creds))
+	named, err := reference.WithName(repoName)
+	if err != nil {
+		return nil, err
+	}
+	return client.NewRepository(named, endpoint.String(), transport.NewTransport(http.DefaultTransport, append(modifiers, authorizer)...))
+}
+
+// registryAuth resolves registry credentials from ECR for ECR registries, otherwise from docker CLI configuration
+func (s sdk) registryAuth(ctx context.Context, index *registrytypes.IndexInfo) dockertypes.AuthConfig {
+	if strings.Contains(index.Name, ".dkr.ecr.") {
+		auth, err := s.ecrAuth(ctx)
+		if err == nil {
+			return auth
+		}
+		logrus.Debugf("failed to get ECR authorization token: %v", err)
+	}
+	key := index.Name
+	if index.Official {
+		key = dockerHubAuthKey
+	}
+	cfg, err := config.LoadDefaultConfigFile(os.Stderr).GetAuthConfig(key)
+	if err != nil {
+		return dockertypes.AuthConfig{}
+	}
+	return dockertypes.AuthConfig{
+		Username:      cfg.Username,
+		Password:      cfg.Password,
+		Auth:          cfg.Auth,
+		IdentityToken: cfg.IdentityToken,
+		RegistryToken: cfg.RegistryToken,
+	}
+}
+
+func (s sdk) ecrAuth(ctx context.Context) (dockertypes.AuthConfig, error) {
+	token, err := s.ECR.GetAuthorizationTokenWithContext(ctx, &ecr.GetAuthorizationTokenInput{})
+	if err != nil {
+		return dockertypes.AuthConfig{}, err
+	}
+	if len(token.AuthorizationData) == 0 {
+		return dockertypes.AuthConfig{}, nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(aws.StringValue(token.AuthorizationData[0].AuthorizationToken))
+	if err != nil {
+		return dockertypes.AuthConfig{}, err
+	}
+	parts := strings.SplitN(string(decoded), ":", 2)
+	if len(parts) != 2 {
+		return dockertypes.AuthConfig{}, nil
+	}
+	return dockertypes.AuthConfig{
+		Username:      parts[0],
+		Password:      parts[1],
+		ServerAddress: aws.StringValue(token.AuthorizationData[0].ProxyEndpoint),
+	}, nil
+}
+
+// GetRegistryAuth returns the credentials to log into the ECR registry of the account
+func (s sdk) GetRegistryAuth(ctx context.Context) (dockertypes.AuthConfig, error) {
+	auth, err := s.ecrAuth(ctx)
+	if err != nil {
+		return auth, err
+	}
+	if auth.Username == "" {
+		return auth, errors.Wrap(errdefs.ErrLoginFailed, "invalid ECR authorization token")
+	}
+	return auth, nil
+}
@@ -0,0 +1,47 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+
+	"github.com/docker/distribution/manifest/manifestlist"
+	"gotest.tools/v3/assert"
+)
+
+func imageManifest(platform manifestlist.PlatformSpec) manifestlist.ManifestDescriptor {
+	return manifestlist.ManifestDescriptor{Platform: platform}
+}
+
+func TestImagePlatforms(t *testing.T) {
+	// single manifest, which platform getManifests reads from image config
+	platforms, err := imagePlatforms("arm_only", []manifestlist.ManifestDescriptor{
+		imageManifest(manifestlist.PlatformSpec{OS: "linux", Architecture: "arm64"}),
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, platforms, []string{"linux/arm64"})
+
+	platforms, err = imagePlatforms("multi", []manifestlist.ManifestDescriptor{
+		imageManifest(manifestlist.PlatformSpec{OS: "linux", Architecture: "amd64"}),
+		imageManifest(manifestlist.PlatformSpec{OS: "linux", Architecture: "arm64", Variant: "v8"}),
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, platforms, []string{"linux/amd64", "linux/arm64/v8"})
+
+	_, err = imagePlatforms("unknown", []manifestlist.ManifestDescriptor{imageManifest(manifestlist.PlatformSpec{})})
+	assert.ErrorContains(t, err, "image unknown doesn't declare its platform")
+}
@@ -0,0 +1,154 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/progress"
+	"github.com/docker/compose-cli/utils"
+)
+
+// Restart forces a new deployment of services, replacing their tasks without a stack update, and waits for them to
+// reach a steady state
+func (b *ecsAPIService) Restart(ctx context.Context, projectName string, services []string) error {
+	cluster, arns, err := b.projectServices(ctx, projectName, services)
+	if err != nil {
+		return err
+	}
+	names := sortedKeys(arns)
+	w := progress.ContextWriter(ctx)
+	restarted := []string{}
+	for _, service := range names {
+		err := b.aws.RestartService(ctx, cluster, arns[service])
+		if err != nil {
+			return err
+		}
+		w.Event(progress.Event{
+			ID:         service,
+			Status:     progress.Working,
+			StatusText: "Restarting",
+		})
+		restarted = append(restarted, arns[service])
+	}
+
+	err = b.waitServicesStable(ctx, cluster, restarted)
+	for _, service := range names {
+		if err != nil {
+			w.Event(progress.Event{
+				ID:         service,
+				Status:     progress.Error,
+				StatusText: "Service didn't reach a steady state",
+			})
+			continue
+		}
+		w.Event(progress.Event{
+			ID:         service,
+			Status:     progress.Done,
+			StatusText: "Restarted",
+		})
+	}
+	return err
+}
+
+// Kill stops running tasks of services. ECS sends SIGTERM to their containers, then SIGKILL once stop timeout
+// expires, and services replace the tasks to keep their desired count
+func (b *ecsAPIService) Kill(ctx context.Context, projectName string, opts compose.KillOptions) error {
+	signal := strings.TrimPrefix(strings.ToUpper(opts.Signal), "SIG")
+	switch signal {
+	case "":
+		signal = "TERM"
+	case "KILL", "TERM":
+	default:
+		return errors.Wrapf(errdefs.ErrNotImplemented, "ECS can't send signal %s to containers, only SIGTERM then SIGKILL as tasks are stopped", opts.Signal)
+	}
+	cluster, arns, err := b.projectServices(ctx, projectName, opts.Services)
+	if err != nil {
+		return err
+	}
+	w := progress.ContextWriter(ctx)
+	for _, service := range sortedKeys(arns) {
+		tasks, err := b.aws.GetServiceTasks(ctx, cluster, arns[service], false)
+		if err != nil {
+			return err
+		}
+		for _, task := range tasks {
+			err := b.aws.StopTask(ctx, cluster, aws.StringValue(task.TaskArn), fmt.Sprintf("Killed by compose kill (SIG%s)", signal))
+			if err != nil {
+				return err
+			}
+		}
+		status := fmt.Sprintf("Stopped %d tasks", len(tasks))
+		if signal == "KILL" {
+			// ECS can't kill containers right away
+			status = fmt.Sprintf("Killed %d tasks, after SIGTERM and stop timeout", len(tasks))
+		}
+		w.Event(progress.Event{
+			ID:         service,
+			Status:     progress.Done,
+			StatusText: status,
+		})
+	}
+	return nil
+}
+
+// projectServices returns the cluster and ARN of services deployed by project, by name, all services when empty
+func (b *ecsAPIService) projectServices(ctx context.Context, projectName string, services []string) (string, map[string]string, error) {
+	cluster, err := b.aws.GetStackClusterID(ctx, projectName)
+	if err != nil {
+		return "", nil, err
+	}
+	list, err := b.aws.ListStackServices(ctx, projectName)
+	if err != nil {
+		return "", nil, err
+	}
+	names, err := b.aws.GetServiceNames(ctx, cluster, list)
+	if err != nil {
+		return "", nil, err
+	}
+	arns := map[string]string{}
+	for _, arn := range list {
+		service := names[arn]
+		if len(services) > 0 && !utils.StringContains(services, service) {
+			continue
+		}
+		arns[service] = arn
+	}
+	for _, service := range services {
+		if _, ok := arns[service]; !ok {
+			return "", nil, errors.Wrapf(errdefs.ErrNotFound, "service %q isn't running in %s", service, projectName)
+		}
+	}
+	return cluster, arns, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
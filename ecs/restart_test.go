@@ -0,0 +1,95 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+const (
+	fooServiceARN = "arn:aws:ecs:us-east-1:012345678910:service/cluster/foo"
+	barServiceARN = "arn:aws:ecs:us-east-1:012345678910:service/cluster/bar"
+)
+
+func expectProjectServices(m *MockAPIMockRecorder) {
+	m.GetStackClusterID(gomock.Any(), "test").Return("cluster", nil)
+	m.ListStackServices(gomock.Any(), "test").Return([]string{fooServiceARN, barServiceARN}, nil)
+	m.GetServiceNames(gomock.Any(), "cluster", []string{fooServiceARN, barServiceARN}).Return(map[string]string{
+		fooServiceARN: "foo",
+		barServiceARN: "bar",
+	}, nil)
+}
+
+func TestRestart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+
+	expectProjectServices(m.EXPECT())
+	m.EXPECT().RestartService(gomock.Any(), "cluster", barServiceARN).Return(nil)
+	m.EXPECT().RestartService(gomock.Any(), "cluster", fooServiceARN).Return(nil)
+	m.EXPECT().WaitServicesStable(gomock.Any(), "cluster", []string{barServiceARN, fooServiceARN}).Return(nil)
+
+	backend := &ecsAPIService{aws: m}
+	err := backend.Restart(context.TODO(), "test", nil)
+	assert.NilError(t, err)
+
+	expectProjectServices(m.EXPECT())
+	err = backend.Restart(context.TODO(), "test", []string{"baz"})
+	assert.Check(t, errdefs.IsNotFoundError(err))
+}
+
+func TestKill(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+
+	expectProjectServices(m.EXPECT())
+	m.EXPECT().GetServiceTasks(gomock.Any(), "cluster", fooServiceARN, false).Return([]*ecs.Task{
+		{TaskArn: aws.String("task1")},
+		{TaskArn: aws.String("task2")},
+	}, nil)
+	m.EXPECT().StopTask(gomock.Any(), "cluster", "task1", "Killed by compose kill (SIGTERM)").Return(nil)
+	m.EXPECT().StopTask(gomock.Any(), "cluster", "task2", "Killed by compose kill (SIGTERM)").Return(nil)
+
+	backend := &ecsAPIService{aws: m}
+	err := backend.Kill(context.TODO(), "test", compose.KillOptions{
+		Services: []string{"foo"},
+		Signal:   "sigterm",
+	})
+	assert.NilError(t, err)
+
+	expectProjectServices(m.EXPECT())
+	m.EXPECT().GetServiceTasks(gomock.Any(), "cluster", fooServiceARN, false).Return([]*ecs.Task{
+		{TaskArn: aws.String("task1")},
+	}, nil)
+	m.EXPECT().StopTask(gomock.Any(), "cluster", "task1", "Killed by compose kill (SIGTERM)").Return(nil)
+	err = backend.Kill(context.TODO(), "test", compose.KillOptions{Services: []string{"foo"}})
+	assert.NilError(t, err)
+
+	err = backend.Kill(context.TODO(), "test", compose.KillOptions{Signal: "SIGHUP"})
+	assert.Check(t, errdefs.IsErrNotImplemented(err))
+}
@@ -0,0 +1,91 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/google/uuid"
+)
+
+const (
+	// maxThrottleRetries bounds retries of throttled requests, other failures being retried as many times as by the
+	// SDK default retryer
+	maxThrottleRetries = 10
+	minThrottleDelay   = time.Second
+	maxThrottleDelay   = 30 * time.Second
+)
+
+// throttleRetryer retries requests with exponential backoff as the SDK default retryer does, but retries throttled
+// requests longer, as busy accounts hit API rate limits while deploying
+type throttleRetryer struct {
+	client.DefaultRetryer
+}
+
+func newThrottleRetryer() throttleRetryer {
+	return throttleRetryer{
+		DefaultRetryer: client.DefaultRetryer{
+			NumMaxRetries:    maxThrottleRetries,
+			MinThrottleDelay: minThrottleDelay,
+			MaxThrottleDelay: maxThrottleDelay,
+		},
+	}
+}
+
+// ShouldRetry retries throttled requests up to maxThrottleRetries times, other retryable failures up to the SDK
+// default number of retries
+func (r throttleRetryer) ShouldRetry(req *request.Request) bool {
+	if isThrottled(req.Error) {
+		return true
+	}
+	return req.RetryCount < client.DefaultRetryerMaxNumRetries && r.DefaultRetryer.ShouldRetry(req)
+}
+
+// RetryRules doubles the delay before retrying a throttled request on each attempt, with jitter
+func (r throttleRetryer) RetryRules(req *request.Request) time.Duration {
+	if !isThrottled(req.Error) {
+		return r.DefaultRetryer.RetryRules(req)
+	}
+	delay := maxThrottleDelay
+	if req.RetryCount < 5 {
+		delay = minThrottleDelay << uint(req.RetryCount)
+	}
+	if delay > maxThrottleDelay {
+		delay = maxThrottleDelay
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)))
+}
+
+// isThrottled tells if a request failed because of API rate limits, some services reporting "Rate exceeded" without a
+// throttling error code
+func isThrottled(err error) bool {
+	if err == nil {
+		return false
+	}
+	return request.IsErrorThrottle(err) || strings.Contains(err.Error(), "Rate exceeded")
+}
+
+// newRequestToken returns a client request token for a stack operation. The SDK sends the same token when retrying
+// the request, so that CloudFormation ignores the retry if the operation was already submitted
+func newRequestToken(operation string) string {
+	return fmt.Sprintf("compose-%s-%s", operation, uuid.New().String())
+}
@@ -0,0 +1,58 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"gotest.tools/v3/assert"
+)
+
+func TestThrottleRetryer(t *testing.T) {
+	r := newThrottleRetryer()
+	assert.Equal(t, r.MaxRetries(), maxThrottleRetries)
+
+	throttled := &request.Request{Error: awserr.New("Throttling", "Rate exceeded", nil), RetryCount: 7}
+	assert.Check(t, r.ShouldRetry(throttled))
+	delay := r.RetryRules(throttled)
+	assert.Check(t, delay >= maxThrottleDelay/2 && delay <= maxThrottleDelay, delay)
+
+	// ECS reports some rate limits as client errors
+	rateExceeded := &request.Request{Error: awserr.New("ClientException", "Rate exceeded", nil)}
+	assert.Check(t, r.ShouldRetry(rateExceeded))
+	delay = r.RetryRules(rateExceeded)
+	assert.Check(t, delay >= minThrottleDelay/2 && delay <= minThrottleDelay, delay)
+
+	timeout := &request.Request{Error: awserr.New("RequestTimeout", "timeout", nil)}
+	assert.Check(t, r.ShouldRetry(timeout))
+	timeout.RetryCount = client.DefaultRetryerMaxNumRetries
+	assert.Check(t, !r.ShouldRetry(timeout))
+
+	invalid := &request.Request{Error: awserr.New("ValidationError", "invalid template", nil)}
+	assert.Check(t, !r.ShouldRetry(invalid))
+}
+
+func TestRequestToken(t *testing.T) {
+	token := newRequestToken("create")
+	assert.Check(t, regexp.MustCompile("^compose-create-[-a-f0-9]{36}$").MatchString(token), token)
+	assert.Check(t, len(token) <= 128)
+	assert.Check(t, token != newRequestToken("create"))
+}
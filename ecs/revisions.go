@@ -0,0 +1,115 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+type keepRevisionsKey struct{}
+
+// WithKeepRevisions lets up and down deregister superseded task definition revisions compose registered, but the
+// keep most recent ones of each service. Without it, no revision is deregistered.
+func WithKeepRevisions(ctx context.Context, keep int) context.Context {
+	return context.WithValue(ctx, keepRevisionsKey{}, keep)
+}
+
+func getKeepRevisions(ctx context.Context) (int, bool) {
+	keep, ok := ctx.Value(keepRevisionsKey{}).(int)
+	return keep, ok
+}
+
+// pruneTaskDefinitions deregisters task definition revisions compose registered for project, but those in use and the
+// most recent superseded ones to keep. Running tasks are not affected by deregistration.
+func (b *ecsAPIService) pruneTaskDefinitions(ctx context.Context, project string, inUse map[string]bool) error {
+	keep, ok := getKeepRevisions(ctx)
+	if !ok {
+		return nil
+	}
+	revisions, err := b.aws.ListTaskDefinitionRevisions(ctx, project)
+	if err != nil {
+		return err
+	}
+	for family, arns := range revisions {
+		kept := 0
+		for _, arn := range arns {
+			if inUse[arn] {
+				continue
+			}
+			if kept < keep {
+				kept++
+				continue
+			}
+			logrus.Debugf("deregistering superseded task definition %s of %s", lastSegment(arn), family)
+			err := b.aws.DeregisterTaskDefinition(ctx, arn)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// taskDefinitionsInUse returns the task definitions managed by project stack, and those its services run
+func (b *ecsAPIService) taskDefinitionsInUse(ctx context.Context, project string) (map[string]bool, error) {
+	resources, err := b.aws.ListStackResources(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	inUse := map[string]bool{}
+	var services []string
+	for _, r := range resources {
+		switch r.Type {
+		case awsTypeTaskDefinition:
+			inUse[r.ARN] = true
+		case awsTypeService:
+			services = append(services, r.ARN)
+		}
+	}
+	if len(services) == 0 {
+		return inUse, nil
+	}
+	cluster, err := b.aws.GetStackClusterID(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	definitions, err := b.aws.GetServiceTaskDefinition(ctx, cluster, services)
+	if err != nil {
+		return nil, err
+	}
+	for _, definition := range definitions {
+		inUse[definition] = true
+	}
+	return inUse, nil
+}
+
+// pruneSupersededTaskDefinitions deregisters task definitions a deployment superseded. As this is only housekeeping,
+// failures are reported as warnings.
+func (b *ecsAPIService) pruneSupersededTaskDefinitions(ctx context.Context, project string) {
+	if _, ok := getKeepRevisions(ctx); !ok {
+		return
+	}
+	inUse, err := b.taskDefinitionsInUse(ctx, project)
+	if err == nil {
+		err = b.pruneTaskDefinitions(ctx, project, inUse)
+	}
+	if err != nil {
+		logrus.Warnf("failed to deregister superseded task definitions: %s", err)
+	}
+}
@@ -0,0 +1,80 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func TestPruneTaskDefinitions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+	m.EXPECT().ListStackResources(gomock.Any(), "test").Return(stackResources{
+		{LogicalID: "WebService", Type: awsTypeService, ARN: "arn:aws:ecs:service/web"},
+		{LogicalID: "WebTaskDefinition", Type: awsTypeTaskDefinition, ARN: "arn:aws:ecs:task-definition/test-web:3"},
+	}, nil)
+	m.EXPECT().GetStackClusterID(gomock.Any(), "test").Return("cluster", nil)
+	m.EXPECT().GetServiceTaskDefinition(gomock.Any(), "cluster", []string{"arn:aws:ecs:service/web"}).Return(map[string]string{
+		"arn:aws:ecs:service/web": "arn:aws:ecs:task-definition/test-web:5",
+	}, nil)
+	m.EXPECT().ListTaskDefinitionRevisions(gomock.Any(), "test").Return(map[string][]string{
+		"test-web": {
+			"arn:aws:ecs:task-definition/test-web:5",
+			"arn:aws:ecs:task-definition/test-web:4",
+			"arn:aws:ecs:task-definition/test-web:3",
+			"arn:aws:ecs:task-definition/test-web:2",
+			"arn:aws:ecs:task-definition/test-web:1",
+		},
+	}, nil)
+	m.EXPECT().DeregisterTaskDefinition(gomock.Any(), "arn:aws:ecs:task-definition/test-web:2").Return(nil)
+	m.EXPECT().DeregisterTaskDefinition(gomock.Any(), "arn:aws:ecs:task-definition/test-web:1").Return(nil)
+
+	backend := &ecsAPIService{aws: m}
+	backend.pruneSupersededTaskDefinitions(WithKeepRevisions(context.TODO(), 1), "test")
+}
+
+func TestPruneAllTaskDefinitions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+	m.EXPECT().ListTaskDefinitionRevisions(gomock.Any(), "test").Return(map[string][]string{
+		"test-web": {"arn:aws:ecs:task-definition/test-web:2"},
+		"test-db":  {"arn:aws:ecs:task-definition/test-db:1"},
+	}, nil)
+	m.EXPECT().DeregisterTaskDefinition(gomock.Any(), "arn:aws:ecs:task-definition/test-web:2").Return(nil)
+	m.EXPECT().DeregisterTaskDefinition(gomock.Any(), "arn:aws:ecs:task-definition/test-db:1").Return(nil)
+
+	backend := &ecsAPIService{aws: m}
+	err := backend.pruneTaskDefinitions(WithKeepRevisions(context.TODO(), 0), "test", nil)
+	assert.NilError(t, err)
+}
+
+func TestPruneTaskDefinitionsWithoutKeep(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+
+	backend := &ecsAPIService{aws: m}
+	backend.pruneSupersededTaskDefinitions(context.TODO(), "test")
+	err := backend.pruneTaskDefinitions(context.TODO(), "test", nil)
+	assert.NilError(t, err)
+}
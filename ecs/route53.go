@@ -0,0 +1,78 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/cloudformation/route53"
+	"github.com/compose-spec/compose-go/types"
+)
+
+// dnsConfig is the Route 53 record set by x-aws-dns to alias load balancer
+type dnsConfig struct {
+	// Zone is the hosted zone ID or domain name
+	Zone string `json:"zone,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+func (b *ecsAPIService) createDNSRecord(project *types.Project, template *cloudformation.Template, resources awsResources) error {
+	v, ok := project.Extensions[extensionDNS]
+	if !ok {
+		return nil
+	}
+	marshalled, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var config dnsConfig
+	err = json.Unmarshal(marshalled, &config)
+	if err != nil {
+		return err
+	}
+	if config.Zone == "" || config.Name == "" {
+		return fmt.Errorf("%s MUST define zone and name", extensionDNS)
+	}
+	lb, ok := resources.loadBalancer.(cloudformationARNResource)
+	if !ok {
+		return fmt.Errorf("%s requires a load balancer created for the application", extensionDNS)
+	}
+
+	record := &route53.RecordSet{
+		AliasTarget: &route53.RecordSet_AliasTarget{
+			DNSName:      cloudformation.GetAtt(lb.logicalName, "DNSName"),
+			HostedZoneId: cloudformation.GetAtt(lb.logicalName, "CanonicalHostedZoneID"),
+		},
+		Name: config.Name,
+		Type: "A",
+	}
+	if strings.Contains(config.Zone, ".") {
+		record.HostedZoneName = strings.TrimSuffix(config.Zone, ".") + "."
+	} else {
+		record.HostedZoneId = config.Zone
+	}
+	template.Resources["LoadBalancerDNSRecord"] = record
+	if dualStack(project) {
+		ipv6Record := *record
+		ipv6Record.Type = "AAAA"
+		template.Resources["LoadBalancerIPv6DNSRecord"] = &ipv6Record
+	}
+	return nil
+}
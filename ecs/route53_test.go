@@ -0,0 +1,60 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/cloudformation/route53"
+	"gotest.tools/v3/assert"
+)
+
+func TestDNSRecord(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  foo:
+    image: nginx
+    ports:
+      - 80:80
+x-aws-dns:
+  zone: example.com
+  name: app.example.com
+`, useDefaultVPC)
+	record := template.Resources["LoadBalancerDNSRecord"].(*route53.RecordSet)
+	assert.Equal(t, record.HostedZoneName, "example.com.")
+	assert.Equal(t, record.Name, "app.example.com")
+	assert.Equal(t, record.Type, "A")
+	assert.Equal(t, record.AliasTarget.DNSName, cloudformation.GetAtt("LoadBalancer", "DNSName"))
+	assert.Equal(t, record.AliasTarget.HostedZoneId, cloudformation.GetAtt("LoadBalancer", "CanonicalHostedZoneID"))
+}
+
+func TestDNSRecordHostedZoneID(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  foo:
+    image: nginx
+    ports:
+      - 80:80
+x-aws-dns:
+  zone: Z1D633PJN98FT9
+  name: app.example.com
+`, useDefaultVPC)
+	record := template.Resources["LoadBalancerDNSRecord"].(*route53.RecordSet)
+	assert.Equal(t, record.HostedZoneId, "Z1D633PJN98FT9")
+	assert.Equal(t, record.HostedZoneName, "")
+}
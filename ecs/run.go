@@ -0,0 +1,89 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// logsFlushDelay lets CloudWatch ingest the last log events of a stopped task
+const logsFlushDelay = 3 * time.Second
+
+func (b *ecsAPIService) RunOneOffContainer(ctx context.Context, project *types.Project, opts compose.RunOptions) (int, error) {
+	return b.runOneOffTask(ctx, project, opts, os.Stdout)
+}
+
+// runOneOffTask runs a standalone task as service does, streaming its logs to out until it exits
+func (b *ecsAPIService) runOneOffTask(ctx context.Context, project *types.Project, opts compose.RunOptions, out io.Writer) (int, error) {
+	_, err := project.GetService(opts.Service)
+	if err != nil {
+		return 0, err
+	}
+	cluster, err := b.aws.GetStackClusterID(ctx, project.Name)
+	if err != nil {
+		return 0, err
+	}
+	resources, err := b.aws.ListStackResources(ctx, project.Name)
+	if err != nil {
+		return 0, err
+	}
+	var service string
+	for _, r := range resources {
+		if r.LogicalID == serviceResourceName(opts.Service) {
+			service = r.ARN
+		}
+	}
+	if service == "" {
+		return 0, errors.Wrapf(errdefs.ErrNotFound, "service %q isn't running as an ECS service in %s, deploy it with docker compose up", opts.Service, project.Name)
+	}
+
+	task, err := b.aws.RunTask(ctx, cluster, service, opts.Service, opts.Command)
+	if err != nil {
+		return 0, err
+	}
+	if opts.Detach {
+		fmt.Fprintln(out, task)
+		return 0, nil
+	}
+
+	logCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// awslogs stream name is prefix/container/task-id
+	taskID := lastSegment(task)
+	go b.aws.GetLogs(logCtx, project.Name, func(service, container, message string, timestamp time.Time) { // nolint:errcheck
+		if container == taskID {
+			fmt.Fprintln(out, message)
+		}
+	}, compose.LogOptions{Follow: true, Tail: -1})
+
+	exitCode, err := b.aws.WaitTaskStopped(ctx, cluster, task, opts.Service)
+	if err != nil {
+		return 0, err
+	}
+	time.Sleep(logsFlushDelay)
+	return exitCode, nil
+}
@@ -0,0 +1,85 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func TestRunOneOffTask(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  migrate:
+    image: hello_world
+`)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+
+	task := "arn:aws:ecs:us-east-1:012345678910:task/cluster/0123456789abcdef"
+	logged := make(chan struct{})
+	m.EXPECT().GetStackClusterID(gomock.Any(), project.Name).Return("cluster", nil)
+	m.EXPECT().ListStackResources(gomock.Any(), project.Name).Return(stackResources{
+		{LogicalID: "MigrateService", Type: awsTypeService, ARN: "arn:aws:ecs:us-east-1:012345678910:service/cluster/migrate"},
+	}, nil)
+	m.EXPECT().RunTask(gomock.Any(), "cluster", "arn:aws:ecs:us-east-1:012345678910:service/cluster/migrate", "migrate", []string{"migrate", "--all"}).Return(task, nil)
+	m.EXPECT().GetLogs(gomock.Any(), project.Name, gomock.Any(), compose.LogOptions{Follow: true, Tail: -1}).DoAndReturn(func(ctx context.Context, name string, consumer func(service, container, message string, timestamp time.Time), opts compose.LogOptions) error {
+		consumer("web", "fedcba9876543210", "another task", time.Now())
+		consumer("migrate", "0123456789abcdef", "migrated", time.Now())
+		close(logged)
+		return nil
+	})
+	m.EXPECT().WaitTaskStopped(gomock.Any(), "cluster", task, "migrate").DoAndReturn(func(ctx context.Context, cluster, task, container string) (int, error) {
+		<-logged
+		return 3, nil
+	})
+
+	backend := &ecsAPIService{aws: m}
+	var out bytes.Buffer
+	exitCode, err := backend.runOneOffTask(context.TODO(), project, compose.RunOptions{
+		Service: "migrate",
+		Command: []string{"migrate", "--all"},
+	}, &out)
+	assert.NilError(t, err)
+	assert.Equal(t, exitCode, 3)
+	assert.Equal(t, out.String(), "migrated\n")
+}
+
+func TestRunOneOffTaskNotDeployed(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  migrate:
+    image: hello_world
+`)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+	m.EXPECT().GetStackClusterID(gomock.Any(), project.Name).Return("cluster", nil)
+	m.EXPECT().ListStackResources(gomock.Any(), project.Name).Return(stackResources{}, nil)
+
+	backend := &ecsAPIService{aws: m}
+	_, err := backend.runOneOffTask(context.TODO(), project, compose.RunOptions{Service: "migrate"}, &bytes.Buffer{})
+	assert.ErrorContains(t, err, `service "migrate" isn't running as an ECS service`)
+}
@@ -0,0 +1,91 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/progress"
+)
+
+// Scale updates desired count of ECS services without a stack update and waits for them to reach a steady state. Next
+// up resets them to the replicas set by compose file.
+func (b *ecsAPIService) Scale(ctx context.Context, projectName string, replicas map[string]int) error {
+	cluster, err := b.aws.GetStackClusterID(ctx, projectName)
+	if err != nil {
+		return err
+	}
+	resources, err := b.aws.ListStackResources(ctx, projectName)
+	if err != nil {
+		return err
+	}
+	services := make([]string, 0, len(replicas))
+	for service := range replicas {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+
+	arns := map[string]string{}
+	for _, service := range services {
+		for _, r := range resources {
+			if r.Type == awsTypeService && r.LogicalID == serviceResourceName(service) {
+				arns[service] = r.ARN
+			}
+		}
+		if arns[service] == "" {
+			return errors.Wrapf(errdefs.ErrNotFound, "service %q isn't running in %s", service, projectName)
+		}
+	}
+
+	w := progress.ContextWriter(ctx)
+	scaled := []string{}
+	for _, service := range services {
+		err := b.aws.ScaleService(ctx, cluster, arns[service], replicas[service])
+		if err != nil {
+			return err
+		}
+		w.Event(progress.Event{
+			ID:         service,
+			Status:     progress.Working,
+			StatusText: fmt.Sprintf("Scaling to %d tasks", replicas[service]),
+		})
+		scaled = append(scaled, arns[service])
+	}
+
+	err = b.waitServicesStable(ctx, cluster, scaled)
+	for _, service := range services {
+		if err != nil {
+			w.Event(progress.Event{
+				ID:         service,
+				Status:     progress.Error,
+				StatusText: "Service didn't reach a steady state",
+			})
+			continue
+		}
+		w.Event(progress.Event{
+			ID:         service,
+			Status:     progress.Done,
+			StatusText: fmt.Sprintf("Scaled to %d tasks", replicas[service]),
+		})
+	}
+	return err
+}
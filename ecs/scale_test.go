@@ -0,0 +1,53 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestScale(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+
+	resources := stackResources{
+		{LogicalID: "FooService", Type: awsTypeService, ARN: "arn:aws:ecs:us-east-1:012345678910:service/cluster/foo"},
+		{LogicalID: "BarService", Type: awsTypeService, ARN: "arn:aws:ecs:us-east-1:012345678910:service/cluster/bar"},
+	}
+	m.EXPECT().GetStackClusterID(gomock.Any(), "test").Return("cluster", nil).Times(2)
+	m.EXPECT().ListStackResources(gomock.Any(), "test").Return(resources, nil).Times(2)
+	m.EXPECT().ScaleService(gomock.Any(), "cluster", "arn:aws:ecs:us-east-1:012345678910:service/cluster/bar", 0).Return(nil)
+	m.EXPECT().ScaleService(gomock.Any(), "cluster", "arn:aws:ecs:us-east-1:012345678910:service/cluster/foo", 3).Return(nil)
+	m.EXPECT().WaitServicesStable(gomock.Any(), "cluster", []string{
+		"arn:aws:ecs:us-east-1:012345678910:service/cluster/bar",
+		"arn:aws:ecs:us-east-1:012345678910:service/cluster/foo",
+	}).Return(nil)
+
+	backend := &ecsAPIService{aws: m}
+	err := backend.Scale(context.TODO(), "test", map[string]int{"foo": 3, "bar": 0})
+	assert.NilError(t, err)
+
+	err = backend.Scale(context.TODO(), "test", map[string]int{"baz": 1})
+	assert.Check(t, errdefs.IsNotFoundError(err))
+}
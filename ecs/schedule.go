@@ -0,0 +1,102 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/cloudformation/events"
+	"github.com/awslabs/goformation/v4/cloudformation/iam"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// isScheduled tells if service runs as scheduled task rather than as an ECS service
+func isScheduled(project *types.Project, name string) bool {
+	service, err := project.GetService(name)
+	if err != nil {
+		return false
+	}
+	_, ok := service.Extensions[extensionSchedule]
+	return ok
+}
+
+// createScheduledTask runs service tasks on x-aws-schedule, a cron() or rate() EventBridge schedule expression, as a
+// one-off job rather than a long-running service
+func (b *ecsAPIService) createScheduledTask(project *types.Project, service types.ServiceConfig, schedule string, taskDefinition string, template *cloudformation.Template, resources awsResources) error {
+	if !strings.HasPrefix(schedule, "cron(") && !strings.HasPrefix(schedule, "rate(") {
+		return errors.Wrapf(errdefs.ErrParsingFailed, "service %q: %s must be a cron() or rate() expression, got %q", service.Name, extensionSchedule, schedule)
+	}
+	if len(service.Ports) > 0 {
+		return fmt.Errorf("service %q: scheduled tasks can't expose ports", service.Name)
+	}
+	if service.Deploy != nil {
+		if _, ok := service.Deploy.Extensions[extensionAutoScaling]; ok {
+			return fmt.Errorf("service %q: scheduled tasks can't use %s", service.Name, extensionAutoScaling)
+		}
+	}
+
+	taskCount := 1
+	if service.Deploy != nil && service.Deploy.Replicas != nil {
+		taskCount = int(*service.Deploy.Replicas)
+	}
+
+	var dependsOn []string
+	for _, s := range service.Volumes {
+		dependsOn = append(dependsOn, b.mountTargets(s.Source, resources)...)
+	}
+
+	scheduleRole := fmt.Sprintf("%sScheduleRole", normalizeResourceName(service.Name))
+	template.Resources[scheduleRole] = &iam.Role{
+		AssumeRolePolicyDocument: eventsAssumeRolePolicyDocument,
+		ManagedPolicyArns:        []string{ecsEventsPolicy},
+		Tags:                     serviceTags(project, service),
+	}
+
+	launchType, platformVersion, assignPublicIP := serviceLaunchType(project, service)
+	template.Resources[fmt.Sprintf("%sSchedule", normalizeResourceName(service.Name))] = &events.Rule{
+		AWSCloudFormationDependsOn: dependsOn,
+		Description:                fmt.Sprintf("Run %s service of %s project on schedule", service.Name, project.Name),
+		ScheduleExpression:         schedule,
+		State:                      "ENABLED",
+		Targets: []events.Rule_Target{
+			{
+				Arn: resources.cluster.ARN(),
+				Id:  normalizeResourceName(service.Name),
+				EcsParameters: &events.Rule_EcsParameters{
+					LaunchType: launchType,
+					NetworkConfiguration: &events.Rule_NetworkConfiguration{
+						AwsVpcConfiguration: &events.Rule_AwsVpcConfiguration{
+							AssignPublicIp: assignPublicIP,
+							SecurityGroups: resources.serviceSecurityGroups(service),
+							Subnets:        resources.subnetsIDs(),
+						},
+					},
+					PlatformVersion:   platformVersion,
+					TaskCount:         taskCount,
+					TaskDefinitionArn: cloudformation.Ref(taskDefinition),
+				},
+				RoleArn: cloudformation.GetAtt(scheduleRole, "Arn"),
+			},
+		},
+	}
+	return nil
+}
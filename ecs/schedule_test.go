@@ -0,0 +1,79 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"github.com/awslabs/goformation/v4/cloudformation/events"
+	"gotest.tools/v3/assert"
+)
+
+func TestScheduledTask(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  backup:
+    image: hello_world
+    x-aws-schedule: cron(0 2 * * ? *)
+  web:
+    image: nginx
+    depends_on:
+      - backup
+`, useDefaultVPC)
+	_, ok := template.Resources["BackupService"]
+	assert.Check(t, !ok)
+	_, ok = template.Resources["BackupScheduleRole"]
+	assert.Check(t, ok)
+
+	rule := template.Resources["BackupSchedule"].(*events.Rule)
+	assert.Equal(t, rule.ScheduleExpression, "cron(0 2 * * ? *)")
+	assert.Equal(t, len(rule.Targets), 1)
+	ecsParameters := rule.Targets[0].EcsParameters
+	assert.Equal(t, ecsParameters.LaunchType, "FARGATE")
+	assert.Equal(t, ecsParameters.TaskCount, 1)
+	assert.Equal(t, ecsParameters.NetworkConfiguration.AwsVpcConfiguration.AssignPublicIp, "ENABLED")
+
+	web := template.Resources["WebService"].(*ecs.Service)
+	assert.DeepEqual(t, web.AWSCloudFormationDependsOn, []string(nil))
+}
+
+func TestScheduledTaskInvalid(t *testing.T) {
+	tests := []struct {
+		yaml string
+		err  string
+	}{
+		{
+			yaml: `x-aws-schedule: "0 2 * * *"`,
+			err:  `x-aws-schedule must be a cron() or rate() expression, got "0 2 * * *"`,
+		},
+		{
+			yaml: `x-aws-schedule: rate(1 hour)
+    ports:
+      - 80:80`,
+			err: "scheduled tasks can't expose ports",
+		},
+	}
+	for _, test := range tests {
+		err := convertYamlError(t, `
+services:
+  backup:
+    image: hello_world
+    `+test.yaml, useDefaultVPC)
+		assert.ErrorContains(t, err, test.err)
+	}
+}
@@ -20,6 +20,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -30,6 +33,8 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
@@ -38,8 +43,12 @@ import (
 	"github.com/aws/aws-sdk-go/service/cloudformation/cloudformationiface"
 	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
 	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
+	"github.com/aws/aws-sdk-go/service/codedeploy"
+	"github.com/aws/aws-sdk-go/service/codedeploy/codedeployiface"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/aws/aws-sdk-go/service/ecr/ecriface"
 	"github.com/aws/aws-sdk-go/service/ecs"
 	"github.com/aws/aws-sdk-go/service/ecs/ecsiface"
 	"github.com/aws/aws-sdk-go/service/efs"
@@ -48,8 +57,12 @@ import (
 	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/aws/aws-sdk-go/service/pricing"
+	"github.com/aws/aws-sdk-go/service/pricing/pricingiface"
 	"github.com/aws/aws-sdk-go/service/secretsmanager"
 	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
 	"github.com/aws/aws-sdk-go/service/ssm"
 	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
 	"github.com/hashicorp/go-multierror"
@@ -59,6 +72,7 @@ import (
 
 type sdk struct {
 	ECS ecsiface.ECSAPI
+	ECR ecriface.ECRAPI
 	EC2 ec2iface.EC2API
 	EFS efsiface.EFSAPI
 	ELB elbv2iface.ELBV2API
@@ -68,6 +82,13 @@ type sdk struct {
 	SM  secretsmanageriface.SecretsManagerAPI
 	SSM ssmiface.SSMAPI
 	AG  autoscalingiface.AutoScalingAPI
+	CD  codedeployiface.CodeDeployAPI
+	PR  pricingiface.PricingAPI
+	SNS snsiface.SNSAPI
+	// tags set by context on stacks
+	tags map[string]string
+	// region of the session clients are created for
+	region string
 }
 
 // sdk implement API
@@ -77,8 +98,10 @@ func newSDK(sess *session.Session) sdk {
 	sess.Handlers.Build.PushBack(func(r *request.Request) {
 		request.AddToUserAgent(r, internal.ECSUserAgentName+"/"+internal.Version)
 	})
+	request.WithRetryer(sess.Config, newThrottleRetryer())
 	return sdk{
 		ECS: ecs.New(sess),
+		ECR: ecr.New(sess),
 		EC2: ec2.New(sess),
 		EFS: efs.New(sess),
 		ELB: elbv2.New(sess),
@@ -88,6 +111,12 @@ func newSDK(sess *session.Session) sdk {
 		SM:  secretsmanager.New(sess),
 		SSM: ssm.New(sess),
 		AG:  autoscaling.New(sess),
+		CD:  codedeploy.New(sess),
+		// Price List API is only available in us-east-1 and ap-south-1
+		PR:  pricing.New(sess, aws.NewConfig().WithRegion(endpoints.UsEast1RegionID)),
+		SNS: sns.New(sess),
+
+		region: aws.StringValue(sess.Config.Region),
 	}
 }
 
@@ -102,8 +131,8 @@ func (s sdk) CheckRequirements(ctx context.Context, region string) error {
 	serviceLongArnFormat := settings.Settings[0].Value
 	if *serviceLongArnFormat != "enabled" {
 		return fmt.Errorf("this tool requires the \"new ARN resource ID format\".\n"+
-			"Check https://%s.console.aws.amazon.com/ecs/home#/settings\n"+
-			"Learn more: https://aws.amazon.com/blogs/compute/migrating-your-amazon-ecs-deployment-to-the-new-arn-and-resource-id-format-2", region)
+			"Check %s/ecs/home?region=%s#/settings\n"+
+			"Learn more: https://aws.amazon.com/blogs/compute/migrating-your-amazon-ecs-deployment-to-the-new-arn-and-resource-id-format-2", consoleURL(region), region)
 	}
 	return nil
 }
@@ -126,6 +155,26 @@ func (s sdk) ResolveCluster(ctx context.Context, nameOrArn string) (awsResource,
 	}, nil
 }
 
+// ContainerInsightsEnabled tells if CloudWatch Container Insights collects metrics of cluster tasks
+func (s sdk) ContainerInsightsEnabled(ctx context.Context, cluster string) (bool, error) {
+	clusters, err := s.ECS.DescribeClustersWithContext(ctx, &ecs.DescribeClustersInput{
+		Clusters: aws.StringSlice([]string{cluster}),
+		Include:  aws.StringSlice([]string{ecs.ClusterFieldSettings}),
+	})
+	if err != nil {
+		return false, err
+	}
+	if len(clusters.Clusters) == 0 {
+		return false, errors.Wrapf(errdefs.ErrNotFound, "cluster %q does not exist", cluster)
+	}
+	for _, setting := range clusters.Clusters[0].Settings {
+		if aws.StringValue(setting.Name) == ecs.ClusterSettingNameContainerInsights {
+			return aws.StringValue(setting.Value) == "enabled", nil
+		}
+	}
+	return false, nil
+}
+
 func (s sdk) CreateCluster(ctx context.Context, name string) (string, error) {
 	logrus.Debug("Create cluster ", name)
 	response, err := s.ECS.CreateClusterWithContext(ctx, &ecs.CreateClusterInput{ClusterName: aws.String(name)})
@@ -150,6 +199,104 @@ func (s sdk) CheckVPC(ctx context.Context, vpcID string) error {
 	return nil
 }
 
+// CheckSubnets makes sure subnets belong to VPC, span multiple availability zones and, when internet is set, have a
+// default route tasks reach the internet by
+func (s sdk) CheckSubnets(ctx context.Context, vpcID string, subnets []string, internet bool) error {
+	logrus.Debug("Check subnets ", subnets)
+	output, err := s.EC2.DescribeSubnetsWithContext(ctx, &ec2.DescribeSubnetsInput{
+		SubnetIds: aws.StringSlice(subnets),
+	})
+	if err != nil {
+		return err
+	}
+	zones := map[string]bool{}
+	for _, subnet := range output.Subnets {
+		if aws.StringValue(subnet.VpcId) != vpcID {
+			return fmt.Errorf("subnet %q doesn't belong to VPC %s", aws.StringValue(subnet.SubnetId), vpcID)
+		}
+		zones[aws.StringValue(subnet.AvailabilityZone)] = true
+	}
+	if len(zones) < 2 {
+		return fmt.Errorf("subnets %s should be in at least 2 availability zones", strings.Join(subnets, ", "))
+	}
+	if !internet {
+		return nil
+	}
+
+	tables, err := s.EC2.DescribeRouteTablesWithContext(ctx, &ec2.DescribeRouteTablesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("vpc-id"),
+				Values: []*string{aws.String(vpcID)},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	hasInternet := subnetsInternetRoutes(tables.RouteTables, subnets)
+	for _, subnet := range subnets {
+		if !hasInternet[subnet] {
+			return fmt.Errorf("subnet %q has no route to the internet tasks pull images by, set %s to deploy in private subnets", subnet, extensionVPCEndpoints)
+		}
+	}
+	return nil
+}
+
+// subnetsInternetRoutes tells which subnets have a default route, through their own route table or the VPC main one
+func subnetsInternetRoutes(tables []*ec2.RouteTable, subnets []string) map[string]bool {
+	var (
+		main        bool
+		hasInternet = map[string]bool{}
+		associated  = map[string]bool{}
+	)
+	for _, table := range tables {
+		defaultRoute := false
+		for _, route := range table.Routes {
+			if aws.StringValue(route.DestinationCidrBlock) == "0.0.0.0/0" && aws.StringValue(route.State) == ec2.RouteStateActive {
+				defaultRoute = true
+			}
+		}
+		for _, association := range table.Associations {
+			if aws.BoolValue(association.Main) {
+				main = defaultRoute
+				continue
+			}
+			subnet := aws.StringValue(association.SubnetId)
+			associated[subnet] = true
+			hasInternet[subnet] = defaultRoute
+		}
+	}
+	for _, subnet := range subnets {
+		if !associated[subnet] {
+			hasInternet[subnet] = main
+		}
+	}
+	return hasInternet
+}
+
+func (s sdk) CheckSubnetsIPv6(ctx context.Context, subnets []string) error {
+	logrus.Debug("Check IPv6 CIDR blocks of subnets ", subnets)
+	output, err := s.EC2.DescribeSubnetsWithContext(ctx, &ec2.DescribeSubnetsInput{
+		SubnetIds: aws.StringSlice(subnets),
+	})
+	if err != nil {
+		return err
+	}
+	for _, subnet := range output.Subnets {
+		hasIPv6 := false
+		for _, association := range subnet.Ipv6CidrBlockAssociationSet {
+			if association.Ipv6CidrBlockState != nil && aws.StringValue(association.Ipv6CidrBlockState.State) == ec2.SubnetCidrBlockStateCodeAssociated {
+				hasIPv6 = true
+			}
+		}
+		if !hasIPv6 {
+			return fmt.Errorf("subnet %q has no IPv6 CIDR block, dual-stack requires IPv6 enabled subnets", aws.StringValue(subnet.SubnetId))
+		}
+	}
+	return nil
+}
+
 func (s sdk) GetDefaultVPC(ctx context.Context) (string, error) {
 	logrus.Debug("Retrieve default VPC")
 	vpcs, err := s.EC2.DescribeVpcsWithContext(ctx, &ec2.DescribeVpcsInput{
@@ -194,6 +341,48 @@ func (s sdk) GetSubNets(ctx context.Context, vpcID string) ([]awsResource, error
 	return ids, nil
 }
 
+// GetRouteTables returns the route tables associated with subnets, including the VPC main route table for subnets
+// without explicit association
+func (s sdk) GetRouteTables(ctx context.Context, vpcID string, subnets []string) ([]string, error) {
+	logrus.Debug("Retrieve route tables of subnets ", subnets)
+	tables, err := s.EC2.DescribeRouteTablesWithContext(ctx, &ec2.DescribeRouteTablesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("vpc-id"),
+				Values: []*string{aws.String(vpcID)},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var (
+		ids        []string
+		main       string
+		associated = map[string]bool{}
+	)
+	for _, table := range tables.RouteTables {
+		id := aws.StringValue(table.RouteTableId)
+		for _, association := range table.Associations {
+			if aws.BoolValue(association.Main) {
+				main = id
+				continue
+			}
+			subnet := aws.StringValue(association.SubnetId)
+			if contains(subnets, subnet) && !associated[subnet] {
+				associated[subnet] = true
+				if !contains(ids, id) {
+					ids = append(ids, id)
+				}
+			}
+		}
+	}
+	if len(associated) < len(subnets) && main != "" && !contains(ids, main) {
+		ids = append(ids, main)
+	}
+	return ids, nil
+}
+
 func (s sdk) GetRoleArn(ctx context.Context, name string) (string, error) {
 	role, err := s.IAM.GetRoleWithContext(ctx, &iam.GetRoleInput{
 		RoleName: aws.String(name),
@@ -221,23 +410,43 @@ func (s sdk) CreateStack(ctx context.Context, name string, template []byte) erro
 	logrus.Debug("Create CloudFormation stack")
 
 	_, err := s.CF.CreateStackWithContext(ctx, &cloudformation.CreateStackInput{
-		OnFailure:        aws.String("DELETE"),
-		StackName:        aws.String(name),
-		TemplateBody:     aws.String(string(template)),
-		TimeoutInMinutes: nil,
+		ClientRequestToken: aws.String(newRequestToken("create")),
+		OnFailure:          aws.String("DELETE"),
+		StackName:          aws.String(name),
+		TemplateBody:       aws.String(string(template)),
+		TimeoutInMinutes:   nil,
 		Capabilities: []*string{
 			aws.String(cloudformation.CapabilityCapabilityIam),
 		},
-		Tags: []*cloudformation.Tag{
-			{
-				Key:   aws.String(compose.ProjectTag),
-				Value: aws.String(name),
-			},
-		},
+		Tags: s.stackTags(name),
 	})
 	return err
 }
 
+// stackTags returns the tags set on stack, which CloudFormation propagates to the resources it creates
+func (s sdk) stackTags(name string) []*cloudformation.Tag {
+	var keys []string
+	for k := range s.tags {
+		if k != compose.ProjectTag {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	stackTags := []*cloudformation.Tag{
+		{
+			Key:   aws.String(compose.ProjectTag),
+			Value: aws.String(name),
+		},
+	}
+	for _, k := range keys {
+		stackTags = append(stackTags, &cloudformation.Tag{
+			Key:   aws.String(k),
+			Value: aws.String(s.tags[k]),
+		})
+	}
+	return stackTags
+}
+
 func (s sdk) CreateChangeSet(ctx context.Context, name string, template []byte) (string, error) {
 	logrus.Debug("Create CloudFormation Changeset")
 
@@ -245,11 +454,13 @@ func (s sdk) CreateChangeSet(ctx context.Context, name string, template []byte)
 	changeset, err := s.CF.CreateChangeSetWithContext(ctx, &cloudformation.CreateChangeSetInput{
 		ChangeSetName: aws.String(update),
 		ChangeSetType: aws.String(cloudformation.ChangeSetTypeUpdate),
+		ClientToken:   aws.String(newRequestToken("changeset")),
 		StackName:     aws.String(name),
 		TemplateBody:  aws.String(string(template)),
 		Capabilities: []*string{
 			aws.String(cloudformation.CapabilityCapabilityIam),
 		},
+		Tags: s.stackTags(name),
 	})
 	if err != nil {
 		return "", err
@@ -261,6 +472,56 @@ func (s sdk) CreateChangeSet(ctx context.Context, name string, template []byte)
 	return *changeset.Id, err
 }
 
+func (s sdk) DescribeChangeSet(ctx context.Context, changeset string) ([]resourceChange, error) {
+	var (
+		changes []resourceChange
+		token   *string
+	)
+	for {
+		desc, err := s.CF.DescribeChangeSetWithContext(ctx, &cloudformation.DescribeChangeSetInput{
+			ChangeSetName: aws.String(changeset),
+			NextToken:     token,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range desc.Changes {
+			r := c.ResourceChange
+			if r == nil {
+				continue
+			}
+			change := resourceChange{
+				Action:      aws.StringValue(r.Action),
+				LogicalID:   aws.StringValue(r.LogicalResourceId),
+				Type:        aws.StringValue(r.ResourceType),
+				Replacement: aws.StringValue(r.Replacement),
+			}
+			for _, d := range r.Details {
+				if d.Target == nil || aws.StringValue(d.Target.RequiresRecreation) == cloudformation.RequiresRecreationNever {
+					continue
+				}
+				name := aws.StringValue(d.Target.Name)
+				if name != "" && !contains(change.Causes, name) {
+					change.Causes = append(change.Causes, name)
+				}
+			}
+			changes = append(changes, change)
+		}
+		if desc.NextToken == nil {
+			return changes, nil
+		}
+		token = desc.NextToken
+	}
+}
+
+func (s sdk) DeleteChangeSet(ctx context.Context, changeset string) error {
+	logrus.Debug("Delete CloudFormation Changeset")
+	_, err := s.CF.DeleteChangeSetWithContext(ctx, &cloudformation.DeleteChangeSetInput{
+		ChangeSetName: aws.String(changeset),
+	})
+	return err
+}
+
 func (s sdk) UpdateStack(ctx context.Context, changeset string) error {
 	desc, err := s.CF.DescribeChangeSetWithContext(ctx, &cloudformation.DescribeChangeSetInput{
 		ChangeSetName: aws.String(changeset),
@@ -273,8 +534,9 @@ func (s sdk) UpdateStack(ctx context.Context, changeset string) error {
 		return nil
 	}
 
-	_, err = s.CF.ExecuteChangeSet(&cloudformation.ExecuteChangeSetInput{
-		ChangeSetName: aws.String(changeset),
+	_, err = s.CF.ExecuteChangeSetWithContext(ctx, &cloudformation.ExecuteChangeSetInput{
+		ChangeSetName:      aws.String(changeset),
+		ClientRequestToken: aws.String(newRequestToken("update")),
 	})
 	return err
 }
@@ -291,14 +553,35 @@ func (s sdk) WaitStackComplete(ctx context.Context, name string, operation int)
 	}
 	switch operation {
 	case stackCreate:
-		return s.CF.WaitUntilStackCreateCompleteWithContext(ctx, input)
+		return s.CF.WaitUntilStackCreateCompleteWithContext(ctx, input, waiterOptions(ctx)...)
+	case stackUpdate:
+		return s.waitStackStable(ctx, input)
 	case stackDelete:
-		return s.CF.WaitUntilStackDeleteCompleteWithContext(ctx, input)
+		return s.CF.WaitUntilStackDeleteCompleteWithContext(ctx, input, waiterOptions(ctx)...)
 	default:
 		return fmt.Errorf("internal error: unexpected stack operation %d", operation)
 	}
 }
 
+// waitStackStable waits for stack to leave any in progress status. Unlike WaitUntilStackUpdateComplete, it doesn't
+// block when change set had no changes and stack wasn't updated.
+func (s sdk) waitStackStable(ctx context.Context, input *cloudformation.DescribeStacksInput) error {
+	for {
+		stacks, err := s.CF.DescribeStacksWithContext(ctx, input)
+		if err != nil {
+			return err
+		}
+		if len(stacks.Stacks) == 0 || !strings.HasSuffix(aws.StringValue(stacks.Stacks[0].StackStatus), "_IN_PROGRESS") {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pollInterval(ctx, 5*time.Second)):
+		}
+	}
+}
+
 func (s sdk) GetStackID(ctx context.Context, name string) (string, error) {
 	stacks, err := s.CF.DescribeStacksWithContext(ctx, &cloudformation.DescribeStacksInput{
 		StackName: aws.String(name),
@@ -383,23 +666,37 @@ type templateMetadata struct {
 
 func (s sdk) GetServiceTaskDefinition(ctx context.Context, cluster string, serviceArns []string) (map[string]string, error) {
 	defs := map[string]string{}
-	svc := []*string{}
-	for _, s := range serviceArns {
-		svc = append(svc, aws.String(s))
-	}
-	services, err := s.ECS.DescribeServicesWithContext(ctx, &ecs.DescribeServicesInput{
-		Cluster:  aws.String(cluster),
-		Services: svc,
-	})
+	services, _, err := s.describeServices(ctx, cluster, serviceArns)
 	if err != nil {
 		return nil, err
 	}
-	for _, s := range services.Services {
+	for _, s := range services {
 		defs[aws.StringValue(s.ServiceArn)] = aws.StringValue(s.TaskDefinition)
 	}
 	return defs, nil
 }
 
+// describeServices describes services by batches, as DescribeServices accepts a limited number of them
+func (s sdk) describeServices(ctx context.Context, cluster string, arns []string, include ...string) ([]*ecs.Service, []*ecs.Failure, error) {
+	var (
+		services []*ecs.Service
+		failures []*ecs.Failure
+	)
+	for _, batch := range batches(arns, describeServicesBatch) {
+		out, err := s.ECS.DescribeServicesWithContext(ctx, &ecs.DescribeServicesInput{
+			Cluster:  aws.String(cluster),
+			Services: aws.StringSlice(batch),
+			Include:  aws.StringSlice(include),
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		services = append(services, out.Services...)
+		failures = append(failures, out.Failures...)
+	}
+	return services, failures, nil
+}
+
 func (s sdk) ListStackServices(ctx context.Context, stack string) ([]string, error) {
 	arns := []string{}
 	var nextToken *string
@@ -431,25 +728,30 @@ func (s sdk) GetServiceTasks(ctx context.Context, cluster string, service string
 	if stopped {
 		state = "STOPPED"
 	}
-	tasks, err := s.ECS.ListTasksWithContext(ctx, &ecs.ListTasksInput{
+	var arns []*string
+	err := s.ECS.ListTasksPagesWithContext(ctx, &ecs.ListTasksInput{
 		Cluster:       aws.String(cluster),
 		ServiceName:   aws.String(service),
 		DesiredStatus: aws.String(state),
+	}, func(page *ecs.ListTasksOutput, lastPage bool) bool {
+		arns = append(arns, page.TaskArns...)
+		return true
 	})
 	if err != nil {
 		return nil, err
 	}
-	if len(tasks.TaskArns) > 0 {
+	var tasks []*ecs.Task
+	for _, batch := range batches(aws.StringValueSlice(arns), describeTasksBatch) {
 		taskDescriptions, err := s.ECS.DescribeTasksWithContext(ctx, &ecs.DescribeTasksInput{
 			Cluster: aws.String(cluster),
-			Tasks:   tasks.TaskArns,
+			Tasks:   aws.StringSlice(batch),
 		})
 		if err != nil {
 			return nil, err
 		}
-		return taskDescriptions.Tasks, nil
+		tasks = append(tasks, taskDescriptions.Tasks...)
 	}
-	return nil, nil
+	return tasks, nil
 }
 
 func (s sdk) GetTaskStoppedReason(ctx context.Context, cluster string, taskArn string) (string, error) {
@@ -507,6 +809,85 @@ func (s sdk) ListStackParameters(ctx context.Context, name string) (map[string]s
 	return parameters, nil
 }
 
+func (s sdk) ListStackOutputs(ctx context.Context, name string) (map[string]string, error) {
+	st, err := s.CF.DescribeStacksWithContext(ctx, &cloudformation.DescribeStacksInput{
+		StackName: aws.String(name),
+	})
+	if err != nil {
+		return nil, err
+	}
+	outputs := map[string]string{}
+	for _, output := range st.Stacks[0].Outputs {
+		outputs[aws.StringValue(output.OutputKey)] = aws.StringValue(output.OutputValue)
+	}
+	return outputs, nil
+}
+
+func (s sdk) GetStackTemplate(ctx context.Context, name string) ([]byte, error) {
+	template, err := s.CF.GetTemplateWithContext(ctx, &cloudformation.GetTemplateInput{
+		StackName:     aws.String(name),
+		TemplateStage: aws.String(cloudformation.TemplateStageOriginal),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []byte(aws.StringValue(template.TemplateBody)), nil
+}
+
+func (s sdk) GetStackTags(ctx context.Context, name string) (map[string]string, error) {
+	st, err := s.CF.DescribeStacksWithContext(ctx, &cloudformation.DescribeStacksInput{
+		StackName: aws.String(name),
+	})
+	if err != nil {
+		return nil, err
+	}
+	tags := map[string]string{}
+	for _, tag := range st.Stacks[0].Tags {
+		tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return tags, nil
+}
+
+// UpdateStackTags replaces stack tags, keeping its template and parameters
+func (s sdk) UpdateStackTags(ctx context.Context, name string, tags map[string]string) error {
+	st, err := s.CF.DescribeStacksWithContext(ctx, &cloudformation.DescribeStacksInput{
+		StackName: aws.String(name),
+	})
+	if err != nil {
+		return err
+	}
+	var parameters []*cloudformation.Parameter
+	for _, parameter := range st.Stacks[0].Parameters {
+		parameters = append(parameters, &cloudformation.Parameter{
+			ParameterKey:     parameter.ParameterKey,
+			UsePreviousValue: aws.Bool(true),
+		})
+	}
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	stackTags := []*cloudformation.Tag{}
+	for _, k := range keys {
+		stackTags = append(stackTags, &cloudformation.Tag{
+			Key:   aws.String(k),
+			Value: aws.String(tags[k]),
+		})
+	}
+	_, err = s.CF.UpdateStackWithContext(ctx, &cloudformation.UpdateStackInput{
+		ClientRequestToken:  aws.String(newRequestToken("tags")),
+		StackName:           aws.String(name),
+		UsePreviousTemplate: aws.Bool(true),
+		Parameters:          parameters,
+		Capabilities: []*string{
+			aws.String(cloudformation.CapabilityCapabilityIam),
+		},
+		Tags: stackTags,
+	})
+	return err
+}
+
 type stackResource struct {
 	LogicalID string
 	Type      string
@@ -555,7 +936,8 @@ func (s sdk) ListStackResources(ctx context.Context, name string) (stackResource
 func (s sdk) DeleteStack(ctx context.Context, name string) error {
 	logrus.Debug("Delete CloudFormation stack")
 	_, err := s.CF.DeleteStackWithContext(ctx, &cloudformation.DeleteStackInput{
-		StackName: aws.String(name),
+		ClientRequestToken: aws.String(newRequestToken("delete")),
+		StackName:          aws.String(name),
 	})
 	return err
 }
@@ -564,31 +946,37 @@ func (s sdk) CreateSecret(ctx context.Context, secret secrets.Secret) (string, e
 	logrus.Debug("Create secret " + secret.Name)
 	var tags []*secretsmanager.Tag
 	for k, v := range secret.Labels {
-		tags = []*secretsmanager.Tag{
-			{
-				Key:   aws.String(k),
-				Value: aws.String(v),
-			},
-		}
+		tags = append(tags, &secretsmanager.Tag{
+			Key:   aws.String(k),
+			Value: aws.String(v),
+		})
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return aws.StringValue(tags[i].Key) < aws.StringValue(tags[j].Key)
+	})
+	var description *string
+	if secret.Description != "" {
+		description = aws.String(secret.Description)
 	}
 	// store the secret content as string
 	content := string(secret.GetContent())
-	response, err := s.SM.CreateSecret(&secretsmanager.CreateSecretInput{
+	response, err := s.SM.CreateSecretWithContext(ctx, &secretsmanager.CreateSecretInput{
 		Name:         &secret.Name,
+		Description:  description,
 		SecretString: &content,
 		Tags:         tags,
 	})
 	if err != nil {
-		return "", err
+		return "", secretError(err, secret.Name)
 	}
 	return aws.StringValue(response.ARN), nil
 }
 
 func (s sdk) InspectSecret(ctx context.Context, id string) (secrets.Secret, error) {
 	logrus.Debug("Inspect secret " + id)
-	response, err := s.SM.DescribeSecret(&secretsmanager.DescribeSecretInput{SecretId: &id})
+	response, err := s.SM.DescribeSecretWithContext(ctx, &secretsmanager.DescribeSecretInput{SecretId: &id})
 	if err != nil {
-		return secrets.Secret{}, err
+		return secrets.Secret{}, secretError(err, id)
 	}
 	tags := map[string]string{}
 	for _, tag := range response.Tags {
@@ -596,93 +984,380 @@ func (s sdk) InspectSecret(ctx context.Context, id string) (secrets.Secret, erro
 	}
 
 	secret := secrets.Secret{
-		ID:     aws.StringValue(response.ARN),
-		Name:   aws.StringValue(response.Name),
-		Labels: tags,
+		ID:          aws.StringValue(response.ARN),
+		Name:        aws.StringValue(response.Name),
+		Description: aws.StringValue(response.Description),
+		Labels:      tags,
 	}
 	return secret, nil
 }
 
 func (s sdk) ListSecrets(ctx context.Context) ([]secrets.Secret, error) {
 	logrus.Debug("List secrets ...")
-	response, err := s.SM.ListSecrets(&secretsmanager.ListSecretsInput{})
-	if err != nil {
-		return nil, err
-	}
-
 	var ls []secrets.Secret
-	for _, sec := range response.SecretList {
-
-		tags := map[string]string{}
-		for _, tag := range sec.Tags {
-			tags[*tag.Key] = *tag.Value
+	err := s.SM.ListSecretsPagesWithContext(ctx, &secretsmanager.ListSecretsInput{}, func(response *secretsmanager.ListSecretsOutput, lastPage bool) bool {
+		for _, sec := range response.SecretList {
+			tags := map[string]string{}
+			for _, tag := range sec.Tags {
+				tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+			}
+			ls = append(ls, secrets.Secret{
+				ID:          aws.StringValue(sec.ARN),
+				Name:        aws.StringValue(sec.Name),
+				Description: aws.StringValue(sec.Description),
+				Labels:      tags,
+			})
 		}
-		ls = append(ls, secrets.Secret{
-			ID:     *sec.ARN,
-			Name:   *sec.Name,
-			Labels: tags,
-		})
+		return true
+	})
+	if err != nil {
+		return nil, err
 	}
 	return ls, nil
 }
 
 func (s sdk) DeleteSecret(ctx context.Context, id string, recover bool) error {
-	logrus.Debug("List secrets ...")
+	logrus.Debug("Delete secret " + id)
 	force := !recover
-	_, err := s.SM.DeleteSecret(&secretsmanager.DeleteSecretInput{SecretId: &id, ForceDeleteWithoutRecovery: &force})
+	_, err := s.SM.DeleteSecretWithContext(ctx, &secretsmanager.DeleteSecretInput{SecretId: &id, ForceDeleteWithoutRecovery: &force})
+	return secretError(err, id)
+}
+
+// secretError maps Secrets Manager errors on secret id to the matching errdefs error
+func secretError(err error, id string) error {
+	if aerr, ok := err.(awserr.Error); ok {
+		switch aerr.Code() {
+		case secretsmanager.ErrCodeResourceNotFoundException:
+			return errors.Wrapf(errdefs.ErrNotFound, "secret %q", id)
+		case secretsmanager.ErrCodeResourceExistsException:
+			return errors.Wrapf(errdefs.ErrAlreadyExists, "secret %q", id)
+		}
+	}
 	return err
 }
 
-func (s sdk) GetLogs(ctx context.Context, name string, consumer func(service, container, message string)) error {
+// GetLogs sends the project's CloudWatch log events to consumer, starting with the last opts.Tail events of each
+// container and polling for new events as long as opts.Follow is set and ctx is not done
+// logsIngestionDelay is how late, in milliseconds, events can be ingested by CloudWatch Logs and still be followed
+const logsIngestionDelay = int64(time.Minute / time.Millisecond)
+
+func (s sdk) GetLogs(ctx context.Context, name string, consumer func(service, container, message string, timestamp time.Time), opts compose.LogOptions) error {
 	logGroup := fmt.Sprintf("/docker-compose/%s", name)
 	var startTime int64
-	for {
-		select {
-		case <-ctx.Done():
+	if !opts.Since.IsZero() {
+		startTime = toMillis(opts.Since)
+	}
+
+	// events ingested late have a timestamp older than the latest one sent, so following polls again from
+	// logsIngestionDelay before it. seen indexes events already sent by ID, or by content for tailed events which
+	// have none, with their timestamp.
+	var latest int64
+	seen := map[string]int64{}
+	emit := func(id, stream, message string, timestamp int64) {
+		key := fmt.Sprintf("%s/%d/%s", stream, timestamp, message)
+		if _, ok := seen[key]; ok {
+			return
+		}
+		if _, ok := seen[id]; ok && id != "" {
+			return
+		}
+		seen[key] = timestamp
+		if id != "" {
+			seen[id] = timestamp
+		}
+		if timestamp > latest {
+			latest = timestamp
+		}
+		p := strings.SplitN(stream, "/", 3)
+		if len(p) < 3 {
+			return
+		}
+		consumer(p[1], p[2], message, time.Unix(0, timestamp*int64(time.Millisecond)))
+	}
+
+	switch {
+	case opts.Tail == 0:
+		if !opts.Follow {
 			return nil
-		default:
-			var hasMore = true
-			var token *string
-			for hasMore {
-				events, err := s.CW.FilterLogEvents(&cloudwatchlogs.FilterLogEventsInput{
-					LogGroupName: aws.String(logGroup),
-					NextToken:    token,
-					StartTime:    aws.Int64(startTime),
-				})
-				if err != nil {
-					return err
-				}
-				if events.NextToken == nil {
-					hasMore = false
-				} else {
-					token = events.NextToken
-				}
+		}
+		now := toMillis(time.Now())
+		if now > startTime {
+			startTime = now
+		}
+	case opts.Tail > 0:
+		err := s.tailLogs(ctx, logGroup, startTime, opts.Tail, emit)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if !opts.Follow {
+			return nil
+		}
+	}
 
-				for _, event := range events.Events {
-					p := strings.Split(aws.StringValue(event.LogStreamName), "/")
-					consumer(p[1], p[2], aws.StringValue(event.Message))
-					startTime = *event.IngestionTime
+	for {
+		from := startTime
+		if latest-logsIngestionDelay > from {
+			from = latest - logsIngestionDelay
+		}
+		var token *string
+		for {
+			events, err := s.CW.FilterLogEventsWithContext(ctx, &cloudwatchlogs.FilterLogEventsInput{
+				LogGroupName: aws.String(logGroup),
+				NextToken:    token,
+				StartTime:    aws.Int64(from),
+			})
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
 				}
+				return err
+			}
+			for _, event := range events.Events {
+				emit(aws.StringValue(event.EventId), aws.StringValue(event.LogStreamName), aws.StringValue(event.Message), aws.Int64Value(event.Timestamp))
+			}
+			token = events.NextToken
+			if token == nil {
+				break
+			}
+		}
+		// next polls start at or after from, and won't return older events again
+		for key, timestamp := range seen {
+			if timestamp < from {
+				delete(seen, key)
 			}
 		}
-		time.Sleep(500 * time.Millisecond)
+		if !opts.Follow {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(500 * time.Millisecond):
+		}
 	}
 }
 
-func (s sdk) DescribeService(ctx context.Context, cluster string, arn string) (compose.ServiceStatus, error) {
-	services, err := s.ECS.DescribeServicesWithContext(ctx, &ecs.DescribeServicesInput{
-		Cluster:  aws.String(cluster),
-		Services: []*string{aws.String(arn)},
-		Include:  aws.StringSlice([]string{"TAGS"}),
+// tailLogs sends the last tail events of each log stream updated after startTime, ordered by timestamp
+func (s sdk) tailLogs(ctx context.Context, logGroup string, startTime int64, tail int, emit func(id, stream, message string, timestamp int64)) error {
+	type logEvent struct {
+		stream    string
+		message   string
+		timestamp int64
+	}
+	var events []logEvent
+	var token *string
+	for {
+		streams, err := s.CW.DescribeLogStreamsWithContext(ctx, &cloudwatchlogs.DescribeLogStreamsInput{
+			LogGroupName: aws.String(logGroup),
+			OrderBy:      aws.String(cloudwatchlogs.OrderByLastEventTime),
+			Descending:   aws.Bool(true),
+			NextToken:    token,
+		})
+		if err != nil {
+			return err
+		}
+		done := false
+		for _, stream := range streams.LogStreams {
+			if stream.LastEventTimestamp == nil {
+				continue
+			}
+			if aws.Int64Value(stream.LastEventTimestamp) < startTime {
+				done = true
+				break
+			}
+			output, err := s.CW.GetLogEventsWithContext(ctx, &cloudwatchlogs.GetLogEventsInput{
+				LogGroupName:  aws.String(logGroup),
+				LogStreamName: stream.LogStreamName,
+				StartTime:     aws.Int64(startTime),
+				StartFromHead: aws.Bool(false),
+				Limit:         aws.Int64(int64(tail)),
+			})
+			if err != nil {
+				return err
+			}
+			for _, event := range output.Events {
+				events = append(events, logEvent{
+					stream:    aws.StringValue(stream.LogStreamName),
+					message:   aws.StringValue(event.Message),
+					timestamp: aws.Int64Value(event.Timestamp),
+				})
+			}
+		}
+		token = streams.NextToken
+		if done || token == nil {
+			break
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].timestamp < events[j].timestamp
 	})
-	if err != nil {
-		return compose.ServiceStatus{}, err
+	for _, event := range events {
+		emit("", event.stream, event.message, event.timestamp)
 	}
+	return nil
+}
+
+func toMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
 
+// RunTask runs a standalone task with service task definition and network configuration, overriding container command
+func (s sdk) RunTask(ctx context.Context, cluster string, service string, container string, command []string) (string, error) {
+	services, err := s.ECS.DescribeServicesWithContext(ctx, &ecs.DescribeServicesInput{
+		Cluster:  aws.String(cluster),
+		Services: []*string{aws.String(service)},
+	})
+	if err != nil {
+		return "", err
+	}
 	for _, f := range services.Failures {
-		return compose.ServiceStatus{}, errors.Wrapf(errdefs.ErrNotFound, "can't get service status %s: %s", aws.StringValue(f.Detail), aws.StringValue(f.Reason))
+		return "", errors.Wrapf(errdefs.ErrNotFound, "can't get service %s: %s", aws.StringValue(f.Detail), aws.StringValue(f.Reason))
+	}
+	svc := services.Services[0]
+	input := &ecs.RunTaskInput{
+		Cluster:              aws.String(cluster),
+		Count:                aws.Int64(1),
+		NetworkConfiguration: svc.NetworkConfiguration,
+		PlatformVersion:      svc.PlatformVersion,
+		PropagateTags:        aws.String(ecs.PropagateTagsTaskDefinition),
+		StartedBy:            aws.String("docker-compose"),
+		TaskDefinition:       svc.TaskDefinition,
+	}
+	if len(svc.CapacityProviderStrategy) > 0 {
+		input.CapacityProviderStrategy = svc.CapacityProviderStrategy
+	} else {
+		input.LaunchType = svc.LaunchType
+	}
+	if len(command) > 0 {
+		input.Overrides = &ecs.TaskOverride{
+			ContainerOverrides: []*ecs.ContainerOverride{
+				{
+					Name:    aws.String(container),
+					Command: aws.StringSlice(command),
+				},
+			},
+		}
 	}
-	service := services.Services[0]
+	logrus.Debugf("Run task for service %s", service)
+	tasks, err := s.ECS.RunTaskWithContext(ctx, input)
+	if err != nil {
+		return "", err
+	}
+	for _, f := range tasks.Failures {
+		return "", fmt.Errorf("can't run task: %s %s", aws.StringValue(f.Reason), aws.StringValue(f.Detail))
+	}
+	return aws.StringValue(tasks.Tasks[0].TaskArn), nil
+}
+
+// WaitTaskStopped waits for task to stop and returns the exit code of container
+func (s sdk) WaitTaskStopped(ctx context.Context, cluster string, task string, container string) (int, error) {
+	for {
+		tasks, err := s.ECS.DescribeTasksWithContext(ctx, &ecs.DescribeTasksInput{
+			Cluster: aws.String(cluster),
+			Tasks:   []*string{aws.String(task)},
+		})
+		if err != nil {
+			return 0, err
+		}
+		if len(tasks.Tasks) == 0 {
+			return 0, errors.Wrapf(errdefs.ErrNotFound, "task %s", task)
+		}
+		t := tasks.Tasks[0]
+		if aws.StringValue(t.LastStatus) == ecs.DesiredStatusStopped {
+			for _, c := range t.Containers {
+				if aws.StringValue(c.Name) != container {
+					continue
+				}
+				if c.ExitCode != nil {
+					return int(aws.Int64Value(c.ExitCode)), nil
+				}
+				return 0, fmt.Errorf("task stopped: %s %s", aws.StringValue(t.StoppedReason), aws.StringValue(c.Reason))
+			}
+			return 0, fmt.Errorf("task stopped: %s", aws.StringValue(t.StoppedReason))
+		}
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+// ExecuteCommand opens an SSM session to run an interactive command in container
+func (s sdk) ExecuteCommand(ctx context.Context, cluster string, task string, container string, command string) (execSession, error) {
+	output, err := s.ECS.ExecuteCommandWithContext(ctx, &ecs.ExecuteCommandInput{
+		Cluster:     aws.String(cluster),
+		Command:     aws.String(command),
+		Container:   aws.String(container),
+		Interactive: aws.Bool(true),
+		Task:        aws.String(task),
+	})
+	if err != nil {
+		return execSession{}, err
+	}
+	if output.Session == nil {
+		return execSession{}, fmt.Errorf("ECS didn't open a session to execute command in %s", task)
+	}
+	return s.execSession(output.Session.SessionId, output.Session.StreamUrl, output.Session.TokenValue)
+}
+
+// StartPortForwardingSession opens an SSM session forwarding localPort to port of target
+func (s sdk) StartPortForwardingSession(ctx context.Context, target string, port int, localPort int) (execSession, error) {
+	output, err := s.SSM.StartSessionWithContext(ctx, &ssm.StartSessionInput{
+		DocumentName: aws.String(portForwardingDocument),
+		Parameters: map[string][]*string{
+			"portNumber":      aws.StringSlice([]string{strconv.Itoa(port)}),
+			"localPortNumber": aws.StringSlice([]string{strconv.Itoa(localPort)}),
+		},
+		Target: aws.String(target),
+	})
+	if err != nil {
+		return execSession{}, err
+	}
+	return s.execSession(output.SessionId, output.StreamUrl, output.TokenValue)
+}
+
+// execSession completes an SSM session with the region and SSM endpoint session manager plugin connects to
+func (s sdk) execSession(id *string, streamURL *string, token *string) (execSession, error) {
+	endpoint, err := endpoints.DefaultResolver().EndpointFor(ssm.EndpointsID, s.region)
+	if err != nil {
+		return execSession{}, err
+	}
+	return execSession{
+		SessionID:  aws.StringValue(id),
+		StreamURL:  aws.StringValue(streamURL),
+		TokenValue: aws.StringValue(token),
+		Region:     s.region,
+		Endpoint:   endpoint.URL,
+	}, nil
+}
+
+// DescribeServices returns the status of services, in the same order. Load balancers and tasks of services are
+// described concurrently.
+func (s sdk) DescribeServices(ctx context.Context, cluster string, arns []string) ([]compose.ServiceStatus, error) {
+	services, failures, err := s.describeServices(ctx, cluster, arns, "TAGS")
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range failures {
+		return nil, errors.Wrapf(errdefs.ErrNotFound, "can't get service status %s: %s", aws.StringValue(f.Detail), aws.StringValue(f.Reason))
+	}
+	status := make([]compose.ServiceStatus, len(services))
+	err = concurrently(ctx, len(services), func(ctx context.Context, i int) error {
+		st, err := s.serviceStatus(ctx, cluster, services[i])
+		status[i] = st
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+func (s sdk) serviceStatus(ctx context.Context, cluster string, service *ecs.Service) (compose.ServiceStatus, error) {
 	var name string
 	for _, t := range service.Tags {
 		if *t.Key == compose.ServiceTag {
@@ -702,15 +1377,104 @@ func (s sdk) DescribeService(ctx context.Context, cluster string, arn string) (c
 	if err != nil {
 		return compose.ServiceStatus{}, err
 	}
+	tasks, err := s.describeServiceTasks(ctx, cluster, aws.StringValue(service.ServiceName), targetGroupArns)
+	if err != nil {
+		return compose.ServiceStatus{}, err
+	}
 	return compose.ServiceStatus{
 		ID:         aws.StringValue(service.ServiceName),
 		Name:       name,
 		Replicas:   int(aws.Int64Value(service.RunningCount)),
 		Desired:    int(aws.Int64Value(service.DesiredCount)),
 		Publishers: loadBalancers,
+		Rollout:    rolloutState(service),
+		Tasks:      tasks,
 	}, nil
 }
 
+// GetServiceEvents returns the compose service name of an ECS service and its latest events, newest first
+func (s sdk) GetServiceEvents(ctx context.Context, cluster string, arn string) (string, []*ecs.ServiceEvent, error) {
+	services, err := s.ECS.DescribeServicesWithContext(ctx, &ecs.DescribeServicesInput{
+		Cluster:  aws.String(cluster),
+		Services: []*string{aws.String(arn)},
+		Include:  aws.StringSlice([]string{"TAGS"}),
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	for _, f := range services.Failures {
+		return "", nil, errors.Wrapf(errdefs.ErrNotFound, "can't get service events %s: %s", aws.StringValue(f.Detail), aws.StringValue(f.Reason))
+	}
+	service := services.Services[0]
+	for _, t := range service.Tags {
+		if aws.StringValue(t.Key) == compose.ServiceTag {
+			return aws.StringValue(t.Value), service.Events, nil
+		}
+	}
+	return "", nil, fmt.Errorf("service %s doesn't have a %s tag", aws.StringValue(service.ServiceArn), compose.ServiceTag)
+}
+
+// GetServiceNames returns the compose service names of services, by ARN
+func (s sdk) GetServiceNames(ctx context.Context, cluster string, arns []string) (map[string]string, error) {
+	services, failures, err := s.describeServices(ctx, cluster, arns, "TAGS")
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range failures {
+		return nil, errors.Wrapf(errdefs.ErrNotFound, "can't get service %s: %s", aws.StringValue(f.Arn), aws.StringValue(f.Reason))
+	}
+	names := map[string]string{}
+	for _, service := range services {
+		arn := aws.StringValue(service.ServiceArn)
+		for _, t := range service.Tags {
+			if aws.StringValue(t.Key) == compose.ServiceTag {
+				names[arn] = aws.StringValue(t.Value)
+			}
+		}
+		if _, ok := names[arn]; !ok {
+			return nil, fmt.Errorf("service %s doesn't have a %s tag", arn, compose.ServiceTag)
+		}
+	}
+	return names, nil
+}
+
+func (s sdk) describeServiceTasks(ctx context.Context, cluster string, service string, targetGroupArns []string) ([]compose.TaskStatus, error) {
+	tasks, err := s.GetServiceTasks(ctx, cluster, service, false)
+	if err != nil || len(tasks) == 0 {
+		return nil, err
+	}
+	targetHealth := map[string]string{}
+	for _, arn := range targetGroupArns {
+		health, err := s.ELB.DescribeTargetHealthWithContext(ctx, &elbv2.DescribeTargetHealthInput{
+			TargetGroupArn: aws.String(arn),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, target := range health.TargetHealthDescriptions {
+			targetHealth[aws.StringValue(target.Target.Id)] = aws.StringValue(target.TargetHealth.State)
+		}
+	}
+	var interfaces []string
+	for _, task := range tasks {
+		if id, _ := taskNetworkInterface(task); id != "" {
+			interfaces = append(interfaces, id)
+		}
+	}
+	publicIPs := map[string]string{}
+	if len(interfaces) > 0 {
+		publicIPs, err = s.GetPublicIPs(ctx, interfaces...)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var status []compose.TaskStatus
+	for _, task := range tasks {
+		status = append(status, taskStatus(task, targetHealth, publicIPs))
+	}
+	return status, nil
+}
+
 func (s sdk) getURLWithPortMapping(ctx context.Context, targetGroupArns []string) ([]compose.PortPublisher, error) {
 	if len(targetGroupArns) == 0 {
 		return nil, nil
@@ -822,6 +1586,20 @@ func (s sdk) ResolveLoadBalancer(ctx context.Context, nameOrarn string) (awsReso
 	}, aws.StringValue(it.Type), nil
 }
 
+func (s sdk) GetLoadBalancerListeners(ctx context.Context, arn string) (map[int64]string, error) {
+	logrus.Debug("Retrieve load balancer listeners: ", arn)
+	listeners := map[int64]string{}
+	err := s.ELB.DescribeListenersPagesWithContext(ctx, &elbv2.DescribeListenersInput{
+		LoadBalancerArn: aws.String(arn),
+	}, func(page *elbv2.DescribeListenersOutput, lastPage bool) bool {
+		for _, l := range page.Listeners {
+			listeners[aws.Int64Value(l.Port)] = aws.StringValue(l.ListenerArn)
+		}
+		return true
+	})
+	return listeners, err
+}
+
 func (s sdk) GetLoadBalancerURL(ctx context.Context, arn string) (string, error) {
 	logrus.Debug("Retrieve load balancer URL: ", arn)
 	lbs, err := s.ELB.DescribeLoadBalancersWithContext(ctx, &elbv2.DescribeLoadBalancersInput{
@@ -929,6 +1707,47 @@ func (s sdk) ListFileSystems(ctx context.Context, tags map[string]string) ([]aws
 	}
 }
 
+func (s sdk) DescribeFileSystems(ctx context.Context) ([]fileSystem, error) {
+	var results []fileSystem
+	var token *string
+	for {
+		desc, err := s.EFS.DescribeFileSystemsWithContext(ctx, &efs.DescribeFileSystemsInput{
+			Marker: token,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, filesystem := range desc.FileSystems {
+			fs := fileSystem{
+				ID:    aws.StringValue(filesystem.FileSystemId),
+				ARN:   aws.StringValue(filesystem.FileSystemArn),
+				State: aws.StringValue(filesystem.LifeCycleState),
+				Tags:  map[string]string{},
+			}
+			if filesystem.SizeInBytes != nil {
+				fs.SizeInBytes = aws.Int64Value(filesystem.SizeInBytes.Value)
+			}
+			for _, t := range filesystem.Tags {
+				fs.Tags[aws.StringValue(t.Key)] = aws.StringValue(t.Value)
+			}
+			accessPoints, err := s.EFS.DescribeAccessPointsWithContext(ctx, &efs.DescribeAccessPointsInput{
+				FileSystemId: filesystem.FileSystemId,
+			})
+			if err != nil {
+				return nil, err
+			}
+			for _, ap := range accessPoints.AccessPoints {
+				fs.AccessPoints = append(fs.AccessPoints, aws.StringValue(ap.AccessPointId))
+			}
+			results = append(results, fs)
+		}
+		if desc.NextMarker == nil {
+			return results, nil
+		}
+		token = desc.NextMarker
+	}
+}
+
 func containsAll(tags []*efs.Tag, required map[string]string) bool {
 TAGS:
 	for key, value := range required {
@@ -985,9 +1804,482 @@ func (s sdk) CreateFileSystem(ctx context.Context, tags map[string]string, optio
 	}, nil
 }
 
+// CreateAccessPoint creates an access point to a file system, once available
+func (s sdk) CreateAccessPoint(ctx context.Context, id string, tags map[string]string, options VolumeCreateOptions) (string, error) {
+	for {
+		desc, err := s.EFS.DescribeFileSystemsWithContext(ctx, &efs.DescribeFileSystemsInput{
+			FileSystemId: aws.String(id),
+		})
+		if err != nil {
+			return "", err
+		}
+		if len(desc.FileSystems) > 0 && aws.StringValue(desc.FileSystems[0].LifeCycleState) == efs.LifeCycleStateAvailable {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
+	}
+	var efsTags []*efs.Tag
+	for k, v := range tags {
+		efsTags = append(efsTags, &efs.Tag{
+			Key:   aws.String(k),
+			Value: aws.String(v),
+		})
+	}
+	input := &efs.CreateAccessPointInput{
+		FileSystemId: aws.String(id),
+		Tags:         efsTags,
+	}
+	if options.UID != "" {
+		uid, err := strconv.ParseInt(options.UID, 10, 64)
+		if err != nil {
+			return "", err
+		}
+		gid, err := strconv.ParseInt(options.GID, 10, 64)
+		if err != nil {
+			return "", err
+		}
+		input.PosixUser = &efs.PosixUser{Uid: aws.Int64(uid), Gid: aws.Int64(gid)}
+	}
+	if options.RootDirectory != "" {
+		input.RootDirectory = &efs.RootDirectory{Path: aws.String(options.RootDirectory)}
+		if input.PosixUser != nil {
+			permissions := options.Permissions
+			if permissions == "" {
+				permissions = "0755"
+			}
+			input.RootDirectory.CreationInfo = &efs.CreationInfo{
+				OwnerUid:    input.PosixUser.Uid,
+				OwnerGid:    input.PosixUser.Gid,
+				Permissions: aws.String(permissions),
+			}
+		}
+	}
+	ap, err := s.EFS.CreateAccessPointWithContext(ctx, input)
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(ap.AccessPointId), nil
+}
+
+// DeleteFileSystem deletes a file system and its access points
 func (s sdk) DeleteFileSystem(ctx context.Context, id string) error {
-	_, err := s.EFS.DeleteFileSystemWithContext(ctx, &efs.DeleteFileSystemInput{
+	accessPoints, err := s.EFS.DescribeAccessPointsWithContext(ctx, &efs.DescribeAccessPointsInput{
 		FileSystemId: aws.String(id),
 	})
+	if err != nil {
+		return err
+	}
+	for _, ap := range accessPoints.AccessPoints {
+		_, err := s.EFS.DeleteAccessPointWithContext(ctx, &efs.DeleteAccessPointInput{
+			AccessPointId: ap.AccessPointId,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	_, err = s.EFS.DeleteFileSystemWithContext(ctx, &efs.DeleteFileSystemInput{
+		FileSystemId: aws.String(id),
+	})
+	return err
+}
+
+func (s sdk) DeployBlueGreen(ctx context.Context, deployment blueGreenDeployment) (string, error) {
+	err := s.ensureDeploymentConfig(ctx, deployment.Config)
+	if err != nil {
+		return "", err
+	}
+	err = s.ensureDeploymentGroup(ctx, deployment)
+	if err != nil {
+		return "", err
+	}
+
+	appSpec, err := json.Marshal(map[string]interface{}{
+		"version": "0.0",
+		"Resources": []interface{}{
+			map[string]interface{}{
+				"TargetService": map[string]interface{}{
+					"Type": "AWS::ECS::Service",
+					"Properties": map[string]interface{}{
+						"TaskDefinition": deployment.TaskDefinition,
+						"LoadBalancerInfo": map[string]interface{}{
+							"ContainerName": deployment.ContainerName,
+							"ContainerPort": deployment.ContainerPort,
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	logrus.Debugf("Create CodeDeploy deployment for service %s", deployment.Service)
+	created, err := s.CD.CreateDeploymentWithContext(ctx, &codedeploy.CreateDeploymentInput{
+		ApplicationName:     aws.String(deployment.Application),
+		DeploymentGroupName: aws.String(deployment.DeploymentGroup),
+		Revision: &codedeploy.RevisionLocation{
+			RevisionType: aws.String(codedeploy.RevisionLocationTypeAppSpecContent),
+			AppSpecContent: &codedeploy.AppSpecContent{
+				Content: aws.String(string(appSpec)),
+			},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(created.DeploymentId), nil
+}
+
+// ensureDeploymentConfig creates the CodeDeploy deployment configuration for canary or linear traffic shifting
+func (s sdk) ensureDeploymentConfig(ctx context.Context, config blueGreenConfig) error {
+	if config.Strategy != blueGreenCanary && config.Strategy != blueGreenLinear {
+		return nil
+	}
+	name := config.deploymentConfigName()
+	_, err := s.CD.GetDeploymentConfigWithContext(ctx, &codedeploy.GetDeploymentConfigInput{
+		DeploymentConfigName: aws.String(name),
+	})
+	if err == nil {
+		return nil
+	}
+	if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != codedeploy.ErrCodeDeploymentConfigDoesNotExistException {
+		return err
+	}
+
+	routing := &codedeploy.TrafficRoutingConfig{}
+	if config.Strategy == blueGreenCanary {
+		routing.Type = aws.String(codedeploy.TrafficRoutingTypeTimeBasedCanary)
+		routing.TimeBasedCanary = &codedeploy.TimeBasedCanary{
+			CanaryInterval:   aws.Int64(int64(config.Interval)),
+			CanaryPercentage: aws.Int64(int64(config.Percentage)),
+		}
+	} else {
+		routing.Type = aws.String(codedeploy.TrafficRoutingTypeTimeBasedLinear)
+		routing.TimeBasedLinear = &codedeploy.TimeBasedLinear{
+			LinearInterval:   aws.Int64(int64(config.Interval)),
+			LinearPercentage: aws.Int64(int64(config.Percentage)),
+		}
+	}
+	logrus.Debugf("Create CodeDeploy deployment configuration %s", name)
+	_, err = s.CD.CreateDeploymentConfigWithContext(ctx, &codedeploy.CreateDeploymentConfigInput{
+		ComputePlatform:      aws.String(codedeploy.ComputePlatformEcs),
+		DeploymentConfigName: aws.String(name),
+		TrafficRoutingConfig: routing,
+	})
+	return err
+}
+
+// ensureDeploymentGroup creates or updates the CodeDeploy deployment group of an ECS service, which rolls back
+// deployment on failure or when one of the configured alarms is triggered
+func (s sdk) ensureDeploymentGroup(ctx context.Context, deployment blueGreenDeployment) error {
+	var alarms []*codedeploy.Alarm
+	for _, alarm := range deployment.Config.Alarms {
+		alarms = append(alarms, &codedeploy.Alarm{Name: aws.String(alarm)})
+	}
+	alarmConfiguration := &codedeploy.AlarmConfiguration{
+		Enabled: aws.Bool(len(alarms) > 0),
+		Alarms:  alarms,
+	}
+	rollback := &codedeploy.AutoRollbackConfiguration{
+		Enabled: aws.Bool(true),
+		Events: aws.StringSlice([]string{
+			codedeploy.AutoRollbackEventDeploymentFailure,
+			codedeploy.AutoRollbackEventDeploymentStopOnAlarm,
+		}),
+	}
+
+	services := []*codedeploy.ECSService{
+		{
+			ClusterName: aws.String(deployment.Cluster),
+			ServiceName: aws.String(deployment.Service),
+		},
+	}
+
+	_, err := s.CD.GetDeploymentGroupWithContext(ctx, &codedeploy.GetDeploymentGroupInput{
+		ApplicationName:     aws.String(deployment.Application),
+		DeploymentGroupName: aws.String(deployment.DeploymentGroup),
+	})
+	if err == nil {
+		_, err = s.CD.UpdateDeploymentGroupWithContext(ctx, &codedeploy.UpdateDeploymentGroupInput{
+			ApplicationName:            aws.String(deployment.Application),
+			CurrentDeploymentGroupName: aws.String(deployment.DeploymentGroup),
+			DeploymentConfigName:       aws.String(deployment.Config.deploymentConfigName()),
+			ServiceRoleArn:             aws.String(deployment.ServiceRole),
+			EcsServices:                services,
+			LoadBalancerInfo:           deploymentLoadBalancerInfo(deployment),
+			AlarmConfiguration:         alarmConfiguration,
+			AutoRollbackConfiguration:  rollback,
+		})
+		return err
+	}
+	if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != codedeploy.ErrCodeDeploymentGroupDoesNotExistException {
+		return err
+	}
+
+	logrus.Debugf("Create CodeDeploy deployment group %s", deployment.DeploymentGroup)
+	_, err = s.CD.CreateDeploymentGroupWithContext(ctx, &codedeploy.CreateDeploymentGroupInput{
+		ApplicationName:      aws.String(deployment.Application),
+		DeploymentGroupName:  aws.String(deployment.DeploymentGroup),
+		DeploymentConfigName: aws.String(deployment.Config.deploymentConfigName()),
+		ServiceRoleArn:       aws.String(deployment.ServiceRole),
+		DeploymentStyle: &codedeploy.DeploymentStyle{
+			DeploymentOption: aws.String(codedeploy.DeploymentOptionWithTrafficControl),
+			DeploymentType:   aws.String(codedeploy.DeploymentTypeBlueGreen),
+		},
+		BlueGreenDeploymentConfiguration: &codedeploy.BlueGreenDeploymentConfiguration{
+			DeploymentReadyOption: &codedeploy.DeploymentReadyOption{
+				ActionOnTimeout: aws.String(codedeploy.DeploymentReadyActionContinueDeployment),
+			},
+			TerminateBlueInstancesOnDeploymentSuccess: &codedeploy.BlueInstanceTerminationOption{
+				Action:                       aws.String(codedeploy.InstanceActionTerminate),
+				TerminationWaitTimeInMinutes: aws.Int64(5),
+			},
+		},
+		EcsServices:               services,
+		LoadBalancerInfo:          deploymentLoadBalancerInfo(deployment),
+		AlarmConfiguration:        alarmConfiguration,
+		AutoRollbackConfiguration: rollback,
+	})
+	return err
+}
+
+// deploymentLoadBalancerInfo pairs the blue and green target groups with the production listener, and the test
+// listener if any, CodeDeploy reroutes to the green target group during deployment
+func deploymentLoadBalancerInfo(deployment blueGreenDeployment) *codedeploy.LoadBalancerInfo {
+	var targetGroups []*codedeploy.TargetGroupInfo
+	for _, tg := range deployment.TargetGroups {
+		targetGroups = append(targetGroups, &codedeploy.TargetGroupInfo{Name: aws.String(tg)})
+	}
+	pair := &codedeploy.TargetGroupPairInfo{
+		TargetGroups: targetGroups,
+		ProdTrafficRoute: &codedeploy.TrafficRoute{
+			ListenerArns: aws.StringSlice([]string{deployment.Listener}),
+		},
+	}
+	if deployment.TestListener != "" {
+		pair.TestTrafficRoute = &codedeploy.TrafficRoute{
+			ListenerArns: aws.StringSlice([]string{deployment.TestListener}),
+		}
+	}
+	return &codedeploy.LoadBalancerInfo{
+		TargetGroupPairInfoList: []*codedeploy.TargetGroupPairInfo{pair},
+	}
+}
+
+func (s sdk) WaitDeploymentComplete(ctx context.Context, id string) error {
+	return s.CD.WaitUntilDeploymentSuccessfulWithContext(ctx, &codedeploy.GetDeploymentInput{
+		DeploymentId: aws.String(id),
+	}, waiterOptions(ctx)...)
+}
+
+func (s sdk) ScaleService(ctx context.Context, cluster string, arn string, count int) error {
+	_, err := s.ECS.UpdateServiceWithContext(ctx, &ecs.UpdateServiceInput{
+		Cluster:      aws.String(cluster),
+		Service:      aws.String(arn),
+		DesiredCount: aws.Int64(int64(count)),
+	})
+	return err
+}
+
+// RestartService starts a new deployment of service, replacing its tasks with new ones
+func (s sdk) RestartService(ctx context.Context, cluster string, arn string) error {
+	_, err := s.ECS.UpdateServiceWithContext(ctx, &ecs.UpdateServiceInput{
+		Cluster:            aws.String(cluster),
+		Service:            aws.String(arn),
+		ForceNewDeployment: aws.Bool(true),
+	})
+	return err
+}
+
+// StopTask stops a running task, sending SIGTERM then SIGKILL to its containers once their stop timeout expires
+func (s sdk) StopTask(ctx context.Context, cluster string, task string, reason string) error {
+	_, err := s.ECS.StopTaskWithContext(ctx, &ecs.StopTaskInput{
+		Cluster: aws.String(cluster),
+		Task:    aws.String(task),
+		Reason:  aws.String(reason),
+	})
+	return err
+}
+
+// RegisterTaskDefinitionRevision registers a copy of a task definition, with images and environment of containers
+// replaced by updates
+func (s sdk) RegisterTaskDefinitionRevision(ctx context.Context, taskDefinition string, updates map[string]containerUpdate) (string, error) {
+	desc, err := s.ECS.DescribeTaskDefinitionWithContext(ctx, &ecs.DescribeTaskDefinitionInput{
+		TaskDefinition: aws.String(taskDefinition),
+		Include:        aws.StringSlice([]string{ecs.TaskDefinitionFieldTags}),
+	})
+	if err != nil {
+		return "", err
+	}
+	def := desc.TaskDefinition
+	for _, c := range def.ContainerDefinitions {
+		update, ok := updates[aws.StringValue(c.Name)]
+		if !ok {
+			continue
+		}
+		c.Image = aws.String(update.Image)
+		c.Environment = update.Environment
+	}
+	input := &ecs.RegisterTaskDefinitionInput{
+		ContainerDefinitions:    def.ContainerDefinitions,
+		Cpu:                     def.Cpu,
+		ExecutionRoleArn:        def.ExecutionRoleArn,
+		Family:                  def.Family,
+		InferenceAccelerators:   def.InferenceAccelerators,
+		IpcMode:                 def.IpcMode,
+		Memory:                  def.Memory,
+		NetworkMode:             def.NetworkMode,
+		PidMode:                 def.PidMode,
+		PlacementConstraints:    def.PlacementConstraints,
+		ProxyConfiguration:      def.ProxyConfiguration,
+		RequiresCompatibilities: def.RequiresCompatibilities,
+		TaskRoleArn:             def.TaskRoleArn,
+		Volumes:                 def.Volumes,
+	}
+	if len(desc.Tags) > 0 {
+		input.Tags = desc.Tags
+	}
+	registered, err := s.ECS.RegisterTaskDefinitionWithContext(ctx, input)
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(registered.TaskDefinition.TaskDefinitionArn), nil
+}
+
+func (s sdk) UpdateServiceTaskDefinition(ctx context.Context, cluster string, arn string, taskDefinition string) error {
+	_, err := s.ECS.UpdateServiceWithContext(ctx, &ecs.UpdateServiceInput{
+		Cluster:        aws.String(cluster),
+		Service:        aws.String(arn),
+		TaskDefinition: aws.String(taskDefinition),
+	})
+	return err
+}
+
+// ListTaskDefinitionRevisions returns the active task definition revisions of families tagged with project, newest
+// first, by family. All revisions of a family share the project tag, so only the latest one is described.
+func (s sdk) ListTaskDefinitionRevisions(ctx context.Context, project string) (map[string][]string, error) {
+	var families []*string
+	err := s.ECS.ListTaskDefinitionFamiliesPagesWithContext(ctx, &ecs.ListTaskDefinitionFamiliesInput{
+		FamilyPrefix: aws.String(project + "-"),
+		Status:       aws.String(ecs.TaskDefinitionFamilyStatusActive),
+	}, func(page *ecs.ListTaskDefinitionFamiliesOutput, lastPage bool) bool {
+		families = append(families, page.Families...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	revisions := map[string][]string{}
+	for _, family := range families {
+		var arns []*string
+		err := s.ECS.ListTaskDefinitionsPagesWithContext(ctx, &ecs.ListTaskDefinitionsInput{
+			FamilyPrefix: family,
+			Sort:         aws.String(ecs.SortOrderDesc),
+			Status:       aws.String(ecs.TaskDefinitionStatusActive),
+		}, func(page *ecs.ListTaskDefinitionsOutput, lastPage bool) bool {
+			arns = append(arns, page.TaskDefinitionArns...)
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(arns) == 0 {
+			continue
+		}
+		desc, err := s.ECS.DescribeTaskDefinitionWithContext(ctx, &ecs.DescribeTaskDefinitionInput{
+			TaskDefinition: arns[0],
+			Include:        aws.StringSlice([]string{ecs.TaskDefinitionFieldTags}),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range desc.Tags {
+			if aws.StringValue(tag.Key) == compose.ProjectTag && aws.StringValue(tag.Value) == project {
+				revisions[aws.StringValue(family)] = aws.StringValueSlice(arns)
+			}
+		}
+	}
+	return revisions, nil
+}
+
+func (s sdk) DeregisterTaskDefinition(ctx context.Context, arn string) error {
+	_, err := s.ECS.DeregisterTaskDefinitionWithContext(ctx, &ecs.DeregisterTaskDefinitionInput{
+		TaskDefinition: aws.String(arn),
+	})
+	return err
+}
+
+func (s sdk) WaitServicesStable(ctx context.Context, cluster string, arns []string) error {
+	return s.ECS.WaitUntilServicesStableWithContext(ctx, &ecs.DescribeServicesInput{
+		Cluster:  aws.String(cluster),
+		Services: aws.StringSlice(arns),
+	}, waiterOptions(ctx)...)
+}
+
+// waiterOptions applies wait options to AWS waiters. With a timeout set, waiters only stop on ctx deadline rather than
+// after their default number of attempts.
+func waiterOptions(ctx context.Context) []request.WaiterOption {
+	var options []request.WaiterOption
+	opts := getWaitOptions(ctx)
+	if opts.Interval > 0 {
+		options = append(options, request.WithWaiterDelay(request.ConstantWaiterDelay(opts.Interval)))
+	}
+	if opts.Timeout > 0 {
+		options = append(options, request.WithWaiterMaxAttempts(math.MaxInt32))
+	}
+	return options
+}
+
+// pollInterval returns interval set by wait options, or defaultInterval
+func pollInterval(ctx context.Context, defaultInterval time.Duration) time.Duration {
+	if interval := getWaitOptions(ctx).Interval; interval > 0 {
+		return interval
+	}
+	return defaultInterval
+}
+
+func (s sdk) EnsureRepository(ctx context.Context, name string, tags map[string]string) (string, error) {
+	desc, err := s.ECR.DescribeRepositoriesWithContext(ctx, &ecr.DescribeRepositoriesInput{
+		RepositoryNames: aws.StringSlice([]string{name}),
+	})
+	if err == nil && len(desc.Repositories) > 0 {
+		return aws.StringValue(desc.Repositories[0].RepositoryUri), nil
+	}
+	if aerr, ok := err.(awserr.Error); err != nil && (!ok || aerr.Code() != ecr.ErrCodeRepositoryNotFoundException) {
+		return "", err
+	}
+
+	var ecrTags []*ecr.Tag
+	for k, v := range tags {
+		ecrTags = append(ecrTags, &ecr.Tag{
+			Key:   aws.String(k),
+			Value: aws.String(v),
+		})
+	}
+	logrus.Debugf("creating ECR repository %q", name)
+	repository, err := s.ECR.CreateRepositoryWithContext(ctx, &ecr.CreateRepositoryInput{
+		RepositoryName: aws.String(name),
+		ImageScanningConfiguration: &ecr.ImageScanningConfiguration{
+			ScanOnPush: aws.Bool(true),
+		},
+		Tags: ecrTags,
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(repository.Repository.RepositoryUri), nil
+}
+
+// PublishNotification publishes message to an SNS topic
+func (s sdk) PublishNotification(ctx context.Context, topic string, subject string, message string) error {
+	_, err := s.SNS.PublishWithContext(ctx, &sns.PublishInput{
+		Message:  aws.String(message),
+		Subject:  aws.String(subject),
+		TopicArn: aws.String(topic),
+	})
 	return err
 }
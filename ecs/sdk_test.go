@@ -0,0 +1,66 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/codedeploy"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func TestStackTags(t *testing.T) {
+	s := sdk{tags: map[string]string{
+		"team":             "payments",
+		"cost-center":      "42",
+		compose.ProjectTag: "ignored",
+	}}
+	assert.DeepEqual(t, s.stackTags("myproject"), []*cloudformation.Tag{
+		{Key: aws.String(compose.ProjectTag), Value: aws.String("myproject")},
+		{Key: aws.String("cost-center"), Value: aws.String("42")},
+		{Key: aws.String("team"), Value: aws.String("payments")},
+	})
+}
+
+func TestDeploymentLoadBalancerInfo(t *testing.T) {
+	deployment := blueGreenDeployment{
+		TargetGroups: []string{"blue", "green"},
+		Listener:     "arn:aws:elasticloadbalancing:eu-west-3:123456789012:listener/app/lb/1234/80",
+	}
+	prod := &codedeploy.TrafficRoute{ListenerArns: aws.StringSlice([]string{deployment.Listener})}
+	targetGroups := []*codedeploy.TargetGroupInfo{{Name: aws.String("blue")}, {Name: aws.String("green")}}
+	assert.DeepEqual(t, deploymentLoadBalancerInfo(deployment), &codedeploy.LoadBalancerInfo{
+		TargetGroupPairInfoList: []*codedeploy.TargetGroupPairInfo{
+			{TargetGroups: targetGroups, ProdTrafficRoute: prod},
+		},
+	})
+
+	deployment.TestListener = "arn:aws:elasticloadbalancing:eu-west-3:123456789012:listener/app/lb/1234/8080"
+	assert.DeepEqual(t, deploymentLoadBalancerInfo(deployment), &codedeploy.LoadBalancerInfo{
+		TargetGroupPairInfoList: []*codedeploy.TargetGroupPairInfo{
+			{
+				TargetGroups:     targetGroups,
+				ProdTrafficRoute: prod,
+				TestTrafficRoute: &codedeploy.TrafficRoute{ListenerArns: aws.StringSlice([]string{deployment.TestListener})},
+			},
+		},
+	})
+}
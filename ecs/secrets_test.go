@@ -0,0 +1,111 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/secrets"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+type secretsClient struct {
+	secretsmanageriface.SecretsManagerAPI
+	created *secretsmanager.CreateSecretInput
+	pages   [][]*secretsmanager.SecretListEntry
+}
+
+func (c *secretsClient) CreateSecretWithContext(ctx aws.Context, input *secretsmanager.CreateSecretInput, opts ...request.Option) (*secretsmanager.CreateSecretOutput, error) {
+	if aws.StringValue(input.Name) == "existing" {
+		return nil, awserr.New(secretsmanager.ErrCodeResourceExistsException, "secret already exists", nil)
+	}
+	c.created = input
+	return &secretsmanager.CreateSecretOutput{ARN: aws.String("arn:aws:secretsmanager:eu-west-3:xxx:secret:" + aws.StringValue(input.Name))}, nil
+}
+
+func (c *secretsClient) DescribeSecretWithContext(ctx aws.Context, input *secretsmanager.DescribeSecretInput, opts ...request.Option) (*secretsmanager.DescribeSecretOutput, error) {
+	return nil, awserr.New(secretsmanager.ErrCodeResourceNotFoundException, "secret not found", nil)
+}
+
+func (c *secretsClient) ListSecretsPagesWithContext(ctx aws.Context, input *secretsmanager.ListSecretsInput, fn func(*secretsmanager.ListSecretsOutput, bool) bool, opts ...request.Option) error {
+	for i, page := range c.pages {
+		if !fn(&secretsmanager.ListSecretsOutput{SecretList: page}, i == len(c.pages)-1) {
+			break
+		}
+	}
+	return nil
+}
+
+func TestCreateSecret(t *testing.T) {
+	client := &secretsClient{}
+	s := sdk{SM: client}
+	secret := secrets.NewSecret("db-password", []byte("pass1"))
+	secret.Description = "database password"
+	secret.Labels = map[string]string{"team": "payments", "env": "production"}
+
+	arn, err := s.CreateSecret(context.Background(), secret)
+	assert.NilError(t, err)
+	assert.Equal(t, arn, "arn:aws:secretsmanager:eu-west-3:xxx:secret:db-password")
+	assert.DeepEqual(t, client.created, &secretsmanager.CreateSecretInput{
+		Name:         aws.String("db-password"),
+		Description:  aws.String("database password"),
+		SecretString: aws.String("pass1"),
+		Tags: []*secretsmanager.Tag{
+			{Key: aws.String("env"), Value: aws.String("production")},
+			{Key: aws.String("team"), Value: aws.String("payments")},
+		},
+	})
+
+	_, err = s.CreateSecret(context.Background(), secrets.NewSecret("existing", []byte("pass1")))
+	assert.Check(t, errdefs.IsAlreadyExistsError(err))
+}
+
+func TestInspectMissingSecret(t *testing.T) {
+	s := sdk{SM: &secretsClient{}}
+	_, err := s.InspectSecret(context.Background(), "missing")
+	assert.Check(t, errdefs.IsNotFoundError(err))
+}
+
+func TestListSecretsPages(t *testing.T) {
+	s := sdk{SM: &secretsClient{
+		pages: [][]*secretsmanager.SecretListEntry{
+			{
+				{ARN: aws.String("arn1"), Name: aws.String("first"), Description: aws.String("first secret")},
+			},
+			{
+				{ARN: aws.String("arn2"), Name: aws.String("second"), Tags: []*secretsmanager.Tag{
+					{Key: aws.String("team"), Value: aws.String("payments")},
+				}},
+			},
+		},
+	}}
+	ls, err := s.ListSecrets(context.Background())
+	assert.NilError(t, err)
+	assert.DeepEqual(t, ls, []secrets.Secret{
+		{ID: "arn1", Name: "first", Description: "first secret", Labels: map[string]string{}},
+		{ID: "arn2", Name: "second", Labels: map[string]string{"team": "payments"}},
+	}, cmpopts.IgnoreUnexported(secrets.Secret{}))
+}
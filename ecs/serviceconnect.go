@@ -0,0 +1,168 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/go-units"
+)
+
+// serviceConnectConfig is the x-aws-service_connect project configuration, set to true to use defaults
+type serviceConnectConfig struct {
+	// Proxy reserves resources for the Service Connect proxy ECS adds to tasks, on top of services limits
+	Proxy *serviceConnectProxy `json:"proxy,omitempty"`
+}
+
+type serviceConnectProxy struct {
+	CPUs   string `json:"cpus,omitempty"`
+	Memory string `json:"memory,omitempty"`
+}
+
+func getServiceConnectConfig(project *types.Project) (*serviceConnectConfig, error) {
+	v, ok := project.Extensions[extensionServiceConnect]
+	if !ok {
+		return nil, nil
+	}
+	config := serviceConnectConfig{}
+	if enabled, ok := v.(bool); ok {
+		if !enabled {
+			return nil, nil
+		}
+	} else {
+		marshalled, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal(marshalled, &config)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be true or a Service Connect configuration: %w", extensionServiceConnect, err)
+		}
+	}
+	if mesh, err := getAppMeshConfig(project); err == nil && mesh != nil {
+		return nil, fmt.Errorf("%s can't be used with %s", extensionServiceConnect, extensionAppMesh)
+	}
+	return &config, nil
+}
+
+// useServiceConnect tells if service joins the Service Connect namespace rather than registering in Cloud Map DNS
+func useServiceConnect(project *types.Project, service types.ServiceConfig) bool {
+	if isScheduled(project, service.Name) {
+		return false
+	}
+	config, err := getServiceConnectConfig(project)
+	return err == nil && config != nil
+}
+
+// serviceConnectProxySize returns the CPU, in thousandths of CPU, and memory to reserve in service tasks for the
+// Service Connect proxy
+func serviceConnectProxySize(project *types.Project, service types.ServiceConfig) (int64, types.UnitBytes, error) {
+	if isScheduled(project, service.Name) {
+		return 0, 0, nil
+	}
+	c, err := getServiceConnectConfig(project)
+	if err != nil || c == nil || c.Proxy == nil {
+		return 0, 0, err
+	}
+	var (
+		cpu int64
+		mem int64
+	)
+	if c.Proxy.CPUs != "" {
+		cpus, err := strconv.ParseFloat(c.Proxy.CPUs, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("%s: invalid proxy cpus %q", extensionServiceConnect, c.Proxy.CPUs)
+		}
+		cpu = int64(cpus * 1000)
+	}
+	if c.Proxy.Memory != "" {
+		var err error
+		mem, err = units.RAMInBytes(c.Proxy.Memory)
+		if err != nil {
+			return 0, 0, fmt.Errorf("%s: invalid proxy memory %q", extensionServiceConnect, c.Proxy.Memory)
+		}
+	}
+	return cpu, types.UnitBytes(mem), nil
+}
+
+var invalidPortNameChars = regexp.MustCompile("[^a-z0-9-]+")
+
+// serviceConnectPortNames returns the names of service ports, which Service Connect refers to them by. Ports
+// publishing an already named target port, or using UDP, get no name.
+func serviceConnectPortNames(service types.ServiceConfig) []string {
+	var names []string
+	named := map[uint32]bool{}
+	for _, port := range service.Ports {
+		if named[port.Target] || strings.EqualFold(port.Protocol, "udp") {
+			names = append(names, "")
+			continue
+		}
+		named[port.Target] = true
+		name := invalidPortNameChars.ReplaceAllString(strings.ToLower(service.Name), "-")
+		names = append(names, fmt.Sprintf("%s-%d", name, port.Target))
+	}
+	return names
+}
+
+// serviceConnectPortMappings returns the extra properties naming container port mappings
+func serviceConnectPortMappings(service types.ServiceConfig) []interface{} {
+	var mappings []interface{}
+	for _, name := range serviceConnectPortNames(service) {
+		if name == "" {
+			mappings = append(mappings, nil)
+			continue
+		}
+		mappings = append(mappings, map[string]interface{}{"Name": name})
+	}
+	return mappings
+}
+
+// createServiceConnect returns the ServiceConnectConfiguration of service, which makes each named port reachable by
+// other services as <service>:<port>
+func createServiceConnect(project *types.Project, service types.ServiceConfig) map[string]interface{} {
+	var services []interface{}
+	for i, name := range serviceConnectPortNames(service) {
+		if name == "" {
+			continue
+		}
+		services = append(services, map[string]interface{}{
+			"PortName":      name,
+			"DiscoveryName": name,
+			"ClientAliases": []interface{}{
+				map[string]interface{}{
+					"DnsName": service.Name,
+					"Port":    service.Ports[i].Target,
+				},
+			},
+		})
+	}
+	config := map[string]interface{}{
+		"Enabled":          true,
+		"Namespace":        cloudformation.GetAtt("CloudMap", "Arn"),
+		"LogConfiguration": getLogConfiguration(service, project),
+	}
+	if len(services) > 0 {
+		config["Services"] = services
+	}
+	return config
+}
@@ -0,0 +1,122 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"gotest.tools/v3/assert"
+)
+
+func TestServiceConnect(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  web:
+    image: nginx
+    ports:
+      - 80:80
+  client:
+    image: curl
+x-aws-service_connect: true
+`, useDefaultVPC)
+	_, ok := template.Resources["WebServiceDiscoveryEntry"]
+	assert.Check(t, !ok)
+	assert.Equal(t, len(template.Resources["WebService"].(*ecs.Service).ServiceRegistries), 0)
+
+	marshalled, err := marshall(template)
+	assert.NilError(t, err)
+	var parsed struct {
+		Resources map[string]struct {
+			Properties map[string]interface{}
+		}
+	}
+	assert.NilError(t, json.Unmarshal(marshalled, &parsed))
+
+	web := parsed.Resources["WebService"].Properties["ServiceConnectConfiguration"].(map[string]interface{})
+	assert.Equal(t, web["Enabled"], true)
+	assert.DeepEqual(t, web["Namespace"], map[string]interface{}{"Fn::GetAtt": []interface{}{"CloudMap", "Arn"}})
+	assert.DeepEqual(t, web["Services"], []interface{}{
+		map[string]interface{}{
+			"PortName":      "web-80",
+			"DiscoveryName": "web-80",
+			"ClientAliases": []interface{}{
+				map[string]interface{}{"DnsName": "web", "Port": float64(80)},
+			},
+		},
+	})
+	client := parsed.Resources["ClientService"].Properties["ServiceConnectConfiguration"].(map[string]interface{})
+	assert.Check(t, client["Services"] == nil)
+
+	definitions := parsed.Resources["WebTaskDefinition"].Properties["ContainerDefinitions"].([]interface{})
+	var main map[string]interface{}
+	for _, d := range definitions {
+		if d.(map[string]interface{})["Name"] == "web" {
+			main = d.(map[string]interface{})
+		}
+	}
+	mapping := main["PortMappings"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, mapping["Name"], "web-80")
+	assert.Equal(t, mapping["ContainerPort"], float64(80))
+}
+
+func TestServiceConnectProxySize(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  web:
+    image: nginx
+    ports:
+      - 80:80
+x-aws-service_connect:
+  proxy:
+    cpus: "0.25"
+    memory: 64M
+`, useDefaultVPC)
+	def := template.Resources["WebTaskDefinition"].(*ecs.TaskDefinition)
+	assert.Equal(t, def.Cpu, "512")
+	assert.Equal(t, def.Memory, "1024")
+}
+
+func TestServiceConnectWithAppMesh(t *testing.T) {
+	err := convertYamlError(t, `
+services:
+  web:
+    image: nginx
+    ports:
+      - 80:80
+x-aws-service_connect: true
+x-aws-appmesh: true
+`, useDefaultVPC)
+	assert.ErrorContains(t, err, "x-aws-service_connect can't be used with x-aws-appmesh")
+}
+
+func TestMergeExtraPropertiesLists(t *testing.T) {
+	properties := map[string]interface{}{
+		"Items": []interface{}{
+			map[string]interface{}{"Name": "a"},
+			map[string]interface{}{"Name": "b"},
+		},
+	}
+	mergeProperties(properties, map[string]interface{}{
+		"Items": []interface{}{nil, map[string]interface{}{"Extra": true}},
+	})
+	assert.DeepEqual(t, properties["Items"], []interface{}{
+		map[string]interface{}{"Name": "a"},
+		map[string]interface{}{"Name": "b", "Extra": true},
+	})
+}
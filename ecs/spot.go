@@ -0,0 +1,115 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	capacityProviderFargate     = "FARGATE"
+	capacityProviderFargateSpot = "FARGATE_SPOT"
+)
+
+// spotConfig is the Fargate capacity provider strategy set by x-aws-spot. Base tasks run on-demand, then
+// tasks are distributed according to weights, all on Spot by default.
+type spotConfig struct {
+	Base           int `json:"base,omitempty"`
+	OnDemandWeight int `json:"on_demand_weight,omitempty"`
+	SpotWeight     int `json:"spot_weight,omitempty"`
+}
+
+func getSpotConfig(project *types.Project) (*spotConfig, error) {
+	v, ok := project.Extensions[extensionSpot]
+	if !ok {
+		return nil, nil
+	}
+	marshalled, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var config spotConfig
+	err = json.Unmarshal(marshalled, &config)
+	if err != nil {
+		return nil, err
+	}
+	if config.Base < 0 || config.OnDemandWeight < 0 || config.SpotWeight < 0 {
+		return nil, fmt.Errorf("%s base and weights can't be negative", extensionSpot)
+	}
+	if config.OnDemandWeight == 0 && config.SpotWeight == 0 {
+		config.SpotWeight = 1
+	}
+	return &config, nil
+}
+
+// createSpotStrategy enables Fargate capacity providers on cluster, with the x-aws-spot strategy as default one
+func (b *ecsAPIService) createSpotStrategy(project *types.Project, template *cloudformation.Template) error {
+	config, err := getSpotConfig(project)
+	if err != nil || config == nil {
+		return err
+	}
+	for _, service := range project.Services {
+		if requireEC2(project, service) {
+			return fmt.Errorf("%s can't be used with service %q which requires EC2 instances", extensionSpot, service.Name)
+		}
+	}
+	r, ok := template.Resources["Cluster"]
+	if !ok {
+		logrus.Warnf("%s requires %s and %s capacity providers to be enabled on the existing cluster", extensionSpot, capacityProviderFargate, capacityProviderFargateSpot)
+		return nil
+	}
+	cluster := r.(*ecs.Cluster)
+	cluster.CapacityProviders = []string{capacityProviderFargate, capacityProviderFargateSpot}
+	cluster.DefaultCapacityProviderStrategy = []ecs.Cluster_CapacityProviderStrategyItem{
+		{
+			CapacityProvider: capacityProviderFargate,
+			Base:             config.Base,
+			Weight:           config.OnDemandWeight,
+		},
+		{
+			CapacityProvider: capacityProviderFargateSpot,
+			Weight:           config.SpotWeight,
+		},
+	}
+	return nil
+}
+
+// spotCapacityProviderStrategy returns the capacity provider strategy set by x-aws-spot for a Fargate service. It is
+// set on services, as existing clusters don't get the strategy as default one.
+func spotCapacityProviderStrategy(project *types.Project, service types.ServiceConfig) ([]interface{}, error) {
+	config, err := getSpotConfig(project)
+	if err != nil || config == nil || useExternal(project) || requireEC2(project, service) {
+		return nil, err
+	}
+	return []interface{}{
+		map[string]interface{}{
+			"CapacityProvider": capacityProviderFargate,
+			"Base":             config.Base,
+			"Weight":           config.OnDemandWeight,
+		},
+		map[string]interface{}{
+			"CapacityProvider": capacityProviderFargateSpot,
+			"Weight":           config.SpotWeight,
+		},
+	}, nil
+}
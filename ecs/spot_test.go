@@ -0,0 +1,90 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func TestSpotStrategy(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  foo:
+    image: hello_world
+x-aws-spot:
+  base: 1
+  on_demand_weight: 1
+  spot_weight: 3
+`, useDefaultVPC)
+	cluster := template.Resources["Cluster"].(*ecs.Cluster)
+	assert.DeepEqual(t, cluster.CapacityProviders, []string{"FARGATE", "FARGATE_SPOT"})
+	assert.DeepEqual(t, cluster.DefaultCapacityProviderStrategy, []ecs.Cluster_CapacityProviderStrategyItem{
+		{CapacityProvider: "FARGATE", Base: 1, Weight: 1},
+		{CapacityProvider: "FARGATE_SPOT", Weight: 3},
+	})
+	service := template.Resources["FooService"].(*ecs.Service)
+	assert.Equal(t, service.LaunchType, "")
+	assert.DeepEqual(t, service.AWSCloudFormationMetadata, extraProperties(map[string]interface{}{
+		"CapacityProviderStrategy": []interface{}{
+			map[string]interface{}{"CapacityProvider": "FARGATE", "Base": 1, "Weight": 1},
+			map[string]interface{}{"CapacityProvider": "FARGATE_SPOT", "Weight": 3},
+		},
+	}))
+}
+
+func TestSpotStrategyExistingCluster(t *testing.T) {
+	template := convertYaml(t, `
+x-aws-cluster: "arn:aws:ecs:region:account:cluster/name"
+services:
+  foo:
+    image: hello_world
+x-aws-spot: {}
+`, useDefaultVPC, func(m *MockAPIMockRecorder) {
+		m.ResolveCluster(gomock.Any(), "arn:aws:ecs:region:account:cluster/name").Return(existingAWSResource{
+			arn: "arn:aws:ecs:region:account:cluster/name",
+			id:  "name",
+		}, nil)
+	})
+	_, ok := template.Resources["Cluster"]
+	assert.Check(t, !ok)
+	service := template.Resources["FooService"].(*ecs.Service)
+	assert.Equal(t, service.LaunchType, "")
+	assert.DeepEqual(t, service.AWSCloudFormationMetadata, extraProperties(map[string]interface{}{
+		"CapacityProviderStrategy": []interface{}{
+			map[string]interface{}{"CapacityProvider": "FARGATE", "Base": 0, "Weight": 0},
+			map[string]interface{}{"CapacityProvider": "FARGATE_SPOT", "Weight": 1},
+		},
+	}))
+}
+
+func TestSpotStrategyDefaults(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  foo:
+    image: hello_world
+x-aws-spot: {}
+`, useDefaultVPC)
+	cluster := template.Resources["Cluster"].(*ecs.Cluster)
+	assert.DeepEqual(t, cluster.DefaultCapacityProviderStrategy, []ecs.Cluster_CapacityProviderStrategyItem{
+		{CapacityProvider: "FARGATE"},
+		{CapacityProvider: "FARGATE_SPOT", Weight: 1},
+	})
+}
@@ -0,0 +1,66 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"github.com/compose-spec/compose-go/types"
+)
+
+// ssmParameters returns environment variables set by x-aws-ssm, mapped to the SSM parameter name or ARN to resolve at task start
+func ssmParameters(service types.ServiceConfig) map[string]string {
+	x, ok := service.Extensions[extensionSSM]
+	if !ok {
+		return nil
+	}
+	parameters := map[string]string{}
+	if m, ok := x.(map[string]interface{}); ok {
+		for name, parameter := range m {
+			parameters[name] = fmt.Sprint(parameter)
+		}
+	}
+	return parameters
+}
+
+// ssmParameterARN returns the ARN of an SSM parameter set by name in the stack account and region
+func ssmParameterARN(parameter string) string {
+	if arn.IsARN(parameter) {
+		return parameter
+	}
+	return cloudformation.Sub("arn:${AWS::Partition}:ssm:${AWS::Region}:${AWS::AccountId}:parameter/" + strings.TrimPrefix(parameter, "/"))
+}
+
+func toSSMSecrets(service types.ServiceConfig) []ecs.TaskDefinition_Secret {
+	parameters := ssmParameters(service)
+	var secrets []ecs.TaskDefinition_Secret
+	for name, parameter := range parameters {
+		secrets = append(secrets, ecs.TaskDefinition_Secret{
+			Name:      name,
+			ValueFrom: ssmParameterARN(parameter),
+		})
+	}
+	sort.Slice(secrets, func(i, j int) bool {
+		return secrets[i].Name < secrets[j].Name
+	})
+	return secrets
+}
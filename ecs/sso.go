@@ -0,0 +1,264 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sso"
+	"github.com/aws/aws-sdk-go/service/sso/ssoiface"
+	"github.com/aws/aws-sdk-go/service/ssooidc"
+)
+
+const (
+	ssoClientName      = "docker-compose-cli"
+	ssoDeviceGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+	ssoProviderName    = "SSOProvider"
+)
+
+// ssoProfile holds the AWS SSO configuration of a profile declared in ~/.aws/config
+type ssoProfile struct {
+	StartURL  string
+	Region    string
+	AccountID string
+	RoleName  string
+}
+
+// loadSSOProfile returns the SSO configuration for profile, or nil if profile doesn't use AWS SSO
+func loadSSOProfile(configFile string, profile string) (*ssoProfile, error) {
+	section, err := loadProfileSection(configFile, configSectionName(profile))
+	if err != nil || section == nil {
+		return nil, err
+	}
+	if !section.HasKey("sso_start_url") {
+		return nil, nil
+	}
+	p := &ssoProfile{
+		StartURL:  section.Key("sso_start_url").String(),
+		Region:    section.Key("sso_region").String(),
+		AccountID: section.Key("sso_account_id").String(),
+		RoleName:  section.Key("sso_role_name").String(),
+	}
+	if p.Region == "" || p.AccountID == "" || p.RoleName == "" {
+		return nil, fmt.Errorf("profile %q must define sso_region, sso_account_id and sso_role_name", profile)
+	}
+	return p, nil
+}
+
+// ssoToken is an SSO access token, stored using the same cache format as AWS CLI v2
+type ssoToken struct {
+	StartURL    string `json:"startUrl"`
+	Region      string `json:"region"`
+	AccessToken string `json:"accessToken"`
+	ExpiresAt   string `json:"expiresAt"`
+}
+
+func (t ssoToken) expired() bool {
+	expiresAt, err := time.Parse(time.RFC3339, t.ExpiresAt)
+	if err != nil {
+		// AWS CLI v2 used to write a non-standard UTC suffix
+		expiresAt, err = time.Parse("2006-01-02T15:04:05UTC", t.ExpiresAt)
+		if err != nil {
+			return true
+		}
+	}
+	return time.Now().Add(time.Minute).After(expiresAt)
+}
+
+func ssoCacheFile(startURL string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	hash := sha1.Sum([]byte(startURL))
+	return filepath.Join(home, ".aws", "sso", "cache", hex.EncodeToString(hash[:])+".json"), nil
+}
+
+func loadSSOToken(file string) (*ssoToken, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var token ssoToken
+	if err := json.Unmarshal(b, &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func saveSSOToken(file string, token ssoToken) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+	b, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, b, 0600)
+}
+
+// ssoLoggedIn checks a valid SSO access token is cached for profile
+func ssoLoggedIn(profile ssoProfile) (bool, error) {
+	cache, err := ssoCacheFile(profile.StartURL)
+	if err != nil {
+		return false, err
+	}
+	token, err := loadSSOToken(cache)
+	if err != nil {
+		return false, err
+	}
+	return token != nil && !token.expired(), nil
+}
+
+// ssoLogin runs the OIDC device authorization flow, unless a valid token is already cached
+func ssoLogin(ctx context.Context, profile ssoProfile) error {
+	loggedIn, err := ssoLoggedIn(profile)
+	if err != nil || loggedIn {
+		return err
+	}
+	cache, err := ssoCacheFile(profile.StartURL)
+	if err != nil {
+		return err
+	}
+
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String(profile.Region),
+		Credentials: credentials.AnonymousCredentials,
+	})
+	if err != nil {
+		return err
+	}
+	oidc := ssooidc.New(sess)
+	client, err := oidc.RegisterClientWithContext(ctx, &ssooidc.RegisterClientInput{
+		ClientName: aws.String(ssoClientName),
+		ClientType: aws.String("public"),
+	})
+	if err != nil {
+		return err
+	}
+	auth, err := oidc.StartDeviceAuthorizationWithContext(ctx, &ssooidc.StartDeviceAuthorizationInput{
+		ClientId:     client.ClientId,
+		ClientSecret: client.ClientSecret,
+		StartUrl:     aws.String(profile.StartURL),
+	})
+	if err != nil {
+		return err
+	}
+
+	url := aws.StringValue(auth.VerificationUriComplete)
+	if url == "" {
+		url = aws.StringValue(auth.VerificationUri)
+	}
+	fmt.Fprintf(os.Stderr, "To sign in with AWS SSO, open %s in a browser and confirm code %s\n", url, aws.StringValue(auth.UserCode))
+
+	interval := time.Duration(aws.Int64Value(auth.Interval)) * time.Second
+	if interval == 0 {
+		interval = 5 * time.Second
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+		created, err := oidc.CreateTokenWithContext(ctx, &ssooidc.CreateTokenInput{
+			ClientId:     client.ClientId,
+			ClientSecret: client.ClientSecret,
+			DeviceCode:   auth.DeviceCode,
+			GrantType:    aws.String(ssoDeviceGrantType),
+		})
+		if err != nil {
+			if aerr, ok := err.(awserr.Error); ok {
+				switch aerr.Code() {
+				case ssooidc.ErrCodeAuthorizationPendingException:
+					continue
+				case ssooidc.ErrCodeSlowDownException:
+					interval += 5 * time.Second
+					continue
+				}
+			}
+			return err
+		}
+		expiresIn := time.Duration(aws.Int64Value(created.ExpiresIn)) * time.Second
+		return saveSSOToken(cache, ssoToken{
+			StartURL:    profile.StartURL,
+			Region:      profile.Region,
+			AccessToken: aws.StringValue(created.AccessToken),
+			ExpiresAt:   time.Now().Add(expiresIn).UTC().Format(time.RFC3339),
+		})
+	}
+}
+
+// ssoCredentialsProvider retrieves role credentials using the cached SSO access token
+type ssoCredentialsProvider struct {
+	credentials.Expiry
+	profile ssoProfile
+	client  ssoiface.SSOAPI
+}
+
+func newSSOCredentials(sess *session.Session, profile ssoProfile) *credentials.Credentials {
+	return credentials.NewCredentials(&ssoCredentialsProvider{
+		profile: profile,
+		client:  sso.New(sess, aws.NewConfig().WithRegion(profile.Region)),
+	})
+}
+
+func (p *ssoCredentialsProvider) Retrieve() (credentials.Value, error) {
+	cache, err := ssoCacheFile(p.profile.StartURL)
+	if err != nil {
+		return credentials.Value{}, err
+	}
+	token, err := loadSSOToken(cache)
+	if err != nil {
+		return credentials.Value{}, err
+	}
+	if token == nil || token.expired() {
+		return credentials.Value{}, fmt.Errorf("AWS SSO session for %s has expired, run `aws sso login` to sign in again", p.profile.StartURL)
+	}
+	output, err := p.client.GetRoleCredentials(&sso.GetRoleCredentialsInput{
+		AccessToken: aws.String(token.AccessToken),
+		AccountId:   aws.String(p.profile.AccountID),
+		RoleName:    aws.String(p.profile.RoleName),
+	})
+	if err != nil {
+		return credentials.Value{}, err
+	}
+	creds := output.RoleCredentials
+	// role credentials expiration is expressed in milliseconds since epoch
+	p.SetExpiration(time.Unix(0, aws.Int64Value(creds.Expiration)*int64(time.Millisecond)), time.Minute)
+	return credentials.Value{
+		AccessKeyID:     aws.StringValue(creds.AccessKeyId),
+		SecretAccessKey: aws.StringValue(creds.SecretAccessKey),
+		SessionToken:    aws.StringValue(creds.SessionToken),
+		ProviderName:    ssoProviderName,
+	}, nil
+}
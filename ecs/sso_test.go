@@ -0,0 +1,88 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+const ssoConfig = `
+[default]
+region = eu-west-3
+
+[profile sso]
+sso_start_url = https://example.awsapps.com/start
+sso_region = us-east-1
+sso_account_id = 123456789012
+sso_role_name = Developer
+
+[profile incomplete]
+sso_start_url = https://example.awsapps.com/start
+`
+
+func TestLoadSSOProfile(t *testing.T) {
+	dir := fs.NewDir(t, "aws", fs.WithFile("config", ssoConfig))
+	config := filepath.Join(dir.Path(), "config")
+
+	p, err := loadSSOProfile(config, "sso")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, *p, ssoProfile{
+		StartURL:  "https://example.awsapps.com/start",
+		Region:    "us-east-1",
+		AccountID: "123456789012",
+		RoleName:  "Developer",
+	})
+
+	p, err = loadSSOProfile(config, "default")
+	assert.NilError(t, err)
+	assert.Check(t, p == nil)
+
+	p, err = loadSSOProfile(config, "unknown")
+	assert.NilError(t, err)
+	assert.Check(t, p == nil)
+
+	_, err = loadSSOProfile(config, "incomplete")
+	assert.ErrorContains(t, err, "must define sso_region")
+}
+
+func TestSSOTokenCache(t *testing.T) {
+	dir := fs.NewDir(t, "sso")
+	cache := filepath.Join(dir.Path(), "cache", "token.json")
+
+	token, err := loadSSOToken(cache)
+	assert.NilError(t, err)
+	assert.Check(t, token == nil)
+
+	err = saveSSOToken(cache, ssoToken{
+		StartURL:    "https://example.awsapps.com/start",
+		AccessToken: "token",
+		ExpiresAt:   time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+	})
+	assert.NilError(t, err)
+	token, err = loadSSOToken(cache)
+	assert.NilError(t, err)
+	assert.Equal(t, token.AccessToken, "token")
+	assert.Check(t, !token.expired())
+
+	expired := ssoToken{ExpiresAt: "2020-01-01T00:00:00UTC"}
+	assert.Check(t, expired.expired())
+}
@@ -0,0 +1,83 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+const (
+	rolloutInProgress = "IN_PROGRESS"
+	rolloutCompleted  = "COMPLETED"
+	rolloutFailed     = "FAILED"
+)
+
+// rolloutState returns the state of service latest deployment. A deployment is failed when the circuit breaker
+// reported it as such in the most recent service event.
+func rolloutState(service *ecs.Service) string {
+	if len(service.Events) > 0 && strings.Contains(aws.StringValue(service.Events[0].Message), "deployment failed") {
+		return rolloutFailed
+	}
+	if len(service.Deployments) > 1 {
+		return rolloutInProgress
+	}
+	for _, deployment := range service.Deployments {
+		if aws.Int64Value(deployment.RunningCount) != aws.Int64Value(deployment.DesiredCount) {
+			return rolloutInProgress
+		}
+	}
+	return rolloutCompleted
+}
+
+// taskNetworkInterface returns the ID and private IP of task's elastic network interface
+func taskNetworkInterface(task *ecs.Task) (string, string) {
+	var id, privateIP string
+	for _, attachment := range task.Attachments {
+		if aws.StringValue(attachment.Type) != "ElasticNetworkInterface" {
+			continue
+		}
+		for _, detail := range attachment.Details {
+			switch aws.StringValue(detail.Name) {
+			case "networkInterfaceId":
+				id = aws.StringValue(detail.Value)
+			case "privateIPv4Address":
+				privateIP = aws.StringValue(detail.Value)
+			}
+		}
+	}
+	return id, privateIP
+}
+
+// taskStatus returns status of task, given target groups health and public IPs indexed by private IP and network
+// interface ID
+func taskStatus(task *ecs.Task, targetHealth map[string]string, publicIPs map[string]string) compose.TaskStatus {
+	arn := aws.StringValue(task.TaskArn)
+	networkInterface, privateIP := taskNetworkInterface(task)
+	return compose.TaskStatus{
+		ID:           arn[strings.LastIndex(arn, "/")+1:],
+		Status:       aws.StringValue(task.LastStatus),
+		Health:       aws.StringValue(task.HealthStatus),
+		TargetHealth: targetHealth[privateIP],
+		PrivateIP:    privateIP,
+		PublicIP:     publicIPs[networkInterface],
+	}
+}
@@ -0,0 +1,76 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func TestRolloutState(t *testing.T) {
+	deployment := func(status string, running, desired int64) *ecs.Deployment {
+		return &ecs.Deployment{Status: aws.String(status), RunningCount: aws.Int64(running), DesiredCount: aws.Int64(desired)}
+	}
+	assert.Equal(t, rolloutState(&ecs.Service{
+		Deployments: []*ecs.Deployment{deployment("PRIMARY", 2, 2)},
+	}), rolloutCompleted)
+	assert.Equal(t, rolloutState(&ecs.Service{
+		Deployments: []*ecs.Deployment{deployment("PRIMARY", 1, 2), deployment("ACTIVE", 2, 2)},
+	}), rolloutInProgress)
+	assert.Equal(t, rolloutState(&ecs.Service{
+		Deployments: []*ecs.Deployment{deployment("PRIMARY", 0, 2)},
+	}), rolloutInProgress)
+	assert.Equal(t, rolloutState(&ecs.Service{
+		Deployments: []*ecs.Deployment{deployment("PRIMARY", 0, 2)},
+		Events: []*ecs.ServiceEvent{
+			{Message: aws.String("(service web) (deployment ecs-svc/123) deployment failed: tasks failed to start.")},
+			{Message: aws.String("(service web) has started 2 tasks.")},
+		},
+	}), rolloutFailed)
+}
+
+func TestTaskStatus(t *testing.T) {
+	task := &ecs.Task{
+		TaskArn:      aws.String("arn:aws:ecs:eu-west-3:123456789012:task/demo/0123456789abcdef"),
+		LastStatus:   aws.String("RUNNING"),
+		HealthStatus: aws.String("HEALTHY"),
+		Attachments: []*ecs.Attachment{
+			{
+				Type: aws.String("ElasticNetworkInterface"),
+				Details: []*ecs.KeyValuePair{
+					{Name: aws.String("subnetId"), Value: aws.String("subnet-123")},
+					{Name: aws.String("networkInterfaceId"), Value: aws.String("eni-123")},
+					{Name: aws.String("privateIPv4Address"), Value: aws.String("10.0.1.5")},
+				},
+			},
+		},
+	}
+	status := taskStatus(task, map[string]string{"10.0.1.5": "healthy"}, map[string]string{"eni-123": "35.180.1.2"})
+	assert.DeepEqual(t, status, compose.TaskStatus{
+		ID:           "0123456789abcdef",
+		Status:       "RUNNING",
+		Health:       "HEALTHY",
+		TargetHealth: "healthy",
+		PrivateIP:    "10.0.1.5",
+		PublicIP:     "35.180.1.2",
+	})
+}
@@ -0,0 +1,118 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// stoppedTagPrefix prefixes stack tags recording desired count of services while project is stopped
+const stoppedTagPrefix = "com.docker.compose.stopped."
+
+// Stop scales all services to 0, recording their desired count in stack tags so that Start can restore them
+func (b *ecsAPIService) Stop(ctx context.Context, projectName string) error {
+	tags, err := b.aws.GetStackTags(ctx, projectName)
+	if err != nil {
+		return err
+	}
+	if len(stoppedReplicas(tags)) > 0 {
+		return errors.Wrapf(errdefs.ErrForbidden, "project %s is already stopped", projectName)
+	}
+	cluster, err := b.aws.GetStackClusterID(ctx, projectName)
+	if err != nil {
+		return err
+	}
+	arns, err := b.aws.ListStackServices(ctx, projectName)
+	if err != nil {
+		return err
+	}
+	services, err := b.aws.DescribeServices(ctx, cluster, arns)
+	if err != nil {
+		return err
+	}
+	replicas := map[string]int{}
+	for _, status := range services {
+		tags[stoppedTagPrefix+status.Name] = strconv.Itoa(status.Desired)
+		replicas[status.Name] = 0
+	}
+	if len(replicas) == 0 {
+		return nil
+	}
+
+	err = b.updateStackTags(ctx, projectName, tags)
+	if err != nil {
+		return err
+	}
+	return b.Scale(ctx, projectName, replicas)
+}
+
+// Start restores desired count of services recorded by Stop
+func (b *ecsAPIService) Start(ctx context.Context, projectName string) error {
+	tags, err := b.aws.GetStackTags(ctx, projectName)
+	if err != nil {
+		return err
+	}
+	replicas := stoppedReplicas(tags)
+	if len(replicas) == 0 {
+		return errors.Wrapf(errdefs.ErrNotFound, "project %s isn't stopped", projectName)
+	}
+	for k := range tags {
+		if strings.HasPrefix(k, stoppedTagPrefix) {
+			delete(tags, k)
+		}
+	}
+	// stack update can reset services desired count to template value, so it has to happen before scaling them
+	err = b.updateStackTags(ctx, projectName, tags)
+	if err != nil {
+		return err
+	}
+	return b.Scale(ctx, projectName, replicas)
+}
+
+func (b *ecsAPIService) updateStackTags(ctx context.Context, projectName string, tags map[string]string) error {
+	previousEvents, err := b.previousStackEvents(ctx, projectName)
+	if err != nil {
+		return err
+	}
+	err = b.aws.UpdateStackTags(ctx, projectName, tags)
+	if err != nil {
+		return err
+	}
+	return b.WaitStackCompletion(ctx, projectName, stackUpdate, previousEvents...)
+}
+
+// stoppedReplicas returns desired count of services recorded in stack tags by Stop
+func stoppedReplicas(tags map[string]string) map[string]int {
+	replicas := map[string]int{}
+	for k, v := range tags {
+		if !strings.HasPrefix(k, stoppedTagPrefix) {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			continue
+		}
+		replicas[strings.TrimPrefix(k, stoppedTagPrefix)] = n
+	}
+	return replicas
+}
@@ -0,0 +1,74 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestStoppedReplicas(t *testing.T) {
+	assert.DeepEqual(t, stoppedReplicas(map[string]string{
+		compose.ProjectTag:           "test",
+		stoppedTagPrefix + "web":     "3",
+		stoppedTagPrefix + "worker":  "0",
+		stoppedTagPrefix + "invalid": "x",
+	}), map[string]int{"web": 3, "worker": 0})
+}
+
+func TestStopAlreadyStopped(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+
+	m.EXPECT().GetStackTags(gomock.Any(), "test").Return(map[string]string{stoppedTagPrefix + "web": "1"}, nil)
+	backend := &ecsAPIService{aws: m}
+	err := backend.Stop(context.TODO(), "test")
+	assert.Check(t, errdefs.IsForbiddenError(err))
+}
+
+func TestStart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+
+	arn := "arn:aws:ecs:us-east-1:012345678910:service/cluster/web"
+	m.EXPECT().GetStackTags(gomock.Any(), "test").Return(map[string]string{
+		compose.ProjectTag:       "test",
+		stoppedTagPrefix + "web": "2",
+	}, nil)
+	m.EXPECT().DescribeStackEvents(gomock.Any(), "test").Return(nil, nil)
+	m.EXPECT().UpdateStackTags(gomock.Any(), "test", map[string]string{compose.ProjectTag: "test"}).Return(nil)
+	m.EXPECT().GetStackID(gomock.Any(), "test").Return("stack", nil)
+	m.EXPECT().WaitStackComplete(gomock.Any(), "stack", stackUpdate).Return(nil)
+	m.EXPECT().DescribeStackEvents(gomock.Any(), "stack").Return(nil, nil).AnyTimes()
+	m.EXPECT().GetStackClusterID(gomock.Any(), "test").Return("cluster", nil)
+	m.EXPECT().ListStackResources(gomock.Any(), "test").Return(stackResources{
+		{LogicalID: "WebService", Type: awsTypeService, ARN: arn},
+	}, nil)
+	m.EXPECT().ScaleService(gomock.Any(), "cluster", arn, 2).Return(nil)
+	m.EXPECT().WaitServicesStable(gomock.Any(), "cluster", []string{arn}).Return(nil)
+
+	backend := &ecsAPIService{aws: m}
+	assert.NilError(t, backend.Start(context.TODO(), "test"))
+}
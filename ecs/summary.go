@@ -0,0 +1,102 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/compose-spec/compose-go/types"
+)
+
+// stackOutput is an endpoint or identifier of a deployed resource users need to know about
+type stackOutput struct {
+	Name  string
+	Type  string
+	Value string
+}
+
+// listStackOutputs returns load balancers DNS names, services Cloud Map names, file systems IDs and the stack outputs
+func (b *ecsAPIService) listStackOutputs(ctx context.Context, project *types.Project) ([]stackOutput, error) {
+	resources, err := b.aws.ListStackResources(ctx, project.Name)
+	if err != nil {
+		return nil, err
+	}
+	var outputs []stackOutput
+	loadBalancerType := "load balancer"
+	if dualStack(project) {
+		// DNS name resolves to both A and AAAA records
+		loadBalancerType = "dual-stack load balancer"
+	}
+	err = resources.apply(awsTypeLoadBalancer, func(r stackResource) error {
+		dnsName, err := b.aws.GetLoadBalancerURL(ctx, r.ARN)
+		if err != nil {
+			return err
+		}
+		outputs = append(outputs, stackOutput{Name: r.LogicalID, Type: loadBalancerType, Value: dnsName})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deployed := map[string]bool{}
+	_ = resources.apply(awsTypeService, func(r stackResource) error {
+		deployed[r.LogicalID] = true
+		return nil
+	})
+	namespace := cloudMapNamespace(project)
+	for _, service := range project.Services {
+		if deployed[serviceResourceName(service.Name)] {
+			outputs = append(outputs, stackOutput{Name: service.Name, Type: "service", Value: fmt.Sprintf("%s.%s", service.Name, namespace)})
+		}
+	}
+
+	_ = resources.apply(awsTypeFileSystem, func(r stackResource) error {
+		outputs = append(outputs, stackOutput{Name: r.LogicalID, Type: "file system", Value: r.ARN})
+		return nil
+	})
+
+	stackOutputs, err := b.aws.ListStackOutputs(ctx, project.Name)
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for key := range stackOutputs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		outputs = append(outputs, stackOutput{Name: key, Type: "output", Value: stackOutputs[key]})
+	}
+	return outputs, nil
+}
+
+func renderStackOutputs(out io.Writer, outputs []stackOutput) {
+	if len(outputs) == 0 {
+		return
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tTYPE\tVALUE")
+	for _, o := range outputs {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", o.Name, o.Type, o.Value)
+	}
+	_ = w.Flush()
+}
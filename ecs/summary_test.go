@@ -0,0 +1,62 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func TestStackOutputs(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  web:
+    image: nginx
+    ports:
+      - 80:80
+  backup:
+    image: backup
+    x-aws-schedule: rate(1 day)
+`)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+	m.EXPECT().ListStackResources(gomock.Any(), project.Name).Return(stackResources{
+		{LogicalID: "LoadBalancer", Type: awsTypeLoadBalancer, ARN: "arn:aws:elasticloadbalancing:eu-west-3:123456789012:loadbalancer/app/demo/123"},
+		{LogicalID: "WebService", Type: awsTypeService, ARN: "arn:aws:ecs:eu-west-3:123456789012:service/demo/web"},
+		{LogicalID: "DataFilesystem", Type: awsTypeFileSystem, ARN: "fs-123456"},
+	}, nil)
+	m.EXPECT().GetLoadBalancerURL(gomock.Any(), "arn:aws:elasticloadbalancing:eu-west-3:123456789012:loadbalancer/app/demo/123").Return("demo-123.eu-west-3.elb.amazonaws.com", nil)
+	m.EXPECT().ListStackOutputs(gomock.Any(), project.Name).Return(map[string]string{"Bucket": "demo-assets"}, nil)
+
+	backend := &ecsAPIService{aws: m}
+	outputs, err := backend.listStackOutputs(context.TODO(), project)
+	assert.NilError(t, err)
+
+	var out bytes.Buffer
+	renderStackOutputs(&out, outputs)
+	assert.Equal(t, out.String(), `NAME            TYPE           VALUE
+LoadBalancer    load balancer  demo-123.eu-west-3.elb.amazonaws.com
+web             service        web.`+project.Name+`.local
+DataFilesystem  file system    fs-123456
+Bucket          output         demo-assets
+`)
+}
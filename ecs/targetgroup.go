@@ -0,0 +1,92 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/awslabs/goformation/v4/cloudformation/elasticloadbalancingv2"
+	"github.com/compose-spec/compose-go/types"
+)
+
+// targetGroupConfig is the x-aws-target_group service configuration of its target groups attributes
+type targetGroupConfig struct {
+	// DeregistrationDelay is the time in seconds to drain connections from targets being stopped
+	DeregistrationDelay *int `json:"deregistration_delay,omitempty"`
+	// Stickiness enables sticky sessions, for the cookie duration in seconds on application load balancer
+	Stickiness int `json:"stickiness,omitempty"`
+	// SlowStart is the time in seconds new targets get a linearly increasing share of traffic
+	SlowStart int `json:"slow_start,omitempty"`
+}
+
+// targetGroupAttributes returns attributes set by x-aws-target_group for a target group using protocol
+func targetGroupAttributes(service types.ServiceConfig, protocol string) ([]elasticloadbalancingv2.TargetGroup_TargetGroupAttribute, error) {
+	v, ok := service.Extensions[extensionTargetGroup]
+	if !ok {
+		return nil, nil
+	}
+	marshalled, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var config targetGroupConfig
+	err = json.Unmarshal(marshalled, &config)
+	if err != nil {
+		return nil, fmt.Errorf("service %q: invalid %s: %w", service.Name, extensionTargetGroup, err)
+	}
+
+	application := protocol == elbv2.ProtocolEnumHttp || protocol == elbv2.ProtocolEnumHttps
+	var attributes []elasticloadbalancingv2.TargetGroup_TargetGroupAttribute
+	attribute := func(key string, value interface{}) {
+		attributes = append(attributes, elasticloadbalancingv2.TargetGroup_TargetGroupAttribute{
+			Key:   key,
+			Value: fmt.Sprint(value),
+		})
+	}
+
+	if d := config.DeregistrationDelay; d != nil {
+		if *d < 0 || *d > 3600 {
+			return nil, fmt.Errorf("service %q: %s deregistration_delay must be between 0 and 3600 seconds", service.Name, extensionTargetGroup)
+		}
+		attribute("deregistration_delay.timeout_seconds", *d)
+	}
+	if config.Stickiness != 0 {
+		attribute("stickiness.enabled", true)
+		if application {
+			if config.Stickiness < 1 || config.Stickiness > 604800 {
+				return nil, fmt.Errorf("service %q: %s stickiness must be between 1 and 604800 seconds", service.Name, extensionTargetGroup)
+			}
+			attribute("stickiness.type", "lb_cookie")
+			attribute("stickiness.lb_cookie.duration_seconds", config.Stickiness)
+		} else {
+			// network load balancer routes a client to the same target by source IP, for the lifetime of the target
+			attribute("stickiness.type", "source_ip")
+		}
+	}
+	if config.SlowStart != 0 {
+		if !application {
+			return nil, fmt.Errorf("service %q: %s slow_start requires an application load balancer", service.Name, extensionTargetGroup)
+		}
+		if config.SlowStart < 30 || config.SlowStart > 900 {
+			return nil, fmt.Errorf("service %q: %s slow_start must be between 30 and 900 seconds", service.Name, extensionTargetGroup)
+		}
+		attribute("slow_start.duration_seconds", config.SlowStart)
+	}
+	return attributes, nil
+}
@@ -0,0 +1,70 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+
+	"github.com/awslabs/goformation/v4/cloudformation/elasticloadbalancingv2"
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestTargetGroupAttributes(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  web:
+    image: nginx
+    ports:
+      - 80:80
+    x-aws-target_group:
+      deregistration_delay: 0
+      stickiness: 3600
+      slow_start: 60
+`, useDefaultVPC)
+	tg := template.Resources["WebTCP80TargetGroup"].(*elasticloadbalancingv2.TargetGroup)
+	assert.DeepEqual(t, tg.TargetGroupAttributes, []elasticloadbalancingv2.TargetGroup_TargetGroupAttribute{
+		{Key: "deregistration_delay.timeout_seconds", Value: "0"},
+		{Key: "stickiness.enabled", Value: "true"},
+		{Key: "stickiness.type", Value: "lb_cookie"},
+		{Key: "stickiness.lb_cookie.duration_seconds", Value: "3600"},
+		{Key: "slow_start.duration_seconds", Value: "60"},
+	})
+}
+
+func TestTargetGroupAttributesNetwork(t *testing.T) {
+	service := types.ServiceConfig{
+		Name: "game",
+		Extensions: map[string]interface{}{
+			extensionTargetGroup: map[string]interface{}{
+				"stickiness": 1,
+			},
+		},
+	}
+	attributes, err := targetGroupAttributes(service, "TCP")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, attributes, []elasticloadbalancingv2.TargetGroup_TargetGroupAttribute{
+		{Key: "stickiness.enabled", Value: "true"},
+		{Key: "stickiness.type", Value: "source_ip"},
+	})
+
+	service.Extensions[extensionTargetGroup] = map[string]interface{}{
+		"slow_start": 60,
+	}
+	_, err = targetGroupAttributes(service, "TCP")
+	assert.Error(t, err, `service "game": x-aws-target_group slow_start requires an application load balancer`)
+}
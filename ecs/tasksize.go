@@ -0,0 +1,184 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// fargateCPUToMem lists memory sizes in MiB supported by Fargate for each task CPU units value
+var fargateCPUToMem = map[int64][]int64{
+	256:  {512, 1024, 2048},
+	512:  {1024, 2048, 3072, 4096},
+	1024: {2048, 3072, 4096, 5120, 6144, 7168, 8192},
+	2048: {4096, 5120, 6144, 7168, 8192, 9216, 10240, 11264, 12288, 13312, 14336, 15360, 16384},
+	4096: {8192, 9216, 10240, 11264, 12288, 13312, 14336, 15360, 16384, 17408, 18432, 19456, 20480, 21504, 22528, 23552, 24576, 25600, 26624, 27648, 28672, 29696, 30720},
+}
+
+// taskSize is a Fargate task size, with cpu in CPU units (1024 per vCPU) and mem in MiB
+type taskSize struct {
+	cpu int64
+	mem int64
+}
+
+func (s taskSize) String() string {
+	return fmt.Sprintf("%g vCPU/%d MiB", float64(s.cpu)/1024, s.mem)
+}
+
+// fargateTaskSizes returns all Fargate task sizes, sorted by CPU then memory
+func fargateTaskSizes() []taskSize {
+	var sizes []taskSize
+	for cpu, mems := range fargateCPUToMem {
+		for _, mem := range mems {
+			sizes = append(sizes, taskSize{cpu: cpu, mem: mem})
+		}
+	}
+	sort.Slice(sizes, func(i, j int) bool {
+		if sizes[i].cpu != sizes[j].cpu {
+			return sizes[i].cpu < sizes[j].cpu
+		}
+		return sizes[i].mem < sizes[j].mem
+	})
+	return sizes
+}
+
+// toCPUUnits converts a CPU limit in thousandths of CPU into task CPU units
+func toCPUUnits(milliCPUs int64) int64 {
+	return (milliCPUs*1024 + 999) / 1000
+}
+
+// fargateTaskSize returns the smallest Fargate task size which exactly matches the set limits. Zero means unset.
+func fargateTaskSize(cpu int64, mem types.UnitBytes) (taskSize, bool) {
+	for _, size := range fargateTaskSizes() {
+		if (cpu == 0 || cpu == size.cpu) && (mem == 0 || int64(mem) == size.mem*miB) {
+			return size, true
+		}
+	}
+	return taskSize{}, false
+}
+
+// roundUpTaskSize returns the smallest Fargate task size providing at least the set limits
+func roundUpTaskSize(cpu int64, mem types.UnitBytes) (taskSize, bool) {
+	for _, size := range fargateTaskSizes() {
+		if size.cpu >= cpu && size.mem*miB >= int64(mem) {
+			return size, true
+		}
+	}
+	return taskSize{}, false
+}
+
+// nearestTaskSizes returns the n Fargate task sizes closest to the set limits, 1 vCPU weighting as 1 GiB
+func nearestTaskSizes(cpu int64, mem types.UnitBytes, n int) []taskSize {
+	memMiB := (int64(mem) + miB - 1) / miB
+	distance := func(s taskSize) int64 {
+		var d int64
+		if cpu > 0 {
+			d += abs(s.cpu - cpu)
+		}
+		if mem > 0 {
+			d += abs(s.mem - memMiB)
+		}
+		return d
+	}
+	sizes := fargateTaskSizes()
+	sort.SliceStable(sizes, func(i, j int) bool {
+		return distance(sizes[i]) < distance(sizes[j])
+	})
+	if len(sizes) > n {
+		sizes = sizes[:n]
+	}
+	sort.Slice(sizes, func(i, j int) bool {
+		if sizes[i].cpu != sizes[j].cpu {
+			return sizes[i].cpu < sizes[j].cpu
+		}
+		return sizes[i].mem < sizes[j].mem
+	})
+	return sizes
+}
+
+func abs(i int64) int64 {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
+
+// taskSizeError reports limits, with CPU in thousandths, which exceed Fargate task sizes, with the nearest valid ones
+func taskSizeError(service string, milliCPUs int64, mem types.UnitBytes) error {
+	cpu := toCPUUnits(milliCPUs)
+	var requested []string
+	if cpu > 0 {
+		requested = append(requested, fmt.Sprintf("%g vCPU", float64(milliCPUs)/1000))
+	}
+	if mem > 0 {
+		requested = append(requested, fmt.Sprintf("%d MiB", (int64(mem)+miB-1)/miB))
+	}
+	var nearest []string
+	for _, size := range nearestTaskSizes(cpu, mem, 3) {
+		nearest = append(nearest, size.String())
+	}
+	return errors.Wrapf(errdefs.ErrParsingFailed, "service %q: resources limits %s exceed the largest Fargate task size, nearest are %s. Set %s to run services on EC2 instances",
+		service, strings.Join(requested, "/"), strings.Join(nearest, ", "), extensionEC2)
+}
+
+type autofixResourcesKey struct{}
+
+// WithAutofixResources lets backend created with ctx set services resources limits to the Fargate task size they are
+// rounded up to, rather than only sizing tasks
+func WithAutofixResources(ctx context.Context) context.Context {
+	return context.WithValue(ctx, autofixResourcesKey{}, true)
+}
+
+// applyAutofixResources sets resources limits of Fargate services to the smallest task size providing them
+func applyAutofixResources(ctx context.Context, project *types.Project) error {
+	if autofix, ok := ctx.Value(autofixResourcesKey{}).(bool); !ok || !autofix {
+		return nil
+	}
+	for i, service := range project.Services {
+		if requireEC2(project, service) {
+			continue
+		}
+		mem, cpu, err := getConfiguredLimits(service)
+		if err != nil {
+			return err
+		}
+		if mem == 0 && cpu == 0 {
+			continue
+		}
+		if _, ok := fargateTaskSize(toCPUUnits(cpu), mem); ok {
+			continue
+		}
+		size, ok := roundUpTaskSize(toCPUUnits(cpu), mem)
+		if !ok {
+			return taskSizeError(service.Name, cpu, mem)
+		}
+		logrus.Infof("service %q: resources limits rounded up to Fargate task size %s", service.Name, size)
+		service.Deploy.Resources.Limits.NanoCPUs = fmt.Sprintf("%g", float64(size.cpu)/1024)
+		service.Deploy.Resources.Limits.MemoryBytes = types.UnitBytes(size.mem * miB)
+		project.Services[i] = service
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestTaskSizeRoundUp(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  test:
+    image: nginx
+    deploy:
+      resources:
+        limits:
+          cpus: '0.3'
+          memory: 2048M
+`)
+	cpu, mem, err := toLimits(project, project.Services[0])
+	assert.NilError(t, err)
+	assert.Equal(t, cpu, "512")
+	assert.Equal(t, mem, "2048")
+
+	project = loadConfig(t, `
+services:
+  test:
+    image: nginx
+    deploy:
+      resources:
+        limits:
+          cpus: '8'
+`)
+	_, _, err = toLimits(project, project.Services[0])
+	assert.ErrorContains(t, err, `service "test": resources limits 8 vCPU exceed the largest Fargate task size, `+
+		`nearest are 4 vCPU/8192 MiB, 4 vCPU/9216 MiB, 4 vCPU/10240 MiB. Set x-aws-ec2 to run services on EC2 instances`)
+}
+
+func TestAutofixResources(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  test:
+    image: nginx
+    deploy:
+      resources:
+        limits:
+          cpus: '0.3'
+          memory: 1500M
+  memory:
+    image: nginx
+    deploy:
+      resources:
+        limits:
+          memory: 4G
+`)
+	err := applyAutofixResources(context.TODO(), project)
+	assert.NilError(t, err)
+	service, err := project.GetService("test")
+	assert.NilError(t, err)
+	assert.Equal(t, service.Deploy.Resources.Limits.NanoCPUs, "0.3")
+
+	err = applyAutofixResources(WithAutofixResources(context.TODO()), project)
+	assert.NilError(t, err)
+	for _, service := range project.Services {
+		cpu, mem, err := toLimits(project, service)
+		assert.NilError(t, err)
+		switch service.Name {
+		case "test":
+			assert.Equal(t, cpu, "512")
+			assert.Equal(t, mem, "2048")
+		case "memory":
+			assert.Equal(t, cpu, "512")
+			assert.Equal(t, mem, "4096")
+		}
+	}
+}
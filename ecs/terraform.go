@@ -0,0 +1,485 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const terraformHeader = `terraform {
+  required_providers {
+    aws = {
+      source = "hashicorp/aws"
+    }
+    awscc = {
+      source = "hashicorp/awscc"
+    }
+  }
+}
+
+data "aws_region" "current" {}
+data "aws_caller_identity" "current" {}
+data "aws_partition" "current" {}
+`
+
+var (
+	terraformPseudoParameters = map[string]string{
+		"AWS::Region":    "data.aws_region.current.name",
+		"AWS::AccountId": "data.aws_caller_identity.current.account_id",
+		"AWS::Partition": "data.aws_partition.current.partition",
+		"AWS::URLSuffix": "data.aws_partition.current.dns_suffix",
+		"AWS::StackName": "local.project",
+		"AWS::NoValue":   "null",
+	}
+	pascalCase   = regexp.MustCompile("^[A-Z][A-Za-z0-9]*$")
+	subVariables = regexp.MustCompile(`\$\{([^!}][^}]*)\}`)
+)
+
+// renderTerraform renders template as Terraform configuration using the awscc provider, which resources and
+// attributes map CloudFormation types and properties. Parameters are rendered as variables and outputs as outputs.
+func renderTerraform(name string, template rawTemplate) ([]byte, error) {
+	r := &terraformRenderer{template: template}
+	var body bytes.Buffer
+	for _, id := range template.parameterIDs() {
+		err := r.variable(&body, id, template.Parameters[id])
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s: %w", id, err)
+		}
+	}
+	for _, id := range template.logicalIDs() {
+		resource := template.Resources[id]
+		fmt.Fprintf(&body, "\nresource %q %q {\n", terraformType(resource.Type), terraformName(id))
+		var properties []string
+		for p := range resource.Properties {
+			properties = append(properties, p)
+		}
+		sort.Strings(properties)
+		for _, p := range properties {
+			value, err := r.value(resource.Properties[p], 1, false)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", id, p, err)
+			}
+			if strings.HasSuffix(p, "PolicyDocument") {
+				// IAM policies are JSON documents
+				value, err = r.value(resource.Properties[p], 1, true)
+				if err != nil {
+					return nil, fmt.Errorf("%s.%s: %w", id, p, err)
+				}
+				value = fmt.Sprintf("jsonencode(%s)", value)
+			}
+			fmt.Fprintf(&body, "  %s = %s\n", snakeCase(p), value)
+		}
+		if len(resource.DependsOn) > 0 {
+			var dependsOn []string
+			for _, d := range resource.DependsOn {
+				expr, err := r.resource(d)
+				if err != nil {
+					return nil, fmt.Errorf("%s.DependsOn: %w", id, err)
+				}
+				dependsOn = append(dependsOn, expr)
+			}
+			fmt.Fprintf(&body, "  depends_on = [%s]\n", strings.Join(dependsOn, ", "))
+		}
+		if retained(resource.DeletionPolicy) || retained(resource.UpdateReplacePolicy) {
+			// Terraform can't leave a resource behind, but refuses to destroy it
+			body.WriteString("  lifecycle {\n    prevent_destroy = true\n  }\n")
+		}
+		body.WriteString("}\n")
+	}
+	for _, id := range template.outputIDs() {
+		output := template.Outputs[id]
+		if output.Export != nil {
+			return nil, fmt.Errorf("output %s: %w", id, unsupportedConstruct("Export", FormatTerraform))
+		}
+		value, err := r.value(output.Value, 1, false)
+		if err != nil {
+			return nil, fmt.Errorf("output %s: %w", id, err)
+		}
+		fmt.Fprintf(&body, "\noutput %q {\n  value = %s\n", terraformName(id), value)
+		if output.Description != "" {
+			fmt.Fprintf(&body, "  description = %s\n", hclString(output.Description))
+		}
+		body.WriteString("}\n")
+	}
+
+	var b bytes.Buffer
+	b.WriteString(terraformHeader)
+	if r.availabilityZones {
+		b.WriteString("data \"aws_availability_zones\" \"available\" {}\n")
+	}
+	fmt.Fprintf(&b, "\nlocals {\n  project = %s\n}\n", hclString(name))
+	b.Write(body.Bytes())
+	return b.Bytes(), nil
+}
+
+// retained tells if a DeletionPolicy or UpdateReplacePolicy keeps the resource when CloudFormation removes it
+func retained(policy string) bool {
+	return policy == "Retain" || policy == "Snapshot"
+}
+
+type terraformRenderer struct {
+	template rawTemplate
+	// availabilityZones is set when Fn::GetAZs is used, rendered with the aws_availability_zones data source
+	availabilityZones bool
+}
+
+// variable renders a template parameter as input variable. List parameters are lists of strings, AllowedValues
+// become a validation rule.
+func (r *terraformRenderer) variable(b *bytes.Buffer, id string, parameter rawParameter) error {
+	cfnType := fmt.Sprint(parameter["Type"])
+	list := cfnType == "CommaDelimitedList" || strings.HasPrefix(cfnType, "List<")
+	name := terraformName(id)
+	fmt.Fprintf(b, "\nvariable %q {\n", name)
+	switch {
+	case strings.HasPrefix(cfnType, "AWS::SSM::Parameter::"):
+		return unsupportedConstruct(cfnType+" parameter type", FormatTerraform)
+	case list:
+		b.WriteString("  type = list(string)\n")
+	case cfnType == "Number":
+		b.WriteString("  type = number\n")
+	default:
+		b.WriteString("  type = string\n")
+	}
+	var keys []string
+	for k := range parameter {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := parameter[k]
+		switch k {
+		case "Type":
+		case "Description":
+			fmt.Fprintf(b, "  description = %s\n", hclString(fmt.Sprint(v)))
+		case "Default":
+			if list {
+				// list defaults are comma delimited strings
+				var items []interface{}
+				for _, item := range strings.Split(fmt.Sprint(v), ",") {
+					items = append(items, strings.TrimSpace(item))
+				}
+				v = items
+			}
+			value, err := r.value(v, 1, false)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(b, "  default = %s\n", value)
+		case "NoEcho":
+			if fmt.Sprint(v) == "true" {
+				b.WriteString("  sensitive = true\n")
+			}
+		case "AllowedValues":
+			values, err := r.value(v, 2, false)
+			if err != nil {
+				return err
+			}
+			condition := fmt.Sprintf("contains(%s, var.%s)", values, name)
+			if list {
+				condition = fmt.Sprintf("length(setsubtract(var.%s, %s)) == 0", name, values)
+			}
+			fmt.Fprintf(b, "  validation {\n    condition = %s\n    error_message = %s\n  }\n",
+				condition, hclString(fmt.Sprintf("%s must be one of the allowed values.", id)))
+		default:
+			return unsupportedConstruct("parameter "+k, FormatTerraform)
+		}
+	}
+	b.WriteString("}\n")
+	return nil
+}
+
+// value renders a property value as HCL expression. Object keys are converted to snake_case attribute names, unless
+// keepKeys is set or they are free-form keys, as logging options.
+func (r *terraformRenderer) value(v interface{}, indent int, keepKeys bool) (string, error) {
+	padding := strings.Repeat("  ", indent)
+	switch v := v.(type) {
+	case nil:
+		return "null", nil
+	case string:
+		return hclString(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case []interface{}:
+		if len(v) == 0 {
+			return "[]", nil
+		}
+		var b strings.Builder
+		b.WriteString("[\n")
+		for _, item := range v {
+			value, err := r.value(item, indent+1, keepKeys)
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprintf(&b, "%s  %s,\n", padding, value)
+		}
+		fmt.Fprintf(&b, "%s]", padding)
+		return b.String(), nil
+	case map[string]interface{}:
+		if expr, ok, err := r.intrinsic(v); ok || err != nil {
+			return expr, err
+		}
+		if len(v) == 0 {
+			return "{}", nil
+		}
+		var keys []string
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var b strings.Builder
+		b.WriteString("{\n")
+		for _, k := range keys {
+			value, err := r.value(v[k], indent+1, keepKeys)
+			if err != nil {
+				return "", err
+			}
+			key := hclString(k)
+			if !keepKeys && pascalCase.MatchString(k) {
+				key = snakeCase(k)
+			}
+			fmt.Fprintf(&b, "%s  %s = %s\n", padding, key, value)
+		}
+		fmt.Fprintf(&b, "%s}", padding)
+		return b.String(), nil
+	default:
+		return "", fmt.Errorf("unexpected value %v", v)
+	}
+}
+
+// intrinsic renders CloudFormation intrinsic functions as HCL expressions. Condition functions and other intrinsics
+// with no Terraform equivalent are rejected.
+func (r *terraformRenderer) intrinsic(v map[string]interface{}) (string, bool, error) {
+	if len(v) != 1 {
+		return "", false, nil
+	}
+	for fn, args := range v {
+		switch fn {
+		case "Ref":
+			expr, err := r.ref(fmt.Sprint(args))
+			return expr, true, err
+		case "Fn::GetAtt":
+			list, ok := args.([]interface{})
+			if !ok || len(list) != 2 {
+				return "", true, fmt.Errorf("unexpected Fn::GetAtt arguments %v", args)
+			}
+			expr, err := r.getAtt(fmt.Sprint(list[0]), fmt.Sprint(list[1]))
+			return expr, true, err
+		case "Fn::Sub":
+			s, ok := args.(string)
+			if !ok {
+				return "", true, fmt.Errorf("unsupported Fn::Sub arguments %v", args)
+			}
+			expr, err := r.sub(s)
+			return expr, true, err
+		case "Fn::Join":
+			list, ok := args.([]interface{})
+			if !ok || len(list) != 2 {
+				return "", true, fmt.Errorf("unexpected Fn::Join arguments %v", args)
+			}
+			items, ok := list[1].([]interface{})
+			if !ok {
+				return "", true, fmt.Errorf("unexpected Fn::Join arguments %v", args)
+			}
+			var values []string
+			for _, item := range items {
+				value, err := r.value(item, 0, false)
+				if err != nil {
+					return "", true, err
+				}
+				values = append(values, value)
+			}
+			return fmt.Sprintf("join(%s, [%s])", hclString(fmt.Sprint(list[0])), strings.Join(values, ", ")), true, nil
+		case "Fn::Select":
+			list, ok := args.([]interface{})
+			if !ok || len(list) != 2 {
+				return "", true, fmt.Errorf("unexpected Fn::Select arguments %v", args)
+			}
+			index, err := strconv.Atoi(fmt.Sprint(list[0]))
+			if err != nil {
+				return "", true, fmt.Errorf("unexpected Fn::Select index %v", list[0])
+			}
+			values, err := r.value(list[1], 0, false)
+			return fmt.Sprintf("element(%s, %d)", values, index), true, err
+		case "Fn::Split":
+			list, ok := args.([]interface{})
+			if !ok || len(list) != 2 {
+				return "", true, fmt.Errorf("unexpected Fn::Split arguments %v", args)
+			}
+			value, err := r.value(list[1], 0, false)
+			return fmt.Sprintf("split(%s, %s)", hclString(fmt.Sprint(list[0])), value), true, err
+		case "Fn::GetAZs":
+			// only the availability zones of the region resources are created in can be listed
+			if region, ok := args.(map[string]interface{}); args != "" && (!ok || region["Ref"] != "AWS::Region") {
+				return "", true, unsupportedConstruct(fmt.Sprintf("Fn::GetAZs for region %v", args), FormatTerraform)
+			}
+			r.availabilityZones = true
+			return "data.aws_availability_zones.available.names", true, nil
+		case "Fn::Base64":
+			value, err := r.value(args, 0, false)
+			return fmt.Sprintf("base64encode(%s)", value), true, err
+		default:
+			if strings.HasPrefix(fn, "Fn::") {
+				return "", true, unsupportedConstruct(fn, FormatTerraform)
+			}
+		}
+	}
+	return "", false, nil
+}
+
+func (r *terraformRenderer) ref(name string) (string, error) {
+	if expr, ok := terraformPseudoParameters[name]; ok {
+		return expr, nil
+	}
+	if _, ok := r.template.Parameters[name]; ok {
+		return "var." + terraformName(name), nil
+	}
+	resource, err := r.resource(name)
+	if err != nil {
+		return "", err
+	}
+	return resource + ".id", nil
+}
+
+func (r *terraformRenderer) getAtt(name string, attribute string) (string, error) {
+	resource, err := r.resource(name)
+	if err != nil {
+		return "", err
+	}
+	var attributes []string
+	for _, a := range strings.Split(attribute, ".") {
+		attributes = append(attributes, snakeCase(a))
+	}
+	return resource + "." + strings.Join(attributes, "."), nil
+}
+
+func (r *terraformRenderer) resource(name string) (string, error) {
+	resource, ok := r.template.Resources[name]
+	if !ok {
+		return "", fmt.Errorf("unknown resource %s", name)
+	}
+	return terraformType(resource.Type) + "." + terraformName(name), nil
+}
+
+// sub renders Fn::Sub as string template, with ${Resource}, ${Resource.Attribute} and pseudo parameters variables
+func (r *terraformRenderer) sub(s string) (string, error) {
+	var (
+		b    strings.Builder
+		last int
+	)
+	b.WriteString(`"`)
+	for _, match := range subVariables.FindAllStringSubmatchIndex(s, -1) {
+		b.WriteString(subLiteral(s[last:match[0]]))
+		variable := s[match[2]:match[3]]
+		var (
+			expr string
+			err  error
+		)
+		if i := strings.Index(variable, "."); i > 0 {
+			expr, err = r.getAtt(variable[:i], variable[i+1:])
+		} else {
+			expr, err = r.ref(variable)
+		}
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "${%s}", expr)
+		last = match[1]
+	}
+	b.WriteString(subLiteral(s[last:]))
+	b.WriteString(`"`)
+	return b.String(), nil
+}
+
+// subLiteral escapes a literal part of Fn::Sub, where ${!Literal} stands for ${Literal}
+func subLiteral(s string) string {
+	return hclEscape(strings.ReplaceAll(s, "${!", "${"))
+}
+
+// terraformType returns the awscc resource type for a CloudFormation type, AWS::ECS::TaskDefinition being awscc_ecs_task_definition
+func terraformType(cfnType string) string {
+	parts := strings.Split(cfnType, "::")
+	if len(parts) != 3 {
+		return snakeCase(cfnType)
+	}
+	return fmt.Sprintf("awscc_%s_%s", strings.ToLower(parts[1]), snakeCase(parts[2]))
+}
+
+func terraformName(logicalID string) string {
+	name := snakeCase(logicalID)
+	if name != "" && unicode.IsDigit(rune(name[0])) {
+		name = "_" + name
+	}
+	return name
+}
+
+// snakeCase converts a PascalCase CloudFormation name to snake_case, keeping acronyms together: VPCZoneIdentifier
+// becomes vpc_zone_identifier
+func snakeCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, c := range runes {
+		if unicode.IsUpper(c) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(unicode.IsUpper(runes[i-1]) && i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteRune('_')
+			}
+			b.WriteRune(unicode.ToLower(c))
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+func hclString(s string) string {
+	return `"` + hclEscape(s) + `"`
+}
+
+// hclEscape escapes a string literal, including template sequences
+func hclEscape(s string) string {
+	var b strings.Builder
+	for _, c := range s {
+		switch c {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if c < 0x20 {
+				fmt.Fprintf(&b, `\u%04x`, c)
+				continue
+			}
+			b.WriteRune(c)
+		}
+	}
+	escaped := strings.ReplaceAll(b.String(), "${", "$${")
+	return strings.ReplaceAll(escaped, "%{", "%%{")
+}
@@ -0,0 +1,113 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/utils"
+)
+
+const topCommand = "ps aux"
+
+func (b *ecsAPIService) Top(ctx context.Context, projectName string, services []string) ([]compose.ContainerProcSummary, error) {
+	plugin, err := lookupSessionManagerPlugin()
+	if err != nil {
+		return nil, err
+	}
+	cluster, err := b.aws.GetStackClusterID(ctx, projectName)
+	if err != nil {
+		return nil, err
+	}
+	arns, err := b.aws.ListStackServices(ctx, projectName)
+	if err != nil {
+		return nil, err
+	}
+	names, err := b.aws.GetServiceNames(ctx, cluster, arns)
+	if err != nil {
+		return nil, err
+	}
+	summaries := []compose.ContainerProcSummary{}
+	for _, arn := range arns {
+		service := names[arn]
+		if len(services) > 0 && !utils.StringContains(services, service) {
+			continue
+		}
+		tasks, err := b.aws.GetServiceTasks(ctx, cluster, arn, false)
+		if err != nil {
+			return nil, err
+		}
+		for _, task := range tasks {
+			args, err := b.taskSessionArgs(ctx, cluster, task, service, topCommand)
+			if err != nil {
+				return nil, err
+			}
+			var stdout, stderr bytes.Buffer
+			cmd := exec.CommandContext(ctx, plugin, args...)
+			cmd.Stdout = &stdout
+			cmd.Stderr = &stderr
+			if err := cmd.Run(); err != nil {
+				return nil, errors.Wrapf(err, "can't list processes of service %q: %s", service, strings.TrimSpace(stderr.String()))
+			}
+			titles, processes, err := parseProcesses(stdout.String())
+			if err != nil {
+				return nil, errors.Wrapf(err, "can't list processes of service %q", service)
+			}
+			summaries = append(summaries, compose.ContainerProcSummary{
+				ID:        lastSegment(aws.StringValue(task.TaskArn)),
+				Service:   service,
+				Titles:    titles,
+				Processes: processes,
+			})
+		}
+	}
+	return summaries, nil
+}
+
+// parseProcesses parses ps output captured from a session, ignoring messages printed by session manager plugin. Last
+// column, the command, can contain spaces.
+func parseProcesses(output string) ([]string, [][]string, error) {
+	var titles []string
+	processes := [][]string{}
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "Starting session with SessionId") || strings.HasPrefix(line, "Exiting session with sessionId") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if titles == nil {
+			titles = fields
+			continue
+		}
+		if len(fields) > len(titles) {
+			fields = append(fields[:len(titles)-1], strings.Join(fields[len(titles)-1:], " "))
+		}
+		processes = append(processes, fields)
+	}
+	if !utils.StringContains(titles, "PID") {
+		return nil, nil, fmt.Errorf("unexpected %s output: %s", topCommand, strings.TrimSpace(output))
+	}
+	return titles, processes, nil
+}
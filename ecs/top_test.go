@@ -0,0 +1,40 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseProcesses(t *testing.T) {
+	titles, processes, err := parseProcesses("\r\nStarting session with SessionId: ecs-execute-command-0123\r\n" +
+		"USER       PID %CPU %MEM    VSZ   RSS TTY      STAT START   TIME COMMAND\r\n" +
+		"root         1  0.0  0.1  10648  5968 ?        Ss   12:00   0:00 nginx: master process nginx -g daemon off;\r\n" +
+		"nginx       29  0.0  0.0  11044  2572 ?        S    12:00   0:00 nginx: worker process\r\n" +
+		"\r\n\r\nExiting session with sessionId: ecs-execute-command-0123.\r\n")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, titles, []string{"USER", "PID", "%CPU", "%MEM", "VSZ", "RSS", "TTY", "STAT", "START", "TIME", "COMMAND"})
+	assert.DeepEqual(t, processes, [][]string{
+		{"root", "1", "0.0", "0.1", "10648", "5968", "?", "Ss", "12:00", "0:00", "nginx: master process nginx -g daemon off;"},
+		{"nginx", "29", "0.0", "0.0", "11044", "2572", "?", "S", "12:00", "0:00", "nginx: worker process"},
+	})
+
+	_, _, err = parseProcesses("Starting session with SessionId: ecs-execute-command-0123\r\nsh: ps: not found\r\n")
+	assert.ErrorContains(t, err, "unexpected ps aux output")
+}
@@ -24,15 +24,66 @@ import (
 	"syscall"
 
 	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/errdefs"
 )
 
 func (b *ecsAPIService) Up(ctx context.Context, project *types.Project, detach bool) error {
+	return b.notifyDeployment(ctx, project, detach, func() error {
+		return b.up(ctx, project, detach)
+	})
+}
+
+func (b *ecsAPIService) up(ctx context.Context, project *types.Project, detach bool) error {
 	err := b.aws.CheckRequirements(ctx, b.Region)
 	if err != nil {
 		return err
 	}
 
-	template, err := b.Convert(ctx, project)
+	err = checkServicesAvailability(b.Region, requiredServices(project)...)
+	if err != nil {
+		return err
+	}
+
+	selected, err := selectServices(ctx, project)
+	if err != nil {
+		return err
+	}
+	ctx = WithServices(ctx, selected)
+
+	err = applyDeployStrategy(ctx, project)
+	if err != nil {
+		return err
+	}
+
+	err = applyAutofixResources(ctx, project)
+	if err != nil {
+		return err
+	}
+
+	err = b.buildImages(ctx, project)
+	if err != nil {
+		return err
+	}
+
+	err = b.checkImagesPlatform(ctx, project)
+	if err != nil {
+		return err
+	}
+
+	template, err := b.convert(ctx, project)
+	if err != nil {
+		return err
+	}
+
+	err = b.checkMaxMonthlyCost(ctx, project, template)
+	if err != nil {
+		return err
+	}
+
+	err = b.pinImageDigests(ctx, project, template)
 	if err != nil {
 		return err
 	}
@@ -42,33 +93,108 @@ func (b *ecsAPIService) Up(ctx context.Context, project *types.Project, detach b
 		return err
 	}
 	operation := stackCreate
+	var blueGreen map[string]string
 	if update {
+		fast, err := b.fastUpdate(ctx, project, template, detach)
+		if err != nil {
+			return err
+		}
+		if fast {
+			if !detach {
+				b.pruneSupersededTaskDefinitions(ctx, project.Name)
+			}
+			return nil
+		}
 		operation = stackUpdate
-		changeset, err := b.aws.CreateChangeSet(ctx, project.Name, template)
+		blueGreen, err = b.keepBlueGreenTaskDefinitions(ctx, project, template)
+		if err != nil {
+			return err
+		}
+		var previous []byte
+		if len(selected) > 0 {
+			previous, err = b.aws.GetStackTemplate(ctx, project.Name)
+			if err != nil {
+				return err
+			}
+		}
+		marshalled, err := marshallTemplate(ctx, project, template, previous)
 		if err != nil {
 			return err
 		}
+		changeset, err := b.aws.CreateChangeSet(ctx, project.Name, marshalled)
+		if err != nil {
+			return err
+		}
+		apply, err := b.reviewChangeSet(ctx, changeset)
+		if err == nil && !apply && !isDryRun(ctx) {
+			err = errors.Wrap(errdefs.ErrCanceled, "stack update canceled")
+		}
+		if err != nil || !apply {
+			if deleteErr := b.aws.DeleteChangeSet(ctx, changeset); deleteErr != nil {
+				logrus.Warnf("failed to delete change set %s: %s", changeset, deleteErr)
+			}
+			return err
+		}
 		err = b.aws.UpdateStack(ctx, changeset)
 		if err != nil {
 			return err
 		}
 	} else {
-		err = b.aws.CreateStack(ctx, project.Name, template)
+		if isDryRun(ctx) {
+			previewStackCreation(template)
+			return nil
+		}
+		marshalled, err := marshallTemplate(ctx, project, template, nil)
+		if err != nil {
+			return err
+		}
+		err = b.aws.CreateStack(ctx, project.Name, marshalled)
 		if err != nil {
 			return err
 		}
 	}
-	if detach {
+	// blue/green deployments can only start once stack has been updated
+	if detach && len(blueGreen) == 0 {
 		return nil
 	}
+	// only delete stack on interruption while deploying, as up may then stay attached to logs
 	signalChan := make(chan os.Signal, 1)
 	signal.Notify(signalChan, syscall.SIGINT, syscall.SIGTERM)
+	deployed := make(chan struct{})
+	defer func() {
+		signal.Stop(signalChan)
+		close(deployed)
+	}()
 	go func() {
-		<-signalChan
-		fmt.Println("user interrupted deployment. Deleting stack...")
-		b.Down(ctx, project.Name) // nolint:errcheck
+		select {
+		case <-signalChan:
+			fmt.Println("user interrupted deployment. Deleting stack...")
+			b.Down(ctx, project.Name) // nolint:errcheck
+		case <-deployed:
+		}
 	}()
 
-	err = b.WaitStackCompletion(ctx, project.Name, operation)
-	return err
+	err = b.WaitStackCompletion(withProgressTree(ctx, project, template, operation == stackCreate), project.Name, operation)
+	if err != nil {
+		return err
+	}
+	if operation == stackUpdate {
+		_, err = b.reconcileTaskDefinitions(ctx, project)
+		if err != nil {
+			return err
+		}
+	}
+	err = b.deployBlueGreen(ctx, project, blueGreen, detach)
+	if err != nil || detach {
+		return err
+	}
+	b.pruneSupersededTaskDefinitions(ctx, project.Name)
+
+	outputs, err := b.listStackOutputs(ctx, project)
+	if err != nil {
+		logrus.Warnf("failed to list stack outputs: %s", err)
+		return nil
+	}
+	renderStackOutputs(os.Stdout, outputs)
+	return nil
 }
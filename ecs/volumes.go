@@ -19,17 +19,94 @@ package ecs
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/docker/compose-cli/api/compose"
 	"github.com/docker/compose-cli/api/volumes"
 	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/progress"
 
 	"github.com/awslabs/goformation/v4/cloudformation"
 	"github.com/awslabs/goformation/v4/cloudformation/efs"
 	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/go-units"
 	"github.com/pkg/errors"
 )
 
+// defaultAccessPointPermissions are set on the access point root directory, when created for uid and gid
+const defaultAccessPointPermissions = "0755"
+
+var permissionsPattern = regexp.MustCompile(`^[0-7]{3,4}$`)
+
+// checkVolumesDriverOpts validates driver_opts of volumes backed by EFS file systems managed by the stack
+func checkVolumesDriverOpts(project *types.Project) error {
+	for name, volume := range project.Volumes {
+		if volume.External.External {
+			continue
+		}
+		opts := volume.DriverOpts
+		if err := checkVolumeOption(name, opts, "performance_mode", "generalPurpose", "maxIO"); err != nil {
+			return err
+		}
+		if err := checkVolumeOption(name, opts, "throughput_mode", "bursting", "provisioned", "elastic"); err != nil {
+			return err
+		}
+		if err := checkVolumeOption(name, opts, "transit_encryption", "enabled", "disabled"); err != nil {
+			return err
+		}
+		if t, ok := opts["provisioned_throughput"]; ok {
+			if v, err := strconv.ParseFloat(t, 64); err != nil || v <= 0 {
+				return fmt.Errorf("volume %q: provisioned_throughput must be a throughput in MiB/s", name)
+			}
+			if mode, ok := opts["throughput_mode"]; ok && mode != "provisioned" {
+				return fmt.Errorf("volume %q: provisioned_throughput requires throughput_mode provisioned", name)
+			}
+		} else if opts["throughput_mode"] == "provisioned" {
+			return fmt.Errorf("volume %q: throughput_mode provisioned requires provisioned_throughput", name)
+		}
+		for _, id := range []string{"uid", "gid"} {
+			if v, ok := opts[id]; ok {
+				if _, err := strconv.ParseUint(v, 10, 32); err != nil {
+					return fmt.Errorf("volume %q: %s must be a numeric ID", name, id)
+				}
+			}
+		}
+		if (opts["uid"] == "") != (opts["gid"] == "") {
+			return fmt.Errorf("volume %q: uid and gid must be set together", name)
+		}
+		if p, ok := opts["permissions"]; ok && !permissionsPattern.MatchString(p) {
+			return fmt.Errorf("volume %q: permissions must be octal, e.g. %s", name, defaultAccessPointPermissions)
+		}
+		if p, ok := opts["root_directory"]; ok && !strings.HasPrefix(p, "/") {
+			return fmt.Errorf("volume %q: root_directory must be an absolute path", name)
+		}
+		if !transitEncryption(volume) && (opts["uid"] != "" || opts["root_directory"] != "") {
+			return fmt.Errorf("volume %q: EFS access points require transit_encryption to set uid, gid or root_directory", name)
+		}
+	}
+	return nil
+}
+
+func checkVolumeOption(name string, opts map[string]string, option string, values ...string) error {
+	v, ok := opts[option]
+	if !ok {
+		return nil
+	}
+	for _, value := range values {
+		if v == value {
+			return nil
+		}
+	}
+	return fmt.Errorf("volume %q: %s must be one of %s", name, option, strings.Join(values, ", "))
+}
+
+// transitEncryption tells if volume is mounted with TLS, as required to use its access point and IAM authorization
+func transitEncryption(volume types.VolumeConfig) bool {
+	return volume.External.External || volume.DriverOpts["transit_encryption"] != "disabled"
+}
+
 func (b *ecsAPIService) createNFSMountTarget(project *types.Project, resources awsResources, template *cloudformation.Template) {
 	for volume := range project.Volumes {
 		for _, subnet := range resources.subnets {
@@ -53,6 +130,9 @@ func (b *ecsAPIService) mountTargets(volume string, resources awsResources) []st
 
 func (b *ecsAPIService) createAccessPoints(project *types.Project, r awsResources, template *cloudformation.Template) {
 	for name, volume := range project.Volumes {
+		if !transitEncryption(volume) {
+			continue
+		}
 		n := fmt.Sprintf("%sAccessPoint", normalizeResourceName(name))
 
 		uid := volume.DriverOpts["uid"]
@@ -90,6 +170,9 @@ func (b *ecsAPIService) createAccessPoints(project *types.Project, r awsResource
 			}
 			ap.RootDirectory = &root
 			if uid != "" {
+				if permissions == "" {
+					permissions = defaultAccessPointPermissions
+				}
 				root.CreationInfo = &efs.AccessPoint_CreationInfo{
 					OwnerUid:    uid,
 					OwnerGid:    gid,
@@ -108,49 +191,150 @@ type VolumeCreateOptions struct {
 	PerformanceMode              string
 	ProvisionedThroughputInMibps float64
 	ThroughputMode               string
+	// UID, GID, Permissions and RootDirectory configure the access point created with the file system
+	UID           string
+	GID           string
+	Permissions   string
+	RootDirectory string
+}
+
+// fileSystem describes an EFS file system
+type fileSystem struct {
+	ID           string
+	ARN          string
+	State        string
+	SizeInBytes  int64
+	Tags         map[string]string
+	AccessPoints []string
 }
 
 type ecsVolumeService struct {
 	backend *ecsAPIService
 }
 
+// List returns file systems managed as volumes, either by docker volume create or by a compose project
 func (e ecsVolumeService) List(ctx context.Context) ([]volumes.Volume, error) {
-	filesystems, err := e.backend.aws.ListFileSystems(ctx, nil)
+	filesystems, err := e.backend.aws.DescribeFileSystems(ctx)
 	if err != nil {
 		return nil, err
 	}
-	var vol []volumes.Volume
+	vol := []volumes.Volume{}
 	for _, fs := range filesystems {
-		vol = append(vol, volumes.Volume{
-			ID:          fs.ID(),
-			Description: fs.ARN(),
-		})
+		if _, ok := fs.Tags[compose.VolumeTag]; ok {
+			vol = append(vol, toVolume(fs))
+		}
 	}
 	return vol, nil
 }
 
 func (e ecsVolumeService) Create(ctx context.Context, name string, options interface{}) (volumes.Volume, error) {
-	fs, err := e.backend.aws.CreateFileSystem(ctx, map[string]string{
-		"Name": name,
-	}, options.(VolumeCreateOptions))
-	return volumes.Volume{
-		ID:          fs.ID(),
-		Description: fs.ARN(),
-	}, err
+	var opts VolumeCreateOptions
+	switch o := options.(type) {
+	case VolumeCreateOptions:
+		opts = o
+	case *VolumeCreateOptions:
+		opts = *o
+	}
+	if opts.UID != "" && opts.GID == "" {
+		return volumes.Volume{}, errors.Wrap(errdefs.ErrParsingFailed, "--gid is required with --uid")
+	}
+	_, err := e.resolve(ctx, name)
+	if err == nil {
+		return volumes.Volume{}, errors.Wrapf(errdefs.ErrAlreadyExists, "volume %q already exists", name)
+	}
+	if !errdefs.IsNotFoundError(err) {
+		return volumes.Volume{}, err
+	}
 
+	// volumes created outside a compose project have no project tag
+	tags := map[string]string{
+		"Name":            name,
+		compose.VolumeTag: name,
+	}
+	w := progress.ContextWriter(ctx)
+	w.Event(progress.Event{
+		ID:         name,
+		Status:     progress.Working,
+		StatusText: "Creating EFS file system",
+	})
+	fs, err := e.backend.aws.CreateFileSystem(ctx, tags, opts)
+	if err != nil {
+		return volumes.Volume{}, err
+	}
+	ap, err := e.backend.aws.CreateAccessPoint(ctx, fs.ID(), tags, opts)
+	if err != nil {
+		return volumes.Volume{}, err
+	}
+	w.Event(progress.Event{
+		ID:         name,
+		Status:     progress.Done,
+		StatusText: fmt.Sprintf("Created %s with access point %s", fs.ID(), ap),
+	})
+	return toVolume(fileSystem{
+		ID:           fs.ID(),
+		ARN:          fs.ARN(),
+		Tags:         tags,
+		AccessPoints: []string{ap},
+	}), nil
 }
 
+// Delete deletes a file system created by docker volume create, by ID or name, and its access points
 func (e ecsVolumeService) Delete(ctx context.Context, volumeID string, options interface{}) error {
-	return e.backend.aws.DeleteFileSystem(ctx, volumeID)
+	fs, err := e.resolve(ctx, volumeID)
+	if err != nil {
+		return err
+	}
+	if project, ok := fs.Tags[compose.ProjectTag]; ok {
+		return errors.Wrapf(errdefs.ErrForbidden, "volume %q belongs to project %s, use docker compose down --volumes to delete it", volumeID, project)
+	}
+	return e.backend.aws.DeleteFileSystem(ctx, fs.ID)
 }
 
 func (e ecsVolumeService) Inspect(ctx context.Context, volumeID string) (volumes.Volume, error) {
-	ok, err := e.backend.aws.ResolveFileSystem(ctx, volumeID)
-	if ok == nil {
-		err = errors.Wrapf(errdefs.ErrNotFound, "filesystem %q does not exists", volumeID)
+	fs, err := e.resolve(ctx, volumeID)
+	if err != nil {
+		return volumes.Volume{}, err
+	}
+	return toVolume(fs), nil
+}
+
+// resolve finds a file system by ID, or by the volume name it is tagged with
+func (e ecsVolumeService) resolve(ctx context.Context, nameOrID string) (fileSystem, error) {
+	filesystems, err := e.backend.aws.DescribeFileSystems(ctx)
+	if err != nil {
+		return fileSystem{}, err
+	}
+	for _, fs := range filesystems {
+		if fs.ID == nameOrID || fs.ARN == nameOrID {
+			return fs, nil
+		}
+	}
+	for _, fs := range filesystems {
+		_, project := fs.Tags[compose.ProjectTag]
+		if !project && fs.Tags[compose.VolumeTag] == nameOrID {
+			return fs, nil
+		}
+	}
+	return fileSystem{}, errors.Wrapf(errdefs.ErrNotFound, "volume %q does not exist", nameOrID)
+}
+
+func toVolume(fs fileSystem) volumes.Volume {
+	name := fs.Tags[compose.VolumeTag]
+	if project, ok := fs.Tags[compose.ProjectTag]; ok {
+		name = fmt.Sprintf("%s_%s", project, name)
+	}
+	description := []string{name}
+	if fs.State != "" {
+		description = append(description, fs.State)
+	}
+	if fs.SizeInBytes > 0 {
+		description = append(description, units.BytesSize(float64(fs.SizeInBytes)))
+	}
+	if len(fs.AccessPoints) > 0 {
+		description = append(description, "access points: "+strings.Join(fs.AccessPoints, ","))
 	}
 	return volumes.Volume{
-		ID:          volumeID,
-		Description: ok.ARN(),
-	}, err
+		ID:          fs.ID,
+		Description: strings.Join(description, ", "),
+	}
 }
@@ -0,0 +1,81 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/api/volumes"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+var volumeFileSystems = []fileSystem{
+	{ID: "fs-123", ARN: "arn:fs-123", State: "available", SizeInBytes: 6144, Tags: map[string]string{compose.VolumeTag: "data"}, AccessPoints: []string{"fsap-123"}},
+	{ID: "fs-456", ARN: "arn:fs-456", State: "available", Tags: map[string]string{compose.VolumeTag: "data", compose.ProjectTag: "myapp"}},
+	{ID: "fs-789", ARN: "arn:fs-789", State: "available", Tags: map[string]string{"Name": "unmanaged"}},
+}
+
+func TestVolumeList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+	m.EXPECT().DescribeFileSystems(gomock.Any()).Return(volumeFileSystems, nil)
+
+	vols, err := ecsVolumeService{backend: &ecsAPIService{aws: m}}.List(context.TODO())
+	assert.NilError(t, err)
+	assert.DeepEqual(t, vols, []volumes.Volume{
+		{ID: "fs-123", Description: "data, available, 6KiB, access points: fsap-123"},
+		{ID: "fs-456", Description: "myapp_data, available"},
+	})
+}
+
+func TestVolumeDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+	m.EXPECT().DescribeFileSystems(gomock.Any()).Return(volumeFileSystems, nil).Times(2)
+	m.EXPECT().DeleteFileSystem(gomock.Any(), "fs-123").Return(nil)
+
+	service := ecsVolumeService{backend: &ecsAPIService{aws: m}}
+	assert.NilError(t, service.Delete(context.TODO(), "data", nil))
+	err := service.Delete(context.TODO(), "fs-456", nil)
+	assert.Check(t, errdefs.IsForbiddenError(err))
+}
+
+func TestVolumeCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewMockAPI(ctrl)
+	tags := map[string]string{"Name": "logs", compose.VolumeTag: "logs"}
+	opts := VolumeCreateOptions{UID: "1000", GID: "1000", RootDirectory: "/logs"}
+	m.EXPECT().DescribeFileSystems(gomock.Any()).Return(volumeFileSystems, nil).Times(2)
+	m.EXPECT().CreateFileSystem(gomock.Any(), tags, opts).Return(existingAWSResource{id: "fs-abc", arn: "arn:fs-abc"}, nil)
+	m.EXPECT().CreateAccessPoint(gomock.Any(), "fs-abc", tags, opts).Return("fsap-abc", nil)
+
+	service := ecsVolumeService{backend: &ecsAPIService{aws: m}}
+	vol, err := service.Create(context.TODO(), "logs", &opts)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, vol, volumes.Volume{ID: "fs-abc", Description: "logs, access points: fsap-abc"})
+
+	_, err = service.Create(context.TODO(), "data", &opts)
+	assert.Check(t, errdefs.IsAlreadyExistsError(err))
+}
@@ -0,0 +1,116 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/cloudformation/ec2"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/sirupsen/logrus"
+)
+
+const vpcEndpointsSecurityGroup = "VpcEndpointsSecurityGroup"
+
+// interfaceEndpoints are the AWS services Fargate tasks reach to pull images from ECR, send logs and get secrets,
+// indexed by resource name prefix
+var interfaceEndpoints = map[string]string{
+	"EcrApi":         "ecr.api",
+	"EcrDkr":         "ecr.dkr",
+	"Logs":           "logs",
+	"SecretsManager": "secretsmanager",
+	"Ssm":            "ssm",
+}
+
+// useVPCEndpoints tells if x-aws-vpc_endpoints makes tasks run in private subnets without internet access
+func useVPCEndpoints(project *types.Project) bool {
+	v, ok := project.Extensions[extensionVPCEndpoints]
+	if !ok {
+		return false
+	}
+	enabled, ok := v.(bool)
+	return ok && enabled
+}
+
+// createVPCEndpoints creates the VPC endpoints tasks need to run without internet egress: interface endpoints for ECR,
+// CloudWatch Logs, Secrets Manager and SSM, and a gateway endpoint for S3 which ECR stores image layers in
+func (b *ecsAPIService) createVPCEndpoints(ctx context.Context, project *types.Project, template *cloudformation.Template, resources awsResources) error {
+	if !useVPCEndpoints(project) {
+		return nil
+	}
+	for _, service := range project.Services {
+		if !strings.Contains(service.Image, ".dkr.ecr.") {
+			logrus.Warnf("service %q uses image %q, which can't be pulled through VPC endpoints, as it isn't hosted on Amazon ECR", service.Name, service.Image)
+		}
+	}
+
+	template.Resources[vpcEndpointsSecurityGroup] = &ec2.SecurityGroup{
+		GroupDescription: fmt.Sprintf("%s Security Group for VPC endpoints", project.Name),
+		VpcId:            resources.vpc,
+		Tags:             projectTags(project),
+	}
+	for name := range project.Networks {
+		template.Resources[fmt.Sprintf("%s%sIngress", vpcEndpointsSecurityGroup, normalizeResourceName(name))] = &ec2.SecurityGroupIngress{
+			Description:           fmt.Sprintf("HTTPS to VPC endpoints from %s network", name),
+			GroupId:               cloudformation.Ref(vpcEndpointsSecurityGroup),
+			SourceSecurityGroupId: resources.securityGroups[name],
+			FromPort:              httpsPort,
+			IpProtocol:            "TCP",
+			ToPort:                httpsPort,
+		}
+	}
+
+	endpoints := map[string]string{}
+	for name, service := range interfaceEndpoints {
+		endpoints[name] = service
+	}
+	for _, service := range project.Services {
+		if execEnabled(service) {
+			// ECS Exec opens sessions with SSM Session Manager
+			endpoints["SsmMessages"] = "ssmmessages"
+		}
+	}
+	for name, service := range endpoints {
+		template.Resources[fmt.Sprintf("%sVpcEndpoint", name)] = &ec2.VPCEndpoint{
+			PrivateDnsEnabled: true,
+			SecurityGroupIds:  []string{cloudformation.Ref(vpcEndpointsSecurityGroup)},
+			ServiceName:       vpcEndpointServiceName(service),
+			SubnetIds:         resources.subnetsIDs(),
+			VpcEndpointType:   "Interface",
+			VpcId:             resources.vpc,
+		}
+	}
+
+	routeTables, err := b.aws.GetRouteTables(ctx, resources.vpc, resources.subnetsIDs())
+	if err != nil {
+		return err
+	}
+	template.Resources["S3VpcEndpoint"] = &ec2.VPCEndpoint{
+		RouteTableIds:   routeTables,
+		ServiceName:     vpcEndpointServiceName("s3"),
+		VpcEndpointType: "Gateway",
+		VpcId:           resources.vpc,
+	}
+	return nil
+}
+
+func vpcEndpointServiceName(service string) string {
+	return cloudformation.Sub(fmt.Sprintf("com.amazonaws.${AWS::Region}.%s", service))
+}
@@ -0,0 +1,78 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/cloudformation/ec2"
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"github.com/awslabs/goformation/v4/cloudformation/elasticloadbalancingv2"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func TestVPCEndpoints(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  foo:
+    image: 012345678910.dkr.ecr.eu-west-3.amazonaws.com/foo
+    ports:
+      - 80:80
+x-aws-vpc_endpoints: true
+`, useDefaultVPC, func(m *MockAPIMockRecorder) {
+		m.GetRouteTables(gomock.Any(), "vpc-123", []string{"subnet1", "subnet2"}).Return([]string{"rtb-123"}, nil)
+	})
+	for _, name := range []string{"EcrApi", "EcrDkr", "Logs", "SecretsManager", "Ssm"} {
+		endpoint := template.Resources[name+"VpcEndpoint"].(*ec2.VPCEndpoint)
+		assert.Equal(t, endpoint.VpcEndpointType, "Interface")
+		assert.Check(t, endpoint.PrivateDnsEnabled)
+		assert.DeepEqual(t, endpoint.SubnetIds, []string{"subnet1", "subnet2"})
+		assert.DeepEqual(t, endpoint.SecurityGroupIds, []string{cloudformation.Ref("VpcEndpointsSecurityGroup")})
+	}
+	_, ok := template.Resources["SsmMessagesVpcEndpoint"]
+	assert.Check(t, !ok)
+
+	s3 := template.Resources["S3VpcEndpoint"].(*ec2.VPCEndpoint)
+	assert.Equal(t, s3.VpcEndpointType, "Gateway")
+	assert.Equal(t, s3.ServiceName, cloudformation.Sub("com.amazonaws.${AWS::Region}.s3"))
+	assert.DeepEqual(t, s3.RouteTableIds, []string{"rtb-123"})
+
+	ingress := template.Resources["VpcEndpointsSecurityGroupDefaultIngress"].(*ec2.SecurityGroupIngress)
+	assert.Equal(t, ingress.FromPort, 443)
+	assert.Equal(t, ingress.SourceSecurityGroupId, cloudformation.Ref("DefaultNetwork"))
+
+	service := template.Resources["FooService"].(*ecs.Service)
+	assert.Equal(t, service.NetworkConfiguration.AwsvpcConfiguration.AssignPublicIp, "DISABLED")
+	lb := template.Resources["LoadBalancer"].(*elasticloadbalancingv2.LoadBalancer)
+	assert.Equal(t, lb.Scheme, "internal")
+}
+
+func TestVPCEndpointsExec(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  foo:
+    image: 012345678910.dkr.ecr.eu-west-3.amazonaws.com/foo
+    x-aws-exec: true
+x-aws-vpc_endpoints: true
+`, useDefaultVPC, func(m *MockAPIMockRecorder) {
+		m.GetRouteTables(gomock.Any(), "vpc-123", gomock.Any()).Return([]string{"rtb-123"}, nil)
+	})
+	endpoint := template.Resources["SsmMessagesVpcEndpoint"].(*ec2.VPCEndpoint)
+	assert.Equal(t, endpoint.ServiceName, cloudformation.Sub("com.amazonaws.${AWS::Region}.ssmmessages"))
+}
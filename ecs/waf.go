@@ -0,0 +1,53 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/cloudformation/wafv2"
+	"github.com/compose-spec/compose-go/types"
+)
+
+// createWebACLAssociation associates the WAF WebACL set by x-aws-waf with the application load balancer
+func (b *ecsAPIService) createWebACLAssociation(project *types.Project, template *cloudformation.Template, resources awsResources) error {
+	v, ok := project.Extensions[extensionWAF]
+	if !ok {
+		return nil
+	}
+	webACL, ok := v.(string)
+	if !ok || !arn.IsARN(webACL) {
+		return fmt.Errorf("%s must be a WebACL ARN", extensionWAF)
+	}
+	loadBalancer := resources.loadBalancer
+	if resources.extraLoadBalancerType == elbv2.LoadBalancerTypeEnumApplication {
+		loadBalancer = resources.extraLoadBalancer
+	} else if resources.loadBalancerType != elbv2.LoadBalancerTypeEnumApplication {
+		return fmt.Errorf("%s requires services to be exposed by an application load balancer", extensionWAF)
+	}
+	if _, ok := loadBalancer.(cloudformationARNResource); !ok {
+		return fmt.Errorf("%s requires a load balancer created for the application", extensionWAF)
+	}
+	template.Resources["LoadBalancerWebACLAssociation"] = &wafv2.WebACLAssociation{
+		ResourceArn: loadBalancer.ARN(),
+		WebACLArn:   webACL,
+	}
+	return nil
+}
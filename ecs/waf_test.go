@@ -0,0 +1,51 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/cloudformation/wafv2"
+	"gotest.tools/v3/assert"
+)
+
+const testWebACL = "arn:aws:wafv2:eu-west-3:123456789012:regional/webacl/app/12345678-1234-1234-1234-123456789012"
+
+func TestWebACLAssociation(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  foo:
+    image: nginx
+    ports:
+      - 80:80
+x-aws-waf: `+testWebACL, useDefaultVPC)
+	association := template.Resources["LoadBalancerWebACLAssociation"].(*wafv2.WebACLAssociation)
+	assert.Equal(t, association.ResourceArn, cloudformation.Ref("LoadBalancer"))
+	assert.Equal(t, association.WebACLArn, testWebACL)
+}
+
+func TestWebACLAssociationRequiresALB(t *testing.T) {
+	err := convertYamlError(t, `
+services:
+  foo:
+    image: nginx
+    ports:
+      - 5432:5432
+x-aws-waf: `+testWebACL, useDefaultVPC)
+	assert.ErrorContains(t, err, "x-aws-waf requires services to be exposed by an application load balancer")
+}
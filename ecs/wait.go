@@ -26,9 +26,59 @@ import (
 	"github.com/docker/compose-cli/progress"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
 )
 
-func (b *ecsAPIService) WaitStackCompletion(ctx context.Context, name string, operation int, ignored ...string) error { //nolint:gocyclo
+type waitOptionsKey struct{}
+
+// WaitOptions control waiting for CloudFormation stacks and ECS services to be stable
+type WaitOptions struct {
+	// Timeout aborts waiting once exceeded, while the operation goes on in background
+	Timeout time.Duration
+	// Interval overrides delay between polls of AWS APIs
+	Interval time.Duration
+}
+
+// WithWaitOptions sets timeout and polling interval to wait for stacks and services to be stable
+func WithWaitOptions(ctx context.Context, options WaitOptions) context.Context {
+	return context.WithValue(ctx, waitOptionsKey{}, options)
+}
+
+func getWaitOptions(ctx context.Context) WaitOptions {
+	options, _ := ctx.Value(waitOptionsKey{}).(WaitOptions)
+	return options
+}
+
+// waitWithTimeout runs wait, aborting it once wait timeout is exceeded
+func waitWithTimeout(ctx context.Context, what string, wait func(ctx context.Context) error) error {
+	timeout := getWaitOptions(ctx).Timeout
+	if timeout <= 0 {
+		return wait(ctx)
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	err := wait(waitCtx)
+	if err != nil && ctx.Err() == nil && waitCtx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("%s still in progress after %s, use docker compose ps to check its progress", what, timeout)
+	}
+	return err
+}
+
+// WaitStackCompletion waits for stack operation to complete, displaying stack events as progress
+func (b *ecsAPIService) WaitStackCompletion(ctx context.Context, name string, operation int, ignored ...string) error {
+	return waitWithTimeout(ctx, fmt.Sprintf("stack %s", name), func(ctx context.Context) error {
+		return b.waitStackCompletion(ctx, name, operation, ignored...)
+	})
+}
+
+// waitServicesStable waits for services to reach a steady state
+func (b *ecsAPIService) waitServicesStable(ctx context.Context, cluster string, arns []string) error {
+	return waitWithTimeout(ctx, "services deployment", func(ctx context.Context) error {
+		return b.aws.WaitServicesStable(ctx, cluster, arns)
+	})
+}
+
+func (b *ecsAPIService) waitStackCompletion(ctx context.Context, name string, operation int, ignored ...string) error { //nolint:gocyclo
 	knownEvents := map[string]struct{}{}
 	for _, id := range ignored {
 		knownEvents[id] = struct{}{}
@@ -41,8 +91,18 @@ func (b *ecsAPIService) WaitStackCompletion(ctx context.Context, name string, op
 	if err != nil {
 		return err
 	}
-
-	ticker := time.NewTicker(1 * time.Second)
+	tree := getProgressTree(ctx)
+	tree.start(w)
+
+	// failures are diagnosed on deployment, as rollback removes failing resources
+	diagnose := operation != stackDelete
+	since := time.Now()
+	var (
+		failed    []*cloudformation.StackEvent
+		diagnosis []serviceDiagnosis
+	)
+
+	ticker := time.NewTicker(pollInterval(ctx, time.Second))
 	done := make(chan bool)
 	go func() {
 		b.aws.WaitStackComplete(ctx, stackID, operation) //nolint:errcheck
@@ -94,13 +154,19 @@ func (b *ecsAPIService) WaitStackCompletion(ctx context.Context, name string, op
 			default:
 				if strings.HasSuffix(status, "_FAILED") {
 					progressStatus = progress.Error
+					if !isCancellation(event) {
+						failed = append(failed, event)
+					}
 					if stackErr == nil {
+						if diagnose {
+							diagnosis = b.diagnoseServices(ctx, name, since)
+						}
 						operation = stackDelete
 						stackErr = fmt.Errorf(reason)
 					}
 				}
 			}
-			w.Event(progress.Event{
+			tree.event(w, progress.Event{
 				ID:         resource,
 				Status:     progressStatus,
 				StatusText: fmt.Sprintf("%s %s", status, reason),
@@ -110,6 +176,7 @@ func (b *ecsAPIService) WaitStackCompletion(ctx context.Context, name string, op
 			continue
 		}
 		if err := b.checkStackState(ctx, name); err != nil {
+			diagnosis = b.diagnoseServices(ctx, name, since)
 			if e := b.aws.DeleteStack(ctx, name); e != nil {
 				return e
 			}
@@ -123,5 +190,11 @@ func (b *ecsAPIService) WaitStackCompletion(ctx context.Context, name string, op
 		}
 	}
 
+	if stackErr != nil && diagnose {
+		return stackFailure{
+			err:       stackErr,
+			diagnosis: formatDiagnosis(failed, diagnosis),
+		}
+	}
 	return stackErr
 }
@@ -0,0 +1,57 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestWaitWithTimeout(t *testing.T) {
+	ctx := WithWaitOptions(context.Background(), WaitOptions{Timeout: 10 * time.Millisecond})
+	err := waitWithTimeout(ctx, "stack test", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	assert.Error(t, err, "stack test still in progress after 10ms, use docker compose ps to check its progress")
+
+	err = waitWithTimeout(ctx, "stack test", func(ctx context.Context) error {
+		return nil
+	})
+	assert.NilError(t, err)
+}
+
+func TestWaitWithoutTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := waitWithTimeout(ctx, "stack test", func(ctx context.Context) error {
+		return ctx.Err()
+	})
+	assert.Equal(t, err, context.Canceled)
+}
+
+func TestWaiterOptions(t *testing.T) {
+	assert.Equal(t, len(waiterOptions(context.Background())), 0)
+	assert.Equal(t, pollInterval(context.Background(), 5*time.Second), 5*time.Second)
+
+	ctx := WithWaitOptions(context.Background(), WaitOptions{Timeout: time.Hour, Interval: 10 * time.Second})
+	assert.Equal(t, len(waiterOptions(ctx)), 2)
+	assert.Equal(t, pollInterval(ctx, 5*time.Second), 10*time.Second)
+}
@@ -0,0 +1,61 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+)
+
+// environment variables used by OIDC federation, e.g. GitHub Actions or EKS IAM roles for service accounts
+const (
+	envWebIdentityTokenFile = "AWS_WEB_IDENTITY_TOKEN_FILE"
+	envRoleARN              = "AWS_ROLE_ARN"
+	envRoleSessionName      = "AWS_ROLE_SESSION_NAME"
+)
+
+// hasEnvCredentials checks environment either sets static credentials or a web identity to assume a role
+func hasEnvCredentials() bool {
+	if _, err := credentials.NewEnvCredentials().Get(); err == nil {
+		return true
+	}
+	return hasWebIdentity()
+}
+
+func hasWebIdentity() bool {
+	return os.Getenv(envWebIdentityTokenFile) != "" && os.Getenv(envRoleARN) != ""
+}
+
+// envCredentialsProvider returns static credentials from environment when set, otherwise assumes role
+// from AWS_ROLE_ARN using the web identity token stored in AWS_WEB_IDENTITY_TOKEN_FILE
+func envCredentialsProvider(sess *session.Session) credentials.Provider {
+	static := &credentials.EnvProvider{}
+	if _, err := static.Retrieve(); err == nil || !hasWebIdentity() {
+		return static
+	}
+	name := os.Getenv(envRoleSessionName)
+	if name == "" {
+		name = fmt.Sprintf("docker-compose-cli-%d", time.Now().UnixNano())
+	}
+	return stscreds.NewWebIdentityRoleProvider(sts.New(sess), os.Getenv(envRoleARN), name, os.Getenv(envWebIdentityTokenFile))
+}
@@ -0,0 +1,144 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+)
+
+const (
+	platformWindows = "windows/amd64"
+
+	defaultWindowsOSFamily = "WINDOWS_SERVER_2019_CORE"
+	// windowsFargatePlatformVersion is the only Fargate platform version running Windows containers
+	windowsFargatePlatformVersion = "1.0.0"
+	// windowsMinTaskCPU is the smallest Fargate task size for Windows containers, in CPU units
+	windowsMinTaskCPU = 1024
+
+	windowsAMIParameter = "/aws/service/ami-windows-latest/Windows_Server-2019-English-Core-ECS_Optimized/image_id"
+)
+
+var windowsOSFamilies = []string{
+	"WINDOWS_SERVER_2019_CORE",
+	"WINDOWS_SERVER_2019_FULL",
+	"WINDOWS_SERVER_2022_CORE",
+	"WINDOWS_SERVER_2022_FULL",
+}
+
+// isWindows tells if service runs Windows containers, as set by platform
+func isWindows(service types.ServiceConfig) bool {
+	platform, err := servicePlatform(service)
+	return err == nil && platform == platformWindows
+}
+
+// requireWindows tells if project has services running Windows containers
+func requireWindows(project *types.Project) bool {
+	for _, service := range project.Services {
+		if isWindows(service) {
+			return true
+		}
+	}
+	return false
+}
+
+// windowsOSFamily returns the Windows Server version service containers are built for, as set by x-aws-os_family
+func windowsOSFamily(service types.ServiceConfig) (string, error) {
+	v, ok := service.Extensions[extensionOSFamily]
+	if !ok {
+		return defaultWindowsOSFamily, nil
+	}
+	family := strings.ToUpper(fmt.Sprint(v))
+	for _, f := range windowsOSFamilies {
+		if family == f {
+			return family, nil
+		}
+	}
+	return "", fmt.Errorf("service %q: %s must be one of %s", service.Name, extensionOSFamily, strings.Join(windowsOSFamilies, ", "))
+}
+
+// checkWindows rejects features Windows containers don't support on ECS, as they rely on Linux kernel features or on
+// Linux sidecar containers
+func checkWindows(project *types.Project) error {
+	for _, service := range project.Services {
+		if !isWindows(service) {
+			if _, ok := service.Extensions[extensionOSFamily]; ok {
+				return fmt.Errorf("service %q: %s requires platform %s", service.Name, extensionOSFamily, platformWindows)
+			}
+			continue
+		}
+		if _, err := windowsOSFamily(service); err != nil {
+			return err
+		}
+		var unsupported []string
+		if len(service.Secrets) > 0 {
+			unsupported = append(unsupported, "secrets (use x-aws-ssm environment variables)")
+		}
+		if len(service.Volumes) > 0 {
+			unsupported = append(unsupported, "volumes")
+		}
+		if service.ReadOnly {
+			unsupported = append(unsupported, "read_only")
+		}
+		if len(service.Tmpfs) > 0 {
+			unsupported = append(unsupported, "tmpfs")
+		}
+		if len(service.Ulimits) > 0 {
+			unsupported = append(unsupported, "ulimits")
+		}
+		if service.Init != nil && *service.Init {
+			unsupported = append(unsupported, "init")
+		}
+		if len(service.Sysctls) > 0 {
+			unsupported = append(unsupported, "sysctls")
+		}
+		if len(service.CapAdd) > 0 || len(service.CapDrop) > 0 {
+			unsupported = append(unsupported, "capabilities")
+		}
+		if service.Privileged {
+			unsupported = append(unsupported, "privileged")
+		}
+		for _, dependency := range service.DependsOn {
+			if dependency.Condition == types.ServiceConditionHealthy {
+				unsupported = append(unsupported, "depends_on service_healthy condition")
+				break
+			}
+		}
+		if gpuRequirements(service) > 0 {
+			unsupported = append(unsupported, "GPUs")
+		}
+		if useFirelens(service) {
+			unsupported = append(unsupported, logDriverFirelens+" logging driver")
+		}
+		for _, x := range []string{extensionXRay, extensionStorage} {
+			if _, ok := service.Extensions[x]; ok {
+				unsupported = append(unsupported, x)
+			}
+		}
+		for _, x := range []string{extensionAppMesh, extensionServiceConnect} {
+			if _, ok := project.Extensions[x]; ok {
+				unsupported = append(unsupported, x)
+			}
+		}
+		if len(unsupported) > 0 {
+			return fmt.Errorf("service %q: Windows containers don't support %s", service.Name, strings.Join(unsupported, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func TestWindowsService(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  web:
+    image: mcr.microsoft.com/windows/servercore/iis
+    platform: windows/amd64
+    x-aws-os_family: windows_server_2022_core
+  api:
+    image: nginx
+`, useDefaultVPC)
+	def := template.Resources["WebTaskDefinition"].(*ecs.TaskDefinition)
+	assert.Equal(t, def.Cpu, "1024")
+	assert.Equal(t, def.Memory, "2048")
+	assert.Equal(t, len(def.ContainerDefinitions), 1)
+
+	s := template.Resources["WebService"].(*ecs.Service)
+	assert.Equal(t, s.PlatformVersion, "1.0.0")
+	s = template.Resources["ApiService"].(*ecs.Service)
+	assert.Equal(t, s.PlatformVersion, "1.4.0")
+
+	marshalled, err := marshall(template)
+	assert.NilError(t, err)
+	var parsed struct {
+		Resources map[string]struct {
+			Properties map[string]interface{}
+		}
+	}
+	assert.NilError(t, json.Unmarshal(marshalled, &parsed))
+	assert.DeepEqual(t, parsed.Resources["WebTaskDefinition"].Properties["RuntimePlatform"], map[string]interface{}{
+		"CpuArchitecture":       "X86_64",
+		"OperatingSystemFamily": "WINDOWS_SERVER_2022_CORE",
+	})
+}
+
+func TestWindowsTaskSize(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  web:
+    image: iis
+    platform: windows
+    deploy:
+      resources:
+        limits:
+          cpus: '0.5'
+          memory: 2Gb
+`)
+	_, _, err := toLimits(project, project.Services[0])
+	assert.Error(t, err, `service "web": Windows tasks on Fargate require at least 1 vCPU and 2 GiB, resources limits are 0.5 vCPU/2048 MiB`)
+}
+
+func TestWindowsUnsupportedFeatures(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  web:
+    image: iis
+    platform: windows/amd64
+    read_only: true
+    cap_drop:
+      - ALL
+    x-aws-xray: true
+`)
+	err := checkWindows(project)
+	assert.Error(t, err, `service "web": Windows containers don't support read_only, capabilities, x-aws-xray`)
+
+	project = loadConfig(t, `
+services:
+  web:
+    image: nginx
+    x-aws-os_family: WINDOWS_SERVER_2019_CORE
+`)
+	err = checkWindows(project)
+	assert.Error(t, err, `service "web": x-aws-os_family requires platform windows/amd64`)
+}
+
+func TestWindowsEC2(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  web:
+    image: iis
+    platform: windows/amd64
+x-aws-ec2:
+  instance_type: m5.large
+`, useDefaultVPC, func(m *MockAPIMockRecorder) {
+		m.GetParameter(gomock.Any(), windowsAMIParameter).Return("ami-windows", nil)
+	})
+	def := template.Resources["WebTaskDefinition"].(*ecs.TaskDefinition)
+	assert.DeepEqual(t, def.RequiresCompatibilities, []string{"EC2"})
+
+	err := convertYamlError(t, `
+services:
+  web:
+    image: iis
+    platform: windows/amd64
+  api:
+    image: nginx
+x-aws-ec2:
+  instance_type: m5.large
+`, useDefaultVPC)
+	assert.Error(t, err, "x-aws-ec2 instances can't run both Windows and Linux services")
+}
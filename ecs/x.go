@@ -17,17 +17,48 @@
 package ecs
 
 const (
-	extensionSecurityGroup   = "x-aws-securitygroup"
-	extensionVPC             = "x-aws-vpc"
-	extensionPullCredentials = "x-aws-pull_credentials"
-	extensionLoadBalancer    = "x-aws-loadbalancer"
-	extensionProtocol        = "x-aws-protocol"
-	extensionCluster         = "x-aws-cluster"
-	extensionKeys            = "x-aws-keys"
-	extensionMinPercent      = "x-aws-min_percent"
-	extensionMaxPercent      = "x-aws-max_percent"
-	extensionRetention       = "x-aws-logs_retention"
-	extensionRole            = "x-aws-role"
-	extensionManagedPolicies = "x-aws-policies"
-	extensionAutoScaling     = "x-aws-autoscaling"
+	extensionSecurityGroup     = "x-aws-securitygroup"
+	extensionNetworkSecGroup   = "x-aws-security_group"
+	extensionVPC               = "x-aws-vpc"
+	extensionSubnets           = "x-aws-subnets"
+	extensionPullCredentials   = "x-aws-pull_credentials"
+	extensionLoadBalancer      = "x-aws-loadbalancer"
+	extensionProtocol          = "x-aws-protocol"
+	extensionCluster           = "x-aws-cluster"
+	extensionKeys              = "x-aws-keys"
+	extensionMinPercent        = "x-aws-min_percent"
+	extensionMaxPercent        = "x-aws-max_percent"
+	extensionRetention         = "x-aws-logs_retention"
+	extensionLogsKMSKey        = "x-aws-logs_kms_key"
+	extensionSecretsKMSKey     = "x-aws-secrets_kms_key"
+	extensionKMSKey            = "x-aws-kms_key"
+	extensionRole              = "x-aws-role"
+	extensionManagedPolicies   = "x-aws-policies"
+	extensionTaskRole          = "x-aws-task_role"
+	extensionAutoScaling       = "x-aws-autoscaling"
+	extensionSSM               = "x-aws-ssm"
+	extensionSpot              = "x-aws-spot"
+	extensionCertificate       = "x-aws-certificate"
+	extensionHTTPRedirect      = "x-aws-http_redirect"
+	extensionDNS               = "x-aws-dns"
+	extensionNamespace         = "x-aws-cloudmap_namespace"
+	extensionBlueGreen         = "x-aws-blue_green"
+	extensionEC2               = "x-aws-ec2"
+	extensionStorage           = "x-aws-ephemeral_storage"
+	extensionSchedule          = "x-aws-schedule"
+	extensionExec              = "x-aws-exec"
+	extensionHealthCheck       = "x-aws-healthcheck"
+	extensionAppMesh           = "x-aws-appmesh"
+	extensionServiceConnect    = "x-aws-service_connect"
+	extensionBalancerType      = "x-aws-loadbalancer_type"
+	extensionTargetGroup       = "x-aws-target_group"
+	extensionWAF               = "x-aws-waf"
+	extensionAlarms            = "x-aws-alarms"
+	extensionContainerInsights = "x-aws-container_insights"
+	extensionXRay              = "x-aws-xray"
+	extensionIPv6              = "x-aws-ipv6"
+	extensionVPCEndpoints      = "x-aws-vpc_endpoints"
+	extensionNotifications     = "x-aws-notifications"
+	extensionExternal          = "x-aws-external"
+	extensionOSFamily          = "x-aws-os_family"
 )
@@ -0,0 +1,71 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"fmt"
+
+	ecsapi "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"github.com/compose-spec/compose-go/types"
+)
+
+const (
+	xrayDaemonImage   = "public.ecr.aws/xray/aws-xray-daemon:3.x"
+	xrayDaemonPort    = 2000
+	xrayDaemonAddress = "AWS_XRAY_DAEMON_ADDRESS"
+)
+
+func useXRay(service types.ServiceConfig) bool {
+	v, ok := service.Extensions[extensionXRay]
+	if !ok {
+		return false
+	}
+	enabled, ok := v.(bool)
+	return ok && enabled
+}
+
+// createXRayDaemon creates the X-Ray daemon sidecar service sends trace segments to over UDP. As task containers share
+// the network namespace, service reaches it on localhost.
+func createXRayDaemon(service types.ServiceConfig, logConfiguration *ecs.TaskDefinition_LogConfiguration) ecs.TaskDefinition_ContainerDefinition {
+	return ecs.TaskDefinition_ContainerDefinition{
+		Name:      fmt.Sprintf("%s_XRayDaemon", normalizeResourceName(service.Name)),
+		Image:     xrayDaemonImage,
+		Essential: false,
+		PortMappings: []ecs.TaskDefinition_PortMapping{
+			{
+				ContainerPort: xrayDaemonPort,
+				Protocol:      ecsapi.TransportProtocolUdp,
+			},
+		},
+		LogConfiguration:  logConfiguration,
+		MemoryReservation: 256,
+	}
+}
+
+// withXRayDaemonAddress sets the daemon address X-Ray SDKs send segments to, unless service already does
+func withXRayDaemonAddress(pairs []ecs.TaskDefinition_KeyValuePair) []ecs.TaskDefinition_KeyValuePair {
+	for _, p := range pairs {
+		if p.Name == xrayDaemonAddress {
+			return pairs
+		}
+	}
+	return append(pairs, ecs.TaskDefinition_KeyValuePair{
+		Name:  xrayDaemonAddress,
+		Value: fmt.Sprintf("127.0.0.1:%d", xrayDaemonPort),
+	})
+}
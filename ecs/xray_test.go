@@ -0,0 +1,84 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"github.com/awslabs/goformation/v4/cloudformation/iam"
+	"gotest.tools/v3/assert"
+)
+
+func TestXRayDaemon(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  foo:
+    image: hello_world
+    x-aws-xray: true
+`, useDefaultVPC)
+	def := template.Resources["FooTaskDefinition"].(*ecs.TaskDefinition)
+	var daemon, main ecs.TaskDefinition_ContainerDefinition
+	for _, c := range def.ContainerDefinitions {
+		switch c.Name {
+		case "Foo_XRayDaemon":
+			daemon = c
+		case "foo":
+			main = c
+		}
+	}
+	assert.Equal(t, daemon.Image, xrayDaemonImage)
+	assert.DeepEqual(t, daemon.PortMappings, []ecs.TaskDefinition_PortMapping{{ContainerPort: 2000, Protocol: "udp"}})
+	assert.DeepEqual(t, main.DependsOnProp[len(main.DependsOnProp)-1], ecs.TaskDefinition_ContainerDependency{
+		Condition:     "START",
+		ContainerName: "Foo_XRayDaemon",
+	})
+	assert.Check(t, contains(envNames(main.Environment), "AWS_XRAY_DAEMON_ADDRESS"))
+
+	role := template.Resources["FooTaskRole"].(*iam.Role)
+	assert.DeepEqual(t, role.ManagedPolicyArns, []string{xrayDaemonPolicy})
+}
+
+func TestXRayDaemonAddressOverride(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  foo:
+    image: hello_world
+    x-aws-xray: true
+    environment:
+      AWS_XRAY_DAEMON_ADDRESS: xray.example.com:2000
+`, useDefaultVPC)
+	def := template.Resources["FooTaskDefinition"].(*ecs.TaskDefinition)
+	for _, c := range def.ContainerDefinitions {
+		if c.Name != "foo" {
+			continue
+		}
+		for _, e := range c.Environment {
+			if e.Name == "AWS_XRAY_DAEMON_ADDRESS" {
+				assert.Equal(t, e.Value, "xray.example.com:2000")
+			}
+		}
+	}
+}
+
+func envNames(pairs []ecs.TaskDefinition_KeyValuePair) []string {
+	var names []string
+	for _, p := range pairs {
+		names = append(names, p.Name)
+	}
+	return names
+}
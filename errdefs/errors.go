@@ -17,6 +17,8 @@
 package errdefs
 
 import (
+	"fmt"
+
 	"github.com/pkg/errors"
 )
 
@@ -51,6 +53,16 @@ var (
 	ErrWrongContextType = errors.New("wrong context type")
 )
 
+// StatusError reports the non-zero exit code of a command which ran to completion, such as a one-off container,
+// for the CLI to exit with
+type StatusError struct {
+	StatusCode int
+}
+
+func (e StatusError) Error() string {
+	return fmt.Sprintf("exit status %d", e.StatusCode)
+}
+
 // IsNotFoundError returns true if the unwrapped error is ErrNotFound
 func IsNotFoundError(err error) bool {
 	return errors.Is(err, ErrNotFound)
@@ -85,3 +97,12 @@ func IsErrParsingFailed(err error) bool {
 func IsErrCanceled(err error) bool {
 	return errors.Is(err, ErrCanceled)
 }
+
+// ExitCode returns the exit code a StatusError reports, and whether err is one
+func ExitCode(err error) (int, bool) {
+	var statusErr StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode, true
+	}
+	return 0, false
+}
@@ -50,3 +50,12 @@ func TestIsUnknown(t *testing.T) {
 
 	assert.Assert(t, !IsUnknownError(errors.New("another error")))
 }
+
+func TestExitCode(t *testing.T) {
+	code, ok := ExitCode(errors.Wrap(StatusError{StatusCode: 3}, "run"))
+	assert.Assert(t, ok)
+	assert.Equal(t, code, 3)
+
+	_, ok = ExitCode(errors.New("another error"))
+	assert.Assert(t, !ok)
+}
@@ -154,10 +154,58 @@ func (cs *composeService) Ps(ctx context.Context, project string) ([]compose.Ser
 func (cs *composeService) List(ctx context.Context, project string) ([]compose.Stack, error) {
 	return nil, errdefs.ErrNotImplemented
 }
-func (cs *composeService) Logs(ctx context.Context, project string, w io.Writer) error {
+func (cs *composeService) Logs(ctx context.Context, project string, w io.Writer, opts compose.LogOptions) error {
 	return errdefs.ErrNotImplemented
 }
 
-func (cs *composeService) Convert(ctx context.Context, project *types.Project) ([]byte, error) {
+func (cs *composeService) RunOneOffContainer(ctx context.Context, project *types.Project, opts compose.RunOptions) (int, error) {
+	return 0, errdefs.ErrNotImplemented
+}
+
+func (cs *composeService) Exec(ctx context.Context, projectName string, opts compose.ExecOptions) error {
+	return errdefs.ErrNotImplemented
+}
+
+func (cs *composeService) PortForward(ctx context.Context, projectName string, opts compose.PortForwardOptions) error {
+	return errdefs.ErrNotImplemented
+}
+
+func (cs *composeService) Cost(ctx context.Context, project *types.Project) ([]compose.CostEstimate, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (cs *composeService) Stop(ctx context.Context, projectName string) error {
+	return errdefs.ErrNotImplemented
+}
+
+func (cs *composeService) Start(ctx context.Context, projectName string) error {
+	return errdefs.ErrNotImplemented
+}
+
+func (cs *composeService) Restart(ctx context.Context, projectName string, services []string) error {
+	return errdefs.ErrNotImplemented
+}
+
+func (cs *composeService) Kill(ctx context.Context, projectName string, opts compose.KillOptions) error {
+	return errdefs.ErrNotImplemented
+}
+
+func (cs *composeService) Scale(ctx context.Context, projectName string, replicas map[string]int) error {
+	return errdefs.ErrNotImplemented
+}
+
+func (cs *composeService) Top(ctx context.Context, projectName string, services []string) ([]compose.ContainerProcSummary, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (cs *composeService) Images(ctx context.Context, projectName string) ([]compose.ImageSummary, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (cs *composeService) Events(ctx context.Context, projectName string, consumer func(compose.Event)) error {
+	return errdefs.ErrNotImplemented
+}
+
+func (cs *composeService) Convert(ctx context.Context, project *types.Project, format string) ([]byte, error) {
 	return nil, errdefs.ErrNotImplemented
 }
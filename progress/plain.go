@@ -20,11 +20,24 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strings"
+	"sync"
 )
 
 type plainWriter struct {
 	out  io.Writer
 	done chan bool
+	// last records the last line printed per event, so that repeated events aren't printed again
+	last map[string]string
+	mtx  sync.Mutex
+}
+
+func newPlainWriter(out io.Writer) *plainWriter {
+	return &plainWriter{
+		out:  out,
+		done: make(chan bool),
+		last: map[string]string{},
+	}
 }
 
 func (p *plainWriter) Start(ctx context.Context) error {
@@ -37,7 +50,24 @@ func (p *plainWriter) Start(ctx context.Context) error {
 }
 
 func (p *plainWriter) Event(e Event) {
-	fmt.Println(e.ID, e.Text, e.StatusText)
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	id := e.ID
+	if e.ParentID != "" {
+		id = e.ParentID + "/" + e.ID
+	}
+	fields := []string{id}
+	for _, f := range []string{e.Text, e.StatusText} {
+		if f != "" {
+			fields = append(fields, f)
+		}
+	}
+	line := strings.TrimSpace(strings.Join(fields, " "))
+	if p.last[id] == line {
+		return
+	}
+	p.last[id] = line
+	fmt.Fprintln(p.out, line)
 }
 
 func (p *plainWriter) Stop() {
@@ -39,6 +39,8 @@ type ttyWriter struct {
 	numLines int
 	done     chan bool
 	mtx      *sync.RWMutex
+	// startTime is the time of the first event, to estimate remaining time
+	startTime time.Time
 }
 
 func (w *ttyWriter) Start(ctx context.Context) error {
@@ -65,8 +67,12 @@ func (w *ttyWriter) Stop() {
 func (w *ttyWriter) Event(e Event) {
 	w.mtx.Lock()
 	defer w.mtx.Unlock()
-	if !utils.StringContains(w.eventIDs, e.ID) {
-		w.eventIDs = append(w.eventIDs, e.ID)
+	if w.startTime.IsZero() {
+		w.startTime = time.Now()
+	}
+	// parent is registered first, so that it is rendered above its children
+	if _, ok := w.events[e.ParentID]; e.ParentID != "" && !ok {
+		w.register(Event{ID: e.ParentID, Status: Working})
 	}
 	if _, ok := w.events[e.ID]; ok {
 		last := w.events[e.ID]
@@ -75,18 +81,30 @@ func (w *ttyWriter) Event(e Event) {
 			if last.Status != e.Status {
 				last.stop()
 			}
+		case Working:
+			if last.Status != Working {
+				last.spinner = newSpinner()
+			}
 		}
+		last.ParentID = e.ParentID
 		last.Status = e.Status
 		last.Text = e.Text
 		last.StatusText = e.StatusText
 		w.events[e.ID] = last
 	} else {
-		e.startTime = time.Now()
-		e.spinner = newSpinner()
-		w.events[e.ID] = e
+		w.register(e)
 	}
 }
 
+func (w *ttyWriter) register(e Event) {
+	if !utils.StringContains(w.eventIDs, e.ID) {
+		w.eventIDs = append(w.eventIDs, e.ID)
+	}
+	e.startTime = time.Now()
+	e.spinner = newSpinner()
+	w.events[e.ID] = e
+}
+
 func (w *ttyWriter) print() {
 	w.mtx.Lock()
 	defer w.mtx.Unlock()
@@ -109,6 +127,9 @@ func (w *ttyWriter) print() {
 	defer fmt.Fprint(w.out, aec.Show)
 
 	firstLine := fmt.Sprintf("[+] Running %d/%d", numDone(w.events), w.numLines)
+	if eta := remainingTime(time.Since(w.startTime), numDone(w.events), len(w.events)); eta >= time.Second {
+		firstLine += fmt.Sprintf(", ETA %s", eta)
+	}
 	if w.numLines != 0 && numDone(w.events) == w.numLines {
 		firstLine = aec.Apply(firstLine, aec.BlueF)
 	}
@@ -116,14 +137,14 @@ func (w *ttyWriter) print() {
 
 	var statusPadding int
 	for _, v := range w.eventIDs {
-		l := len(fmt.Sprintf("%s %s", w.events[v].ID, w.events[v].Text))
+		l := len(fmt.Sprintf("%s%s %s", indent(w.events[v]), w.events[v].ID, w.events[v].Text))
 		if statusPadding < l {
 			statusPadding = l
 		}
 	}
 
 	numLines := 0
-	for _, v := range w.eventIDs {
+	for _, v := range w.treeOrder() {
 		line := lineText(w.events[v], terminalWidth, statusPadding, runtime.GOOS != "windows")
 		// nolint: errcheck
 		fmt.Fprint(w.out, line)
@@ -133,6 +154,40 @@ func (w *ttyWriter) print() {
 	w.numLines = numLines
 }
 
+// treeOrder lists events IDs with children events right after their parent
+func (w *ttyWriter) treeOrder() []string {
+	children := map[string][]string{}
+	for _, v := range w.eventIDs {
+		if parent := w.events[v].ParentID; parent != "" {
+			children[parent] = append(children[parent], v)
+		}
+	}
+	var ids []string
+	for _, v := range w.eventIDs {
+		if w.events[v].ParentID == "" {
+			ids = append(ids, v)
+			ids = append(ids, children[v]...)
+		}
+	}
+	return ids
+}
+
+// indent shifts children events below their parent
+func indent(event Event) string {
+	if event.ParentID != "" {
+		return "  "
+	}
+	return ""
+}
+
+// remainingTime estimates the time left to complete all events, assuming they take as long as those already done
+func remainingTime(elapsed time.Duration, done, total int) time.Duration {
+	if done == 0 || done >= total {
+		return 0
+	}
+	return (elapsed * time.Duration(total-done) / time.Duration(done)).Round(time.Second)
+}
+
 func lineText(event Event, terminalWidth, statusPadding int, color bool) string {
 	endTime := time.Now()
 	if event.Status != Working {
@@ -141,7 +196,7 @@ func lineText(event Event, terminalWidth, statusPadding int, color bool) string
 
 	elapsed := endTime.Sub(event.startTime).Seconds()
 
-	textLen := len(fmt.Sprintf("%s %s", event.ID, event.Text))
+	textLen := len(fmt.Sprintf("%s%s %s", indent(event), event.ID, event.Text))
 	padding := statusPadding - textLen
 	if padding < 0 {
 		padding = 0
@@ -153,7 +208,8 @@ func lineText(event Event, terminalWidth, statusPadding int, color bool) string
 	if len(status) > maxStatusLen {
 		status = status[:maxStatusLen] + "..."
 	}
-	text := fmt.Sprintf(" %s %s %s%s %s",
+	text := fmt.Sprintf(" %s%s %s %s%s %s",
+		indent(event),
 		event.spinner.String(),
 		event.ID,
 		event.Text,
@@ -84,3 +84,29 @@ func TestErrorEvent(t *testing.T) {
 	assert.Assert(t, ok)
 	assert.Assert(t, event.endTime.After(time.Now().Add(-10*time.Second)))
 }
+
+func TestTreeOrder(t *testing.T) {
+	w := &ttyWriter{
+		events: map[string]Event{},
+		mtx:    &sync.RWMutex{},
+	}
+	w.Event(Event{ID: "stack"})
+	w.Event(Event{ID: "WebTaskDefinition", ParentID: "web"})
+	w.Event(Event{ID: "Cluster", ParentID: "stack"})
+	w.Event(Event{ID: "WebService", ParentID: "web"})
+	assert.DeepEqual(t, w.eventIDs, []string{"stack", "web", "WebTaskDefinition", "Cluster", "WebService"})
+	assert.DeepEqual(t, w.treeOrder(), []string{"stack", "Cluster", "web", "WebTaskDefinition", "WebService"})
+
+	ev := w.events["WebService"]
+	ev.spinner = &spinner{chars: []string{"."}}
+	ev.endTime = ev.startTime
+	ev.Status = Done
+	assert.Equal(t, lineText(ev, 50, 15, false), "   . WebService                              0.0s\n")
+}
+
+func TestRemainingTime(t *testing.T) {
+	assert.Equal(t, remainingTime(time.Minute, 0, 10), time.Duration(0))
+	assert.Equal(t, remainingTime(time.Minute, 10, 10), time.Duration(0))
+	assert.Equal(t, remainingTime(time.Minute, 2, 10), 4*time.Minute)
+	assert.Equal(t, remainingTime(10*time.Second, 3, 4), 3*time.Second)
+}
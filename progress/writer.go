@@ -41,7 +41,9 @@ const (
 
 // Event reprensents a progress event
 type Event struct {
-	ID         string
+	ID string
+	// ParentID renders the event below the event it identifies, as a tree
+	ParentID   string
 	Text       string
 	Status     EventStatus
 	StatusText string
@@ -64,8 +66,33 @@ type Writer interface {
 	Event(Event)
 }
 
+// Progress modes select how Run displays progress events
+const (
+	// ModeAuto renders events as a tree when output is a terminal, as plain text otherwise
+	ModeAuto = "auto"
+	// ModePlain prints events as plain text, one line per event, e.g. for CI logs
+	ModePlain = "plain"
+	// ModeQuiet doesn't display progress
+	ModeQuiet = "quiet"
+)
+
 type writerKey struct{}
 
+type modeKey struct{}
+
+// WithMode selects how Run displays progress events
+func WithMode(ctx context.Context, mode string) context.Context {
+	return context.WithValue(ctx, modeKey{}, mode)
+}
+
+func contextMode(ctx context.Context) string {
+	mode, ok := ctx.Value(modeKey{}).(string)
+	if !ok {
+		return ModeAuto
+	}
+	return mode
+}
+
 // WithContextWriter adds the writer to the context
 func WithContextWriter(ctx context.Context, writer Writer) context.Context {
 	return context.WithValue(ctx, writerKey{}, writer)
@@ -86,7 +113,7 @@ type progressFunc func(context.Context) (string, error)
 // in parallel
 func Run(ctx context.Context, pf progressFunc) (string, error) {
 	eg, _ := errgroup.WithContext(ctx)
-	w, err := NewWriter(os.Stderr)
+	w, err := newModeWriter(os.Stderr, contextMode(ctx))
 	var result string
 	if err != nil {
 		return "", err
@@ -110,6 +137,16 @@ func Run(ctx context.Context, pf progressFunc) (string, error) {
 	return result, err
 }
 
+func newModeWriter(out console.File, mode string) (Writer, error) {
+	switch mode {
+	case ModeQuiet:
+		return &noopWriter{}, nil
+	case ModePlain:
+		return newPlainWriter(out), nil
+	}
+	return NewWriter(out)
+}
+
 // NewWriter returns a new multi-progress writer
 func NewWriter(out console.File) (Writer, error) {
 	_, isTerminal := term.GetFdInfo(out)
@@ -130,8 +167,5 @@ func NewWriter(out console.File) (Writer, error) {
 		}, nil
 	}
 
-	return &plainWriter{
-		out:  out,
-		done: make(chan bool),
-	}, nil
+	return newPlainWriter(out), nil
 }
@@ -17,7 +17,9 @@
 package progress
 
 import (
+	"bytes"
 	"context"
+	"os"
 	"testing"
 
 	"gotest.tools/v3/assert"
@@ -29,3 +31,31 @@ func TestNoopWriter(t *testing.T) {
 
 	assert.Equal(t, writer, &noopWriter{})
 }
+
+func TestModeWriter(t *testing.T) {
+	assert.Equal(t, contextMode(context.TODO()), ModeAuto)
+	ctx := WithMode(context.TODO(), ModeQuiet)
+	assert.Equal(t, contextMode(ctx), ModeQuiet)
+
+	w, err := newModeWriter(os.Stderr, ModeQuiet)
+	assert.NilError(t, err)
+	assert.Equal(t, w, &noopWriter{})
+
+	w, err = newModeWriter(os.Stderr, ModePlain)
+	assert.NilError(t, err)
+	_, ok := w.(*plainWriter)
+	assert.Assert(t, ok)
+}
+
+func TestPlainWriter(t *testing.T) {
+	var out bytes.Buffer
+	w := newPlainWriter(&out)
+	w.Event(Event{ID: "web", StatusText: "1/2"})
+	w.Event(Event{ID: "WebService", ParentID: "web", StatusText: "CREATE_IN_PROGRESS"})
+	w.Event(Event{ID: "web", StatusText: "1/2"})
+	w.Event(Event{ID: "WebService", ParentID: "web", Text: "arn", StatusText: "CREATE_COMPLETE"})
+	assert.Equal(t, out.String(), `web 1/2
+web/WebService CREATE_IN_PROGRESS
+web/WebService arn CREATE_COMPLETE
+`)
+}
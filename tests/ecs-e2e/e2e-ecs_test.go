@@ -89,14 +89,18 @@ func TestCompose(t *testing.T) {
 		res := c.RunDockerCmd("compose", "ps", "--project-name", stack)
 		lines := strings.Split(res.Stdout(), "\n")
 
-		assert.Equal(t, 3, len(lines))
+		assert.Check(t, len(lines) > 3)
 		fields := strings.Fields(lines[1])
-		assert.Equal(t, 4, len(fields))
+		assert.Equal(t, 5, len(fields))
 		assert.Check(t, strings.Contains(fields[0], stack))
 		assert.Equal(t, "nginx", fields[1])
 		assert.Equal(t, "1/1", fields[2])
-		assert.Check(t, strings.Contains(fields[3], "->80/http"))
-		url = "http://" + strings.Replace(fields[3], "->80/http", "", 1)
+		assert.Check(t, strings.Contains(fields[4], "->80/http"))
+		url = "http://" + strings.Replace(fields[4], "->80/http", "", 1)
+
+		tasks := strings.Fields(lines[4])
+		assert.Equal(t, "nginx", tasks[1])
+		assert.Equal(t, "RUNNING", tasks[2])
 	})
 
 	t.Run("compose ls", func(t *testing.T) {